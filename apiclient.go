@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// apiClient calls the API of a running service
+type apiClient struct {
+	base  string
+	token string
+}
+
+// newAPIClient creates a client for the API at base, or at the configured
+// api.listen address when base is empty
+func newAPIClient(cfg *types.Config, base string) (*apiClient, error) {
+	if base == "" {
+		if cfg.API.Listen == "" {
+			return nil, fmt.Errorf("api.listen is not configured, pass --api")
+		}
+		host := cfg.API.Listen
+		if strings.HasPrefix(host, ":") {
+			host = "localhost" + host
+		}
+		base = "http://" + host
+	}
+	return &apiClient{base: strings.TrimSuffix(base, "/"), token: cfg.API.Token}, nil
+}
+
+// call sends a request to path and decodes the JSON response into v
+func (c *apiClient) call(ctx context.Context, method, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the service API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s: %s", resp.Status, apiErr.Error)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchNetworks    int
+	benchProposals   int
+	benchCycles      int
+	benchConcurrency int
+	benchCPUProfile  string
+	benchMemProfile  string
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark check cycles against a synthetic multi-network LCD",
+	Long: `Starts an in-process fake LCD serving synthetic proposals for the requested
+number of networks and runs full check cycles against it with notifications
+disabled, reporting timing and memory usage.`,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().IntVar(&benchNetworks, "networks", 100, "Number of synthetic networks")
+	benchCmd.Flags().IntVar(&benchProposals, "proposals", 20, "Proposals in voting period per network")
+	benchCmd.Flags().IntVar(&benchCycles, "cycles", 5, "Number of check cycles to run")
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", 10, "Maximum networks checked concurrently")
+	benchCmd.Flags().StringVar(&benchCPUProfile, "cpuprofile", "", "Write a CPU profile to this file")
+	benchCmd.Flags().StringVar(&benchMemProfile, "memprofile", "", "Write a heap profile to this file")
+	rootCmd.AddCommand(benchCmd)
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchNetworks <= 0 || benchCycles <= 0 || benchConcurrency <= 0 {
+		return fmt.Errorf("networks, cycles and concurrency must be greater than 0")
+	}
+
+	server := httptest.NewServer(syntheticLCDHandler(benchProposals))
+	defer server.Close()
+
+	cfg, err := config.Default()
+	if err != nil {
+		return err
+	}
+	cfg.Alerts.HoursBeforeStart = 24
+	cfg.Alerts.HoursBeforeEnd = 6
+	cfg.Alerts.CheckIntervalMinutes = 60
+	cfg.Networks = make(map[string]types.NetworkConfig, benchNetworks)
+	cfg.Performance.MaxConcurrentNetworks = benchConcurrency
+	for i := 0; i < benchNetworks; i++ {
+		name := fmt.Sprintf("bench-%d", i)
+		cfg.Networks[name] = types.NetworkConfig{
+			Name:         fmt.Sprintf("Bench Network %d", i),
+			RestEndpoint: fmt.Sprintf("%s/%s", server.URL, name),
+			ChainID:      fmt.Sprintf("bench-%d", i),
+		}
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		return err
+	}
+
+	svc, err := service.NewService(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+
+	if benchCPUProfile != "" {
+		f, err := os.Create(benchCPUProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	// The service logs every proposal, silence it while measuring
+	durations := make([]time.Duration, 0, benchCycles)
+	logrus.SetOutput(io.Discard)
+	for i := 0; i < benchCycles; i++ {
+		start := time.Now()
+		if err := svc.CheckOnce(context.Background()); err != nil {
+			logrus.SetOutput(os.Stderr)
+			return fmt.Errorf("check cycle %d failed: %w", i+1, err)
+		}
+		durations = append(durations, time.Since(start))
+	}
+	logrus.SetOutput(os.Stderr)
+
+	runtime.ReadMemStats(&after)
+
+	if benchMemProfile != "" {
+		f, err := os.Create(benchMemProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create heap profile: %w", err)
+		}
+		defer f.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+	}
+
+	var total, slowest time.Duration
+	for _, d := range durations {
+		total += d
+		if d > slowest {
+			slowest = d
+		}
+	}
+
+	fmt.Printf("Networks:          %d\n", benchNetworks)
+	fmt.Printf("Proposals/network: %d\n", benchProposals)
+	fmt.Printf("Concurrency:       %d\n", benchConcurrency)
+	fmt.Printf("Cycles:            %d\n", benchCycles)
+	fmt.Printf("Avg cycle:         %s\n", (total / time.Duration(len(durations))).Round(time.Microsecond))
+	fmt.Printf("Slowest cycle:     %s\n", slowest.Round(time.Microsecond))
+	fmt.Printf("Alloc/cycle:       %.1f MiB\n", float64(after.TotalAlloc-before.TotalAlloc)/float64(benchCycles)/(1<<20))
+	fmt.Printf("Heap in use:       %.1f MiB\n", float64(after.HeapInuse)/(1<<20))
+	fmt.Printf("GC runs:           %d\n", after.NumGC-before.NumGC)
+	fmt.Printf("Goroutines:        %d\n", runtime.NumGoroutine())
+
+	return nil
+}
+
+// syntheticLCDHandler serves the gov v1 proposals endpoint for any network
+// prefix with a fixed set of proposals in voting period
+func syntheticLCDHandler(proposals int) http.Handler {
+	now := time.Now().UTC()
+	items := make([]map[string]interface{}, 0, proposals)
+	for i := 1; i <= proposals; i++ {
+		items = append(items, map[string]interface{}{
+			"id":                fmt.Sprintf("%d", i),
+			"title":             fmt.Sprintf("Synthetic proposal %d", i),
+			"description":       strings.Repeat("Lorem ipsum dolor sit amet. ", 40),
+			"status":            "PROPOSAL_STATUS_VOTING_PERIOD",
+			"voting_start_time": now.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			"voting_end_time":   now.Add(time.Duration(i+72) * time.Hour).Format(time.RFC3339),
+		})
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"proposals":  items,
+		"pagination": map[string]string{"next_key": "", "total": fmt.Sprintf("%d", proposals)},
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/cosmos/gov/v1/proposals") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+}
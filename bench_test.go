@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestBenchSmoke runs one bench cycle against the synthetic LCD with the
+// same code path as the documented bench command
+func TestBenchSmoke(t *testing.T) {
+	defer func(networks, proposals, cycles, concurrency int) {
+		benchNetworks, benchProposals, benchCycles, benchConcurrency = networks, proposals, cycles, concurrency
+	}(benchNetworks, benchProposals, benchCycles, benchConcurrency)
+	benchNetworks, benchProposals, benchCycles, benchConcurrency = 2, 3, 1, 2
+
+	if err := runBench(benchCmd, nil); err != nil {
+		t.Fatalf("runBench() error = %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkNetwork string
+	checkAPI     string
+	checkOnce    bool
+)
+
+// Exit codes of check --once, besides 1 when it could not check at all
+const (
+	exitNetworksFailed = 2
+	exitAlertsFailed   = 3
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Make the running service check a network right away, or check every network once without a service",
+	Long: `Make the running service check a network right away through its API.
+
+With --once, run a single check cycle across every network (or --network)
+without a running service, send the alerts that are due and exit, for cron
+or a Kubernetes CronJob. Set state.path so later runs remember the alerts
+already sent. The exit code is 0 when every network was checked and every
+alert sent, 2 when a network could not be checked, 3 when an alert failed
+to send and 1 when the check could not run at all.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVarP(&checkNetwork, "network", "n", "", "Network to check, by name, chain ID or alias (required without --once)")
+	checkCmd.Flags().StringVar(&checkAPI, "api", "", "Base URL of the service API (default from api.listen)")
+	checkCmd.Flags().BoolVar(&checkOnce, "once", false, "Check every network once without a running service, send due alerts and exit")
+	checkCmd.MarkFlagsMutuallyExclusive("once", "api")
+	rootCmd.AddCommand(checkCmd)
+}
+
+// runCheck asks the service API to check a network outside its schedule,
+// so the service stays the only writer of the alert state
+func runCheck(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if checkOnce {
+		return runCheckOnce(cmd, cfg)
+	}
+	if checkNetwork == "" {
+		return fmt.Errorf("--network is required without --once")
+	}
+
+	client, err := newAPIClient(cfg, checkAPI)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var result service.CheckResult
+	path := fmt.Sprintf("/api/v1/networks/%s/check", url.PathEscape(checkNetwork))
+	if err := client.call(ctx, http.MethodPost, path, &result); err != nil {
+		return fmt.Errorf("check failed: %w", err)
+	}
+
+	fmt.Printf("%s (%s): %d proposals in voting period\n", result.Network, result.ChainID, result.Proposals)
+	if len(result.Alerts) == 0 {
+		fmt.Println("No alerts sent")
+	}
+	for _, alert := range result.Alerts {
+		fmt.Printf("Sent: %s for #%d\n", alert.AlertType, alert.ProposalID)
+	}
+	return nil
+}
+
+// runCheckOnce runs one check cycle in this process, as the service does
+// on each tick, for schedulers that start the tool instead of keeping it
+// running
+func runCheckOnce(cmd *cobra.Command, cfg *types.Config) error {
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %w", err)
+	}
+	logrus.SetLevel(level)
+	if err := configureLogging(cmd, cfg.Logging); err != nil {
+		return err
+	}
+	if checkNetwork != "" {
+		name, networkConfig, ok := config.LookupNetwork(cfg, checkNetwork)
+		if !ok {
+			return fmt.Errorf("unknown network %q", checkNetwork)
+		}
+		cfg.Networks = map[string]types.NetworkConfig{name: networkConfig}
+	}
+	if cfg.State.Path == "" {
+		logrus.Warn("No state.path configured, every run alerts as if it were the first")
+	}
+
+	privacy.Install(cfg)
+	svc, err := service.NewService(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	result := svc.CheckAll(ctx)
+	if err := svc.FlushAnalytics(ctx); err != nil {
+		logrus.WithError(err).Warn("Failed to export to the analytics database")
+	}
+
+	for _, network := range result.Networks {
+		fmt.Printf("%s (%s): %d proposals in voting period\n", network.Network, network.ChainID, network.Proposals)
+		for _, alert := range network.Alerts {
+			fmt.Printf("  Sent: %s for #%d\n", alert.AlertType, alert.ProposalID)
+		}
+	}
+	failed := make([]string, 0, len(result.Failed))
+	for name := range result.Failed {
+		failed = append(failed, name)
+	}
+	sort.Strings(failed)
+	for _, name := range failed {
+		fmt.Printf("%s: check failed: %s\n", name, result.Failed[name])
+	}
+
+	switch {
+	case result.FailedAlerts > 0:
+		return &exitError{code: exitAlertsFailed, err: fmt.Errorf("%d alerts failed to send", result.FailedAlerts)}
+	case len(failed) > 0:
+		return &exitError{code: exitNetworksFailed, err: fmt.Errorf("%d of %d networks could not be checked", len(failed), len(cfg.Networks))}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"governance-alerts-cosmos/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configuration utilities",
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := json.MarshalIndent(config.GenerateSchema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode schema: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:          "validate",
+	Short:        "Validate the configuration file and exit",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.LoadConfig(configPath); err != nil {
+			return err
+		}
+		if config.EnvOnly(configPath) {
+			fmt.Printf("The %s environment variables are valid\n", config.EnvPrefix)
+			return nil
+		}
+		fmt.Printf("%s is valid\n", configPath)
+		return nil
+	},
+}
+
+var configEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "List the environment variables that set configuration keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, variable := range config.EnvVariables() {
+			fmt.Fprintln(w, variable)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configEnvCmd)
+	rootCmd.AddCommand(configCmd)
+}
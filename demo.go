@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Demo proposal timeline: a new proposal every demoProposalEvery, each with
+// a short deposit period and voting window so the whole lifecycle is seen
+// within minutes
+const (
+	demoProposalEvery = 2 * time.Minute
+	demoDepositPeriod = time.Minute
+	demoVotingPeriod  = 5 * time.Minute
+	demoMaxProposals  = 20
+)
+
+// The demo chain produces a block every demoBlockTime from the start of the
+// demo, its first block
+const (
+	demoChainID   = "demo-1"
+	demoBlockTime = 6 * time.Second
+)
+
+var demoMode bool
+
+func init() {
+	rootCmd.Flags().BoolVar(&demoMode, "demo", false, "Monitor a built-in fake network with synthetic short-lived proposals instead of the configured networks")
+}
+
+// startDemo starts the fake network and points cfg at it, keeping the
+// notification settings. The returned function stops the network
+func startDemo(cfg *types.Config) func() {
+	// Backdate the start so the first proposal is already in voting
+	server := httptest.NewServer(demoLCDHandler(time.Now().Add(-demoDepositPeriod)))
+
+	cfg.Networks = map[string]types.NetworkConfig{
+		"demo": {
+			Name:         "Demo Network",
+			RestEndpoint: server.URL,
+			ChainID:      demoChainID,
+		},
+	}
+	cfg.Alerts.HoursBeforeStart = 1
+	cfg.Alerts.HoursBeforeEnd = 1
+	cfg.Alerts.CheckIntervalMinutes = 1
+	cfg.Alerts.NotifyOnNewProposal = true
+	cfg.Alerts.QuietHours = types.QuietHoursConfig{}
+	cfg.State.Path = ""
+	if cfg.Performance.MaxConcurrentNetworks <= 0 {
+		cfg.Performance.MaxConcurrentNetworks = 1
+	}
+
+	return server.Close
+}
+
+// demoProposal returns the n-th synthetic proposal (starting at 1) as it
+// looks at now
+func demoProposal(start time.Time, n int, now time.Time) map[string]interface{} {
+	submitted := start.Add(time.Duration(n-1) * demoProposalEvery)
+	votingStart := submitted.Add(demoDepositPeriod)
+	votingEnd := votingStart.Add(demoVotingPeriod)
+
+	status := "PROPOSAL_STATUS_DEPOSIT_PERIOD"
+	switch {
+	case now.After(votingEnd) && n%3 == 0:
+		status = "PROPOSAL_STATUS_REJECTED"
+	case now.After(votingEnd):
+		status = "PROPOSAL_STATUS_PASSED"
+	case now.After(votingStart):
+		status = "PROPOSAL_STATUS_VOTING_PERIOD"
+	}
+
+	proposal := map[string]interface{}{
+		"id":                strconv.Itoa(n),
+		"title":             fmt.Sprintf("Demo proposal %d", n),
+		"summary":           "A synthetic proposal generated by demo mode.",
+		"status":            status,
+		"submit_time":       submitted.UTC().Format(time.RFC3339),
+		"deposit_end_time":  votingStart.UTC().Format(time.RFC3339),
+		"voting_start_time": votingStart.UTC().Format(time.RFC3339),
+		"voting_end_time":   votingEnd.UTC().Format(time.RFC3339),
+		"messages": []map[string]interface{}{{
+			"@type":     "/cosmos.distribution.v1beta1.MsgCommunityPoolSpend",
+			"authority": "demo1gov",
+			"recipient": "demo1recipient",
+			"amount":    []map[string]string{{"denom": "udemo", "amount": strconv.Itoa(n * 1000000)}},
+		}},
+	}
+	if n > 1 {
+		proposal["summary"] = fmt.Sprintf("A synthetic proposal generated by demo mode. It follows up on prop %d.", n-1)
+	}
+	if status != "PROPOSAL_STATUS_VOTING_PERIOD" && status != "PROPOSAL_STATUS_DEPOSIT_PERIOD" {
+		proposal["final_tally_result"] = demoTally(n, demoVotingPeriod)
+	}
+	return proposal
+}
+
+// demoTally returns a tally that grows with the time spent voting
+func demoTally(n int, voted time.Duration) map[string]string {
+	weight := int(voted.Seconds()) * 1000
+	return map[string]string{
+		"yes_count":          strconv.Itoa(weight * (3 + n%3)),
+		"no_count":           strconv.Itoa(weight * 2),
+		"abstain_count":      strconv.Itoa(weight),
+		"no_with_veto_count": strconv.Itoa(weight / 2),
+	}
+}
+
+// demoBlock returns the block at height of a demo chain started at start
+func demoBlock(start time.Time, height int64) map[string]interface{} {
+	return map[string]interface{}{"header": map[string]string{
+		"chain_id": demoChainID,
+		"height":   strconv.FormatInt(height, 10),
+		"time":     start.Add(time.Duration(height-1) * demoBlockTime).UTC().Format(time.RFC3339Nano),
+	}}
+}
+
+// demoLCDHandler serves the gov, staking and block endpoints used by the
+// service for proposals generated since start
+func demoLCDHandler(start time.Time) http.Handler {
+	count := func(now time.Time) int {
+		return min(int(now.Sub(start)/demoProposalEvery)+1, demoMaxProposals)
+	}
+	latestHeight := func(now time.Time) int64 {
+		return int64(max(now.Sub(start), 0)/demoBlockTime) + 1
+	}
+
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		path := strings.TrimPrefix(r.URL.Path, "/cosmos/gov/v1/proposals")
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/cosmos/base/tendermint/v1beta1/blocks/"):
+			height := latestHeight(now)
+			if requested := strings.TrimPrefix(r.URL.Path, "/cosmos/base/tendermint/v1beta1/blocks/"); requested != "latest" {
+				n, err := strconv.ParseInt(requested, 10, 64)
+				if err != nil || n < 1 || n > height {
+					http.NotFound(w, r)
+					return
+				}
+				height = n
+			}
+			writeJSON(w, map[string]interface{}{"block": demoBlock(start, height)})
+		case r.URL.Path == "/cosmos/staking/v1beta1/pool":
+			writeJSON(w, map[string]interface{}{"pool": map[string]string{
+				"bonded_tokens":     "1000000000000",
+				"not_bonded_tokens": "50000000000",
+			}})
+		case path == "" && r.URL.Path != "/":
+			proposals := make([]map[string]interface{}, 0, demoMaxProposals)
+			for n := 1; n <= count(now); n++ {
+				proposals = append(proposals, demoProposal(start, n, now))
+			}
+			writeJSON(w, map[string]interface{}{
+				"proposals":  proposals,
+				"pagination": map[string]string{"next_key": "", "total": strconv.Itoa(len(proposals))},
+			})
+		default:
+			parts := strings.Split(strings.Trim(path, "/"), "/")
+			n, err := strconv.Atoi(parts[0])
+			if err != nil || n < 1 || n > count(now) {
+				http.NotFound(w, r)
+				return
+			}
+
+			proposal := demoProposal(start, n, now)
+			switch {
+			case len(parts) == 1:
+				writeJSON(w, map[string]interface{}{"proposal": proposal})
+			case parts[1] == "tally":
+				votingStart, _ := time.Parse(time.RFC3339, proposal["voting_start_time"].(string))
+				writeJSON(w, map[string]interface{}{"tally": demoTally(n, max(now.Sub(votingStart), 0))})
+			case parts[1] == "votes":
+				writeJSON(w, map[string]interface{}{
+					"votes":      []interface{}{},
+					"pagination": map[string]string{"next_key": "", "total": strconv.Itoa(n * 7)},
+				})
+			case parts[1] == "deposits":
+				writeJSON(w, map[string]interface{}{
+					"deposits": []map[string]interface{}{{
+						"proposal_id": strconv.Itoa(n),
+						"depositor":   "cosmos1demodepositor",
+						"amount":      []map[string]string{{"denom": "uatom", "amount": "250000000"}},
+					}},
+					"pagination": map[string]string{"next_key": "", "total": "1"},
+				})
+			default:
+				http.NotFound(w, r)
+			}
+		}
+	})
+}
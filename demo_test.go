@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// TestDemoLatestBlock checks that the demo network passes chain ID
+// verification and that its latest block follows the demo clock
+func TestDemoLatestBlock(t *testing.T) {
+	start := time.Now().Add(-demoDepositPeriod)
+	server := httptest.NewServer(demoLCDHandler(start))
+	defer server.Close()
+
+	client, err := governance.NewClient(types.NetworkConfig{Name: "Demo Network", RestEndpoint: server.URL, ChainID: demoChainID})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for _, identity := range client.VerifyChainID(context.Background()) {
+		if identity.Err != nil || identity.ChainID != demoChainID {
+			t.Errorf("VerifyChainID() = %s, %v, want %s", identity.ChainID, identity.Err, demoChainID)
+		}
+	}
+
+	height, blockTime, err := client.LatestBlock(context.Background())
+	if err != nil {
+		t.Fatalf("LatestBlock() error = %v", err)
+	}
+	if want := int64(demoDepositPeriod/demoBlockTime) + 1; height < want {
+		t.Errorf("LatestBlock() height = %d, want at least %d", height, want)
+	}
+	if now := time.Now(); blockTime.After(now) || now.Sub(blockTime) > demoBlockTime+time.Second {
+		t.Errorf("LatestBlock() time = %s, want within %s before %s", blockTime, demoBlockTime, now)
+	}
+}
@@ -0,0 +1,187 @@
+// Package amounts formats token amounts for messages and reports: base
+// denoms are converted to their display unit, grouped with the separators
+// of the configured locale and abbreviated from a million up. Durations
+// are spelled out in the words of the locale
+package amounts
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// Locales lists the supported locales
+var Locales = []string{"en", "de", "fr", "ch", "plain"}
+
+// separators are the thousands and decimal separators of a locale
+type separators struct {
+	thousands, decimal string
+}
+
+var localeSeparators = map[string]separators{
+	"":      {",", "."},
+	"en":    {",", "."},
+	"de":    {".", ","},
+	"fr":    {" ", ","},
+	"ch":    {"'", "."},
+	"plain": {"", "."},
+}
+
+// abbreviations are the suffixes of large amounts, largest first
+var abbreviations = []struct {
+	min    float64
+	suffix string
+}{
+	{1e12, "T"},
+	{1e9, "B"},
+	{1e6, "M"},
+}
+
+// defaultConfig is used until Install is called, matching the config
+// defaults
+var defaultConfig = types.FormattingConfig{Locale: "en", Precision: 2, Abbreviate: true}
+
+var (
+	mu     sync.RWMutex
+	config = defaultConfig
+)
+
+// Install sets the formatting used by the package, and by the coins of
+// decoded proposal messages
+func Install(cfg types.FormattingConfig) {
+	mu.Lock()
+	config = cfg
+	mu.Unlock()
+	cosmosgov.SetCoinFormatter(Coin)
+}
+
+// current returns the installed formatting
+func current() types.FormattingConfig {
+	mu.RLock()
+	defer mu.RUnlock()
+	return config
+}
+
+// Coin formats an amount of a base denom in its display unit, such as
+// 1234567uatom as 1.23 ATOM. Amounts that are not integers are returned as
+// the SDK prints them
+func Coin(amount, denom string) string {
+	cfg := current()
+	value, ok := new(big.Float).SetString(amount)
+	if !ok {
+		return amount + denom
+	}
+	display, exponent := unit(cfg, denom)
+	if exponent > 0 {
+		value.Quo(value, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)))
+	}
+	v, _ := value.Float64()
+	return format(cfg, v) + " " + display
+}
+
+// Coins formats coins in their display units, separated by commas
+func Coins(coins []types.Coin) string {
+	parts := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		parts = append(parts, Coin(coin.Amount, coin.Denom))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Amount formats an amount of denom, such as voting power in the staking
+// denom of a network. Without a denom the amount is formatted as a number
+// in base units
+func Amount(amount, denom string) string {
+	if denom != "" {
+		return Coin(amount, denom)
+	}
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return amount
+	}
+	return format(current(), value)
+}
+
+// unit returns the display unit of a base denom and its exponent
+func unit(cfg types.FormattingConfig, denom string) (string, int) {
+	if d, ok := cfg.Denoms[denom]; ok {
+		if d.Display == "" {
+			return denom, d.Exponent
+		}
+		return d.Display, d.Exponent
+	}
+	switch {
+	case len(denom) > 1 && denom[0] == 'u' && isLetters(denom[1:]):
+		return strings.ToUpper(denom[1:]), 6
+	case len(denom) > 3 && denom[0] == 'a' && isLetters(denom[1:]):
+		return strings.ToUpper(denom[1:]), 18
+	}
+	return denom, 0
+}
+
+// isLetters reports whether s is only lowercase ASCII letters, which
+// excludes IBC and token factory denoms from unit guessing
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// format renders a number with the precision, abbreviation and separators
+// of cfg. Trailing zeros are dropped and amounts too small for the
+// precision are shown as below its smallest step
+func format(cfg types.FormattingConfig, v float64) string {
+	sep, ok := localeSeparators[cfg.Locale]
+	if !ok {
+		sep = localeSeparators[""]
+	}
+	precision := max(cfg.Precision, 0)
+
+	suffix := ""
+	if cfg.Abbreviate {
+		for _, a := range abbreviations {
+			if math.Abs(v) >= a.min {
+				v /= a.min
+				suffix = a.suffix
+				break
+			}
+		}
+	}
+
+	s := strconv.FormatFloat(math.Abs(v), 'f', precision, 64)
+	if v != 0 && strings.Trim(s, "0.") == "" {
+		smallest := "1"
+		if precision > 0 {
+			smallest = "0" + sep.decimal + strings.Repeat("0", precision-1) + "1"
+		}
+		return "<" + smallest
+	}
+
+	integer, fraction, _ := strings.Cut(s, ".")
+	fraction = strings.TrimRight(fraction, "0")
+
+	var b strings.Builder
+	if v < 0 {
+		b.WriteByte('-')
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(sep.thousands)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(sep.decimal + fraction)
+	}
+	b.WriteString(suffix)
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package amounts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// durationWords are the words of durations in a language: the day, hour
+// and minute units, singular and plural, as a duration on its own and after
+// in or ago, where German declines them
+type durationWords struct {
+	units      [3][2]string
+	afterIn    [3][2]string
+	lessMinute string
+	lessAfter  string
+	in, ago    string
+}
+
+var englishDurations = durationWords{
+	units:      [3][2]string{{"day", "days"}, {"hour", "hours"}, {"minute", "minutes"}},
+	afterIn:    [3][2]string{{"day", "days"}, {"hour", "hours"}, {"minute", "minutes"}},
+	lessMinute: "less than a minute",
+	lessAfter:  "less than a minute",
+	in:         "in %s",
+	ago:        "%s ago",
+}
+
+// localeDurations are the duration words of the locales with their own
+// language, the others use English
+var localeDurations = map[string]durationWords{
+	"de": {
+		units:      [3][2]string{{"Tag", "Tage"}, {"Stunde", "Stunden"}, {"Minute", "Minuten"}},
+		afterIn:    [3][2]string{{"Tag", "Tagen"}, {"Stunde", "Stunden"}, {"Minute", "Minuten"}},
+		lessMinute: "weniger als eine Minute",
+		lessAfter:  "weniger als einer Minute",
+		in:         "in %s",
+		ago:        "vor %s",
+	},
+	"fr": {
+		units:      [3][2]string{{"jour", "jours"}, {"heure", "heures"}, {"minute", "minutes"}},
+		afterIn:    [3][2]string{{"jour", "jours"}, {"heure", "heures"}, {"minute", "minutes"}},
+		lessMinute: "moins d'une minute",
+		lessAfter:  "moins d'une minute",
+		in:         "dans %s",
+		ago:        "il y a %s",
+	},
+}
+
+// words returns the duration words of the installed locale
+func words() durationWords {
+	if w, ok := localeDurations[current().Locale]; ok {
+		return w
+	}
+	return englishDurations
+}
+
+// Duration spells out a duration in its two largest units among days,
+// hours and minutes, such as "2 days 4 hours" or "45 minutes", in the
+// language of the locale. Negative durations are spelled as positive ones
+func Duration(d time.Duration) string {
+	w := words()
+	return spell(d, w.units, w.lessMinute)
+}
+
+// In phrases a duration from now, such as "in 2 days 4 hours"
+func In(d time.Duration) string {
+	w := words()
+	return fmt.Sprintf(w.in, spell(d, w.afterIn, w.lessAfter))
+}
+
+// Ago phrases a duration until now, such as "3 hours ago"
+func Ago(d time.Duration) string {
+	w := words()
+	return fmt.Sprintf(w.ago, spell(d, w.afterIn, w.lessAfter))
+}
+
+// spell renders the two largest non-zero units of d with units
+func spell(d time.Duration, units [3][2]string, lessMinute string) string {
+	if d < 0 {
+		d = -d
+	}
+	values := [3]int{int(d.Hours()) / 24, int(d.Hours()) % 24, int(d.Minutes()) % 60}
+
+	var parts []string
+	for i, value := range values {
+		if value == 0 {
+			// Units are only skipped before the first one shown, 2 days
+			// 30 minutes would read as more precise than it is
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+		unit := units[i][1]
+		if value == 1 {
+			unit = units[i][0]
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", value, unit))
+		if len(parts) == 2 {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return lessMinute
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,251 @@
+// Package analytics streams the timeline and tally snapshot events of the
+// service, the CloudEvents of internal/events, to an analytics database,
+// ClickHouse or BigQuery, so governance can be analyzed across chains and
+// beyond the retention of the JSON state file. Rows are buffered and
+// inserted in batches into tables the sink creates and migrates itself
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"governance-alerts-cosmos/internal/events"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tables written by the sink
+const (
+	TableEvents  = "governance_events"
+	TableTallies = "tally_snapshots"
+)
+
+// maxPendingBatches bounds the rows buffered per table while the database
+// is unreachable, in batches. The oldest rows are dropped beyond it
+const maxPendingBatches = 20
+
+// finalFlushTimeout bounds the insert of the last rows at shutdown
+const finalFlushTimeout = 10 * time.Second
+
+// envelope holds the CloudEvents attributes every row starts with
+type envelope struct {
+	ID      string    `json:"id"`
+	Type    string    `json:"type"`
+	Source  string    `json:"source"`
+	Subject string    `json:"subject"`
+	Time    time.Time `json:"time"`
+}
+
+// eventRow is a row of governance_events: a timeline event, something the
+// service observed or did about a proposal
+type eventRow struct {
+	envelope
+	events.TimelineData
+}
+
+// tallyRow is a row of tally_snapshots: a tally snapshot event
+type tallyRow struct {
+	envelope
+	events.TallyData
+}
+
+// row returns the row of an event and its table, false for events that
+// are not exported
+func row(event events.Event) (string, interface{}, bool) {
+	attributes := envelope{ID: event.ID, Type: event.Type, Source: event.Source, Subject: event.Subject, Time: event.Time.UTC()}
+	switch data := event.Data.(type) {
+	case events.TimelineData:
+		return TableEvents, eventRow{envelope: attributes, TimelineData: data}, true
+	case events.TallyData:
+		return TableTallies, tallyRow{envelope: attributes, TallyData: data}, true
+	}
+	return "", nil, false
+}
+
+// backend is an analytics database
+type backend interface {
+	// migrate creates the tables and brings their schema up to date
+	migrate(ctx context.Context) error
+	// insert inserts rows into a table. Rows are eventRow or tallyRow
+	insert(ctx context.Context, table string, rows []interface{}) error
+}
+
+// Sink buffers rows and inserts them into the backend in batches
+type Sink struct {
+	backend   backend
+	batchSize int
+	interval  time.Duration
+	mu        sync.Mutex
+	pending   map[string][]interface{}
+	dropped   int
+	full      chan struct{}
+}
+
+// New creates the sink of an enabled analytics configuration
+func New(cfg types.AnalyticsConfig) (*Sink, error) {
+	var b backend
+	switch cfg.Backend {
+	case types.AnalyticsClickHouse:
+		b = newClickHouse(cfg.ClickHouse)
+	case types.AnalyticsBigQuery:
+		bq, err := newBigQuery(cfg.BigQuery)
+		if err != nil {
+			return nil, err
+		}
+		b = bq
+	default:
+		return nil, fmt.Errorf("unknown analytics backend %q", cfg.Backend)
+	}
+
+	return &Sink{
+		backend:   b,
+		batchSize: cfg.BatchSize,
+		interval:  time.Duration(cfg.FlushIntervalSeconds) * time.Second,
+		pending:   make(map[string][]interface{}),
+		full:      make(chan struct{}, 1),
+	}, nil
+}
+
+// Export queues a timeline event or tally snapshot, other events are not
+// exported. A nil sink discards it
+func (s *Sink) Export(event events.Event) {
+	if s == nil {
+		return
+	}
+	if table, row, ok := row(event); ok {
+		s.add(table, row)
+	}
+}
+
+// rowID returns the event ID of a row
+func rowID(row interface{}) string {
+	switch row := row.(type) {
+	case eventRow:
+		return row.ID
+	case tallyRow:
+		return row.ID
+	}
+	return ""
+}
+
+// add queues a row of table, waking the sink once a batch is full
+func (s *Sink) add(table string, row interface{}) {
+
+	s.mu.Lock()
+	rows := append(s.pending[table], row)
+	if limit := s.batchSize * maxPendingBatches; len(rows) > limit {
+		s.dropped += len(rows) - limit
+		rows = rows[len(rows)-limit:]
+	}
+	s.pending[table] = rows
+	ready := len(rows) >= s.batchSize
+	s.mu.Unlock()
+
+	if ready {
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run migrates the schema, retrying until the database is reachable, and
+// then inserts the queued rows every flush interval or once a batch is
+// full, until ctx is done. The rows left are inserted before returning
+func (s *Sink) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	migrated := false
+	for {
+		if !migrated {
+			if err := s.backend.migrate(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				logrus.WithError(err).WithField("event", "analytics").Warn("Failed to migrate the analytics schema, retrying")
+			} else {
+				migrated = true
+				logrus.WithField("event", "analytics").Info("Analytics schema up to date")
+			}
+		}
+		if migrated {
+			s.flush(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			if migrated {
+				flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+				s.flush(flushCtx)
+				cancel()
+			}
+			return
+		case <-ticker.C:
+		case <-s.full:
+		}
+	}
+}
+
+// Flush migrates the schema and inserts the queued rows once, for runs
+// that exit after a single check instead of running the sink. A nil sink
+// has nothing to flush
+func (s *Sink) Flush(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.backend.migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate the analytics schema: %w", err)
+	}
+	s.flush(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for table, rows := range s.pending {
+		if len(rows) > 0 {
+			return fmt.Errorf("%d rows of %s could not be inserted", len(rows), table)
+		}
+	}
+	return nil
+}
+
+// flush inserts the queued rows of every table in batches. Rows of a
+// failed insert stay queued for the next flush
+func (s *Sink) flush(ctx context.Context) {
+	s.mu.Lock()
+	if s.dropped > 0 {
+		logrus.WithField("event", "analytics").Warnf("Dropped %d analytics rows queued while the database was unreachable", s.dropped)
+		s.dropped = 0
+	}
+	s.mu.Unlock()
+
+	for _, table := range []string{TableEvents, TableTallies} {
+		for {
+			s.mu.Lock()
+			rows := s.pending[table]
+			if len(rows) > s.batchSize {
+				rows = rows[:s.batchSize]
+			}
+			s.mu.Unlock()
+			if len(rows) == 0 {
+				break
+			}
+
+			if err := s.backend.insert(ctx, table, rows); err != nil {
+				logrus.WithError(err).WithFields(logrus.Fields{"table": table, "rows": len(rows), "event": "analytics"}).Warn("Failed to insert analytics rows")
+				break
+			}
+			logrus.WithFields(logrus.Fields{"table": table, "rows": len(rows), "event": "analytics"}).Debug("Inserted analytics rows")
+
+			// Rows queued meanwhile were appended, the inserted ones are
+			// still first unless dropped for room
+			s.mu.Lock()
+			inserted := min(len(rows), len(s.pending[table]))
+			s.pending[table] = s.pending[table][inserted:]
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// bigQueryAPIURL is the base URL of the BigQuery REST API
+const bigQueryAPIURL = "https://bigquery.googleapis.com/bigquery/v2"
+
+// bigQueryScope is the OAuth scope of the access tokens, which must both
+// manage tables and insert rows
+const bigQueryScope = "https://www.googleapis.com/auth/bigquery"
+
+// defaultTokenURI is where service account keys are exchanged for access
+// tokens when the key does not say
+const defaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// bigQueryTimeout bounds each request to BigQuery
+const bigQueryTimeout = 30 * time.Second
+
+// bigQueryField is a column of a BigQuery table schema
+type bigQueryField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Mode string `json:"mode,omitempty"`
+}
+
+// bigQuerySchemas are the schemas of the BigQuery tables. Columns added
+// later must be NULLABLE, they are added to existing tables at startup
+var bigQuerySchemas = map[string][]bigQueryField{
+	TableEvents: append(envelopeFields(),
+		bigQueryField{Name: "chain_id", Type: "STRING", Mode: "REQUIRED"},
+		bigQueryField{Name: "network", Type: "STRING"},
+		bigQueryField{Name: "proposal_id", Type: "INT64", Mode: "REQUIRED"},
+		bigQueryField{Name: "kind", Type: "STRING", Mode: "REQUIRED"},
+		bigQueryField{Name: "detail", Type: "STRING"},
+	),
+	TableTallies: append(envelopeFields(),
+		bigQueryField{Name: "chain_id", Type: "STRING", Mode: "REQUIRED"},
+		bigQueryField{Name: "network", Type: "STRING"},
+		bigQueryField{Name: "proposal_id", Type: "INT64", Mode: "REQUIRED"},
+		bigQueryField{Name: "yes", Type: "BIGNUMERIC"},
+		bigQueryField{Name: "no", Type: "BIGNUMERIC"},
+		bigQueryField{Name: "abstain", Type: "BIGNUMERIC"},
+		bigQueryField{Name: "no_with_veto", Type: "BIGNUMERIC"},
+		bigQueryField{Name: "denom", Type: "STRING"},
+	),
+}
+
+// envelopeFields returns the columns of the CloudEvents attributes every
+// table starts with
+func envelopeFields() []bigQueryField {
+	return []bigQueryField{
+		{Name: "id", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "type", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "source", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "subject", Type: "STRING"},
+		{Name: "time", Type: "TIMESTAMP", Mode: "REQUIRED"},
+	}
+}
+
+// serviceAccountKey is the part of a service account JSON key used to
+// authenticate
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// bigQuery writes to a BigQuery dataset through the REST API
+type bigQuery struct {
+	config types.BigQueryConfig
+	email  string
+	key    *rsa.PrivateKey
+	tokens string
+	client *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newBigQuery(cfg types.BigQueryConfig) (*bigQuery, error) {
+	data, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bigquery credentials: %w", err)
+	}
+	var account serviceAccountKey
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("invalid bigquery credentials: %w", err)
+	}
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil || account.ClientEmail == "" {
+		return nil, fmt.Errorf("invalid bigquery credentials: not a service account key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bigquery credentials: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid bigquery credentials: not an RSA key")
+	}
+	tokenURI := account.TokenURI
+	if tokenURI == "" {
+		tokenURI = defaultTokenURI
+	}
+
+	return &bigQuery{
+		config: cfg,
+		email:  account.ClientEmail,
+		key:    key,
+		tokens: tokenURI,
+		client: &http.Client{Timeout: bigQueryTimeout},
+	}, nil
+}
+
+// errTableNotFound is returned by api for tables that do not exist
+var errTableNotFound = errors.New("table not found")
+
+// migrate creates the missing tables, partitioned by day, and adds the
+// columns missing from existing ones
+func (b *bigQuery) migrate(ctx context.Context) error {
+	for _, table := range []string{TableEvents, TableTallies} {
+		schema := bigQuerySchemas[table]
+
+		var existing struct {
+			Schema struct {
+				Fields []bigQueryField `json:"fields"`
+			} `json:"schema"`
+		}
+		err := b.api(ctx, http.MethodGet, b.tablePath(table), nil, &existing)
+		if errors.Is(err, errTableNotFound) {
+			create := map[string]interface{}{
+				"tableReference":   map[string]string{"projectId": b.config.Project, "datasetId": b.config.Dataset, "tableId": table},
+				"schema":           map[string]interface{}{"fields": schema},
+				"timePartitioning": map[string]string{"type": "DAY", "field": "time"},
+			}
+			if err := b.api(ctx, http.MethodPost, b.datasetPath()+"/tables", create, nil); err != nil {
+				return fmt.Errorf("failed to create %s: %w", table, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", table, err)
+		}
+
+		fields := existing.Schema.Fields
+		known := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			known[field.Name] = true
+		}
+		added := false
+		for _, field := range schema {
+			if !known[field.Name] {
+				field.Mode = "NULLABLE"
+				fields = append(fields, field)
+				added = true
+			}
+		}
+		if added {
+			patch := map[string]interface{}{"schema": map[string]interface{}{"fields": fields}}
+			if err := b.api(ctx, http.MethodPatch, b.tablePath(table), patch, nil); err != nil {
+				return fmt.Errorf("failed to add columns to %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// insert streams rows with tabledata.insertAll. The insert ID of each row
+// is its event ID, so BigQuery drops the copies of a batch retried after a
+// timeout
+func (b *bigQuery) insert(ctx context.Context, table string, rows []interface{}) error {
+	entries := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return fmt.Errorf("failed to encode row: %w", err)
+		}
+		entries = append(entries, map[string]interface{}{"insertId": rowID(row), "json": json.RawMessage(data)})
+	}
+
+	var response struct {
+		InsertErrors []struct {
+			Index  int `json:"index"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"insertErrors"`
+	}
+	if err := b.api(ctx, http.MethodPost, b.tablePath(table)+"/insertAll", map[string]interface{}{"rows": entries}, &response); err != nil {
+		return err
+	}
+	if len(response.InsertErrors) > 0 {
+		first := response.InsertErrors[0]
+		message := "unknown error"
+		if len(first.Errors) > 0 {
+			message = first.Errors[0].Message
+		}
+		return fmt.Errorf("%d rows rejected, row %d: %s", len(response.InsertErrors), first.Index, message)
+	}
+	return nil
+}
+
+// datasetPath returns the API path of the dataset
+func (b *bigQuery) datasetPath() string {
+	return fmt.Sprintf("/projects/%s/datasets/%s", url.PathEscape(b.config.Project), url.PathEscape(b.config.Dataset))
+}
+
+// tablePath returns the API path of a table of the dataset
+func (b *bigQuery) tablePath(table string) string {
+	return b.datasetPath() + "/tables/" + table
+}
+
+// api calls the BigQuery API, decoding the response into v when set
+func (b *bigQuery) api(ctx context.Context, method, path string, payload interface{}, v interface{}) error {
+	token, err := b.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader = http.NoBody
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, bigQueryAPIURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return errTableNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("bigquery returned status %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("bigquery returned status %d", resp.StatusCode)
+	}
+
+	if v != nil {
+		return json.Unmarshal(raw, v)
+	}
+	return nil
+}
+
+// accessToken returns an OAuth access token of the service account,
+// exchanging a signed JWT for a new one shortly before the last expires
+func (b *bigQuery) accessToken(ctx context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.token != "" && now.Before(b.expires.Add(-time.Minute)) {
+		return b.token, nil
+	}
+
+	assertion, err := b.signJWT(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.tokens, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("access token request returned status %d", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid access token response: %w", err)
+	}
+
+	b.token = token.AccessToken
+	b.expires = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return b.token, nil
+}
+
+// signJWT returns the RS256 JWT asserting the service account for an hour
+func (b *bigQuery) signJWT(now time.Time) (string, error) {
+	encode := func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	}
+	header, err := encode(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := encode(map[string]interface{}{
+		"iss":   b.email,
+		"scope": bigQueryScope,
+		"aud":   b.tokens,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + claims
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, b.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token request: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
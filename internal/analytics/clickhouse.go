@@ -0,0 +1,144 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// clickhouseTimeout bounds each request to ClickHouse
+const clickhouseTimeout = 30 * time.Second
+
+// clickhouseMigrations are the schema changes of the ClickHouse tables,
+// applied in order once each and recorded in gac_schema_migrations. New
+// changes are appended, released ones never edited. {db} is the database
+var clickhouseMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS {db}.governance_events (
+		id String,
+		type LowCardinality(String),
+		source LowCardinality(String),
+		subject String,
+		time DateTime64(3, 'UTC'),
+		chain_id LowCardinality(String),
+		network LowCardinality(String),
+		proposal_id UInt64,
+		kind LowCardinality(String),
+		detail String
+	) ENGINE = MergeTree PARTITION BY toYYYYMM(time) ORDER BY (chain_id, proposal_id, time)`,
+	`CREATE TABLE IF NOT EXISTS {db}.tally_snapshots (
+		id String,
+		type LowCardinality(String),
+		source LowCardinality(String),
+		subject String,
+		time DateTime64(3, 'UTC'),
+		chain_id LowCardinality(String),
+		network LowCardinality(String),
+		proposal_id UInt64,
+		yes String,
+		no String,
+		abstain String,
+		no_with_veto String,
+		denom LowCardinality(String)
+	) ENGINE = MergeTree PARTITION BY toYYYYMM(time) ORDER BY (chain_id, proposal_id, time)`,
+}
+
+// clickhouse writes to ClickHouse over its HTTP interface
+type clickhouse struct {
+	config types.ClickHouseConfig
+	client *http.Client
+}
+
+func newClickHouse(cfg types.ClickHouseConfig) *clickhouse {
+	return &clickhouse{config: cfg, client: &http.Client{Timeout: clickhouseTimeout}}
+}
+
+// migrate creates the database and applies the migrations not recorded
+// as applied yet
+func (c *clickhouse) migrate(ctx context.Context) error {
+	db := c.config.Database
+	if _, err := c.exec(ctx, "CREATE DATABASE IF NOT EXISTS "+db, nil); err != nil {
+		return err
+	}
+	if _, err := c.exec(ctx, "CREATE TABLE IF NOT EXISTS "+db+".gac_schema_migrations (version UInt32, applied_at DateTime('UTC')) ENGINE = MergeTree ORDER BY version", nil); err != nil {
+		return err
+	}
+	out, err := c.exec(ctx, "SELECT max(version) FROM "+db+".gac_schema_migrations FORMAT TabSeparated", nil)
+	if err != nil {
+		return err
+	}
+	applied, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return fmt.Errorf("unexpected schema version %q", strings.TrimSpace(out))
+	}
+
+	for i := applied; i < len(clickhouseMigrations); i++ {
+		version := i + 1
+		if _, err := c.exec(ctx, strings.ReplaceAll(clickhouseMigrations[i], "{db}", db), nil); err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+		record := fmt.Sprintf("INSERT INTO %s.gac_schema_migrations VALUES (%d, now())", db, version)
+		if _, err := c.exec(ctx, record, nil); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// insert inserts rows as JSONEachRow
+func (c *clickhouse) insert(ctx context.Context, table string, rows []interface{}) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode row: %w", err)
+		}
+	}
+	query := fmt.Sprintf("INSERT INTO %s.%s FORMAT JSONEachRow", c.config.Database, table)
+	_, err := c.exec(ctx, query, &body)
+	return err
+}
+
+// exec runs a query, with data as the input of INSERT queries, and returns
+// its output
+func (c *clickhouse) exec(ctx context.Context, query string, data io.Reader) (string, error) {
+	params := url.Values{
+		"query": {query},
+		// Times are sent as RFC 3339
+		"date_time_input_format": {"best_effort"},
+	}
+	if data == nil {
+		data = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.config.URL, "/")+"/?"+params.Encode(), data)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-ClickHouse-User", c.config.Username)
+	if c.config.Password != "" {
+		req.Header.Set("X-ClickHouse-Key", c.config.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("clickhouse returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,329 @@
+// Package api serves the HTTP API operators use to control a running
+// service
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Server is the HTTP API of a running service
+type Server struct {
+	config  types.APIConfig
+	service *service.Service
+	server  *http.Server
+}
+
+// NewServer creates the API server of svc
+func NewServer(config types.APIConfig, svc *service.Service) *Server {
+	s := &Server{config: config, service: svc}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/status", s.authorize(http.HandlerFunc(s.handleStatus)))
+	mux.Handle("POST /api/v1/networks/{network}/check", s.authorize(http.HandlerFunc(s.handleCheck)))
+	mux.Handle("POST /api/v1/networks/{network}/proposals/{id}/recheck", s.authorize(http.HandlerFunc(s.handleRecheck)))
+	mux.Handle("POST /api/v1/networks/{network}/proposals/{id}/ack", s.authorize(http.HandlerFunc(s.handleAck)))
+	mux.Handle("GET /api/v1/networks/{network}/proposals/{id}/timeline", s.authorize(http.HandlerFunc(s.handleTimeline)))
+	mux.Handle("GET /api/v1/networks/{network}/proposals/{id}/links", s.authorize(http.HandlerFunc(s.handleLinks)))
+	mux.Handle("POST /api/v1/networks/{network}/proposals/{id}/links", s.authorize(http.HandlerFunc(s.handleLink)))
+	mux.Handle("DELETE /api/v1/networks/{network}/proposals/{id}/links", s.authorize(http.HandlerFunc(s.handleUnlink)))
+	mux.Handle("GET /api/v1/flags", s.authorize(http.HandlerFunc(s.handleFlags)))
+	mux.Handle("POST /api/v1/flags/tally-polling", s.authorize(http.HandlerFunc(s.handleTallyPolling)))
+	mux.Handle("POST /api/v1/channels/{channel}/pause", s.authorize(http.HandlerFunc(s.handlePause(true))))
+	mux.Handle("POST /api/v1/channels/{channel}/resume", s.authorize(http.HandlerFunc(s.handlePause(false))))
+	mux.Handle("POST /api/v1/networks/{network}/dry-run", s.authorize(http.HandlerFunc(s.handleDryRun)))
+	// Slack signs its requests instead of sending the API token
+	mux.Handle("POST /api/v1/slack/events", svc.SlackEvents())
+	mux.Handle("POST /api/v1/slack/commands", svc.SlackCommands())
+	// Probes carry no token
+	s.registerHealth(mux)
+
+	s.server = &http.Server{
+		Addr:              config.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// ListenAndServe serves the API until Shutdown is called
+func (s *Server) ListenAndServe() error {
+	if err := s.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops the API server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// authorize rejects requests without the configured bearer token
+func (s *Server) authorize(next http.Handler) http.Handler {
+	if s.config.Token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + s.config.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleCheck runs the check of a network right away
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	result, err := s.service.CheckNetwork(r.Context(), r.PathValue("network"))
+	if errors.Is(err, service.ErrUnknownNetwork) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleRecheck re-evaluates a proposal, resetting its alerts when the
+// reset query parameter is true
+func (s *Server) handleRecheck(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal ID %q", r.PathValue("id")))
+		return
+	}
+	reset, _ := strconv.ParseBool(r.URL.Query().Get("reset"))
+
+	result, err := s.service.Recheck(r.Context(), r.PathValue("network"), proposalID, reset)
+	if errors.Is(err, service.ErrUnknownNetwork) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleStatus returns the tracked proposals, channel health and recent
+// alerts of the service
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.service.Status())
+}
+
+// handleAck acknowledges the reminders of a proposal, or snoozes them for
+// the snooze_hours query parameter. The user parameter names who did it
+func (s *Server) handleAck(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal ID %q", r.PathValue("id")))
+		return
+	}
+
+	var snooze time.Duration
+	if hours := r.URL.Query().Get("snooze_hours"); hours != "" {
+		value, err := strconv.Atoi(hours)
+		if err != nil || value <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid snooze_hours %q", hours))
+			return
+		}
+		snooze = time.Duration(value) * time.Hour
+	}
+	user := r.URL.Query().Get("user")
+	if user == "" {
+		user = "api"
+	}
+
+	message, err := s.service.Acknowledge(r.PathValue("network"), proposalID, user, snooze)
+	if errors.Is(err, service.ErrUnknownNetwork) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// handleTimeline returns what the service observed and did about a
+// proposal, oldest first
+func (s *Server) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal ID %q", r.PathValue("id")))
+		return
+	}
+
+	timeline, err := s.service.Timeline(r.PathValue("network"), proposalID)
+	if errors.Is(err, service.ErrUnknownNetwork) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"events": timeline})
+}
+
+// handleLinks returns the links of a proposal to the artifacts tracking it
+func (s *Server) handleLinks(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal ID %q", r.PathValue("id")))
+		return
+	}
+
+	links, err := s.service.Links(r.PathValue("network"), proposalID)
+	if errors.Is(err, service.ErrUnknownNetwork) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"links": links})
+}
+
+// handleLink links a proposal to the artifact of the system and id query
+// parameters, with an optional url. The user parameter names who did it
+func (s *Server) handleLink(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal ID %q", r.PathValue("id")))
+		return
+	}
+	query := r.URL.Query()
+	user := query.Get("user")
+	if user == "" {
+		user = "api"
+	}
+
+	link := types.TrackingLink{System: query.Get("system"), ID: query.Get("id"), URL: query.Get("url"), Author: user}
+	links, err := s.service.LinkProposal(r.PathValue("network"), proposalID, link)
+	if errors.Is(err, service.ErrUnknownNetwork) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidLink) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"links": links})
+}
+
+// handleUnlink removes the link of a proposal to the artifact of the
+// system and id query parameters
+func (s *Server) handleUnlink(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal ID %q", r.PathValue("id")))
+		return
+	}
+
+	links, err := s.service.UnlinkProposal(r.PathValue("network"), proposalID, r.URL.Query().Get("system"), r.URL.Query().Get("id"))
+	if errors.Is(err, service.ErrUnknownNetwork) || errors.Is(err, service.ErrUnknownLink) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"links": links})
+}
+
+// handleFlags returns the runtime flags
+func (s *Server) handleFlags(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.service.Flags())
+}
+
+// handleTallyPolling enables or disables tally polling with the enabled
+// query parameter
+func (s *Server) handleTallyPolling(w http.ResponseWriter, r *http.Request) {
+	enabled, err := strconv.ParseBool(r.URL.Query().Get("enabled"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid enabled %q", r.URL.Query().Get("enabled")))
+		return
+	}
+
+	flags, err := s.service.SetTallyPolling(enabled)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, flags)
+}
+
+// handlePause pauses or resumes delivery to a channel
+func (s *Server) handlePause(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flags, err := s.service.PauseChannel(r.PathValue("channel"), paused)
+		if errors.Is(err, service.ErrUnknownChannel) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, flags)
+	}
+}
+
+// handleDryRun switches the proposal alerts of a network to dry-run, or
+// back when the enabled query parameter is false
+func (s *Server) handleDryRun(w http.ResponseWriter, r *http.Request) {
+	enabled := true
+	if value := r.URL.Query().Get("enabled"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid enabled %q", value))
+			return
+		}
+		enabled = parsed
+	}
+
+	flags, err := s.service.SetDryRun(r.PathValue("network"), enabled)
+	if errors.Is(err, service.ErrUnknownNetwork) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, flags)
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
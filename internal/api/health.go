@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"governance-alerts-cosmos/internal/service"
+)
+
+// NewHealthServer creates a server with only the health endpoints, without
+// authentication, for orchestration probes
+func NewHealthServer(listen string, svc *service.Service) *Server {
+	s := &Server{service: svc}
+
+	mux := http.NewServeMux()
+	s.registerHealth(mux)
+
+	s.server = &http.Server{
+		Addr:              listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// registerHealth adds the health endpoints to mux
+func (s *Server) registerHealth(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+}
+
+// handleHealthz answers liveness probes, failing when the monitoring loop
+// stopped completing check cycles so the service gets restarted
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	health := s.service.Health()
+	status := http.StatusOK
+	if !health.Live {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, health)
+}
+
+// handleReadyz answers readiness probes, failing until the first check
+// cycle is done and while no network is polled or no channel is reachable
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	health := s.service.Health()
+	status := http.StatusOK
+	if !health.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, health)
+}
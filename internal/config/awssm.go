@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// awsSecret returns a secret of AWS Secrets Manager referenced as
+// <secret id>#<key>, the key of a JSON secret, or as <secret id> alone for
+// the whole secret string. The secret ID is its name or ARN
+func (r *secretResolver) awsSecret(ref string) (string, error) {
+	id, key, _ := strings.Cut(ref, "#")
+	if id == "" {
+		return "", fmt.Errorf("invalid reference %q, expected aws-sm:<secret id>#<key>", awsPrefix+ref)
+	}
+
+	secret, cached := r.aws[id]
+	if !cached {
+		var err error
+		if secret, err = r.fetchAWS(id); err != nil {
+			return "", err
+		}
+		r.aws[id] = secret
+	}
+	if key == "" {
+		return secret, nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &object); err != nil {
+		return "", fmt.Errorf("%s is not a JSON secret, reference it without #%s", id, key)
+	}
+	return secretKey(object, key)
+}
+
+// fetchAWS calls GetSecretValue with the credentials of the environment
+func (r *secretResolver) fetchAWS(id string) (string, error) {
+	region := r.config.AWS.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return "", fmt.Errorf("secrets.aws.region and AWS_REGION are not set")
+	}
+	credentials := awsCredentials{
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if credentials.accessKey == "" || credentials.secretKey == "" {
+		return "", fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set")
+	}
+	endpoint := r.config.AWS.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWS(req, body, region, "secretsmanager", credentials, time.Now())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &failure)
+		return "", fmt.Errorf("%s: status %d %s %s", id, resp.StatusCode, failure.Type, failure.Message)
+	}
+
+	var value struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", fmt.Errorf("%s: invalid response: %w", id, err)
+	}
+	if value.SecretString == nil {
+		return "", fmt.Errorf("%s is a binary secret, only string secrets are supported", id)
+	}
+	return *value.SecretString, nil
+}
+
+// awsCredentials are the credentials requests are signed with
+type awsCredentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// signAWS signs a request with AWS Signature Version 4
+func signAWS(req *http.Request, body []byte, region, service string, credentials awsCredentials, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.sessionToken)
+	}
+
+	// Every header set so far is signed, with the host
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query string of a signed request, sorted by
+// key
+func canonicalQuery(query url.Values) string {
+	// Encode sorts by key; spaces must be %20 rather than +
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// hashHex returns the hex SHA-256 of data
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -2,25 +2,56 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
 
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/incidents"
+	"governance-alerts-cosmos/internal/privacy"
 	"governance-alerts-cosmos/internal/types"
 
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-// LoadConfig loads configuration from file and environment variables
+// LoadConfig loads configuration from file and environment variables.
+// GAC_ variables override the keys of the file, and configure the service
+// alone when the file is missing
 func LoadConfig(configPath string) (*types.Config, error) {
 	// Set default config file if not provided
 	if configPath == "" {
 		configPath = "config/config.yaml"
 	}
 
+	env, err := envConfig(os.Environ())
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration environment variables:\n%w", err)
+	}
+	envOnly := EnvOnly(configPath)
+
 	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	if _, err := os.Stat(configPath); os.IsNotExist(err) && !envOnly {
 		return nil, fmt.Errorf("config file not found: %s", configPath)
 	}
 
+	if !envOnly {
+		// Validate file against the config schema, viper ignores unknown keys
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := validateSchema(data); err != nil {
+			return nil, fmt.Errorf("config schema validation failed in %s:\n%w", configPath, err)
+		}
+	}
+
 	// Set config file
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("yaml")
@@ -28,10 +59,21 @@ func LoadConfig(configPath string) (*types.Config, error) {
 	// Read environment variables
 	viper.AutomaticEnv()
 
+	// Set defaults
+	setDefaults(viper.GetViper())
+
 	// Read config file
-	if err := viper.ReadInConfig(); err != nil {
+	if envOnly {
+		// Forget the keys of a file read before
+		if err := viper.ReadConfig(strings.NewReader("")); err != nil {
+			return nil, fmt.Errorf("failed to reset configuration: %w", err)
+		}
+	} else if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
+	if err := viper.MergeConfigMap(env); err != nil {
+		return nil, fmt.Errorf("failed to apply configuration environment variables: %w", err)
+	}
 
 	// Create config struct
 	var config types.Config
@@ -41,6 +83,30 @@ func LoadConfig(configPath string) (*types.Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// The chain registry and secret managers are only reached through the
+	// privacy guard: at startup it is installed from the configuration as
+	// read, on reload the running one stays in force
+	if !privacy.Active() {
+		privacy.Install(&config)
+	}
+
+	if err := resolveRegistryNetworks(&config); err != nil {
+		return nil, err
+	}
+
+	// The first of rest_endpoints is the primary when rest_endpoint is unset
+	for name, network := range config.Networks {
+		if network.RestEndpoint == "" && len(network.RestEndpoints) > 0 {
+			network.RestEndpoint = network.RestEndpoints[0]
+			network.RestEndpoints = network.RestEndpoints[1:]
+			config.Networks[name] = network
+		}
+	}
+
+	if err := resolveSecrets(&config); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	// Validate config
 	if err := validateConfig(&config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -49,6 +115,60 @@ func LoadConfig(configPath string) (*types.Config, error) {
 	return &config, nil
 }
 
+// Defaults of the performance settings, also applied by the service to
+// configurations built in code that leave them unset
+const (
+	DefaultMaxConcurrentNetworks = 10
+	DefaultNetworkTimeoutSeconds = 120
+)
+
+// setDefaults sets the defaults of the settings that are not required
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("performance.max_concurrent_networks", DefaultMaxConcurrentNetworks)
+	v.SetDefault("performance.network_timeout_seconds", DefaultNetworkTimeoutSeconds)
+	v.SetDefault("alerts.bonded_change_threshold_percent", 5)
+	v.SetDefault("alerts.stale_grace_minutes", 30)
+	v.SetDefault("alerts.check_on_reload", true)
+	v.SetDefault("alerts.whale_votes.max_pages", 5)
+	v.SetDefault("notifications.rate_limit.retries", 3)
+	v.SetDefault("notifications.rate_limit.max_wait_seconds", 60)
+	v.SetDefault("notifications.reactions.ack", "eyes")
+	v.SetDefault("notifications.reactions.snooze", "zzz")
+	v.SetDefault("notifications.reactions.snooze_hours", 6)
+	v.SetDefault("reports.participation_proposals", 10)
+	v.SetDefault("formatting.locale", "en")
+	v.SetDefault("formatting.precision", 2)
+	v.SetDefault("formatting.abbreviate", true)
+	v.SetDefault("registry.url", DefaultRegistryURL)
+	v.SetDefault("analytics.batch_size", 500)
+	v.SetDefault("analytics.flush_interval_seconds", 30)
+	v.SetDefault("analytics.clickhouse.database", "governance")
+	v.SetDefault("analytics.clickhouse.username", "default")
+	v.SetDefault("formatting.sort_by", []string{types.SortDeadline, types.SortSeverity})
+}
+
+// Default returns a configuration with only the defaults set, for commands
+// that build their configuration from flags instead of a file. It has no
+// networks and must be completed and passed to Validate
+func Default() (*types.Config, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	var config types.Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal defaults: %w", err)
+	}
+	return &config, nil
+}
+
+// Validate validates a configuration built in code
+func Validate(config *types.Config) error {
+	if err := validateConfig(config); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	return nil
+}
+
 // validateConfig validates the configuration
 func validateConfig(config *types.Config) error {
 	// Validate alert settings
@@ -58,26 +178,658 @@ func validateConfig(config *types.Config) error {
 	if config.Alerts.HoursBeforeEnd <= 0 {
 		return fmt.Errorf("hours_before_end must be greater than 0")
 	}
+	for _, hours := range config.Alerts.ReminderHoursBeforeEnd {
+		if hours <= 0 {
+			return fmt.Errorf("reminder_hours_before_end must be greater than 0, got %d", hours)
+		}
+	}
+	if config.Alerts.HoursBeforeDepositEnd < 0 {
+		return fmt.Errorf("hours_before_deposit_end must not be negative")
+	}
 	if config.Alerts.CheckIntervalMinutes <= 0 {
 		return fmt.Errorf("check_interval_minutes must be greater than 0")
 	}
 
+	if config.Alerts.BondedChangeThresholdPercent < 0 {
+		return fmt.Errorf("bonded_change_threshold_percent must not be negative")
+	}
+	if config.Alerts.StaleGraceMinutes < 0 {
+		return fmt.Errorf("stale_grace_minutes must not be negative")
+	}
+	if config.Alerts.LatencySLOMinutes < 0 {
+		return fmt.Errorf("latency_slo_minutes must not be negative")
+	}
+	if config.Logging.Level != "" {
+		if _, err := logrus.ParseLevel(config.Logging.Level); err != nil {
+			return fmt.Errorf("invalid logging level %q", config.Logging.Level)
+		}
+	}
+	switch config.Logging.Format {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("logging format must be text or json, got %q", config.Logging.Format)
+	}
+	if config.Alerts.SpamDepositorThreshold < 0 {
+		return fmt.Errorf("spam_depositor_threshold must not be negative")
+	}
+	if config.Alerts.CommunityMinDepositPercent < 0 || config.Alerts.CommunityMinDepositPercent > 100 {
+		return fmt.Errorf("community_min_deposit_percent must be between 0 and 100")
+	}
+	if err := validateQuietHours(config.Alerts.QuietHours); err != nil {
+		return err
+	}
+	if config.Health.StaleMinutes < 0 {
+		return fmt.Errorf("health stale_minutes must not be negative")
+	}
+	if err := validateHeartbeatURL(config.Health.HeartbeatURL); err != nil {
+		return fmt.Errorf("invalid health heartbeat_url: %w", err)
+	}
+	if err := validateFormatting(config.Formatting); err != nil {
+		return fmt.Errorf("invalid formatting: %w", err)
+	}
+	if err := validateAnalytics(config.Analytics); err != nil {
+		return fmt.Errorf("invalid analytics: %w", err)
+	}
+	for _, host := range config.Privacy.AllowedHosts {
+		if err := validateAllowedHost(host); err != nil {
+			return fmt.Errorf("invalid privacy allowed_hosts: %w", err)
+		}
+	}
+	for _, percent := range config.Alerts.TallyUpdates.RemainingPercent {
+		if percent <= 0 || percent >= 100 {
+			return fmt.Errorf("tally_updates remaining_percent must be between 1 and 99, got %d", percent)
+		}
+	}
+	if config.Alerts.TallyUpdates.IntervalHours < 0 {
+		return fmt.Errorf("tally_updates interval_hours must not be negative")
+	}
+	if whales := config.Alerts.WhaleVotes; whales.MinPowerPercent < 0 || whales.MinPowerPercent >= 100 {
+		return fmt.Errorf("whale_votes min_power_percent must be between 0 and 100, got %g", whales.MinPowerPercent)
+	}
+	if config.Alerts.WhaleVotes.MaxPages < 0 {
+		return fmt.Errorf("whale_votes max_pages must not be negative")
+	}
+	if config.Performance.MaxConcurrentNetworks <= 0 {
+		return fmt.Errorf("max_concurrent_networks must be greater than 0")
+	}
+	if config.Performance.NetworkTimeoutSeconds <= 0 {
+		return fmt.Errorf("network_timeout_seconds must be greater than 0")
+	}
+	if pool := config.Performance.HTTP; pool.MaxIdleConns < 0 || pool.MaxIdleConnsPerHost < 0 || pool.MaxConnsPerHost < 0 ||
+		pool.IdleConnTimeoutSeconds < 0 || pool.KeepAliveSeconds < 0 {
+		return fmt.Errorf("performance.http settings must not be negative")
+	}
+
+	for _, id := range config.Notifications.Telegram.Admins {
+		if id <= 0 {
+			return fmt.Errorf("telegram admins must be user IDs, got %d", id)
+		}
+	}
+
+	// Validate channel roles
+	if err := validateRoles(config.Notifications.Telegram.Roles); err != nil {
+		return fmt.Errorf("invalid telegram roles: %w", err)
+	}
+	if err := validateRoles(config.Notifications.Slack.Roles); err != nil {
+		return fmt.Errorf("invalid slack roles: %w", err)
+	}
+	if err := validateRoles(config.Notifications.Matrix.Roles); err != nil {
+		return fmt.Errorf("invalid matrix roles: %w", err)
+	}
+	if err := validateAlertTypes(config.Notifications.Telegram.AlertTypes); err != nil {
+		return fmt.Errorf("invalid telegram alert_types: %w", err)
+	}
+	if err := validateAlertTypes(config.Notifications.Slack.AlertTypes); err != nil {
+		return fmt.Errorf("invalid slack alert_types: %w", err)
+	}
+	if err := validateAlertTypes(config.Notifications.Matrix.AlertTypes); err != nil {
+		return fmt.Errorf("invalid matrix alert_types: %w", err)
+	}
+	if err := validateProposalTypes(config.Notifications.Telegram.ProposalTypes); err != nil {
+		return fmt.Errorf("invalid telegram proposal_types: %w", err)
+	}
+	if err := validateProposalTypes(config.Notifications.Slack.ProposalTypes); err != nil {
+		return fmt.Errorf("invalid slack proposal_types: %w", err)
+	}
+	if err := validateProposalTypes(config.Notifications.Matrix.ProposalTypes); err != nil {
+		return fmt.Errorf("invalid matrix proposal_types: %w", err)
+	}
+	for channel, severity := range map[string]string{"telegram": config.Notifications.Telegram.MinSeverity, "slack": config.Notifications.Slack.MinSeverity, "matrix": config.Notifications.Matrix.MinSeverity} {
+		if severity != "" && !slices.Contains(types.Severities, severity) {
+			return fmt.Errorf("invalid %s min_severity %q (expected one of %s)", channel, severity, strings.Join(types.Severities, ", "))
+		}
+	}
+
+	if slack := config.Notifications.Slack; slack.Enabled && slack.BotToken != "" && slack.Channel == "" {
+		return fmt.Errorf("slack channel is required with bot_token")
+	}
+	if slack, reactions := config.Notifications.Slack, config.Notifications.Reactions; slack.Enabled && slack.BotToken != "" && (reactions.Ack != "" || reactions.Snooze != "") && slack.SigningSecret == "" {
+		return fmt.Errorf("slack signing_secret is required with bot_token while reactions are enabled (set reactions.ack and reactions.snooze to \"\" to disable them)")
+	}
+	if matrix := config.Notifications.Matrix; matrix.Enabled {
+		if u, err := url.Parse(matrix.HomeserverURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("matrix homeserver_url must be an http or https URL, got %q", matrix.HomeserverURL)
+		}
+		if matrix.AccessToken == "" {
+			return fmt.Errorf("matrix access_token is required")
+		}
+		if !strings.HasPrefix(matrix.RoomID, "!") {
+			return fmt.Errorf("matrix room_id must be a room ID such as !abc123:example.org, got %q", matrix.RoomID)
+		}
+	}
+	if pagerDuty := config.Notifications.PagerDuty; pagerDuty.Enabled {
+		if pagerDuty.RoutingKey == "" {
+			return fmt.Errorf("pagerduty routing_key is required")
+		}
+		if pagerDuty.MinSeverity != "" && !slices.Contains(types.Severities, pagerDuty.MinSeverity) {
+			return fmt.Errorf("invalid pagerduty min_severity %q (expected one of %s)", pagerDuty.MinSeverity, strings.Join(types.Severities, ", "))
+		}
+		if pagerDuty.UnvotedHoursBeforeEnd < 0 {
+			return fmt.Errorf("pagerduty unvoted_hours_before_end must not be negative")
+		}
+	}
+	for i, webhook := range config.Notifications.Webhooks {
+		if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks[%d]: url must be an http or https URL, got %q", i, webhook.URL)
+		}
+		if err := validateRoles(webhook.Roles); err != nil {
+			return fmt.Errorf("webhooks[%d]: invalid roles: %w", i, err)
+		}
+		if err := validateAlertTypes(webhook.AlertTypes); err != nil {
+			return fmt.Errorf("webhooks[%d]: invalid alert_types: %w", i, err)
+		}
+		if err := validateProposalTypes(webhook.ProposalTypes); err != nil {
+			return fmt.Errorf("webhooks[%d]: invalid proposal_types: %w", i, err)
+		}
+		if webhook.MinSeverity != "" && !slices.Contains(types.Severities, webhook.MinSeverity) {
+			return fmt.Errorf("webhooks[%d]: invalid min_severity %q (expected one of %s)", i, webhook.MinSeverity, strings.Join(types.Severities, ", "))
+		}
+		switch webhook.PayloadStyle {
+		case "", types.PayloadStyleFull, types.PayloadStyleAutomation:
+		default:
+			return fmt.Errorf("webhooks[%d]: invalid payload_style %q (expected %s or %s)", i, webhook.PayloadStyle, types.PayloadStyleFull, types.PayloadStyleAutomation)
+		}
+	}
+	if err := validateRouting(config); err != nil {
+		return err
+	}
+	switch config.Notifications.URLPolicy.Mode {
+	case "", "strip", "defang":
+	default:
+		return fmt.Errorf("invalid url_policy mode %q (expected strip or defang)", config.Notifications.URLPolicy.Mode)
+	}
+	if err := validateRoles(config.Notifications.URLPolicy.Roles); err != nil {
+		return fmt.Errorf("invalid url_policy roles: %w", err)
+	}
+	if config.Notifications.Batching.MaxPerCycle < 0 {
+		return fmt.Errorf("batching.max_per_cycle must not be negative")
+	}
+	if summaryURL := config.Notifications.Batching.SummaryURL; summaryURL != "" {
+		if u, err := url.Parse(summaryURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("batching.summary_url must be an http or https URL, got %q", summaryURL)
+		}
+	}
+	rateLimit := config.Notifications.RateLimit
+	if rateLimit.MessagesPerMinute < 0 || rateLimit.Retries < 0 || rateLimit.MaxWaitSeconds < 0 {
+		return fmt.Errorf("rate_limit settings must not be negative")
+	}
+	if config.Notifications.Reactions.SnoozeHours < 0 {
+		return fmt.Errorf("reactions.snooze_hours must not be negative")
+	}
+
+	for i, policy := range config.Alerts.SeverityPolicies {
+		if !slices.Contains(types.Severities, policy.Severity) {
+			return fmt.Errorf("severity_policies[%d]: unknown severity %q (expected one of %s)", i, policy.Severity, strings.Join(types.Severities, ", "))
+		}
+		if err := validateAlertTypes(policy.AlertTypes); err != nil {
+			return fmt.Errorf("severity_policies[%d]: %w", i, err)
+		}
+	}
+
+	switch config.Alerts.FirstRun {
+	case "", "alert_all", "digest_only", "track_silently":
+	default:
+		return fmt.Errorf("alerts.first_run must be alert_all, digest_only or track_silently, got %q", config.Alerts.FirstRun)
+	}
+
+	switch config.Notifications.Retractions {
+	case "", "edit", "reply":
+	default:
+		return fmt.Errorf("notifications.retractions must be edit or reply, got %q", config.Notifications.Retractions)
+	}
+
+	// Validate reports
+	switch config.Reports.Reliability {
+	case "", "daily", "weekly":
+	default:
+		return fmt.Errorf("reports.reliability must be daily or weekly, got %q", config.Reports.Reliability)
+	}
+	switch config.Reports.Participation {
+	case "", "daily", "weekly":
+	default:
+		return fmt.Errorf("reports.participation must be daily or weekly, got %q", config.Reports.Participation)
+	}
+	if config.Reports.ParticipationProposals < 1 {
+		return fmt.Errorf("reports.participation_proposals must be at least 1")
+	}
+
 	// Validate networks
 	if len(config.Networks) == 0 {
 		return fmt.Errorf("at least one network must be configured")
 	}
 
+	// State is keyed by chain ID, two networks on the same chain would
+	// share it. Names and aliases must resolve to a single network
+	chains := make(map[string]string, len(config.Networks))
+	names := make(map[string]string, len(config.Networks))
+	for name, network := range config.Networks {
+		if other, ok := chains[network.ChainID]; ok && network.ChainID != "" {
+			return fmt.Errorf("networks %s share chain_id %s", sortedPair(name, other), network.ChainID)
+		}
+		chains[network.ChainID] = name
+		for _, alias := range append([]string{name}, network.Aliases...) {
+			if other, ok := names[alias]; ok && other != name {
+				return fmt.Errorf("name %q is used by networks %s", alias, sortedPair(name, other))
+			}
+			names[alias] = name
+		}
+	}
+
 	for name, network := range config.Networks {
 		if network.Name == "" {
 			return fmt.Errorf("network name is required for %s", name)
 		}
-		if network.RestEndpoint == "" {
-			return fmt.Errorf("rest_endpoint is required for network %s", name)
+		if err := validateTransport(network); err != nil {
+			return fmt.Errorf("invalid transport for network %s: %w", name, err)
 		}
 		if network.ChainID == "" {
 			return fmt.Errorf("chain_id is required for network %s", name)
 		}
+		if err := validateAuth(network.Auth); err != nil {
+			return fmt.Errorf("invalid auth for network %s: %w", name, err)
+		}
+		if err := validateIndexer(network.Indexer); err != nil {
+			return fmt.Errorf("invalid indexer for network %s: %w", name, err)
+		}
+		if err := validateIncidents(network.Incidents); err != nil {
+			return fmt.Errorf("invalid incidents for network %s: %w", name, err)
+		}
+		if err := validateAlertTypes(network.DisabledAlerts); err != nil {
+			return fmt.Errorf("invalid disabled_alerts for network %s: %w", name, err)
+		}
+		if err := validateProposalTypes(network.ProposalTypes); err != nil {
+			return fmt.Errorf("invalid proposal_types for network %s: %w", name, err)
+		}
+		if network.Retry.Attempts < 0 || network.Retry.BackoffMS < 0 || network.Retry.MaxBackoffSeconds < 0 {
+			return fmt.Errorf("retry settings must not be negative for network %s", name)
+		}
+		if network.VerifyChainIDEvery < -1 {
+			return fmt.Errorf("verify_chain_id_every must be -1 (disabled) or more for network %s", name)
+		}
+		if network.PageLimit < 0 {
+			return fmt.Errorf("page_limit must not be negative for network %s", name)
+		}
+		if err := validateExplorerTemplate(network.ExplorerURLTemplate); err != nil {
+			return fmt.Errorf("invalid explorer_url_template for network %s: %w", name, err)
+		}
+		for _, template := range network.ExplorerURLTemplates {
+			if template == "" {
+				return fmt.Errorf("invalid explorer_url_templates for network %s: empty template", name)
+			}
+			if err := validateExplorerTemplate(template); err != nil {
+				return fmt.Errorf("invalid explorer_url_templates for network %s: %w", name, err)
+			}
+		}
+		if err := validateHeartbeatURL(network.HeartbeatURL); err != nil {
+			return fmt.Errorf("invalid heartbeat_url for network %s: %w", name, err)
+		}
+		if err := validateVoteOptions(network.VoteOptions, network.VoteOptionLabels); err != nil {
+			return fmt.Errorf("invalid vote options for network %s: %w", name, err)
+		}
+		if network.ValidatorAddress != "" {
+			if _, err := cosmosgov.OperatorAccount(network.ValidatorAddress); err != nil {
+				return fmt.Errorf("invalid validator_address for network %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortedPair joins two network names in a stable order for error messages
+func sortedPair(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + " and " + b
+}
+
+// LookupNetwork finds a network by its configuration key, one of its
+// aliases or its chain ID, returning the key
+func LookupNetwork(config *types.Config, name string) (string, types.NetworkConfig, bool) {
+	if network, ok := config.Networks[name]; ok {
+		return name, network, true
+	}
+	for key, network := range config.Networks {
+		if network.ChainID == name || slices.Contains(network.Aliases, name) {
+			return key, network, true
+		}
+	}
+	return "", types.NetworkConfig{}, false
+}
+
+// validateAuth validates endpoint authentication settings
+func validateAuth(auth types.AuthConfig) error {
+	switch auth.Type {
+	case "":
+		return nil
+	case "basic":
+		if auth.Username == "" {
+			return fmt.Errorf("username is required for basic auth")
+		}
+	case "bearer", "query":
+		if auth.Token == "" {
+			return fmt.Errorf("token is required for %s auth", auth.Type)
+		}
+	default:
+		return fmt.Errorf("unknown auth type %q (expected basic, bearer or query)", auth.Type)
+	}
+	return nil
+}
+
+// validateExplorerTemplate validates an explorer URL template, which must
+// be an http or https URL with an {id} placeholder
+func validateExplorerTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	if !strings.Contains(template, "{id}") {
+		return fmt.Errorf("%q has no {id} placeholder", template)
+	}
+	u, err := url.Parse(strings.NewReplacer("{id}", "1", "{chain_id}", "chain").Replace(template))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", template)
+	}
+	return nil
+}
+
+// validateHeartbeatURL validates a heartbeat URL, empty when disabled
+func validateHeartbeatURL(heartbeatURL string) error {
+	if heartbeatURL == "" {
+		return nil
+	}
+	if u, err := url.Parse(heartbeatURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http:// or https:// URL")
+	}
+	return nil
+}
+
+// analyticsIdentifier matches the ClickHouse database names accepted, which
+// are interpolated in the schema statements
+var analyticsIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateAnalytics validates analytics settings
+func validateAnalytics(analytics types.AnalyticsConfig) error {
+	if !analytics.Enabled {
+		return nil
+	}
+	if analytics.BatchSize <= 0 {
+		return fmt.Errorf("batch_size must be positive")
+	}
+	if analytics.FlushIntervalSeconds <= 0 {
+		return fmt.Errorf("flush_interval_seconds must be positive")
+	}
+	switch analytics.Backend {
+	case types.AnalyticsClickHouse:
+		clickhouse := analytics.ClickHouse
+		if u, err := url.Parse(clickhouse.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("clickhouse url must be an http:// or https:// URL")
+		}
+		if !analyticsIdentifier.MatchString(clickhouse.Database) {
+			return fmt.Errorf("invalid clickhouse database %q", clickhouse.Database)
+		}
+	case types.AnalyticsBigQuery:
+		bigquery := analytics.BigQuery
+		if bigquery.Project == "" || bigquery.Dataset == "" {
+			return fmt.Errorf("bigquery project and dataset are required")
+		}
+		if bigquery.CredentialsFile == "" {
+			return fmt.Errorf("bigquery credentials_file is required")
+		}
+	default:
+		return fmt.Errorf("invalid backend %q (expected clickhouse or bigquery)", analytics.Backend)
+	}
+	return nil
+}
+
+// validateIndexer validates indexer settings
+func validateIndexer(indexer types.IndexerConfig) error {
+	switch indexer.Type {
+	case "":
+		return nil
+	case "rest":
+		if indexer.ProposalsURL == "" || indexer.TallyURL == "" {
+			return fmt.Errorf("proposals_url and tally_url are required for rest indexers")
+		}
+	case "graphql":
+		if indexer.URL == "" || indexer.ProposalsQuery == "" || indexer.TallyQuery == "" {
+			return fmt.Errorf("url, proposals_query and tally_query are required for graphql indexers")
+		}
+	default:
+		return fmt.Errorf("unknown indexer type %q (expected rest or graphql)", indexer.Type)
+	}
+	return nil
+}
+
+// validateTransport validates the endpoints of a network for its
+// transport. gRPC endpoints are http:// (plaintext) or https:// URLs
+func validateTransport(network types.NetworkConfig) error {
+	switch network.Transport {
+	case "", types.TransportREST:
+		if network.RestEndpoint == "" {
+			return fmt.Errorf("rest_endpoint or rest_endpoints is required")
+		}
+	case types.TransportGRPC:
+		if len(network.GRPCEndpoints) == 0 {
+			return fmt.Errorf("grpc_endpoints is required with transport grpc")
+		}
+		for _, endpoint := range network.GRPCEndpoints {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("gRPC endpoint must be an http:// or https:// URL such as https://grpc.example.com:443, got %q", endpoint)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown transport %q (expected rest or grpc)", network.Transport)
+	}
+	return nil
+}
+
+// validateFormatting validates how token amounts are displayed and
+// proposals sorted
+func validateFormatting(cfg types.FormattingConfig) error {
+	if cfg.Locale != "" && !slices.Contains(amounts.Locales, cfg.Locale) {
+		return fmt.Errorf("locale must be one of %s, got %q", strings.Join(amounts.Locales, ", "), cfg.Locale)
+	}
+	if cfg.Precision < 0 || cfg.Precision > 18 {
+		return fmt.Errorf("precision must be between 0 and 18")
+	}
+	for denom, d := range cfg.Denoms {
+		if d.Exponent < 0 || d.Exponent > 18 {
+			return fmt.Errorf("exponent of denom %s must be between 0 and 18", denom)
+		}
+	}
+	for _, key := range cfg.SortBy {
+		if !slices.Contains(types.SortKeys, key) {
+			return fmt.Errorf("unknown sort key %q (expected one of %s)", key, strings.Join(types.SortKeys, ", "))
+		}
+	}
+	return nil
+}
+
+// validateIncidents validates the incident detection of a network
+func validateIncidents(cfg types.IncidentsConfig) error {
+	if cfg.FeedURL != "" {
+		if u, err := url.Parse(cfg.FeedURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("feed_url must be an http(s) URL, got %q", cfg.FeedURL)
+		}
+	}
+	if cfg.Format != "" && !slices.Contains(incidents.Formats, cfg.Format) {
+		return fmt.Errorf("unknown format %q (expected %s)", cfg.Format, strings.Join(incidents.Formats, " or "))
+	}
+	if cfg.HaltMinutes < 0 {
+		return fmt.Errorf("halt_minutes must not be negative")
+	}
+	return nil
+}
+
+// validateQuietHours validates the quiet hours window
+func validateQuietHours(quiet types.QuietHoursConfig) error {
+	if quiet.Start == "" && quiet.End == "" {
+		return nil
+	}
+	if _, err := time.Parse("15:04", quiet.Start); err != nil {
+		return fmt.Errorf("quiet_hours.start must be HH:MM, got %q", quiet.Start)
+	}
+	if _, err := time.Parse("15:04", quiet.End); err != nil {
+		return fmt.Errorf("quiet_hours.end must be HH:MM, got %q", quiet.End)
+	}
+	if _, err := time.LoadLocation(quiet.Timezone); err != nil {
+		return fmt.Errorf("quiet_hours.timezone is invalid: %w", err)
+	}
+	return nil
+}
+
+// validateRouting validates the named channels and the routing rules
+// sending to them
+func validateRouting(config *types.Config) error {
+	notifications := config.Notifications
+	builtIn := []string{types.ChannelTelegram, types.ChannelSlack, types.ChannelMatrix, types.RouteWebhooks, types.RoutePagerDuty}
+	for name, channel := range notifications.Channels {
+		if slices.Contains(builtIn, name) || strings.HasPrefix(name, "webhook") {
+			return fmt.Errorf("channels.%s: name is reserved for a built-in channel", name)
+		}
+		switch channel.Type {
+		case types.ChannelTelegram:
+			if !notifications.Telegram.Enabled {
+				return fmt.Errorf("channels.%s: telegram must be enabled for telegram channels", name)
+			}
+			if channel.ChatID == 0 {
+				return fmt.Errorf("channels.%s: chat_id is required", name)
+			}
+		case types.ChannelMatrix:
+			if !notifications.Matrix.Enabled {
+				return fmt.Errorf("channels.%s: matrix must be enabled for matrix channels", name)
+			}
+			if !strings.HasPrefix(channel.RoomID, "!") {
+				return fmt.Errorf("channels.%s: room_id must be a room ID such as !abc123:example.org", name)
+			}
+		case types.ChannelSlack, types.ChannelWebhook:
+			if u, err := url.Parse(channel.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("channels.%s: url must be an http or https URL, got %q", name, channel.URL)
+			}
+		default:
+			return fmt.Errorf("channels.%s: invalid type %q (expected telegram, slack, matrix or webhook)", name, channel.Type)
+		}
+		switch channel.PayloadStyle {
+		case "", types.PayloadStyleFull, types.PayloadStyleAutomation:
+		default:
+			return fmt.Errorf("channels.%s: invalid payload_style %q (expected %s or %s)", name, channel.PayloadStyle, types.PayloadStyleFull, types.PayloadStyleAutomation)
+		}
+	}
+
+	for i, route := range notifications.Routes {
+		if len(route.Channels) == 0 {
+			return fmt.Errorf("routes[%d]: channels are required", i)
+		}
+		for _, channel := range route.Channels {
+			if _, ok := notifications.Channels[channel]; !ok && !slices.Contains(builtIn, channel) {
+				return fmt.Errorf("routes[%d]: unknown channel %q (expected a name of channels or %s)", i, channel, strings.Join(builtIn, ", "))
+			}
+			if channel == types.RoutePagerDuty && !notifications.PagerDuty.Enabled {
+				return fmt.Errorf("routes[%d]: pagerduty must be enabled to route to it", i)
+			}
+		}
+		for _, network := range route.Networks {
+			if _, ok := config.Networks[network]; !ok {
+				return fmt.Errorf("routes[%d]: unknown network %q", i, network)
+			}
+		}
+		if err := validateAlertTypes(route.AlertTypes); err != nil {
+			return fmt.Errorf("routes[%d]: invalid alert_types: %w", i, err)
+		}
+		if err := validateProposalTypes(route.ProposalTypes); err != nil {
+			return fmt.Errorf("routes[%d]: invalid proposal_types: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateRoles validates channel roles
+func validateRoles(roles []string) error {
+	for _, role := range roles {
+		switch role {
+		case types.RoleCommunity, types.RoleOps, types.RoleAudit:
+		default:
+			return fmt.Errorf("unknown role %q (expected community, ops or audit)", role)
+		}
 	}
+	return nil
+}
+
+// validateAlertTypes validates proposal alert types
+func validateAlertTypes(alertTypes []string) error {
+	for _, alertType := range alertTypes {
+		if !slices.Contains(types.AlertTypes, alertType) {
+			return fmt.Errorf("unknown alert type %q (expected one of %s)", alertType, strings.Join(types.AlertTypes, ", "))
+		}
+	}
+	return nil
+}
 
+// validateAllowedHost validates an allowed host of privacy mode, a host
+// name, an IP address or a *.domain wildcard without scheme, port or path
+func validateAllowedHost(host string) error {
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	name := strings.TrimPrefix(host, "*.")
+	if name == "" || strings.ContainsAny(name, "*/:@ ") {
+		return fmt.Errorf("invalid host %q (expected a host name such as api.example.com or *.example.com)", host)
+	}
+	return nil
+}
+
+// validateVoteOptions validates the vote options of a network and the
+// labels renaming them
+func validateVoteOptions(options []string, labels map[string]string) error {
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if !slices.Contains(types.VoteOptions, option) {
+			return fmt.Errorf("unknown vote option %q (expected one of %s)", option, strings.Join(types.VoteOptions, ", "))
+		}
+		if seen[option] {
+			return fmt.Errorf("vote option %q is listed twice", option)
+		}
+		seen[option] = true
+	}
+	for option := range labels {
+		if !slices.Contains(types.VoteOptions, option) {
+			return fmt.Errorf("unknown vote option %q in vote_option_labels (expected one of %s)", option, strings.Join(types.VoteOptions, ", "))
+		}
+	}
+	return nil
+}
+
+// validateProposalTypes validates proposal type filters, which are
+// categories or short message type names such as MsgSoftwareUpgrade
+func validateProposalTypes(proposalTypes []string) error {
+	for _, proposalType := range proposalTypes {
+		if slices.Contains(cosmosgov.Categories, proposalType) {
+			continue
+		}
+		if proposalType == "" || !unicode.IsUpper(rune(proposalType[0])) || strings.ContainsAny(proposalType, "/. ") {
+			return fmt.Errorf("unknown proposal type %q (expected one of %s, or a message type such as MsgSoftwareUpgrade)", proposalType, strings.Join(cosmosgov.Categories, ", "))
+		}
+	}
 	return nil
 }
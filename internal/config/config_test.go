@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"governance-alerts-cosmos/internal/privacy"
+)
+
+// TestLoadConfigGuardsRemoteConfiguration checks that in privacy mode the
+// chain registry is fetched through the privacy guard: the configured
+// registry host is reached, a redirect to any other host is blocked
+func TestLoadConfigGuardsRemoteConfiguration(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// Same server, under a host name that is not allowed
+		target := strings.Replace("http://"+r.Host, "127.0.0.1", "localhost", 1) + "/mirror" + r.URL.Path
+		http.Redirect(w, r, target, http.StatusFound)
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	data := fmt.Sprintf("privacy:\n  enabled: true\nregistry:\n  url: %q\nnetworks_from_registry: [\"cosmoshub\"]\n", server.URL)
+	if err := os.WriteFile(configPath, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadConfig(configPath)
+	if !errors.Is(err, privacy.ErrBlocked) {
+		t.Fatalf("LoadConfig() error = %v, want %v", err, privacy.ErrBlocked)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("registry requests = %d, want 1", got)
+	}
+	if !privacy.Active() {
+		t.Error("privacy guard not installed by LoadConfig")
+	}
+}
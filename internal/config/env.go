@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the environment variables that set configuration keys
+const EnvPrefix = "GAC_"
+
+// EnvOnly reports whether the configuration comes from the environment
+// alone: the configuration file is missing and GAC_ variables are set
+func EnvOnly(configPath string) bool {
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		return false
+	}
+	for _, variable := range os.Environ() {
+		if strings.HasPrefix(variable, EnvPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// envConfig returns the configuration keys set by GAC_ variables as nested
+// maps and lists. A variable names the key path upper cased and joined by
+// underscores: GAC_NETWORKS_COSMOSHUB_REST_ENDPOINT sets
+// networks.cosmoshub.rest_endpoint and GAC_NOTIFICATIONS_WEBHOOKS_0_URL the
+// url of the first webhook. Lists of values are comma separated. Variables
+// matching no key are rejected like unknown keys in the file
+func envConfig(environ []string) (map[string]interface{}, error) {
+	schema := GenerateSchema()
+	root := make(map[string]interface{})
+
+	var problems []string
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		tokens := strings.Split(strings.ToLower(strings.TrimPrefix(name, EnvPrefix)), "_")
+		path := envPath(schema, tokens)
+		if path == nil {
+			problems = append(problems, fmt.Sprintf("%s matches no configuration key", name))
+			continue
+		}
+		setEnvValue(root, path, envValue(schema, path, value))
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	return envLists(root, schema).(map[string]interface{}), nil
+}
+
+// envPath resolves the lower cased words of a variable name against the
+// schema, returning the key path it sets or nil. Keys are matched longest
+// first, then map keys shortest first, so map keys may contain underscores
+func envPath(schema *Schema, tokens []string) []string {
+	switch schema.Type {
+	case "object":
+		keys := make([]string, 0, len(schema.Properties))
+		for key := range schema.Properties {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+		for _, key := range keys {
+			words := strings.Split(key, "_")
+			if len(words) > len(tokens) || strings.Join(tokens[:len(words)], "_") != key {
+				continue
+			}
+			if rest := envPath(schema.Properties[key], tokens[len(words):]); rest != nil {
+				return append([]string{key}, rest...)
+			}
+		}
+		if additional, ok := schema.AdditionalProperties.(*Schema); ok {
+			for n := 1; n <= len(tokens); n++ {
+				if rest := envPath(additional, tokens[n:]); rest != nil {
+					return append([]string{strings.Join(tokens[:n], "_")}, rest...)
+				}
+			}
+		}
+		return nil
+	case "array":
+		// Lists of values are set whole, lists of objects item by item
+		if schema.Items.Type != "object" && schema.Items.Type != "array" {
+			if len(tokens) == 0 {
+				return []string{}
+			}
+			return nil
+		}
+		if len(tokens) == 0 {
+			return nil
+		}
+		if _, err := strconv.Atoi(tokens[0]); err != nil {
+			return nil
+		}
+		if rest := envPath(schema.Items, tokens[1:]); rest != nil {
+			return append([]string{tokens[0]}, rest...)
+		}
+		return nil
+	default:
+		if len(tokens) == 0 {
+			return []string{}
+		}
+		return nil
+	}
+}
+
+// envValue returns the value of a variable for the key at path, split on
+// commas for lists
+func envValue(schema *Schema, path []string, value string) interface{} {
+	for _, key := range path {
+		switch schema.Type {
+		case "object":
+			if prop, ok := schema.Properties[key]; ok {
+				schema = prop
+			} else {
+				schema = schema.AdditionalProperties.(*Schema)
+			}
+		case "array":
+			schema = schema.Items
+		}
+	}
+	if schema.Type != "array" {
+		return value
+	}
+	items := []interface{}{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setEnvValue sets the value at path in nested maps, list indexes
+// included, creating the maps on the way
+func setEnvValue(root map[string]interface{}, path []string, value interface{}) {
+	node := root
+	for _, key := range path[:len(path)-1] {
+		child, ok := node[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			node[key] = child
+		}
+		node = child
+	}
+	node[path[len(path)-1]] = value
+}
+
+// envLists turns the maps of list indexes built by setEnvValue into lists,
+// in index order
+func envLists(node interface{}, schema *Schema) interface{} {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return node
+	}
+	switch schema.Type {
+	case "array":
+		indexes := make([]int, 0, len(m))
+		for key := range m {
+			index, _ := strconv.Atoi(key)
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		items := make([]interface{}, 0, len(indexes))
+		for _, index := range indexes {
+			items = append(items, envLists(m[strconv.Itoa(index)], schema.Items))
+		}
+		return items
+	case "object":
+		for key, child := range m {
+			prop, ok := schema.Properties[key]
+			if !ok {
+				prop = schema.AdditionalProperties.(*Schema)
+			}
+			m[key] = envLists(child, prop)
+		}
+	}
+	return m
+}
+
+// EnvVariables lists the environment variables that set configuration
+// keys with their types. <NAME> stands for a map key such as a network
+// name and <N> for a list index
+func EnvVariables() []string {
+	var variables []string
+	var walk func(schema *Schema, prefix string)
+	walk = func(schema *Schema, prefix string) {
+		switch {
+		case schema.Type == "object":
+			for key, prop := range schema.Properties {
+				walk(prop, prefix+"_"+strings.ToUpper(key))
+			}
+			if additional, ok := schema.AdditionalProperties.(*Schema); ok {
+				walk(additional, prefix+"_<NAME>")
+			}
+		case schema.Type == "array" && (schema.Items.Type == "object" || schema.Items.Type == "array"):
+			walk(schema.Items, prefix+"_<N>")
+		case schema.Type == "array":
+			variables = append(variables, fmt.Sprintf("%s\tcomma separated %ss", prefix, schema.Items.Type))
+		default:
+			variables = append(variables, fmt.Sprintf("%s\t%s", prefix, schema.Type))
+		}
+	}
+	walk(GenerateSchema(), strings.TrimSuffix(EnvPrefix, "_"))
+	sort.Strings(variables)
+	return variables
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRegistryURL is where chain.json files of the cosmos/chain-registry
+// are fetched from, as <url>/<chain name>/chain.json
+const DefaultRegistryURL = "https://raw.githubusercontent.com/cosmos/chain-registry/master"
+
+// registryTimeout bounds the fetch of each chain from the registry
+const registryTimeout = 15 * time.Second
+
+// maxRegistryEndpoints is how many of the endpoints the registry lists for
+// a chain are used, the primary and its failovers
+const maxRegistryEndpoints = 5
+
+// registryChainName matches chain names, testnets prefixed with testnets/
+var registryChainName = regexp.MustCompile(`^(testnets/)?[a-z0-9]+$`)
+
+// registryChain is the part of a chain.json the networks are built from
+type registryChain struct {
+	ChainName  string `json:"chain_name"`
+	ChainID    string `json:"chain_id"`
+	PrettyName string `json:"pretty_name"`
+	APIs       struct {
+		REST []registryEndpoint `json:"rest"`
+		GRPC []registryEndpoint `json:"grpc"`
+	} `json:"apis"`
+	Explorers []struct {
+		Kind string `json:"kind"`
+		URL  string `json:"url"`
+	} `json:"explorers"`
+}
+
+// registryEndpoint is an endpoint of a chain in the registry
+type registryEndpoint struct {
+	Address  string `json:"address"`
+	Provider string `json:"provider"`
+}
+
+// explorerProposalPaths are the proposal pages of the explorers known by
+// their registry kind, relative to the chain's explorer URL
+var explorerProposalPaths = map[string]string{
+	"mintscan": "/proposals/{id}",
+	"ping.pub": "/gov/{id}",
+}
+
+// resolveRegistryNetworks adds the chains of networks_from_registry to the
+// networks, keyed by chain name. Settings of a network configured under the
+// same key take precedence, so the registry only fills in what is left
+// empty. Fetched chains are saved to registry.cache_path when set and used
+// from there when the registry cannot be reached
+func resolveRegistryNetworks(config *types.Config) error {
+	if len(config.NetworksFromRegistry) == 0 {
+		return nil
+	}
+	if config.Networks == nil {
+		config.Networks = make(map[string]types.NetworkConfig)
+	}
+
+	baseURL := config.Registry.URL
+	if baseURL == "" {
+		baseURL = DefaultRegistryURL
+	}
+	cache := readRegistryCache(config.Registry.CachePath)
+	fetched := false
+	for _, chainName := range config.NetworksFromRegistry {
+		if !registryChainName.MatchString(chainName) {
+			return fmt.Errorf("invalid chain name %q in networks_from_registry", chainName)
+		}
+
+		chain, err := fetchRegistryChain(baseURL, chainName)
+		if err != nil {
+			cached, ok := cache[chainName]
+			if !ok {
+				return fmt.Errorf("failed to fetch %s from the chain registry: %w", chainName, err)
+			}
+			logrus.WithField("chain", chainName).WithError(err).Warn("Chain registry unreachable, using the cached chain")
+			chain = cached
+		} else {
+			cache[chainName] = chain
+			fetched = true
+		}
+
+		key := path.Base(chainName)
+		config.Networks[key] = registryNetwork(config.Networks[key], chain)
+	}
+
+	if fetched && config.Registry.CachePath != "" {
+		if err := writeRegistryCache(config.Registry.CachePath, cache); err != nil {
+			logrus.WithError(err).Warn("Failed to save the chain registry cache")
+		}
+	}
+	return nil
+}
+
+// fetchRegistryChain fetches the chain.json of a chain
+func fetchRegistryChain(baseURL, chainName string) (registryChain, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	var chain registryChain
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/"+chainName+"/chain.json", nil)
+	if err != nil {
+		return chain, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return chain, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return chain, fmt.Errorf("no chain named %s in the registry", chainName)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return chain, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return chain, err
+	}
+	if err := json.Unmarshal(body, &chain); err != nil {
+		return chain, fmt.Errorf("invalid chain.json: %w", err)
+	}
+	if chain.ChainID == "" {
+		return chain, fmt.Errorf("chain.json of %s has no chain_id", chainName)
+	}
+	return chain, nil
+}
+
+// registryNetwork fills in the empty settings of network from a registry
+// chain: name, chain ID, endpoints and explorer links
+func registryNetwork(network types.NetworkConfig, chain registryChain) types.NetworkConfig {
+	if network.Name == "" {
+		network.Name = chain.PrettyName
+		if network.Name == "" {
+			network.Name = chain.ChainName
+		}
+	}
+	if network.ChainID == "" {
+		network.ChainID = chain.ChainID
+	}
+
+	if network.Transport == types.TransportGRPC {
+		if len(network.GRPCEndpoints) == 0 {
+			network.GRPCEndpoints = registryAddresses(chain.APIs.GRPC)
+		}
+	} else if network.RestEndpoint == "" && len(network.RestEndpoints) == 0 {
+		if addresses := registryAddresses(chain.APIs.REST); len(addresses) > 0 {
+			network.RestEndpoint = addresses[0]
+			network.RestEndpoints = addresses[1:]
+		}
+	}
+
+	if network.ExplorerURLTemplate == "" && len(network.ExplorerURLTemplates) == 0 {
+		var templates []string
+		for _, explorer := range chain.Explorers {
+			proposalPath, ok := explorerProposalPaths[strings.ToLower(explorer.Kind)]
+			if ok && strings.HasPrefix(explorer.URL, "https://") {
+				templates = append(templates, strings.TrimSuffix(explorer.URL, "/")+proposalPath)
+			}
+		}
+		if len(templates) > 0 {
+			network.ExplorerURLTemplate = templates[0]
+			network.ExplorerURLTemplates = templates[1:]
+		}
+	}
+	return network
+}
+
+// registryAddresses returns the distinct endpoint addresses of the registry,
+// at most maxRegistryEndpoints
+func registryAddresses(endpoints []registryEndpoint) []string {
+	var addresses []string
+	for _, endpoint := range endpoints {
+		address := strings.TrimSuffix(strings.TrimSpace(endpoint.Address), "/")
+		if address == "" || slices.Contains(addresses, address) {
+			continue
+		}
+		addresses = append(addresses, address)
+		if len(addresses) == maxRegistryEndpoints {
+			break
+		}
+	}
+	return addresses
+}
+
+// readRegistryCache reads the chains saved by earlier fetches, none when
+// there is no cache
+func readRegistryCache(cachePath string) map[string]registryChain {
+	cache := make(map[string]registryChain)
+	if cachePath == "" {
+		return cache
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		logrus.WithError(err).Warn("Ignoring unreadable chain registry cache")
+		return make(map[string]registryChain)
+	}
+	return cache
+}
+
+// writeRegistryCache saves the fetched chains, atomically
+func writeRegistryCache(cachePath string, cache map[string]registryChain) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cachePath)
+}
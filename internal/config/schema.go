@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the subset of JSON Schema generated for the configuration
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+// GenerateSchema builds the JSON Schema of the configuration file from the
+// mapstructure tags of types.Config
+func GenerateSchema() *Schema {
+	schema := schemaFor(reflect.TypeOf(types.Config{}))
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	schema.Title = "Governance Alerts Cosmos configuration"
+	return schema
+}
+
+// schemaFor returns the schema of a Go type
+func schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		schema := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: false,
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+			if key == "" || key == "-" || !field.IsExported() {
+				continue
+			}
+			schema.Properties[key] = schemaFor(field.Type)
+		}
+		return schema
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaFor(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaFor(t.Elem())}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// validateSchema validates raw YAML against the configuration schema and
+// reports every problem with its line and column
+func validateSchema(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	var problems []string
+	validateNode(root.Content[0], GenerateSchema(), "", &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// validateNode validates a YAML node against a schema, appending problems
+func validateNode(node *yaml.Node, schema *Schema, path string, problems *[]string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, fmt.Sprintf("line %d, column %d: %s", node.Line, node.Column, fmt.Sprintf(format, args...)))
+	}
+
+	where := path
+	if where == "" {
+		where = "top level"
+	}
+
+	switch schema.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			fail("%s must be a mapping", where)
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			key := keyNode.Value
+			childPath := joinPath(path, key)
+
+			if prop, ok := schema.Properties[key]; ok {
+				validateNode(valueNode, prop, childPath, problems)
+				continue
+			}
+			if additional, ok := schema.AdditionalProperties.(*Schema); ok {
+				validateNode(valueNode, additional, childPath, problems)
+				continue
+			}
+
+			msg := fmt.Sprintf("line %d, column %d: unknown key %q in %s", keyNode.Line, keyNode.Column, key, where)
+			if suggestion := closestKey(key, schema.Properties); suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			*problems = append(*problems, msg)
+		}
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			fail("%s must be a list", where)
+			return
+		}
+		for i, item := range node.Content {
+			validateNode(item, schema.Items, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			fail("%s must be an integer, got %q", where, node.Value)
+		}
+	case "number":
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			fail("%s must be a number, got %q", where, node.Value)
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			fail("%s must be true or false, got %q", where, node.Value)
+		}
+	case "string":
+		if node.Kind != yaml.ScalarNode {
+			fail("%s must be a string", where)
+		}
+	}
+}
+
+// joinPath joins a dotted config path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closestKey returns the known key closest to key, if it is close enough to
+// be a likely typo
+func closestKey(key string, properties map[string]*Schema) string {
+	candidates := make([]string, 0, len(properties))
+	for name := range properties {
+		candidates = append(candidates, name)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", len(key)/3+2
+	for _, name := range candidates {
+		if d := levenshtein(key, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
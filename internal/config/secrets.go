@@ -0,0 +1,174 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// secretTimeout bounds each request to a secret manager
+const secretTimeout = 10 * time.Second
+
+// Prefixes of the secret manager references
+const (
+	vaultPrefix = "vault:"
+	awsPrefix   = "aws-sm:"
+)
+
+// secretSetting is a setting holding a secret, which can instead be read
+// from file or referenced in a secret manager
+type secretSetting struct {
+	name  string
+	value *string
+	file  string
+}
+
+// secretResolver reads secrets, fetching each secret of a manager once
+type secretResolver struct {
+	config types.SecretsConfig
+	vault  map[string]map[string]interface{}
+	aws    map[string]string
+}
+
+// resolveSecrets replaces the secret settings given as a file or a secret
+// manager reference by the secret, so credentials never need to be in the
+// configuration file
+func resolveSecrets(config *types.Config) error {
+	resolver := &secretResolver{vault: make(map[string]map[string]interface{}), aws: make(map[string]string)}
+
+	// The Vault token itself can only come from a file
+	vault := &config.Secrets.Vault
+	if err := resolver.readFile(secretSetting{"secrets.vault.token", &vault.Token, vault.TokenFile}); err != nil {
+		return err
+	}
+	resolver.config = config.Secrets
+
+	notifications := &config.Notifications
+	settings := []secretSetting{
+		{"notifications.telegram.bot_token", &notifications.Telegram.BotToken, notifications.Telegram.BotTokenFile},
+		{"notifications.slack.webhook_url", &notifications.Slack.WebhookURL, notifications.Slack.WebhookURLFile},
+		{"notifications.slack.bot_token", &notifications.Slack.BotToken, notifications.Slack.BotTokenFile},
+		{"notifications.slack.signing_secret", &notifications.Slack.SigningSecret, notifications.Slack.SigningSecretFile},
+		{"notifications.matrix.access_token", &notifications.Matrix.AccessToken, notifications.Matrix.AccessTokenFile},
+		{"notifications.pagerduty.routing_key", &notifications.PagerDuty.RoutingKey, notifications.PagerDuty.RoutingKeyFile},
+		{"api.token", &config.API.Token, config.API.TokenFile},
+		{"health.heartbeat_url", &config.Health.HeartbeatURL, ""},
+		{"analytics.clickhouse.password", &config.Analytics.ClickHouse.Password, config.Analytics.ClickHouse.PasswordFile},
+	}
+	for i := range notifications.Webhooks {
+		webhook := &notifications.Webhooks[i]
+		settings = append(settings,
+			secretSetting{fmt.Sprintf("notifications.webhooks[%d].url", i), &webhook.URL, webhook.URLFile},
+			secretSetting{fmt.Sprintf("notifications.webhooks[%d].secret", i), &webhook.Secret, webhook.SecretFile},
+		)
+	}
+	for _, setting := range settings {
+		if err := resolver.resolve(setting); err != nil {
+			return err
+		}
+	}
+
+	channels := make([]string, 0, len(notifications.Channels))
+	for name := range notifications.Channels {
+		channels = append(channels, name)
+	}
+	sort.Strings(channels)
+	for _, name := range channels {
+		channel := notifications.Channels[name]
+		for _, setting := range []secretSetting{
+			{fmt.Sprintf("notifications.channels.%s.url", name), &channel.URL, channel.URLFile},
+			{fmt.Sprintf("notifications.channels.%s.secret", name), &channel.Secret, channel.SecretFile},
+		} {
+			if err := resolver.resolve(setting); err != nil {
+				return err
+			}
+		}
+		notifications.Channels[name] = channel
+	}
+
+	names := make([]string, 0, len(config.Networks))
+	for name := range config.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		network := config.Networks[name]
+		auth := &network.Auth
+		for _, setting := range []secretSetting{
+			{fmt.Sprintf("networks.%s.auth.password", name), &auth.Password, auth.PasswordFile},
+			{fmt.Sprintf("networks.%s.auth.token", name), &auth.Token, auth.TokenFile},
+			{fmt.Sprintf("networks.%s.heartbeat_url", name), &network.HeartbeatURL, ""},
+		} {
+			if err := resolver.resolve(setting); err != nil {
+				return err
+			}
+		}
+		config.Networks[name] = network
+	}
+	return nil
+}
+
+// resolve reads a secret setting from its file, then from the secret
+// manager it references, if any
+func (r *secretResolver) resolve(setting secretSetting) error {
+	if err := r.readFile(setting); err != nil {
+		return err
+	}
+
+	var secret string
+	var err error
+	switch value := *setting.value; {
+	case strings.HasPrefix(value, vaultPrefix):
+		secret, err = r.vaultSecret(strings.TrimPrefix(value, vaultPrefix))
+		if err != nil {
+			return fmt.Errorf("failed to read %s from Vault: %w", setting.name, err)
+		}
+	case strings.HasPrefix(value, awsPrefix):
+		secret, err = r.awsSecret(strings.TrimPrefix(value, awsPrefix))
+		if err != nil {
+			return fmt.Errorf("failed to read %s from AWS Secrets Manager: %w", setting.name, err)
+		}
+	default:
+		return nil
+	}
+	*setting.value = secret
+	return nil
+}
+
+// readFile sets a secret setting from its file, without the trailing
+// newline editors add
+func (r *secretResolver) readFile(setting secretSetting) error {
+	if setting.file == "" {
+		return nil
+	}
+	if *setting.value != "" {
+		return fmt.Errorf("%s and %s_file are both set", setting.name, setting.name)
+	}
+	data, err := os.ReadFile(setting.file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s_file: %w", setting.name, err)
+	}
+	*setting.value = strings.TrimSpace(string(data))
+	return nil
+}
+
+// secretKey returns the value of key in a JSON object secret
+func secretKey(secret map[string]interface{}, key string) (string, error) {
+	value, ok := secret[key]
+	if !ok {
+		return "", fmt.Errorf("the secret has no key %q", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
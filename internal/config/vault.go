@@ -0,0 +1,82 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// vaultSecret returns a key of a Vault secret referenced as <path>#<key>,
+// the path being the API path, e.g. secret/data/governance for the
+// governance secret of the KV v2 engine mounted at secret
+func (r *secretResolver) vaultSecret(ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("invalid reference %q, expected vault:<path>#<key>", vaultPrefix+ref)
+	}
+
+	secret, cached := r.vault[path]
+	if !cached {
+		var err error
+		if secret, err = r.fetchVault(path); err != nil {
+			return "", err
+		}
+		r.vault[path] = secret
+	}
+	return secretKey(secret, key)
+}
+
+// fetchVault reads the secret at a Vault API path
+func (r *secretResolver) fetchVault(path string) (map[string]interface{}, error) {
+	address := r.config.Vault.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	token := r.config.Vault.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" {
+		return nil, fmt.Errorf("secrets.vault.address and VAULT_ADDR are not set")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("secrets.vault.token and VAULT_TOKEN are not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if r.config.Vault.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.config.Vault.Namespace)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: status %d", path, resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%s: invalid response: %w", path, err)
+	}
+	// KV v2 nests the secret with its metadata
+	if nested, ok := body.Data["data"].(map[string]interface{}); ok {
+		if _, ok := body.Data["metadata"]; ok {
+			return nested, nil
+		}
+	}
+	return body.Data, nil
+}
@@ -0,0 +1,135 @@
+// Package events defines the CloudEvents envelope wrapping the machine
+// readable outputs of the service, webhooks and the analytics export, and
+// the event types they carry
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// SpecVersion is the CloudEvents specification version emitted
+const SpecVersion = "1.0"
+
+// Event types emitted by the service, see docs/EVENTS.md
+const (
+	TypeServiceStarted         = "cosmos.governance.service.started"
+	TypeServiceMessage         = "cosmos.governance.service.message"
+	TypeProposalVotingStarting = "cosmos.governance.proposal.voting_starting"
+	TypeProposalVotingEnding   = "cosmos.governance.proposal.voting_ending"
+	TypeTallySnapshot          = "cosmos.governance.proposal.tally_snapshot"
+)
+
+// typePrefixProposal prefixes the types of the other proposal alerts, by
+// alert type, and typePrefixTimeline those of timeline events, by kind
+const (
+	typePrefixProposal = "cosmos.governance.proposal."
+	typePrefixTimeline = "cosmos.governance.proposal.timeline."
+)
+
+// AlertType returns the event type of an alert: the voting start and end
+// reminders have their own types, the other proposal alerts are named
+// after their alert type and messages without one, such as ops messages,
+// are service messages
+func AlertType(alertType string) string {
+	switch alertType {
+	case "":
+		return TypeServiceMessage
+	case "voting_start":
+		return TypeProposalVotingStarting
+	case "voting_end":
+		return TypeProposalVotingEnding
+	}
+	return typePrefixProposal + alertType
+}
+
+// TimelineType returns the event type of a timeline event of kind
+func TimelineType(kind string) string {
+	return typePrefixTimeline + kind
+}
+
+// Event represents a CloudEvents 1.0 envelope in structured JSON mode
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// TimelineData is the data of timeline events: what the service observed
+// or did about a proposal, as in its timeline
+type TimelineData struct {
+	Network    string `json:"network"`
+	ChainID    string `json:"chain_id"`
+	ProposalID uint64 `json:"proposal_id"`
+	Kind       string `json:"kind"`
+	Detail     string `json:"detail"`
+}
+
+// TallyData is the data of tally snapshots: the tally of a proposal in
+// voting period when it moved, amounts in base units of Denom
+type TallyData struct {
+	Network    string `json:"network"`
+	ChainID    string `json:"chain_id"`
+	ProposalID uint64 `json:"proposal_id"`
+	Yes        string `json:"yes"`
+	No         string `json:"no"`
+	Abstain    string `json:"abstain"`
+	NoWithVeto string `json:"no_with_veto"`
+	Denom      string `json:"denom"`
+}
+
+// ContentType is the media type of a structured-mode CloudEvent
+const ContentType = "application/cloudevents+json; charset=UTF-8"
+
+// NewEvent creates an event for a chain happening now. Source identifies
+// the chain the event is about and subject the proposal, if any
+func NewEvent(eventType, chainID string, proposalID uint64, data interface{}) Event {
+	return NewEventAt(eventType, chainID, proposalID, data, time.Now())
+}
+
+// NewEventAt creates an event for a chain that happened at a given time,
+// such as a timeline event recorded earlier
+func NewEventAt(eventType, chainID string, proposalID uint64, data interface{}, at time.Time) Event {
+	event := Event{
+		SpecVersion:     SpecVersion,
+		ID:              newID(),
+		Source:          Source(chainID),
+		Type:            eventType,
+		Time:            at.UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	if proposalID != 0 {
+		event.Subject = Subject(proposalID)
+	}
+	return event
+}
+
+// Source returns the CloudEvents source for a chain
+func Source(chainID string) string {
+	if chainID == "" {
+		return "/governance-alerts-cosmos"
+	}
+	return fmt.Sprintf("/governance-alerts-cosmos/chains/%s", chainID)
+}
+
+// Subject returns the CloudEvents subject for a proposal
+func Subject(proposalID uint64) string {
+	return fmt.Sprintf("proposals/%d", proposalID)
+}
+
+// newID generates a unique event ID
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,47 @@
+package governance
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"governance-alerts-cosmos/internal/privacy"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// newArchiveClients creates clients for the archive endpoints of a network.
+// Archive nodes may be run by other providers, so endpoint auth and sticky
+// headers are not sent to them
+func newArchiveClients(endpoints []string) []*cosmosgov.Client {
+	clients := make([]*cosmosgov.Client, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		clients = append(clients, cosmosgov.New(endpoint,
+			cosmosgov.WithHTTPClient(&http.Client{
+				Transport: privacy.Wrap(sharedTransport),
+				Timeout:   30 * time.Second,
+			}),
+			cosmosgov.WithUserAgent(userAgent),
+		))
+	}
+	return clients
+}
+
+// historical runs a query for historical data against the live endpoint
+// and, if it fails, against each archive endpoint in turn, since pruned
+// nodes drop finished proposals, their tallies and votes
+func (c *Client) historical(ctx context.Context, query func(gov *cosmosgov.Client) error) error {
+	err := query(c.gov)
+	if err == nil || len(c.archive) == 0 {
+		return err
+	}
+
+	for _, archive := range c.archive {
+		archiveErr := query(archive)
+		if archiveErr == nil {
+			return nil
+		}
+		c.log().WithField("endpoint", archive.Endpoint()).WithError(archiveErr).Warn("Archive endpoint failed")
+	}
+	return err
+}
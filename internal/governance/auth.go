@@ -0,0 +1,35 @@
+package governance
+
+import (
+	"net/http"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Supported endpoint authentication types
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+	AuthQuery  = "query"
+)
+
+// defaultAuthQueryParam is used for query auth when no parameter is set
+const defaultAuthQueryParam = "api_key"
+
+// applyAuth adds the configured credentials to a request
+func applyAuth(req *http.Request, auth types.AuthConfig) {
+	switch auth.Type {
+	case AuthBasic:
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case AuthQuery:
+		param := auth.QueryParam
+		if param == "" {
+			param = defaultAuthQueryParam
+		}
+		query := req.URL.Query()
+		query.Set(param, auth.Token)
+		req.URL.RawQuery = query.Encode()
+	}
+}
@@ -0,0 +1,28 @@
+package governance
+
+import (
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// MatchesProposalType reports whether a proposal is of one of filters,
+// given as categories (software_upgrade, parameter_change...) or as short
+// message type names (MsgSoftwareUpgrade, TextProposal...). An empty list
+// matches every proposal
+func MatchesProposalType(filters []string, proposal types.Proposal) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if filter == proposal.Category || filter == cosmosgov.TypeName(proposal.Type) {
+			return true
+		}
+		for _, messageType := range proposal.MessageTypes {
+			if filter == cosmosgov.TypeName(messageType) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// Kinds of failures of the requests of a Client. Its errors match one of
+// them with errors.Is when the cause is known, so callers decide between
+// retrying, skipping and alerting without looking at messages. The
+// underlying error stays in the chain
+var (
+	// ErrRateLimited is a request refused with 429 Too Many Requests
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotFound is data the endpoints do not have, such as a cancelled
+	// or pruned proposal
+	ErrNotFound = errors.New("not found")
+	// ErrSchema is a response that does not match the expected structure,
+	// typically after a chain upgrade changed the API
+	ErrSchema = errors.New("unexpected response schema")
+	// ErrStale is a response older than one already seen, served by a node
+	// lagging behind
+	ErrStale = errors.New("stale response")
+	// ErrUnavailable is an endpoint that cannot be reached, timed out or
+	// failed with a server error
+	ErrUnavailable = errors.New("endpoint unavailable")
+)
+
+// Error is a failed request of a Client: the operation, the kind of
+// failure when known and its cause. The cause already names what failed,
+// so it is the message
+type Error struct {
+	Op   string
+	Kind error
+	Err  error
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the kind and the cause of the error
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Retryable reports whether a request that failed with err may succeed when
+// sent again later: rate limits, unavailable endpoints and stale
+// responses. Missing data, schema mismatches and endpoints serving another
+// chain need a change on the chain or in the configuration first
+func Retryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable) || errors.Is(err, ErrStale)
+}
+
+// wrapError classifies the error of an operation, nil staying nil. Errors
+// already classified are returned as is
+func wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	return &Error{Op: op, Kind: errorKind(err), Err: err}
+}
+
+// errorKind returns the kind of a request error, nil when unknown. The
+// caller giving up and endpoints serving another chain have no kind,
+// the latter matching ErrNoTrustedEndpoint
+func errorKind(err error) error {
+	var statusErr *cosmosgov.StatusError
+	var invalid *cosmosgov.InvalidProposalsError
+	var netErr net.Error
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrNoTrustedEndpoint):
+		return nil
+	case errors.Is(err, errHeightRegression):
+		return ErrStale
+	case errors.As(err, &statusErr):
+		switch {
+		case statusErr.Code == http.StatusNotFound:
+			return ErrNotFound
+		case statusErr.Code == http.StatusTooManyRequests:
+			return ErrRateLimited
+		case statusErr.Code >= http.StatusInternalServerError, statusErr.Code == http.StatusRequestTimeout:
+			return ErrUnavailable
+		}
+		return nil
+	case isSchemaError(err), errors.As(err, &invalid), errors.Is(err, errNoChainID):
+		return ErrSchema
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr), errors.As(err, &urlErr):
+		return ErrUnavailable
+	}
+	return nil
+}
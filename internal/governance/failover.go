@@ -0,0 +1,139 @@
+package governance
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Failover settings. A failing endpoint is skipped for a cooldown that
+// doubles with every consecutive failure
+const (
+	attemptTimeout  = 15 * time.Second
+	minCooldown     = 30 * time.Second
+	maxCooldown     = 10 * time.Minute
+	cooldownGrowths = 5
+)
+
+// endpoint is one LCD URL of a network with its health
+type endpoint struct {
+	url     string
+	heights heightTracker
+	stats   endpointStats
+
+	mu        sync.Mutex
+	failures  int
+	downUntil time.Time
+	// wrongChainID is the chain ID reported at the last verification when
+	// it was not the configured one
+	wrongChainID string
+}
+
+// newEndpoints returns the endpoints of a network, the primary
+// rest_endpoint first, or its gRPC endpoints with the grpc transport
+func newEndpoints(config types.NetworkConfig) []*endpoint {
+	var endpoints []*endpoint
+	seen := make(map[string]bool)
+	urls := append([]string{config.RestEndpoint}, config.RestEndpoints...)
+	if config.Transport == types.TransportGRPC {
+		urls = config.GRPCEndpoints
+	}
+	for _, raw := range urls {
+		raw = strings.TrimRight(raw, "/")
+		if raw == "" || seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		endpoints = append(endpoints, &endpoint{url: raw})
+	}
+	return endpoints
+}
+
+// markHealthy resets the failures of the endpoint
+func (e *endpoint) markHealthy() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = 0
+	e.downUntil = time.Time{}
+}
+
+// markFailed deprioritizes the endpoint for a cooldown
+func (e *endpoint) markFailed(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cooldown := minCooldown << min(e.failures, cooldownGrowths)
+	if cooldown > maxCooldown {
+		cooldown = maxCooldown
+	}
+	e.failures++
+	e.downUntil = now.Add(cooldown)
+}
+
+// availableAt returns when the endpoint's cooldown ends
+func (e *endpoint) availableAt() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.downUntil
+}
+
+// orderEndpoints returns the endpoints to try, healthy ones in configured
+// order and then those cooling down, soonest available first. Endpoints
+// cooling down are still tried when every other one failed
+func orderEndpoints(endpoints []*endpoint, now time.Time) []*endpoint {
+	ordered := append([]*endpoint(nil), endpoints...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i].availableAt(), ordered[j].availableAt()
+		aDown, bDown := a.After(now), b.After(now)
+		if aDown != bDown {
+			return !aDown
+		}
+		return aDown && a.Before(b)
+	})
+	return ordered
+}
+
+// shouldFailover reports whether a request should be retried on another
+// endpoint. Other 4xx answers such as 404 are the same on every node
+func shouldFailover(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// rewriteEndpoint returns a copy of req sent to target instead of the
+// primary endpoint
+func rewriteEndpoint(req *http.Request, primary, target string) (*http.Request, error) {
+	if target == primary {
+		return req, nil
+	}
+	rewritten, err := url.Parse(target + strings.TrimPrefix(req.URL.String(), primary))
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.URL = rewritten
+	req.Host = ""
+	return req, nil
+}
+
+// cancelBody cancels the context of a request attempt once the response
+// body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
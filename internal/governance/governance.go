@@ -2,51 +2,104 @@ package governance
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"governance-alerts-cosmos/internal/privacy"
 	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+	"github.com/sirupsen/logrus"
 )
 
+// userAgent is sent with every request
+const userAgent = "Governance-Alerts-Cosmos/1.0"
+
 // Client represents a governance client
 type Client struct {
-	config types.NetworkConfig
-	client *http.Client
-}
-
-// CosmosGovResponse represents the response from Cosmos governance API
-type CosmosGovResponse struct {
-	Proposals  []CosmosProposal `json:"proposals"`
-	Pagination struct {
-		NextKey string `json:"next_key"`
-		Total   string `json:"total"`
-	} `json:"pagination"`
-}
-
-// CosmosProposal represents a proposal from Cosmos governance API
-type CosmosProposal struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	VotingStart string `json:"voting_start_time"`
-	VotingEnd   string `json:"voting_end_time"`
-	Messages    []struct {
-		TypeURL string `json:"@type"`
-	} `json:"messages"`
+	config    types.NetworkConfig
+	gov       *cosmosgov.Client
+	transport *endpointTransport
+	indexer   *indexerClient
+	archive   []*cosmosgov.Client
+	verified  atomic.Bool
 }
 
 // NewClient creates a new governance client
 func NewClient(config types.NetworkConfig) (*Client, error) {
-	return &Client{
-		config: config,
-		client: &http.Client{
-			Timeout: 15 * time.Second,
-		},
-	}, nil
+	// Networks with custom TLS settings get their own transport, as do
+	// gRPC networks, which need HTTP/2
+	var base http.RoundTripper = sharedTransport
+	if isTLSConfigured(config.TLS) || config.Transport == types.TransportGRPC {
+		tlsTransport := sharedTransport.Clone()
+		if isTLSConfigured(config.TLS) {
+			var err error
+			if tlsTransport, err = newTLSTransport(config.TLS); err != nil {
+				return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+			}
+		}
+		base = tlsTransport
+		if config.Transport == types.TransportGRPC {
+			base = newGRPCTransport(tlsTransport)
+		}
+	}
+
+	// Generate a session ID for sticky routing if none is configured
+	stickyValue := config.Sticky.Value
+	if config.Sticky.Header != "" && stickyValue == "" {
+		stickyValue = newSessionID()
+	}
+
+	transport := &endpointTransport{
+		base:        privacy.Wrap(base),
+		config:      config,
+		stickyValue: stickyValue,
+		endpoints:   newEndpoints(config),
+		retry:       newRetryPolicy(config.Retry),
+	}
+	if len(transport.endpoints) == 0 {
+		return nil, fmt.Errorf("no %s endpoint configured", endpointKind(config))
+	}
+
+	// Requests are built against the primary endpoint, the transport sends
+	// them to the healthiest one. Each endpoint gets its own attempt timeout
+	newGov := cosmosgov.New
+	if config.Transport == types.TransportGRPC {
+		newGov = cosmosgov.NewGRPC
+	}
+	gov := newGov(transport.endpoints[0].url,
+		cosmosgov.WithHTTPClient(&http.Client{
+			Transport: transport,
+			Timeout:   transport.retry.budget(len(transport.endpoints)),
+		}),
+		cosmosgov.WithUserAgent(userAgent),
+	)
+
+	client := &Client{
+		config:    config,
+		gov:       gov,
+		transport: transport,
+		archive:   newArchiveClients(config.ArchiveEndpoints),
+	}
+
+	// Proposal lists and tallies come from the indexer when configured
+	if isIndexerConfigured(config.Indexer) {
+		client.indexer = &indexerClient{
+			config: config.Indexer,
+			httpClient: &http.Client{
+				Transport: privacy.Wrap(sharedTransport),
+				Timeout:   15 * time.Second,
+			},
+		}
+	}
+
+	return client, nil
 }
 
 // Close closes the client
@@ -56,115 +109,252 @@ func (c *Client) Close() error {
 
 // GetVotingProposals fetches all proposals and filters voting ones
 func (c *Client) GetVotingProposals(ctx context.Context) ([]types.Proposal, error) {
-	fmt.Printf("Checking proposals for %s (%s)\n", c.config.Name, c.config.ChainID)
+	proposals, err := c.getProposals(ctx, cosmosgov.StatusVotingPeriod)
+	return proposals, wrapError("list voting proposals", err)
+}
 
-	// Build API URL for all proposals
-	apiURL := fmt.Sprintf("%s/cosmos/gov/v1/proposals", c.config.RestEndpoint)
-	fmt.Printf("  API URL: %s\n", apiURL)
+// GetActiveProposals fetches all proposals and filters those in deposit or
+// voting period
+func (c *Client) GetActiveProposals(ctx context.Context) ([]types.Proposal, error) {
+	proposals, err := c.getProposals(ctx, cosmosgov.StatusDepositPeriod, cosmosgov.StatusVotingPeriod)
+	return proposals, wrapError("list active proposals", err)
+}
 
-	// Make HTTP request
-	body, err := c.makeRequest(ctx, apiURL)
+// GetFinishedProposals fetches the proposals that left their voting
+// period as passed, rejected or failed, at most limit of them, latest first
+func (c *Client) GetFinishedProposals(ctx context.Context, limit int) ([]types.Proposal, error) {
+	proposals, err := c.getProposals(ctx, cosmosgov.StatusPassed, cosmosgov.StatusRejected, cosmosgov.StatusFailed)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch proposals: %w", err)
+		return nil, wrapError("list finished proposals", err)
+	}
+	sort.Slice(proposals, func(i, j int) bool { return proposals[i].ID > proposals[j].ID })
+	if limit > 0 && len(proposals) > limit {
+		proposals = proposals[:limit]
 	}
+	return proposals, nil
+}
+
+// log returns a log entry with the fields of the client's network
+func (c *Client) log() *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{"network": c.config.Name, "chain_id": c.config.ChainID})
+}
 
-	// Parse response
-	var response CosmosGovResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// getProposals fetches all proposals and filters those with one of statuses
+func (c *Client) getProposals(ctx context.Context, statuses ...cosmosgov.ProposalStatus) ([]types.Proposal, error) {
+	all, err := c.listProposals(ctx, statuses)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("  Found %d total proposals\n", len(response.Proposals))
+	c.log().WithField("proposals", len(all)).Debug("Listed proposals")
 
-	// Filter proposals in voting period
 	proposals := make([]types.Proposal, 0)
-	for _, proposal := range response.Proposals {
-		if proposal.Status == "PROPOSAL_STATUS_VOTING_PERIOD" {
-			// Parse voting start time
-			votingStart, err := time.Parse(time.RFC3339, proposal.VotingStart)
-			if err != nil {
-				fmt.Printf("Warning: failed to parse voting start time for proposal %s: %v\n", proposal.ID, err)
-				continue
+	for _, proposal := range all {
+		if len(c.config.ProposalIDs) > 0 && !slices.Contains(c.config.ProposalIDs, proposal.ID) {
+			continue
+		}
+		for _, status := range statuses {
+			if proposal.Status == status {
+				proposals = append(proposals, c.toProposal(proposal))
+				break
 			}
+		}
+	}
 
-			// Parse voting end time
-			votingEnd, err := time.Parse(time.RFC3339, proposal.VotingEnd)
-			if err != nil {
-				fmt.Printf("Warning: failed to parse voting end time for proposal %s: %v\n", proposal.ID, err)
-				continue
-			}
+	c.log().WithField("proposals", len(proposals)).Debugf("Found proposals in %s", statusList(statuses))
+	return proposals, nil
+}
 
-			// Get proposal title and description
-			title := proposal.Title
-			if title == "" {
-				title = fmt.Sprintf("Proposal %s", proposal.ID)
-			}
+// statusList describes proposal statuses for logs
+func statusList(statuses []cosmosgov.ProposalStatus) string {
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, strings.ToLower(StatusLabel(string(status))))
+	}
+	return strings.Join(names, " or ")
+}
 
-			description := proposal.Description
-			if description == "" {
-				description = "No description available"
-			}
+// listProposals lists proposals from the indexer, falling back to the LCD
+// when the indexer fails. The LCD is asked for statuses only when the
+// network filters by status server-side, otherwise every proposal is listed
+func (c *Client) listProposals(ctx context.Context, statuses []cosmosgov.ProposalStatus) ([]cosmosgov.Proposal, error) {
+	if c.indexer != nil {
+		c.log().WithField("indexer", c.config.Indexer.Type).Debug("Listing proposals from indexer")
+		proposals, err := c.indexer.proposals(ctx)
+		if err == nil {
+			return proposals, nil
+		}
+		c.log().WithError(err).Warn("Indexer failed, falling back to LCD")
+	}
 
-			// Convert ID to uint64
-			var proposalID uint64
-			if _, err := fmt.Sscanf(proposal.ID, "%d", &proposalID); err != nil {
-				fmt.Printf("Warning: failed to parse proposal ID %s: %v\n", proposal.ID, err)
-				continue
-			}
+	c.log().WithField("endpoint", c.gov.Endpoint()).Debug("Listing proposals from LCD")
 
-			proposals = append(proposals, types.Proposal{
-				ID:          proposalID,
-				Title:       title,
-				Description: description,
-				Status:      proposal.Status,
-				VotingStart: votingStart,
-				VotingEnd:   votingEnd,
-				Network:     c.config.Name,
-			})
+	if !c.config.StatusFilter {
+		return c.listAllProposals(ctx, cosmosgov.ListProposalsRequest{})
+	}
+
+	var all []cosmosgov.Proposal
+	for _, status := range statuses {
+		proposals, err := c.listAllProposals(ctx, cosmosgov.ListProposalsRequest{Status: status})
+		if err != nil {
+			return nil, err
 		}
+		all = append(all, proposals...)
 	}
+	return all, nil
+}
 
-	fmt.Printf("  Found %d proposals in voting period\n", len(proposals))
-	return proposals, nil
+// listAllProposals lists every page of proposals matching req from the LCD,
+// skipping proposals that fail to normalize
+func (c *Client) listAllProposals(ctx context.Context, req cosmosgov.ListProposalsRequest) ([]cosmosgov.Proposal, error) {
+	req.Page.Limit = uint64(c.config.PageLimit)
+
+	all, err := c.gov.ListAllProposals(ctx, req)
+	var invalid *cosmosgov.InvalidProposalsError
+	if errors.As(err, &invalid) {
+		for _, e := range invalid.Errors {
+			c.log().WithError(e).Warn("Skipping invalid proposal")
+		}
+		ep := c.transport.lastEndpoint()
+		ep.stats.observeSchemaErrors(c.transport.metricLabels(ep), len(invalid.Errors))
+	} else if err != nil {
+		c.observeSchemaError(err)
+		return nil, fmt.Errorf("failed to fetch proposals: %w", err)
+	}
+	return all, nil
 }
 
-// GetProposalDetails fetches detailed information about a specific proposal
+// GetProposalDetails fetches detailed information about a specific
+// proposal, from the archive endpoints if the live endpoint pruned it
 func (c *Client) GetProposalDetails(ctx context.Context, proposalID uint64) (*types.Proposal, error) {
-	// Build API URL for specific proposal
-	apiURL := fmt.Sprintf("%s/cosmos/gov/v1/proposals/%d", c.config.RestEndpoint, proposalID)
+	var proposal *cosmosgov.Proposal
+	err := c.historical(ctx, func(gov *cosmosgov.Client) error {
+		var err error
+		proposal, err = gov.GetProposal(ctx, proposalID)
+		return err
+	})
+	if err != nil {
+		return nil, wrapError(fmt.Sprintf("get proposal %d", proposalID), err)
+	}
 
-	// Make HTTP request
-	body, err := c.makeRequest(ctx, apiURL)
+	result := c.toProposal(*proposal)
+	return &result, nil
+}
+
+// CheckProposalStatus checks if a proposal is in voting period
+func (c *Client) CheckProposalStatus(ctx context.Context, proposalID uint64) (string, error) {
+	proposal, err := c.GetProposalDetails(ctx, proposalID)
+	if err != nil {
+		return "", err
+	}
+	return proposal.Status, nil
+}
+
+// GetTally fetches the current tally of a proposal, from the indexer when
+// configured and the LCD otherwise
+func (c *Client) GetTally(ctx context.Context, proposalID uint64) (*types.TallyResult, error) {
+	var tally *cosmosgov.TallyResult
+	var err error
+	if c.indexer != nil {
+		if tally, err = c.indexer.tally(ctx, proposalID); err != nil {
+			c.log().WithError(err).Warn("Indexer failed, falling back to LCD")
+		}
+	}
+	if tally == nil {
+		if tally, err = c.gov.GetTally(ctx, proposalID); err != nil {
+			c.observeSchemaError(err)
+			return nil, wrapError(fmt.Sprintf("get tally of proposal %d", proposalID), err)
+		}
+	}
+
+	return &types.TallyResult{
+		Yes:        tally.Yes,
+		Abstain:    tally.Abstain,
+		No:         tally.No,
+		NoWithVeto: tally.NoWithVeto,
+	}, nil
+}
+
+// GetDeposits fetches every deposit made on a proposal
+func (c *Client) GetDeposits(ctx context.Context, proposalID uint64) ([]types.Deposit, error) {
+	deposits, err := c.gov.GetAllDeposits(ctx, proposalID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch proposal %d: %w", proposalID, err)
+		return nil, wrapError(fmt.Sprintf("get deposits of proposal %d", proposalID), err)
 	}
 
-	// Parse response
-	var response struct {
-		Proposal CosmosProposal `json:"proposal"`
+	converted := make([]types.Deposit, 0, len(deposits))
+	for _, deposit := range deposits {
+		converted = append(converted, types.Deposit{Depositor: deposit.Depositor, Amount: toCoins(deposit.Amount)})
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	return converted, nil
+}
+
+// GetGovParams fetches the gov module parameters of the network
+func (c *Client) GetGovParams(ctx context.Context) (*types.GovParams, error) {
+	params, err := c.gov.GetParams(ctx)
+	if err != nil {
+		return nil, wrapError("get gov params", err)
 	}
 
-	proposal := response.Proposal
+	return &types.GovParams{
+		MinDeposit:       toCoins(params.MinDeposit),
+		VotingPeriod:     params.VotingPeriod,
+		MaxDepositPeriod: params.MaxDepositPeriod,
+		Quorum:           params.Quorum,
+		Threshold:        params.Threshold,
+		VetoThreshold:    params.VetoThreshold,
+	}, nil
+}
 
-	// Parse voting start time
-	votingStart, err := time.Parse(time.RFC3339, proposal.VotingStart)
+// GetStakingPool fetches the bonded and not bonded tokens of the network
+func (c *Client) GetStakingPool(ctx context.Context) (*types.StakingPool, error) {
+	pool, err := c.gov.GetStakingPool(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse voting start time: %w", err)
+		return nil, wrapError("get staking pool", err)
 	}
 
-	// Parse voting end time
-	votingEnd, err := time.Parse(time.RFC3339, proposal.VotingEnd)
+	return &types.StakingPool{
+		BondedTokens:    pool.BondedTokens,
+		NotBondedTokens: pool.NotBondedTokens,
+	}, nil
+}
+
+// GetBondedValidators fetches the validators of the active set, with the
+// account address each votes with
+func (c *Client) GetBondedValidators(ctx context.Context) ([]types.Validator, error) {
+	validators, err := c.gov.GetBondedValidators(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse voting end time: %w", err)
+		return nil, wrapError("get bonded validators", err)
 	}
 
+	converted := make([]types.Validator, 0, len(validators))
+	for _, validator := range validators {
+		account, err := cosmosgov.OperatorAccount(validator.OperatorAddress)
+		if err != nil {
+			continue
+		}
+		converted = append(converted, types.Validator{
+			OperatorAddress: validator.OperatorAddress,
+			Account:         account,
+			Moniker:         validator.Moniker,
+			Tokens:          validator.Tokens,
+		})
+	}
+	return converted, nil
+}
+
+// ClockSkew returns how far the endpoint's clock is ahead of the local clock
+// as of the last response
+func (c *Client) ClockSkew() time.Duration {
+	return c.transport.skew()
+}
+
+// toProposal converts a cosmosgov proposal into the service's proposal
+func (c *Client) toProposal(proposal cosmosgov.Proposal) types.Proposal {
 	// Get proposal title and description
 	title := proposal.Title
 	if title == "" {
-		title = fmt.Sprintf("Proposal %s", proposal.ID)
+		title = fmt.Sprintf("Proposal %d", proposal.ID)
 	}
 
 	description := proposal.Description
@@ -172,56 +362,105 @@ func (c *Client) GetProposalDetails(ctx context.Context, proposalID uint64) (*ty
 		description = "No description available"
 	}
 
-	// Convert ID to uint64
-	var id uint64
-	if _, err := fmt.Sscanf(proposal.ID, "%d", &id); err != nil {
-		return nil, fmt.Errorf("failed to parse proposal ID: %w", err)
+	messages := make([]string, 0, len(proposal.Messages))
+	messageTypes := make([]string, 0, len(proposal.Messages))
+	for _, msg := range proposal.Messages {
+		messages = append(messages, cosmosgov.Describe(msg))
+		typeURL := msg.TypeURL
+		if msg.Content != nil && msg.Content.TypeURL != "" {
+			typeURL = msg.Content.TypeURL
+		}
+		messageTypes = append(messageTypes, typeURL)
 	}
 
-	return &types.Proposal{
-		ID:          id,
-		Title:       title,
-		Description: description,
-		Status:      proposal.Status,
-		VotingStart: votingStart,
-		VotingEnd:   votingEnd,
-		Network:     c.config.Name,
-	}, nil
-}
+	var upgrade *types.UpgradePlan
+	if plan := proposal.UpgradePlan(); plan != nil {
+		upgrade = &types.UpgradePlan{Name: plan.Name, Height: plan.Height, Info: plan.Info}
+	}
 
-// CheckProposalStatus checks if a proposal is in voting period
-func (c *Client) CheckProposalStatus(ctx context.Context, proposalID uint64) (string, error) {
-	proposal, err := c.GetProposalDetails(ctx, proposalID)
-	if err != nil {
-		return "", err
+	var finalTally *types.TallyResult
+	if proposal.FinalTally != nil {
+		finalTally = &types.TallyResult{
+			Yes:        proposal.FinalTally.Yes,
+			Abstain:    proposal.FinalTally.Abstain,
+			No:         proposal.FinalTally.No,
+			NoWithVeto: proposal.FinalTally.NoWithVeto,
+		}
 	}
-	return proposal.Status, nil
-}
 
-// Helper function to make HTTP requests
-func (c *Client) makeRequest(ctx context.Context, url string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	return types.Proposal{
+		ID:           proposal.ID,
+		Title:        title,
+		Description:  description,
+		Status:       string(proposal.Status),
+		SubmitTime:   proposal.SubmitTime,
+		DepositEnd:   proposal.DepositEndTime,
+		VotingStart:  proposal.VotingStart,
+		VotingEnd:    proposal.VotingEnd,
+		Network:      c.config.Name,
+		Type:         proposal.Type(),
+		Category:     proposal.Category(),
+		References:   ExtractReferences(proposal.Title+"\n"+proposal.Description, proposal.ID),
+		Messages:     messages,
+		MessageTypes: messageTypes,
+		Metadata:     proposal.Metadata,
+		FinalTally:   finalTally,
+		TotalDeposit: toCoins(proposal.TotalDeposit),
+		Upgrade:      upgrade,
 	}
+}
 
-	req.Header.Set("User-Agent", "Governance-Alerts-Cosmos/1.0")
-	req.Header.Set("Accept", "application/json")
+// toCoins converts cosmosgov coins into the service's coins
+func toCoins(coins []cosmosgov.Coin) []types.Coin {
+	if len(coins) == 0 {
+		return nil
+	}
+	converted := make([]types.Coin, 0, len(coins))
+	for _, coin := range coins {
+		converted = append(converted, types.Coin{Denom: coin.Denom, Amount: coin.Amount})
+	}
+	return converted
+}
 
-	resp, err := c.client.Do(req)
+// HasVoted reports whether voter has voted on a proposal
+func (c *Client) HasVoted(ctx context.Context, proposalID uint64, voter string) (bool, error) {
+	_, err := c.gov.GetVote(ctx, proposalID, voter)
+	if cosmosgov.IsNoVote(err) {
+		return false, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		c.observeSchemaError(err)
+		return false, wrapError(fmt.Sprintf("get vote of %s on proposal %d", voter, proposalID), err)
 	}
-	defer resp.Body.Close()
+	return true, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// GetVote returns the vote of voter on a proposal, nil if voter has not
+// voted
+func (c *Client) GetVote(ctx context.Context, proposalID uint64, voter string) (*types.Vote, error) {
+	vote, err := c.gov.GetVote(ctx, proposalID, voter)
+	if cosmosgov.IsNoVote(err) {
+		return nil, nil
 	}
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		c.observeSchemaError(err)
+		return nil, wrapError(fmt.Sprintf("get vote of %s on proposal %d", voter, proposalID), err)
 	}
+	converted := toVote(*vote)
+	return &converted, nil
+}
 
-	return body, nil
+// GetVoteCount returns the number of votes cast on a proposal, from the
+// archive endpoints if the live endpoint pruned them
+func (c *Client) GetVoteCount(ctx context.Context, proposalID uint64) (uint64, error) {
+	var page cosmosgov.PageResponse
+	err := c.historical(ctx, func(gov *cosmosgov.Client) error {
+		var err error
+		_, page, err = gov.GetVotes(ctx, proposalID, cosmosgov.PageRequest{Limit: 1, CountTotal: true})
+		return err
+	})
+	if err != nil {
+		return 0, wrapError(fmt.Sprintf("count votes of proposal %d", proposalID), err)
+	}
+	return page.Total, nil
 }
@@ -0,0 +1,31 @@
+package governance
+
+import (
+	"net/http"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// endpointKind names the kind of endpoints a network is queried through
+func endpointKind(config types.NetworkConfig) string {
+	if config.Transport == types.TransportGRPC {
+		return "gRPC"
+	}
+	return "REST"
+}
+
+// newGRPCTransport returns the transport of a gRPC network, HTTP/2 only.
+// TLS endpoints (https://) negotiate HTTP/2, plaintext ones (http://)
+// speak it with prior knowledge
+func newGRPCTransport(base *http.Transport) *http.Transport {
+	transport := base.Clone()
+	transport.ForceAttemptHTTP2 = true
+	// Even with HTTP/2 disabled for LCD endpoints
+	transport.TLSNextProto = nil
+
+	var protocols http.Protocols
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	transport.Protocols = &protocols
+	return transport
+}
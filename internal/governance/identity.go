@@ -0,0 +1,88 @@
+package governance
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoTrustedEndpoint is returned when every endpoint of a network
+// reported another chain ID than the configured one
+var ErrNoTrustedEndpoint = errors.New("no endpoint reports the configured chain ID")
+
+// pinnedEndpointKey is the context key of the endpoint a request must be
+// sent to, bypassing failover
+type pinnedEndpointKey struct{}
+
+// EndpointIdentity is the chain ID an endpoint reported when verified. Err
+// is set when it could not be queried, which leaves its trust unchanged
+type EndpointIdentity struct {
+	Endpoint string
+	ChainID  string
+	Err      error
+}
+
+// Mismatch reports whether the endpoint serves another chain than expected
+func (i EndpointIdentity) Mismatch(expected string) bool {
+	return i.Err == nil && i.ChainID != expected
+}
+
+// VerifyChainID queries the latest block of every endpoint of the network
+// and compares the chain ID in its header with the configured one.
+// Endpoints reporting another chain, such as a provider that silently
+// switched a mainnet URL to a testnet backend, are no longer used until
+// they report the configured chain again
+func (c *Client) VerifyChainID(ctx context.Context) []EndpointIdentity {
+	identities := make([]EndpointIdentity, 0, len(c.transport.endpoints))
+	for _, ep := range c.transport.endpoints {
+		identity := EndpointIdentity{Endpoint: ep.url}
+		latest, err := c.gov.GetLatestBlock(context.WithValue(ctx, pinnedEndpointKey{}, ep))
+		switch {
+		case err != nil:
+			identity.Err = wrapError("get latest block", err)
+		case latest.ChainID == "":
+			identity.Err = wrapError("get latest block", errNoChainID)
+		default:
+			identity.ChainID = latest.ChainID
+			ep.setReportedChainID(latest.ChainID, c.config.ChainID)
+		}
+		identities = append(identities, identity)
+	}
+	c.verified.Store(true)
+	return identities
+}
+
+// ChainIDVerified reports whether the endpoints were verified since the
+// client was created
+func (c *Client) ChainIDVerified() bool {
+	return c.verified.Load()
+}
+
+// setReportedChainID records the chain ID the endpoint reported, distrusting
+// it when it is not the expected one
+func (e *endpoint) setReportedChainID(reported, expected string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.wrongChainID = ""
+	if reported != expected {
+		e.wrongChainID = reported
+	}
+}
+
+// trusted reports whether the endpoint serves the configured chain as far
+// as known
+func (e *endpoint) trusted() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.wrongChainID == ""
+}
+
+// trustedEndpoints returns the endpoints not known to serve another chain
+func trustedEndpoints(endpoints []*endpoint) []*endpoint {
+	trusted := make([]*endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep.trusted() {
+			trusted = append(trusted, ep)
+		}
+	}
+	return trusted
+}
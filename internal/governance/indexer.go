@@ -0,0 +1,144 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+	"github.com/sirupsen/logrus"
+)
+
+// Indexer types
+const (
+	IndexerREST    = "rest"
+	IndexerGraphQL = "graphql"
+)
+
+// indexerClient reads proposals and tallies from an indexer API instead of
+// the LCD. Responses use the LCD field names, either natively or through
+// GraphQL aliases, so they decode like LCD responses
+type indexerClient struct {
+	config     types.IndexerConfig
+	httpClient *http.Client
+}
+
+// isIndexerConfigured reports whether an indexer is configured
+func isIndexerConfigured(config types.IndexerConfig) bool {
+	return config.Type != ""
+}
+
+// proposals fetches the proposals listed by the indexer
+func (ix *indexerClient) proposals(ctx context.Context) ([]cosmosgov.Proposal, error) {
+	var response struct {
+		Proposals []json.RawMessage `json:"proposals"`
+	}
+	if err := ix.query(ctx, ix.config.ProposalsURL, ix.config.ProposalsQuery, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch proposals from indexer: %w", err)
+	}
+
+	proposals := make([]cosmosgov.Proposal, 0, len(response.Proposals))
+	for _, raw := range response.Proposals {
+		proposal, err := cosmosgov.ParseProposal(raw)
+		if err != nil {
+			logrus.WithField("indexer", ix.config.Type).WithError(err).Warn("Skipping invalid indexer proposal")
+			continue
+		}
+		proposals = append(proposals, *proposal)
+	}
+	return proposals, nil
+}
+
+// tally fetches the tally of a proposal from the indexer
+func (ix *indexerClient) tally(ctx context.Context, proposalID uint64) (*cosmosgov.TallyResult, error) {
+	id := strconv.FormatUint(proposalID, 10)
+	url := strings.ReplaceAll(ix.config.TallyURL, "{id}", id)
+
+	var response struct {
+		Tally json.RawMessage `json:"tally"`
+	}
+	if err := ix.query(ctx, url, ix.config.TallyQuery, map[string]interface{}{"id": id}, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch tally of proposal %d from indexer: %w", proposalID, err)
+	}
+	if len(response.Tally) == 0 || string(response.Tally) == "null" {
+		return nil, fmt.Errorf("indexer has no tally for proposal %d", proposalID)
+	}
+	return cosmosgov.ParseTally(response.Tally)
+}
+
+// query runs a REST GET on url or a GraphQL query against the indexer URL
+// and decodes the result into v
+func (ix *indexerClient) query(ctx context.Context, url, query string, variables map[string]interface{}, v interface{}) error {
+	var req *http.Request
+	var err error
+
+	switch ix.config.Type {
+	case IndexerGraphQL:
+		body, marshalErr := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+		if marshalErr != nil {
+			return fmt.Errorf("failed to encode query: %w", marshalErr)
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, ix.config.URL, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	default:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	for name, value := range ix.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := ix.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+
+	if ix.config.Type != IndexerGraphQL {
+		return json.Unmarshal(body, v)
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("graphql error: %s", envelope.Errors[0].Message)
+	}
+	return json.Unmarshal(envelope.Data, v)
+}
+
+// truncate shortens s to at most n bytes for error messages
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
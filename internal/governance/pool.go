@@ -0,0 +1,97 @@
+package governance
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"governance-alerts-cosmos/internal/metrics"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Connection pool defaults, used for the settings left at zero
+const (
+	defaultMaxIdleConns        = 256
+	defaultMaxIdleConnsPerHost = 32
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+)
+
+// Transport metrics
+const (
+	metricHTTPConnections = "governance_http_connections_total"
+	metricHTTPResponses   = "governance_http_responses_total"
+)
+
+func init() {
+	metrics.Register(metricHTTPConnections, "Connections used for LCD requests, by whether they were reused from the pool.", metrics.Counter)
+	metrics.Register(metricHTTPResponses, "LCD responses by protocol and whether they were gzip compressed.", metrics.Counter)
+}
+
+// sharedTransport is reused by every client so that networks served by the
+// same provider share pooled connections instead of dialing per network
+var sharedTransport = newSharedTransport(types.HTTPTransportConfig{})
+
+// ConfigureTransport applies the connection settings of performance.http
+// to the clients created afterwards
+func ConfigureTransport(config types.HTTPTransportConfig) {
+	sharedTransport = newSharedTransport(config)
+}
+
+// newSharedTransport returns the transport shared by clients. Responses are
+// asked gzip compressed and decompressed transparently unless disabled,
+// and HTTP/2 is negotiated with endpoints offering it over TLS
+func newSharedTransport(config types.HTTPTransportConfig) *http.Transport {
+	keepAlive := defaultKeepAlive
+	if config.KeepAliveSeconds > 0 {
+		keepAlive = time.Duration(config.KeepAliveSeconds) * time.Second
+	}
+	idleTimeout := defaultIdleConnTimeout
+	if config.IdleConnTimeoutSeconds > 0 {
+		idleTimeout = time.Duration(config.IdleConnTimeoutSeconds) * time.Second
+	}
+	maxIdle := defaultMaxIdleConns
+	if config.MaxIdleConns > 0 {
+		maxIdle = config.MaxIdleConns
+	}
+	maxIdlePerHost := defaultMaxIdleConnsPerHost
+	if config.MaxIdleConnsPerHost > 0 {
+		maxIdlePerHost = config.MaxIdleConnsPerHost
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: keepAlive}).DialContext,
+		MaxIdleConns:        maxIdle,
+		MaxIdleConnsPerHost: maxIdlePerHost,
+		MaxConnsPerHost:     config.MaxConnsPerHost,
+		IdleConnTimeout:     idleTimeout,
+		TLSHandshakeTimeout: 10 * time.Second,
+		ForceAttemptHTTP2:   !config.DisableHTTP2,
+		DisableCompression:  config.DisableCompression,
+	}
+	if config.DisableHTTP2 {
+		// An empty, non-nil map turns off HTTP/2 negotiation
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+// traceConnection records on reused whether the connection that sends req
+// came from the pool
+func traceConnection(req *http.Request, reused *atomic.Bool) *http.Request {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused.Store(info.Reused) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// observeTransport counts the connection and protocol of a response
+func (t *endpointTransport) observeTransport(resp *http.Response, reused bool) {
+	metrics.Add(metricHTTPConnections, metrics.Labels{"network": t.config.Name, "reused": strconv.FormatBool(reused)}, 1)
+	metrics.Add(metricHTTPResponses, metrics.Labels{"network": t.config.Name, "protocol": resp.Proto, "compressed": strconv.FormatBool(resp.Uncompressed)}, 1)
+}
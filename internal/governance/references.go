@@ -0,0 +1,58 @@
+package governance
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// referencePatterns match mentions of other proposals in proposal text, such
+// as "prop 120", "Proposal #120", "proposal no. 120" or explorer links. Bare
+// "#120" is not matched since it usually refers to GitHub issues
+var referencePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bprop(?:osal)?s?\.?\s*(?:#|no\.?\s*|number\s*|id\s*)?(\d{1,7})\b`),
+	regexp.MustCompile(`(?i)/proposals?/(\d{1,7})\b`),
+}
+
+// ExtractReferences returns the IDs of other proposals referenced in text,
+// sorted and without duplicates or the proposal's own ID
+func ExtractReferences(text string, self uint64) []uint64 {
+	seen := make(map[uint64]bool)
+	for _, pattern := range referencePatterns {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			id, err := strconv.ParseUint(match[1], 10, 64)
+			if err != nil || id == 0 || id == self {
+				continue
+			}
+			seen[id] = true
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil
+	}
+
+	refs := make([]uint64, 0, len(seen))
+	for id := range seen {
+		refs = append(refs, id)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i] < refs[j] })
+	return refs
+}
+
+// StatusLabel returns a human readable label for a proposal status, e.g.
+// PROPOSAL_STATUS_VOTING_PERIOD becomes "Voting Period"
+func StatusLabel(status string) string {
+	label := strings.TrimPrefix(status, "PROPOSAL_STATUS_")
+	if label == "" {
+		return "Unknown"
+	}
+	words := strings.Split(strings.ToLower(label), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,93 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Retry defaults, used for the settings left unset in the network's retry
+// configuration
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 500 * time.Millisecond
+	defaultMaxBackoff    = 30 * time.Second
+	maxRetryAfter        = 5 * time.Minute
+)
+
+// retryPolicy decides how often and after how long a request that failed
+// on every endpoint is sent again
+type retryPolicy struct {
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// newRetryPolicy returns the retry policy of a network
+func newRetryPolicy(cfg types.RetryConfig) retryPolicy {
+	policy := retryPolicy{attempts: defaultRetryAttempts, backoff: defaultRetryBackoff, maxBackoff: defaultMaxBackoff}
+	if cfg.Attempts > 0 {
+		policy.attempts = cfg.Attempts
+	}
+	if cfg.BackoffMS > 0 {
+		policy.backoff = time.Duration(cfg.BackoffMS) * time.Millisecond
+	}
+	if cfg.MaxBackoffSeconds > 0 {
+		policy.maxBackoff = time.Duration(cfg.MaxBackoffSeconds) * time.Second
+	}
+	return policy
+}
+
+// delay returns how long to wait before the next attempt: the backoff
+// doubled for every attempt made, capped and jittered between half and all
+// of it so clients polling the same node spread out. A longer Retry-After
+// from the node is honored
+func (p retryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	backoff := min(p.backoff<<min(attempt-1, 16), p.maxBackoff)
+	backoff = backoff/2 + rand.N(backoff/2+1)
+	if resp != nil {
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); retryAfter > backoff {
+			return min(retryAfter, maxRetryAfter)
+		}
+	}
+	return backoff
+}
+
+// budget returns the longest time a request may take with its retries over
+// n endpoints, used as the HTTP client timeout
+func (p retryPolicy) budget(n int) time.Duration {
+	return attemptTimeout*time.Duration(n*p.attempts) + p.maxBackoff*time.Duration(p.attempts-1)
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP
+// date, returning 0 when absent or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// shouldRetry reports whether a request that failed on every endpoint may
+// succeed later: rate limits, server errors and network errors. Other 4xx
+// answers are permanent, as is the caller giving up
+func shouldRetry(resp *http.Response, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrNoTrustedEndpoint) {
+		return false
+	}
+	if err == nil && resp.StatusCode == http.StatusRequestTimeout {
+		return true
+	}
+	return shouldFailover(resp, err)
+}
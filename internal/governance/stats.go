@@ -0,0 +1,144 @@
+package governance
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"governance-alerts-cosmos/internal/metrics"
+)
+
+// Endpoint metrics
+const (
+	metricEndpointRequests     = "governance_endpoint_requests_total"
+	metricEndpointSchemaErrors = "governance_endpoint_schema_errors_total"
+)
+
+// maxLatencySamples bounds the latencies kept per endpoint between resets
+const maxLatencySamples = 10000
+
+func init() {
+	metrics.Register(metricEndpointRequests, "Requests to LCD endpoints by result.", metrics.Counter)
+	metrics.Register(metricEndpointSchemaErrors, "LCD responses that could not be decoded.", metrics.Counter)
+}
+
+// EndpointStats summarizes the reliability of an endpoint since the last
+// reset
+type EndpointStats struct {
+	Network      string
+	Endpoint     string
+	Requests     int
+	Failures     int
+	SchemaErrors int
+	P95Latency   time.Duration
+}
+
+// SuccessRate returns the share of successful requests in percent, 100 when
+// there were no requests
+func (s EndpointStats) SuccessRate() float64 {
+	if s.Requests == 0 {
+		return 100
+	}
+	return float64(s.Requests-s.Failures) / float64(s.Requests) * 100
+}
+
+// endpointStats collects request outcomes of an endpoint
+type endpointStats struct {
+	mu           sync.Mutex
+	requests     int
+	failures     int
+	schemaErrors int
+	latencies    []time.Duration
+}
+
+// observe records the outcome of a request
+func (s *endpointStats) observe(labels metrics.Labels, resp *http.Response, err error, latency time.Duration) {
+	failed := err != nil || resp.StatusCode != http.StatusOK
+
+	s.mu.Lock()
+	s.requests++
+	if failed {
+		s.failures++
+	}
+	if len(s.latencies) < maxLatencySamples {
+		s.latencies = append(s.latencies, latency)
+	}
+	s.mu.Unlock()
+
+	result := "success"
+	if failed {
+		result = "failure"
+	}
+	metrics.Add(metricEndpointRequests, metrics.Labels{"network": labels["network"], "endpoint": labels["endpoint"], "result": result}, 1)
+}
+
+// observeSchemaErrors records responses that could not be decoded
+func (s *endpointStats) observeSchemaErrors(labels metrics.Labels, count int) {
+	s.mu.Lock()
+	s.schemaErrors += count
+	s.mu.Unlock()
+
+	metrics.Add(metricEndpointSchemaErrors, labels, float64(count))
+}
+
+// snapshot returns the stats collected so far, resetting them if reset is
+// set
+func (s *endpointStats) snapshot(reset bool) EndpointStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := EndpointStats{
+		Requests:     s.requests,
+		Failures:     s.failures,
+		SchemaErrors: s.schemaErrors,
+		P95Latency:   percentile(s.latencies, 0.95),
+	}
+	if reset {
+		s.requests, s.failures, s.schemaErrors = 0, 0, 0
+		s.latencies = s.latencies[:0]
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of durations
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
+
+// isSchemaError reports whether err comes from a response that did not
+// match the expected JSON structure
+func isSchemaError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// EndpointStats returns the reliability of the network's LCD endpoints
+// since the last reset, resetting the counters if reset is set
+func (c *Client) EndpointStats(reset bool) []EndpointStats {
+	stats := make([]EndpointStats, 0, len(c.transport.endpoints))
+	for _, ep := range c.transport.endpoints {
+		snapshot := ep.stats.snapshot(reset)
+		snapshot.Network = c.config.Name
+		snapshot.Endpoint = ep.url
+		stats = append(stats, snapshot)
+	}
+	return stats
+}
+
+// observeSchemaError records err against the endpoint that served the last
+// request if it is a schema error
+func (c *Client) observeSchemaError(err error) {
+	if isSchemaError(err) {
+		ep := c.transport.lastEndpoint()
+		ep.stats.observeSchemaErrors(c.transport.metricLabels(ep), 1)
+	}
+}
@@ -0,0 +1,115 @@
+package governance
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// blockHeightHeader is the header Cosmos SDK nodes use to report the height
+// a query was served at
+const blockHeightHeader = "X-Cosmos-Block-Height"
+
+// errHeightRegression is returned when a response comes from a node behind
+// the one that served the previous response
+var errHeightRegression = errors.New("block height regression")
+
+// heightTracker tracks the highest block height seen by a client
+type heightTracker struct {
+	mu   sync.Mutex
+	last int64
+}
+
+// observe records the height of a response and reports a regression if it
+// is lower than the last one seen
+func (h *heightTracker) observe(resp *http.Response) error {
+	height, ok := responseHeight(resp)
+	if !ok {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if height < h.last {
+		return fmt.Errorf("%w: got height %d after %d", errHeightRegression, height, h.last)
+	}
+	h.last = height
+	return nil
+}
+
+// pinnedHeightKey is the context key of the height the reads of a context
+// are pinned to
+type pinnedHeightKey struct{}
+
+// pinnedHeight is the block height the reads of a context are served at,
+// taken from the first response reporting one
+type pinnedHeight struct {
+	mu     sync.Mutex
+	height int64
+}
+
+// ConsistentReads returns a context whose reads are all served at the block
+// height of its first response, sent back in the X-Cosmos-Block-Height
+// header, when sticky.pin_height is set for the network. Whichever node of
+// a load balancer or endpoint answers then queries the same state, so one
+// check of a network never mixes heights
+func (c *Client) ConsistentReads(ctx context.Context) context.Context {
+	if !c.config.Sticky.PinHeight {
+		return ctx
+	}
+	return context.WithValue(ctx, pinnedHeightKey{}, &pinnedHeight{})
+}
+
+// pinnedHeightOf returns the height reads of ctx are pinned to, 0 before
+// the first response or when they are not pinned
+func pinnedHeightOf(ctx context.Context) int64 {
+	pin, ok := ctx.Value(pinnedHeightKey{}).(*pinnedHeight)
+	if !ok {
+		return 0
+	}
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+	return pin.height
+}
+
+// pinHeight pins the reads of ctx to the height of resp, if they are pinned
+// and not to a height yet
+func pinHeight(ctx context.Context, resp *http.Response) {
+	pin, ok := ctx.Value(pinnedHeightKey{}).(*pinnedHeight)
+	if !ok {
+		return
+	}
+	height, ok := responseHeight(resp)
+	if !ok {
+		return
+	}
+	pin.mu.Lock()
+	if pin.height == 0 {
+		pin.height = height
+	}
+	pin.mu.Unlock()
+}
+
+// responseHeight returns the block height a response was served at
+func responseHeight(resp *http.Response) (int64, bool) {
+	height, err := strconv.ParseInt(resp.Header.Get(blockHeightHeader), 10, 64)
+	if err != nil || height <= 0 {
+		return 0, false
+	}
+	return height, true
+}
+
+// newSessionID generates a random session ID for sticky routing
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "governance-alerts-cosmos"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,86 @@
+package governance
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// isTLSConfigured reports whether a network needs its own TLS settings
+func isTLSConfigured(config types.TLSConfig) bool {
+	return config != (types.TLSConfig{})
+}
+
+// newTLSTransport returns a copy of the shared transport using the network's
+// custom CA and client certificate
+func newTLSTransport(config types.TLSConfig) (*http.Transport, error) {
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := sharedTransport.Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// buildTLSConfig builds a tls.Config from file paths or inline PEM
+func buildTLSConfig(config types.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: config.ServerName,
+	}
+
+	// Load custom CA, added on top of the system roots
+	caPEM, err := loadPEM(config.CAPEM, config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA: %w", err)
+	}
+	if caPEM != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in CA")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// Load client certificate for mutual TLS
+	certPEM, err := loadPEM(config.CertPEM, config.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	keyPEM, err := loadPEM(config.KeyPEM, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client key: %w", err)
+	}
+	if (certPEM == nil) != (keyPEM == nil) {
+		return nil, fmt.Errorf("client certificate and key must be configured together")
+	}
+	if certPEM != nil {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// loadPEM returns inline PEM if set, otherwise the contents of the file
+func loadPEM(inline, path string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,213 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"governance-alerts-cosmos/internal/metrics"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// endpointTransport applies a network's request settings (sticky routing,
+// auth), fails over between its endpoints and observes their responses
+// (clock skew, block height)
+type endpointTransport struct {
+	base        http.RoundTripper
+	config      types.NetworkConfig
+	stickyValue string
+	endpoints   []*endpoint
+	retry       retryPolicy
+	skewMu      sync.Mutex
+	clockSkew   time.Duration
+	lastMu      sync.Mutex
+	last        *endpoint
+}
+
+// RoundTrip implements http.RoundTripper. Requests that fail on every
+// endpoint with a retryable error are sent again after a backoff, until
+// the retry policy's attempts are used or the request's deadline would
+// pass while waiting
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.failover(req)
+		if attempt >= t.retry.attempts || !shouldRetry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := t.retry.delay(attempt, resp)
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+		if err == nil {
+			err = fmt.Errorf("status %d", resp.StatusCode)
+			resp.Body.Close()
+		}
+		logrus.WithFields(logrus.Fields{"network": t.config.Name, "chain_id": t.config.ChainID, "attempt": attempt, "wait": wait.Round(time.Millisecond).String()}).
+			WithError(err).Warn("Request failed on every endpoint, retrying")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// failover sends a request built against the primary endpoint to the
+// healthiest one, moving on to the next on errors, timeouts and server
+// errors. Endpoints serving another chain are skipped, and requests pinned
+// to an endpoint are only sent there
+func (t *endpointTransport) failover(req *http.Request) (*http.Response, error) {
+	primary := t.endpoints[0].url
+	ordered := orderEndpoints(trustedEndpoints(t.endpoints), time.Now())
+	if pinned, ok := req.Context().Value(pinnedEndpointKey{}).(*endpoint); ok {
+		ordered = []*endpoint{pinned}
+	}
+	if len(ordered) == 0 {
+		return nil, fmt.Errorf("%s: %w", t.config.Name, ErrNoTrustedEndpoint)
+	}
+
+	for i, ep := range ordered {
+		attempt, err := rewriteEndpoint(req, primary, ep.url)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := t.attempt(attempt, ep)
+		if errors.Is(err, errHeightRegression) {
+			// Retry once, the balancer may route us back to an up-to-date node
+			resp, err = t.attempt(attempt, ep)
+		}
+		ep.stats.observe(t.metricLabels(ep), resp, err, time.Since(start))
+
+		t.lastMu.Lock()
+		t.last = ep
+		t.lastMu.Unlock()
+
+		if !shouldFailover(resp, err) {
+			ep.markHealthy()
+			return resp, nil
+		}
+		ep.markFailed(time.Now())
+
+		if i == len(ordered)-1 || req.Context().Err() != nil {
+			return resp, err
+		}
+		if err == nil {
+			err = fmt.Errorf("status %d", resp.StatusCode)
+			resp.Body.Close()
+		}
+		logrus.WithFields(logrus.Fields{"network": t.config.Name, "chain_id": t.config.ChainID, "endpoint": ep.url, "next": ordered[i+1].url}).
+			WithError(err).Warn("Endpoint failed, failing over")
+	}
+	return nil, fmt.Errorf("no endpoint configured for %s", t.config.Name)
+}
+
+// attempt sends a request to an endpoint with its own timeout, so a hung
+// endpoint leaves time to try the others
+func (t *endpointTransport) attempt(req *http.Request, ep *endpoint) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), attemptTimeout)
+	resp, err := t.roundTrip(req.WithContext(ctx), ep)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// metricLabels returns the labels of an endpoint's metrics
+func (t *endpointTransport) metricLabels(ep *endpoint) metrics.Labels {
+	return metrics.Labels{"network": t.config.Name, "endpoint": ep.url}
+}
+
+// lastEndpoint returns the endpoint that served the last request
+func (t *endpointTransport) lastEndpoint() *endpoint {
+	t.lastMu.Lock()
+	defer t.lastMu.Unlock()
+	if t.last == nil {
+		return t.endpoints[0]
+	}
+	return t.last
+}
+
+// roundTrip sends a single request
+func (t *endpointTransport) roundTrip(req *http.Request, ep *endpoint) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request. gRPC requests
+	// have a body, replayed for each endpoint tried
+	req = req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+	if t.config.Sticky.Header != "" {
+		req.Header.Set(t.config.Sticky.Header, t.stickyValue)
+	}
+	pinned := pinnedHeightOf(req.Context())
+	if pinned > 0 {
+		req.Header.Set(blockHeightHeader, strconv.FormatInt(pinned, 10))
+	}
+	applyAuth(req, t.config.Auth)
+
+	var reused atomic.Bool
+	resp, err := t.base.RoundTrip(traceConnection(req, &reused))
+	if err != nil {
+		return nil, err
+	}
+
+	t.observeClock(resp)
+	t.observeTransport(resp, reused.Load())
+
+	// Nodes that have not reached the pinned height reject the query as
+	// invalid, like a node behind the previous response
+	if pinned > 0 && resp.StatusCode == http.StatusBadRequest {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: pinned height %d not served", errHeightRegression, pinned)
+	}
+	if resp.StatusCode == http.StatusOK {
+		pinHeight(req.Context(), resp)
+	}
+
+	if t.config.Sticky.RejectHeightRegression && resp.StatusCode == http.StatusOK {
+		if err := ep.heights.observe(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// observeClock records the difference between the endpoint's Date header
+// and the local clock
+func (t *endpointTransport) observeClock(resp *http.Response) {
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	t.skewMu.Lock()
+	t.clockSkew = time.Until(serverTime)
+	t.skewMu.Unlock()
+}
+
+// skew returns the last observed clock skew
+func (t *endpointTransport) skew() time.Duration {
+	t.skewMu.Lock()
+	defer t.skewMu.Unlock()
+	return t.clockSkew
+}
@@ -0,0 +1,92 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// errNoChainID is returned for block headers without a chain ID
+var errNoChainID = errors.New("latest block has no chain ID")
+
+// blockTimeSample is how many blocks back the average block time used for
+// upgrade estimates is measured over
+const blockTimeSample = 1000
+
+// ScheduledUpgrade returns the upgrade scheduled on the network with its
+// estimated time, nil if none is. The estimate extrapolates the average
+// block time of the last blocks, so it shifts as block times change
+func (c *Client) ScheduledUpgrade(ctx context.Context) (*types.UpgradeEstimate, error) {
+	plan, err := c.gov.GetCurrentPlan(ctx)
+	if err != nil || plan == nil {
+		return nil, wrapError("get upgrade plan", err)
+	}
+
+	estimate, err := c.EstimateHeight(ctx, plan.Height)
+	if err != nil {
+		return nil, err
+	}
+	estimate.Name = plan.Name
+	return estimate, nil
+}
+
+// EstimateHeight returns when the network is expected to reach height,
+// extrapolating the average block time of the last blocks. Heights already
+// reached are estimated at the time of the latest block
+func (c *Client) EstimateHeight(ctx context.Context, height int64) (*types.UpgradeEstimate, error) {
+	latest, err := c.gov.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, wrapError("get latest block", err)
+	}
+
+	estimate := &types.UpgradeEstimate{
+		Network: c.config.Name,
+		ChainID: c.config.ChainID,
+		Height:  height,
+		Time:    latest.Time,
+	}
+	if height <= latest.Height {
+		return estimate, nil
+	}
+
+	sampleHeight := latest.Height - blockTimeSample
+	if sampleHeight < 1 {
+		sampleHeight = 1
+	}
+	sample, err := c.gov.GetBlock(ctx, sampleHeight)
+	if err != nil {
+		return nil, wrapError(fmt.Sprintf("get block %d", sampleHeight), err)
+	}
+	if sample.Height >= latest.Height {
+		return nil, fmt.Errorf("not enough blocks to estimate block time")
+	}
+
+	estimate.BlockTime = latest.Time.Sub(sample.Time) / time.Duration(latest.Height-sample.Height)
+	estimate.Time = latest.Time.Add(estimate.BlockTime * time.Duration(height-latest.Height))
+	return estimate, nil
+}
+
+// LatestBlock returns the height and time of the latest block of the
+// network
+func (c *Client) LatestBlock(ctx context.Context) (int64, time.Time, error) {
+	latest, err := c.gov.GetLatestBlock(ctx)
+	if err != nil {
+		return 0, time.Time{}, wrapError("get latest block", err)
+	}
+	return latest.Height, latest.Time, nil
+}
+
+// ChainID returns the chain ID the node reports in its latest block header
+func (c *Client) ChainID(ctx context.Context) (string, error) {
+	latest, err := c.gov.GetLatestBlock(ctx)
+	if err != nil {
+		return "", wrapError("get latest block", err)
+	}
+	if latest.ChainID == "" {
+		return "", wrapError("get latest block", errNoChainID)
+	}
+	return latest.ChainID, nil
+}
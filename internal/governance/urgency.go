@@ -0,0 +1,120 @@
+package governance
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// Urgency is what proposals are ordered by in lists, digests and the
+// dashboard
+type Urgency struct {
+	Network  string
+	ID       uint64
+	Deadline time.Time
+	Severity string
+}
+
+// ProposalUrgency returns the urgency of a proposal of a network, with the
+// severity of its voting end alert under policies
+func ProposalUrgency(network string, chainID string, proposal types.Proposal, policies []types.SeverityPolicy) Urgency {
+	return Urgency{
+		Network:  network,
+		ID:       proposal.ID,
+		Deadline: Deadline(proposal),
+		Severity: Severity(policies, chainID, proposal, types.AlertVotingEnd),
+	}
+}
+
+// Deadline returns the next governance deadline of a proposal on its
+// chain: the end of the deposit period while it collects deposits, the end
+// of voting otherwise
+func Deadline(proposal types.Proposal) time.Time {
+	if proposal.Status == string(cosmosgov.StatusDepositPeriod) {
+		return proposal.DepositEnd
+	}
+	return proposal.VotingEnd
+}
+
+// Severity returns the severity of an alert of alertType about a proposal
+// of the chain with chainID from the first matching policy, info if none
+// matches. Alerts carry no severity when there are no policies
+func Severity(policies []types.SeverityPolicy, chainID string, proposal types.Proposal, alertType string) string {
+	if len(policies) == 0 {
+		return ""
+	}
+
+	for _, policy := range policies {
+		if len(policy.ChainIDs) > 0 && !slices.Contains(policy.ChainIDs, chainID) {
+			continue
+		}
+		if !MatchesProposalType(policy.Types, proposal) {
+			continue
+		}
+		if len(policy.AlertTypes) > 0 && !slices.Contains(policy.AlertTypes, alertType) {
+			continue
+		}
+		return policy.Severity
+	}
+	return types.SeverityInfo
+}
+
+// MoreUrgent reports whether a comes before b when sorting by the keys of
+// sortBy in turn. Soonest deadlines and the most severe come first, unknown
+// deadlines last. Network and ID break the remaining ties so the order is
+// the same on every run
+func MoreUrgent(sortBy []string, a, b Urgency) bool {
+	for _, key := range sortBy {
+		if c := compareUrgency(key, a, b); c != 0 {
+			return c < 0
+		}
+	}
+	if c := compareUrgency(types.SortNetwork, a, b); c != 0 {
+		return c < 0
+	}
+	return compareUrgency(types.SortID, a, b) < 0
+}
+
+// compareUrgency compares a and b by one sort key, negative when a comes
+// first
+func compareUrgency(key string, a, b Urgency) int {
+	switch key {
+	case types.SortDeadline:
+		switch {
+		case a.Deadline.Equal(b.Deadline):
+			return 0
+		case a.Deadline.IsZero():
+			return 1
+		case b.Deadline.IsZero():
+			return -1
+		}
+		return a.Deadline.Compare(b.Deadline)
+	case types.SortSeverity:
+		return severityRank(b.Severity) - severityRank(a.Severity)
+	case types.SortNetwork:
+		return strings.Compare(a.Network, b.Network)
+	case types.SortID:
+		switch {
+		case a.ID < b.ID:
+			return -1
+		case a.ID > b.ID:
+			return 1
+		}
+	}
+	return 0
+}
+
+// severityRank ranks severities from least to most severe, alerts without
+// a severity lowest
+func severityRank(severity string) int {
+	for i, s := range types.Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
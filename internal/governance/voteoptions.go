@@ -0,0 +1,59 @@
+package governance
+
+import (
+	"governance-alerts-cosmos/internal/types"
+)
+
+// defaultVoteLabels are the names of vote options on chains that do not
+// rename them
+var defaultVoteLabels = map[string]string{
+	types.VoteYes:        "Yes",
+	types.VoteNo:         "No",
+	types.VoteAbstain:    "Abstain",
+	types.VoteNoWithVeto: "No With Veto",
+}
+
+// TallyOptions returns the vote options of tally that are valid on the
+// network, in display order and named as the chain names them. Without
+// configured vote_options, options the chain leaves out of its tally
+// responses (e.g. no_with_veto on chains that dropped it) are left out
+func TallyOptions(networkConfig types.NetworkConfig, tally *types.TallyResult) []types.TallyOption {
+	amounts := map[string]string{
+		types.VoteYes:        tally.Yes,
+		types.VoteNo:         tally.No,
+		types.VoteAbstain:    tally.Abstain,
+		types.VoteNoWithVeto: tally.NoWithVeto,
+	}
+
+	valid := networkConfig.VoteOptions
+	if len(valid) == 0 {
+		valid = make([]string, 0, len(types.VoteOptions))
+		for _, option := range types.VoteOptions {
+			if amounts[option] != "" {
+				valid = append(valid, option)
+			}
+		}
+		// An empty tally says nothing about the options of the chain
+		if len(valid) == 0 {
+			valid = types.VoteOptions
+		}
+	}
+
+	options := make([]types.TallyOption, 0, len(valid))
+	for _, option := range valid {
+		options = append(options, types.TallyOption{
+			Option: option,
+			Label:  VoteLabel(networkConfig, option),
+			Amount: amounts[option],
+		})
+	}
+	return options
+}
+
+// VoteLabel returns the name of a vote option on the network
+func VoteLabel(networkConfig types.NetworkConfig, option string) string {
+	if label := networkConfig.VoteOptionLabels[option]; label != "" {
+		return label
+	}
+	return defaultVoteLabels[option]
+}
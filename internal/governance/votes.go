@@ -0,0 +1,134 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// votePageSize is the number of votes fetched per request
+const votePageSize = 100
+
+// VoteTracker follows the votes of proposals across check cycles and
+// reports only votes that are new or changed since the previous sync.
+//
+// The LCD orders votes by voter address, not by time, so new votes can land
+// on any page. A sweep is only started when the vote count changed and is
+// limited to maxPages pages per sync, resuming from the saved page key on the
+// next sync, so large proposals are not re-downloaded every cycle. Votes
+// changed without changing the count are picked up by the next sweep
+type VoteTracker struct {
+	client    *Client
+	maxPages  int
+	mu        sync.Mutex
+	proposals map[uint64]*voteState
+}
+
+// voteState is the sync progress of one proposal
+type voteState struct {
+	mu       sync.Mutex
+	seen     map[string]string
+	total    uint64
+	sweepKey string
+	sweeping bool
+	target   uint64
+}
+
+// NewVoteTracker creates a vote tracker fetching at most maxPages pages per
+// sync, 0 meaning no limit
+func NewVoteTracker(client *Client, maxPages int) *VoteTracker {
+	return &VoteTracker{
+		client:    client,
+		maxPages:  maxPages,
+		proposals: make(map[uint64]*voteState),
+	}
+}
+
+// Sync fetches the votes of a proposal cast or changed since the last sync.
+// The first complete sweep reports every vote
+func (t *VoteTracker) Sync(ctx context.Context, proposalID uint64) ([]types.Vote, error) {
+	t.mu.Lock()
+	state, ok := t.proposals[proposalID]
+	if !ok {
+		state = &voteState{seen: make(map[string]string)}
+		t.proposals[proposalID] = state
+	}
+	t.mu.Unlock()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.sweeping {
+		total, err := t.client.GetVoteCount(ctx, proposalID)
+		if err != nil {
+			return nil, err
+		}
+		if ok && total == state.total {
+			return nil, nil
+		}
+		state.sweeping = true
+		state.sweepKey = ""
+		state.target = total
+	}
+
+	it := t.client.gov.Votes(proposalID, votePageSize, state.sweepKey)
+	var changed []types.Vote
+	for pages := 0; !it.Done() && (t.maxPages == 0 || pages < t.maxPages); pages++ {
+		votes, err := it.Next(ctx)
+		if err != nil {
+			// Keep what was fetched, the sweep resumes from the last page
+			return changed, wrapError(fmt.Sprintf("sync votes of proposal %d", proposalID), err)
+		}
+		state.sweepKey = it.Key()
+
+		for _, vote := range votes {
+			fingerprint := voteFingerprint(vote)
+			if state.seen[vote.Voter] == fingerprint {
+				continue
+			}
+			state.seen[vote.Voter] = fingerprint
+			changed = append(changed, toVote(vote))
+		}
+	}
+
+	if it.Done() {
+		state.sweeping = false
+		state.total = state.target
+	}
+
+	return changed, nil
+}
+
+// Forget drops the state of a proposal that is no longer tracked
+func (t *VoteTracker) Forget(proposalID uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.proposals, proposalID)
+}
+
+// voteFingerprint identifies the options of a vote to detect changed votes
+func voteFingerprint(vote cosmosgov.Vote) string {
+	parts := make([]string, 0, len(vote.Options))
+	for _, option := range vote.Options {
+		parts = append(parts, option.Option+"="+option.Weight)
+	}
+	return strings.Join(parts, ",")
+}
+
+// toVote converts a cosmosgov vote into the service's vote
+func toVote(vote cosmosgov.Vote) types.Vote {
+	options := make([]types.VoteOption, 0, len(vote.Options))
+	for _, option := range vote.Options {
+		options = append(options, types.VoteOption{Option: option.Option, Weight: option.Weight})
+	}
+	return types.Vote{
+		ProposalID: vote.ProposalID,
+		Voter:      vote.Voter,
+		Options:    options,
+	}
+}
@@ -0,0 +1,120 @@
+// Package incidents reads the active incidents of a chain from its status
+// page, either a Statuspage JSON API or an RSS feed
+package incidents
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/outbound"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Feed formats
+const (
+	// FormatStatuspage is the Statuspage API, such as
+	// https://status.example.com/api/v2/incidents/unresolved.json
+	FormatStatuspage = "statuspage"
+	// FormatRSS is an RSS feed of incidents, such as
+	// https://status.example.com/history.rss
+	FormatRSS = "rss"
+)
+
+// Formats lists the supported feed formats
+var Formats = []string{FormatStatuspage, FormatRSS}
+
+// rssWindow is how long an RSS item is considered active when the feed
+// does not say it was resolved, as RSS has no incident status
+const rssWindow = 24 * time.Hour
+
+// statusPages is the dependency feeds are fetched through. Networks
+// sharing a status page within a check get the same response
+var statusPages = outbound.Register("status_pages", outbound.Options{
+	Timeout:     10 * time.Second,
+	MinInterval: time.Second,
+	CacheTTL:    time.Minute,
+})
+
+// Fetch returns the incidents of a feed that are not resolved. An empty
+// format is guessed from the response
+func Fetch(ctx context.Context, feedURL, format string, now time.Time) ([]types.ChainIncident, error) {
+	body, err := statusPages.Get(ctx, feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch incidents feed: %w", err)
+	}
+
+	if format == "" {
+		format = FormatStatuspage
+		if strings.HasPrefix(strings.TrimSpace(string(body)), "<") {
+			format = FormatRSS
+		}
+	}
+	switch format {
+	case FormatStatuspage:
+		return parseStatuspage(body)
+	case FormatRSS:
+		return parseRSS(body, now)
+	default:
+		return nil, fmt.Errorf("unknown incidents feed format %q", format)
+	}
+}
+
+// parseStatuspage parses a Statuspage incidents response, keeping those
+// not resolved or postmortem
+func parseStatuspage(body []byte) ([]types.ChainIncident, error) {
+	var page struct {
+		Incidents []struct {
+			Name      string `json:"name"`
+			Status    string `json:"status"`
+			Shortlink string `json:"shortlink"`
+		} `json:"incidents"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("failed to decode statuspage incidents: %w", err)
+	}
+
+	var active []types.ChainIncident
+	for _, incident := range page.Incidents {
+		if incident.Status == "resolved" || incident.Status == "postmortem" {
+			continue
+		}
+		active = append(active, types.ChainIncident{Name: incident.Name, Status: incident.Status, URL: incident.Shortlink})
+	}
+	return active, nil
+}
+
+// parseRSS parses an RSS feed of incidents, keeping the items published in
+// the last day that do not say they were resolved
+func parseRSS(body []byte, now time.Time) ([]types.ChainIncident, error) {
+	var feed struct {
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("failed to decode incidents RSS: %w", err)
+	}
+
+	var active []types.ChainIncident
+	for _, item := range feed.Items {
+		published, err := time.Parse(time.RFC1123Z, item.PubDate)
+		if err != nil {
+			published, err = time.Parse(time.RFC1123, item.PubDate)
+		}
+		if err != nil || now.Sub(published) > rssWindow {
+			continue
+		}
+		if strings.Contains(strings.ToLower(item.Title+" "+item.Description), "resolved") {
+			continue
+		}
+		active = append(active, types.ChainIncident{Name: strings.TrimSpace(item.Title), Status: "reported", URL: strings.TrimSpace(item.Link)})
+	}
+	return active, nil
+}
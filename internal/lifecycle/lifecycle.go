@@ -0,0 +1,286 @@
+// Package lifecycle starts the components of the service in dependency
+// order and stops them in reverse, each within its own timeout
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultStopTimeout bounds how long a component may take to stop when it
+// sets no timeout of its own
+const DefaultStopTimeout = 5 * time.Second
+
+// Component states
+const (
+	StatePending  = "pending"
+	StateRunning  = "running"
+	StateStopping = "stopping"
+	StateStopped  = "stopped"
+	StateFailed   = "failed"
+	StateTimedOut = "timed_out"
+)
+
+// Component is a long-running part of the service, such as a server or
+// the monitoring loop
+type Component struct {
+	Name string
+	// DependsOn names the components started before this one and stopped
+	// after it
+	DependsOn []string
+	// Run runs the component until ctx is done. Returning before that, even
+	// without an error, marks the component failed
+	Run func(ctx context.Context) error
+	// Stop, when set, is called before ctx of Run is cancelled to stop
+	// gracefully, such as draining an HTTP server. Its ctx expires with
+	// the stop timeout
+	Stop func(ctx context.Context) error
+	// StopTimeout is how long stopping may take, DefaultStopTimeout if 0
+	StopTimeout time.Duration
+}
+
+// Status is the state of a component, with the error it failed with
+type Status struct {
+	Name  string    `json:"name"`
+	State string    `json:"state"`
+	Since time.Time `json:"since"`
+	Error string    `json:"error,omitempty"`
+}
+
+// Manager starts and stops components
+type Manager struct {
+	mu         sync.Mutex
+	components []*component
+	byName     map[string]*component
+	started    bool
+}
+
+// component is a component with its running state
+type component struct {
+	Component
+	state  string
+	since  time.Time
+	err    error
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewManager creates a manager without components
+func NewManager() *Manager {
+	return &Manager{byName: make(map[string]*component)}
+}
+
+// Add adds a component. Components must be added before Start, under a
+// unique name
+func (m *Manager) Add(c Component) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started {
+		return fmt.Errorf("component %s added after start", c.Name)
+	}
+	if c.Name == "" || c.Run == nil {
+		return errors.New("component needs a name and a run function")
+	}
+	if _, ok := m.byName[c.Name]; ok {
+		return fmt.Errorf("duplicate component %s", c.Name)
+	}
+	if c.StopTimeout <= 0 {
+		c.StopTimeout = DefaultStopTimeout
+	}
+	comp := &component{Component: c, state: StatePending, since: time.Now()}
+	m.components = append(m.components, comp)
+	m.byName[c.Name] = comp
+	return nil
+}
+
+// Start starts every component after the components it depends on, in the
+// order they were added otherwise. It fails without starting anything when
+// a dependency is unknown or circular. Components run until Stop, or until
+// ctx is done
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started {
+		return errors.New("already started")
+	}
+	ordered, err := m.order()
+	if err != nil {
+		return err
+	}
+	m.components = ordered
+	m.started = true
+
+	for _, c := range m.components {
+		runCtx, cancel := context.WithCancel(ctx)
+		c.cancel = cancel
+		c.done = make(chan struct{})
+		m.setState(c, StateRunning, nil)
+		componentLog(c.Name).Debug("Component started")
+		go m.run(runCtx, c)
+	}
+	return nil
+}
+
+// order sorts the components so each comes after its dependencies
+func (m *Manager) order() ([]*component, error) {
+	for _, c := range m.components {
+		for _, dependency := range c.DependsOn {
+			if _, ok := m.byName[dependency]; !ok {
+				return nil, fmt.Errorf("component %s depends on unknown component %s", c.Name, dependency)
+			}
+		}
+	}
+
+	placed := make(map[string]bool, len(m.components))
+	ordered := make([]*component, 0, len(m.components))
+	for len(ordered) < len(m.components) {
+		progress := false
+		for _, c := range m.components {
+			if placed[c.Name] || !allPlaced(placed, c.DependsOn) {
+				continue
+			}
+			placed[c.Name] = true
+			ordered = append(ordered, c)
+			progress = true
+		}
+		if !progress {
+			var cycle []string
+			for _, c := range m.components {
+				if !placed[c.Name] {
+					cycle = append(cycle, c.Name)
+				}
+			}
+			return nil, fmt.Errorf("circular dependency between components %s", strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// allPlaced reports whether every name is placed
+func allPlaced(placed map[string]bool, names []string) bool {
+	for _, name := range names {
+		if !placed[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// run runs a component, marking it failed if it returns before stopping
+func (m *Manager) run(ctx context.Context, c *component) {
+	defer close(c.done)
+	err := c.Run(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c.state != StateRunning {
+		return
+	}
+	// The context of every component ends with the manager's
+	if ctx.Err() != nil {
+		m.setState(c, StateStopped, nil)
+		return
+	}
+	if err == nil {
+		err = errors.New("exited unexpectedly")
+	}
+	m.setState(c, StateFailed, err)
+	componentLog(c.Name).WithError(err).Error("Component failed")
+}
+
+// Stop stops the components in reverse start order, each after the
+// components depending on it and within its stop timeout. A component
+// that does not stop in time is left behind and reported in the error
+func (m *Manager) Stop() error {
+	m.mu.Lock()
+	components := make([]*component, len(m.components))
+	copy(components, m.components)
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		if err := m.stop(components[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stop stops one component
+func (m *Manager) stop(c *component) error {
+	m.mu.Lock()
+	if c.done == nil || c.state == StateStopped || c.state == StateTimedOut {
+		m.mu.Unlock()
+		return nil
+	}
+	failed := c.state == StateFailed
+	if !failed {
+		m.setState(c, StateStopping, nil)
+	}
+	m.mu.Unlock()
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), c.StopTimeout)
+	defer cancel()
+	if c.Stop != nil && !failed {
+		if err := c.Stop(ctx); err != nil {
+			componentLog(c.Name).WithError(err).Warn("Component did not stop gracefully")
+		}
+	}
+	c.cancel()
+
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		m.mu.Lock()
+		m.setState(c, StateTimedOut, nil)
+		m.mu.Unlock()
+		componentLog(c.Name).WithField("timeout", c.StopTimeout).Warn("Component did not stop in time, leaving it behind")
+		return fmt.Errorf("component %s did not stop within %s", c.Name, c.StopTimeout)
+	}
+
+	if !failed {
+		m.mu.Lock()
+		m.setState(c, StateStopped, nil)
+		m.mu.Unlock()
+	}
+	componentLog(c.Name).WithField("duration", time.Since(start).Round(time.Millisecond)).Debug("Component stopped")
+	return nil
+}
+
+// setState records the state of a component. The caller holds m.mu
+func (m *Manager) setState(c *component, state string, err error) {
+	c.state = state
+	c.since = time.Now()
+	c.err = err
+}
+
+// Statuses returns the state of every component, in start order once
+// started
+func (m *Manager) Statuses() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]Status, 0, len(m.components))
+	for _, c := range m.components {
+		status := Status{Name: c.Name, State: c.state, Since: c.since}
+		if c.err != nil {
+			status.Error = c.err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// componentLog returns a log entry about a component
+func componentLog(name string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{"component": name, "event": "service"})
+}
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metric types
+const (
+	Gauge   = "gauge"
+	Counter = "counter"
+)
+
+// family is a named metric with its samples keyed by label set
+type family struct {
+	help       string
+	metricType string
+	samples    map[string]float64
+}
+
+// registry holds all metrics of the process
+var registry = struct {
+	sync.Mutex
+	families map[string]*family
+}{families: make(map[string]*family)}
+
+// Labels are the labels of a sample
+type Labels map[string]string
+
+// Register declares a metric with its help text and type. Registering an
+// existing metric is a no-op
+func Register(name, help, metricType string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.families[name]; !ok {
+		registry.families[name] = &family{help: help, metricType: metricType, samples: make(map[string]float64)}
+	}
+}
+
+// Set sets a gauge sample
+func Set(name string, labels Labels, value float64) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if f, ok := registry.families[name]; ok {
+		f.samples[labels.String()] = value
+	}
+}
+
+// Add adds to a counter sample
+func Add(name string, labels Labels, delta float64) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if f, ok := registry.families[name]; ok {
+		f.samples[labels.String()] += delta
+	}
+}
+
+// String renders labels in Prometheus exposition format
+func (l Labels) String() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(l[k])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, k, value))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// WritePrometheus writes all metrics in Prometheus text exposition format
+func WritePrometheus(w io.Writer) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	names := make([]string, 0, len(registry.families))
+	for name := range registry.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := registry.families[name]
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.metricType); err != nil {
+			return err
+		}
+
+		labelSets := make([]string, 0, len(f.samples))
+		for labels := range f.samples {
+			labelSets = append(labelSets, labels)
+		}
+		sort.Strings(labelSets)
+
+		for _, labels := range labelSets {
+			if _, err := fmt.Fprintf(w, "%s%s %g\n", name, labels, f.samples[labels]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteTextfile atomically writes all metrics to a file for the node
+// exporter textfile collector
+func WriteTextfile(path string) error {
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".metrics-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// maxOverflowLines caps how many collapsed alerts an overflow summary lists
+const maxOverflowLines = 15
+
+// cycleBatch counts the proposal alerts sent to each chat channel during a
+// check cycle and holds back those over the limit
+type cycleBatch struct {
+	active   bool
+	sent     map[string]int
+	overflow map[string][]types.NotificationMessage
+}
+
+// SetSortBy sets the sort keys ordering the alerts of overflow summaries,
+// most urgent first
+func (n *Notifier) SetSortBy(sortBy []string) {
+	n.batchMu.Lock()
+	defer n.batchMu.Unlock()
+	n.sortBy = sortBy
+}
+
+// BeginCycle starts counting proposal alerts per channel for a check cycle
+func (n *Notifier) BeginCycle() {
+	if n.batching.MaxPerCycle <= 0 {
+		return
+	}
+
+	n.batchMu.Lock()
+	defer n.batchMu.Unlock()
+	n.batch = cycleBatch{
+		active:   true,
+		sent:     make(map[string]int),
+		overflow: make(map[string][]types.NotificationMessage),
+	}
+}
+
+// admit reports whether msg may be sent to channel in the current cycle.
+// Messages over the limit are kept for the cycle's overflow summary
+func (n *Notifier) admit(channel string, msg types.NotificationMessage) bool {
+	if msg.AlertType == "" {
+		return true
+	}
+
+	n.batchMu.Lock()
+	defer n.batchMu.Unlock()
+	if !n.batch.active {
+		return true
+	}
+	if n.batch.sent[channel] < n.batching.MaxPerCycle {
+		n.batch.sent[channel]++
+		return true
+	}
+	n.batch.overflow[channel] = append(n.batch.overflow[channel], msg)
+	return false
+}
+
+// EndCycle stops counting and sends each channel that went over the limit
+// one summary of the alerts held back
+func (n *Notifier) EndCycle() error {
+	n.batchMu.Lock()
+	overflow := n.batch.overflow
+	sortBy := n.sortBy
+	n.batch = cycleBatch{}
+	n.batchMu.Unlock()
+
+	channels := make([]string, 0, len(overflow))
+	for channel := range overflow {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	var errs []error
+	for _, channel := range channels {
+		messages := overflow[channel]
+		sortByUrgency(sortBy, messages)
+		summary := n.overflowSummary(messages)
+		if n.skipDryRun(channel, summary) {
+			continue
+		}
+		var err error
+		switch channel {
+		case "telegram":
+			_, err = n.sendTelegramNotification(summary)
+		case "slack":
+			_, err = n.sendSlackNotification(summary)
+		case "matrix":
+			_, err = n.sendMatrixNotification(summary)
+		default:
+			_, err = n.sendChannel(n.channels[channel], summary)
+		}
+		n.recordDelivery(channel, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// sortByUrgency sorts proposal alerts most urgent first by the deadline of
+// their proposal and their severity
+func sortByUrgency(sortBy []string, messages []types.NotificationMessage) {
+	urgency := func(msg types.NotificationMessage) governance.Urgency {
+		u := governance.Urgency{Network: msg.Network, ID: msg.ProposalID, Severity: msg.Severity}
+		if msg.Proposal != nil {
+			u.Deadline = governance.Deadline(*msg.Proposal)
+		}
+		return u
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return governance.MoreUrgent(sortBy, urgency(messages[i]), urgency(messages[j]))
+	})
+}
+
+// overflowSummary collapses alerts held back into one message carrying the
+// highest severity among them
+func (n *Notifier) overflowSummary(messages []types.NotificationMessage) types.NotificationMessage {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d more alerts were raised during this check and collapsed into this summary:\n", len(messages))
+
+	severity := ""
+	for i, msg := range messages {
+		if severityRank(msg.Severity) > severityRank(severity) || severity == "" {
+			severity = msg.Severity
+		}
+		if i < maxOverflowLines {
+			fmt.Fprintf(&b, "\n• %s #%d: %s", msg.Network, msg.ProposalID, msg.Title)
+		}
+	}
+	if len(messages) > maxOverflowLines {
+		fmt.Fprintf(&b, "\n• and %d more", len(messages)-maxOverflowLines)
+	}
+	if n.batching.SummaryURL != "" {
+		fmt.Fprintf(&b, "\n\nFull list: %s", n.batching.SummaryURL)
+	}
+
+	return types.NotificationMessage{
+		Title:       "📦 Alert Overflow",
+		Content:     b.String(),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleCommunity,
+		Severity:    severity,
+	}
+}
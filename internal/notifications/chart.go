@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// tallyColors are the bar colors of each vote option
+var tallyColors = map[string]drawing.Color{
+	types.VoteYes:        {R: 46, G: 160, B: 67, A: 255},
+	types.VoteNo:         {R: 207, G: 34, B: 46, A: 255},
+	types.VoteAbstain:    {R: 140, G: 149, B: 159, A: 255},
+	types.VoteNoWithVeto: {R: 130, G: 80, B: 223, A: 255},
+}
+
+// RenderTallyChart renders the vote option distribution of a tally as a
+// PNG bar chart of percentages of votes cast, one bar per option
+func RenderTallyChart(title string, options []types.TallyOption) ([]byte, error) {
+	amounts := make([]float64, len(options))
+	total := 0.0
+	for i, option := range options {
+		amount, err := strconv.ParseFloat(option.Amount, 64)
+		if err != nil && option.Amount != "" {
+			return nil, fmt.Errorf("invalid %s amount %q", option.Label, option.Amount)
+		}
+		amounts[i] = amount
+		total += amount
+	}
+
+	bars := make([]chart.Value, len(options))
+	for i, option := range options {
+		percent := 0.0
+		if total > 0 {
+			percent = amounts[i] / total * 100
+		}
+		bars[i] = chart.Value{
+			Label: fmt.Sprintf("%s %.1f%%", option.Label, percent),
+			Value: percent,
+			Style: chart.Style{
+				FillColor:   tallyColors[option.Option],
+				StrokeColor: tallyColors[option.Option],
+			},
+		}
+	}
+
+	graph := chart.BarChart{
+		Title:      title,
+		Width:      640,
+		Height:     360,
+		BarWidth:   90,
+		Background: chart.Style{Padding: chart.Box{Top: 50, Left: 20, Right: 20, Bottom: 20}},
+		YAxis: chart.YAxis{
+			Range: &chart.ContinuousRange{Min: 0, Max: 100},
+			Ticks: []chart.Tick{
+				{Value: 0, Label: "0%"},
+				{Value: 25, Label: "25%"},
+				{Value: 50, Label: "50%"},
+				{Value: 75, Label: "75%"},
+				{Value: 100, Label: "100%"},
+			},
+		},
+		Bars: bars,
+	}
+
+	var buf bytes.Buffer
+	if err := graph.Render(chart.PNG, &buf); err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
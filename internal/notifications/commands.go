@@ -0,0 +1,129 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// CommandHandler handles a bot command with its arguments, returning the
+// reply. user identifies who sent the command
+type CommandHandler func(args []string, user string) string
+
+// HandleCommand registers a Telegram bot command such as "/proposals".
+// Commands are only accepted from the configured chat
+func (n *Notifier) HandleCommand(command string, handler CommandHandler) {
+	n.handleCommand(command, handler, false)
+}
+
+// HandleAdminCommand registers a Telegram bot command that changes state,
+// such as "/mute". With telegram.admins or telegram.chat_admins set, only
+// those users may issue it
+func (n *Notifier) HandleAdminCommand(command string, handler CommandHandler) {
+	n.handleCommand(command, handler, true)
+}
+
+// handleCommand registers a bot command, logging who issued it. Commands
+// that do not change state are also served as Slack slash commands
+func (n *Notifier) handleCommand(command string, handler CommandHandler, admin bool) {
+	if !admin {
+		n.commandsMu.Lock()
+		if n.commands == nil {
+			n.commands = make(map[string]CommandHandler)
+		}
+		n.commands[command] = handler
+		n.commandsMu.Unlock()
+	}
+	if n.telegram == nil {
+		return
+	}
+
+	n.telegram.Handle(command, func(c telebot.Context) error {
+		if c.Chat() == nil || c.Chat().ID != n.telegramChatID {
+			return nil
+		}
+		user := commandUser(c.Sender())
+		log := logrus.WithFields(logrus.Fields{"command": command, "args": strings.Join(c.Args(), " "), "user": user, "event": "command"})
+		if c.Sender() != nil {
+			log = log.WithField("user_id", c.Sender().ID)
+		}
+		if admin && !n.commandAllowed(c) {
+			log.Warn("Bot command denied, not an admin")
+			return c.Reply(fmt.Sprintf("⛔ Only admins can use %s", command))
+		}
+		log.Info("Bot command")
+
+		// Long replies, such as proposal lists, are sent in several messages
+		for _, chunk := range splitText(handler(c.Args(), user), telegramMaxLength) {
+			if err := c.Reply(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// commandAllowed reports whether the sender of a command may change state:
+// anyone in the chat unless admins are configured, otherwise the listed
+// users and, with chat_admins, the administrators of the chat
+func (n *Notifier) commandAllowed(c telebot.Context) bool {
+	if len(n.telegramAdmins) == 0 && !n.chatAdmins {
+		return true
+	}
+	sender := c.Sender()
+	if sender == nil {
+		return false
+	}
+	for _, id := range n.telegramAdmins {
+		if id == sender.ID {
+			return true
+		}
+	}
+	if !n.chatAdmins {
+		return false
+	}
+
+	admins, err := n.telegram.AdminsOf(c.Chat())
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"channel": "telegram", "event": "channel"}).WithError(err).Warn("Failed to fetch chat admins")
+		return false
+	}
+	for _, member := range admins {
+		if member.User != nil && member.User.ID == sender.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// StartCommands polls Telegram for bot commands until ctx is done, and
+// returns once the poller stopped. It returns immediately when Telegram is
+// disabled
+func (n *Notifier) StartCommands(ctx context.Context) {
+	if n.telegram == nil {
+		return
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		n.telegram.Start()
+	}()
+	<-ctx.Done()
+	n.telegram.Stop()
+	<-stopped
+}
+
+// commandUser describes the sender of a command
+func commandUser(user *telebot.User) string {
+	if user == nil {
+		return "unknown"
+	}
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s", user.FirstName, user.LastName))
+}
@@ -0,0 +1,38 @@
+package notifications
+
+import (
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetDryRun makes the notifier log the messages, edits and incidents it
+// would deliver instead of delivering them. Filters, routing, pauses and
+// batching still apply, so the log shows what each channel would receive
+func (n *Notifier) SetDryRun(enabled bool) {
+	n.dryRun.Store(enabled)
+}
+
+// DryRun reports whether deliveries are only logged
+func (n *Notifier) DryRun() bool {
+	return n.dryRun.Load()
+}
+
+// skipDryRun reports whether msg is only logged instead of delivered to
+// channel, logging it if so
+func (n *Notifier) skipDryRun(channel string, msg types.NotificationMessage) bool {
+	if !n.dryRun.Load() {
+		return false
+	}
+	logrus.WithFields(logrus.Fields{
+		"channel":     channel,
+		"network":     msg.Network,
+		"chain_id":    msg.ChainID,
+		"proposal_id": msg.ProposalID,
+		"alert_type":  msg.AlertType,
+		"severity":    msg.Severity,
+		"content":     msg.Content,
+		"event":       "dry_run",
+	}).Infof("Dry run, not sent: %s", msg.Title)
+	return true
+}
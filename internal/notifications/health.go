@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"time"
+)
+
+// ChannelHealth is the delivery health of a notification channel
+type ChannelHealth struct {
+	Channel     string    `json:"channel"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+	Paused      bool      `json:"paused,omitempty"`
+}
+
+// OK reports whether the last delivery to the channel succeeded
+func (h ChannelHealth) OK() bool {
+	return h.LastError == "" || h.LastSuccess.After(h.LastErrorAt)
+}
+
+// recordDelivery records the result of a delivery to channel
+func (n *Notifier) recordDelivery(channel string, err error) {
+	n.healthMu.Lock()
+	defer n.healthMu.Unlock()
+
+	if n.health == nil {
+		n.health = make(map[string]*ChannelHealth)
+	}
+	health, ok := n.health[channel]
+	if !ok {
+		health = &ChannelHealth{Channel: channel}
+		n.health[channel] = health
+	}
+	if err != nil {
+		health.LastError = err.Error()
+		health.LastErrorAt = time.Now()
+	} else {
+		health.LastSuccess = time.Now()
+	}
+}
+
+// ChannelHealth returns the delivery health of the enabled channels
+func (n *Notifier) ChannelHealth() []ChannelHealth {
+	channels := n.Channels()
+
+	n.pausedMu.RLock()
+	paused := n.paused
+	n.pausedMu.RUnlock()
+
+	n.healthMu.Lock()
+	defer n.healthMu.Unlock()
+
+	healths := make([]ChannelHealth, 0, len(channels))
+	for _, channel := range channels {
+		health := ChannelHealth{Channel: channel}
+		if recorded, ok := n.health[channel]; ok {
+			health = *recorded
+		}
+		health.Paused = paused[channel]
+		healths = append(healths, health)
+	}
+	return healths
+}
@@ -0,0 +1,281 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// matrixTimeout bounds each request to the homeserver
+const matrixTimeout = 10 * time.Second
+
+// matrixTxnCounter makes the transaction IDs of messages sent in the same
+// nanosecond unique
+var matrixTxnCounter atomic.Uint64
+
+// matrixTags matches the HTML tags stripped from the plain text body
+var matrixTags = regexp.MustCompile(`<[^>]*>`)
+
+// acceptsMatrix reports whether the Matrix channel receives msg
+func (n *Notifier) acceptsMatrix(msg types.NotificationMessage) bool {
+	return n.matrix.Enabled && acceptsRole(n.matrix.Roles, msg.Role) &&
+		acceptsAlertType(n.matrix.AlertTypes, msg.AlertType) && acceptsSeverity(n.matrix.MinSeverity, msg.Severity) &&
+		acceptsProposalType(n.matrix.ProposalTypes, msg)
+}
+
+// sendMatrixNotification sends a notification to the Matrix room
+func (n *Notifier) sendMatrixNotification(msg types.NotificationMessage) (types.MessageRef, error) {
+	return n.sendMatrixRoom(n.matrix.RoomID, n.matrix.Roles, msg)
+}
+
+// sendMatrixRoom sends a notification to a room of the Matrix user serving
+// roles, as HTML with a plain text fallback. The tally chart is uploaded to
+// the media repository of the homeserver and sent as an image first, as on
+// Telegram
+func (n *Notifier) sendMatrixRoom(roomID string, roles []string, msg types.NotificationMessage) (types.MessageRef, error) {
+	msg = n.guardURLs(roles, msg)
+	formatted := n.format("matrix", roles, msg, formatMatrixMessage)
+
+	if len(msg.Chart) > 0 {
+		if err := n.sendMatrixChart(roomID, msg.Chart); err != nil {
+			return types.MessageRef{}, fmt.Errorf("failed to send chart: %w", err)
+		}
+	}
+
+	// Info alerts are sent as notices, which clients do not notify about
+	msgType := "m.text"
+	if msg.Severity == types.SeverityInfo {
+		msgType = "m.notice"
+	}
+	eventID, err := n.sendMatrixEvent(roomID, map[string]interface{}{
+		"msgtype":        msgType,
+		"body":           matrixPlainText(formatted),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": formatted,
+	})
+	if err != nil {
+		return types.MessageRef{}, err
+	}
+	ref := types.MessageRef{Channel: "matrix", RoomID: roomID, EventID: eventID, Text: formatted}
+
+	// Send full details as a reply to the alert
+	if msg.Details != "" {
+		if err := n.replyMatrix(ref, msg.Details); err != nil {
+			return ref, fmt.Errorf("failed to send details: %w", err)
+		}
+	}
+	return ref, nil
+}
+
+// replyMatrix posts plain text as a reply to a Matrix message
+func (n *Notifier) replyMatrix(ref types.MessageRef, text string) error {
+	_, err := n.sendMatrixEvent(ref.RoomID, map[string]interface{}{
+		"msgtype": "m.notice",
+		"body":    text,
+		"m.relates_to": map[string]interface{}{
+			"m.in_reply_to": map[string]string{"event_id": ref.EventID},
+		},
+	})
+	return err
+}
+
+// sendMatrixEvent sends an m.room.message event to a room and returns its
+// event ID
+func (n *Notifier) sendMatrixEvent(roomID string, content map[string]interface{}) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), matrixTimeout)
+	defer cancel()
+
+	txnID := fmt.Sprintf("gac-%d-%d", time.Now().UnixNano(), matrixTxnCounter.Add(1))
+	path := fmt.Sprintf("/_matrix/client/v3/rooms/%s/send/m.room.message/%s", url.PathEscape(roomID), txnID)
+	var response struct {
+		EventID string `json:"event_id"`
+	}
+	// The transaction ID makes retries idempotent
+	err := n.paced("matrix "+roomID, func() error {
+		return n.matrixAPI(ctx, http.MethodPut, path, content, &response)
+	})
+	if err != nil {
+		return "", err
+	}
+	return response.EventID, nil
+}
+
+// sendMatrixChart uploads a tally chart to the media repository and sends
+// it to a room as an image
+func (n *Notifier) sendMatrixChart(roomID string, chart []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), matrixTimeout)
+	defer cancel()
+
+	var upload struct {
+		ContentURI string `json:"content_uri"`
+	}
+	err := n.paced("matrix "+roomID, func() error {
+		return n.matrixRequest(ctx, http.MethodPost, "/_matrix/media/v3/upload?filename=tally.png", "image/png", bytes.NewReader(chart), &upload)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.sendMatrixEvent(roomID, map[string]interface{}{
+		"msgtype": "m.image",
+		"body":    "tally.png",
+		"url":     upload.ContentURI,
+		"info":    map[string]interface{}{"mimetype": "image/png", "size": len(chart)},
+	})
+	return err
+}
+
+// matrixAPI calls the client-server API of the homeserver with the access
+// token and a JSON payload, decoding the response into v when set
+func (n *Notifier) matrixAPI(ctx context.Context, method, path string, payload interface{}, v interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	return n.matrixRequest(ctx, method, path, "application/json", body, v)
+}
+
+// matrixRequest sends body of contentType to the homeserver with the access
+// token, decoding the response into v when set
+func (n *Notifier) matrixRequest(ctx context.Context, method, path, contentType string, body io.Reader, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(n.matrix.HomeserverURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+n.matrix.AccessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Errors carry a code such as M_FORBIDDEN and a message, rate limits
+		// how long to wait in milliseconds
+		var matrixErr struct {
+			ErrCode      string `json:"errcode"`
+			Error        string `json:"error"`
+			RetryAfterMS int64  `json:"retry_after_ms"`
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if json.Unmarshal(raw, &matrixErr) == nil && matrixErr.RetryAfterMS > 0 {
+				return &rateLimitedError{retryAfter: time.Duration(matrixErr.RetryAfterMS) * time.Millisecond}
+			}
+			return rateLimited(resp)
+		}
+		if json.Unmarshal(raw, &matrixErr) == nil && matrixErr.ErrCode != "" {
+			return fmt.Errorf("unexpected status code %d: %s: %s", resp.StatusCode, matrixErr.ErrCode, matrixErr.Error)
+		}
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if v != nil {
+		return json.Unmarshal(raw, v)
+	}
+	return nil
+}
+
+// testMatrix verifies the access token with whoami and that its user
+// joined the room
+func (n *Notifier) testMatrix(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, matrixTimeout)
+	defer cancel()
+
+	var whoami struct {
+		UserID string `json:"user_id"`
+	}
+	if err := n.matrixAPI(ctx, http.MethodGet, "/_matrix/client/v3/account/whoami", nil, &whoami); err != nil {
+		return fmt.Errorf("access token rejected: %w", err)
+	}
+	var joined struct {
+		JoinedRooms []string `json:"joined_rooms"`
+	}
+	if err := n.matrixAPI(ctx, http.MethodGet, "/_matrix/client/v3/joined_rooms", nil, &joined); err != nil {
+		return fmt.Errorf("failed to list joined rooms: %w", err)
+	}
+	if !slices.Contains(joined.JoinedRooms, n.matrix.RoomID) {
+		return fmt.Errorf("%s has not joined room %s", whoami.UserID, n.matrix.RoomID)
+	}
+	return nil
+}
+
+// matrixPlainText turns a formatted Matrix message into the plain text body
+// shown by clients without HTML support
+func matrixPlainText(formatted string) string {
+	text := strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n").Replace(formatted)
+	return html.UnescapeString(matrixTags.ReplaceAllString(text, ""))
+}
+
+// formatMatrixMessage formats a message for Matrix as HTML
+func formatMatrixMessage(msg types.NotificationMessage) string {
+	escape := func(text string) string {
+		return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+	}
+
+	// For startup notifications, don't include Network, Chain ID, and Proposal ID
+	if msg.Network == "Governance Alerts" {
+		return fmt.Sprintf("🚀 <b>%s</b><br><br>%s", escape(msg.Title), escape(msg.Content))
+	}
+
+	// For network-wide notifications, there is no proposal to show
+	if msg.ProposalID == 0 {
+		return fmt.Sprintf(
+			"🚨 <b>%s</b><br><br>"+
+				"<b>Network:</b> %s<br>"+
+				"<b>Chain ID:</b> %s<br><br>"+
+				"%s",
+			escape(msg.Title),
+			escape(msg.Network),
+			escape(msg.ChainID),
+			escape(msg.Content),
+		)
+	}
+
+	// For proposal notifications, include all details
+	severity := ""
+	if msg.Severity != "" {
+		severity = fmt.Sprintf("<b>Severity:</b> %s<br>", strings.ToUpper(msg.Severity))
+	}
+	explorer := ""
+	if msg.ExplorerURL != "" {
+		explorer = fmt.Sprintf("<b>Explorer:</b> <a href=\"%s\">View proposal</a><br>", html.EscapeString(msg.ExplorerURL))
+	}
+	return fmt.Sprintf(
+		"%s <b>%s</b><br><br>"+
+			"<b>Network:</b> %s<br>"+
+			"<b>Chain ID:</b> %s<br>"+
+			"<b>Proposal ID:</b> %d<br>"+
+			"%s%s<br>"+
+			"%s",
+		severityEmoji(msg.Severity),
+		escape(msg.Title),
+		escape(msg.Network),
+		escape(msg.ChainID),
+		msg.ProposalID,
+		severity,
+		explorer,
+		escape(msg.Content),
+	)
+}
@@ -2,25 +2,61 @@ package notifications
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"html"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"governance-alerts-cosmos/internal/governance"
 	"governance-alerts-cosmos/internal/types"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/telebot.v3"
 )
 
 // Notifier handles sending notifications to various channels
 type Notifier struct {
-	telegram       *telebot.Bot
-	telegramChatID int64
-	slack          types.SlackConfig
+	telegram        *telebot.Bot
+	telegramChatID  int64
+	telegramRoles   []string
+	telegramAlerts  []string
+	telegramTypes   []string
+	telegramMin     string
+	telegramAdmins  []int64
+	chatAdmins      bool
+	slack           types.SlackConfig
+	matrix          types.MatrixConfig
+	templates       *templateSet
+	reactionHandler ReactionHandler
+	rateLimit       types.RateLimitConfig
+	pacer           pacer
+	commandsMu      sync.RWMutex
+	commands        map[string]CommandHandler
+	pagerDuty       types.PagerDutyConfig
+	webhooks        []types.WebhookConfig
+	healthMu        sync.Mutex
+	health          map[string]*ChannelHealth
+	batching        types.BatchingConfig
+	urlPolicy       types.URLPolicyConfig
+	batchMu         sync.Mutex
+	sortBy          []string
+	batch           cycleBatch
+	pausedMu        sync.RWMutex
+	paused          map[string]bool
+	channels        map[string]types.ChannelConfig
+	routes          []route
+	dryRun          atomic.Bool
 }
 
-// NewNotifier creates a new notifier instance
-func NewNotifier(config *types.NotificationConfig) (*Notifier, error) {
+// NewNotifier creates a new notifier instance. The networks resolve the
+// network names of routing rules
+func NewNotifier(config *types.NotificationConfig, networks map[string]types.NetworkConfig) (*Notifier, error) {
 	notifier := &Notifier{}
 
 	// Initialize Telegram if enabled
@@ -34,80 +70,368 @@ func NewNotifier(config *types.NotificationConfig) (*Notifier, error) {
 		}
 		notifier.telegram = bot
 		notifier.telegramChatID = config.Telegram.ChatID
+		notifier.telegramRoles = config.Telegram.Roles
+		notifier.telegramAlerts = config.Telegram.AlertTypes
+		notifier.telegramTypes = config.Telegram.ProposalTypes
+		notifier.telegramMin = config.Telegram.MinSeverity
+		notifier.telegramAdmins = config.Telegram.Admins
+		notifier.chatAdmins = config.Telegram.ChatAdmins
 	}
 
-	// Store Slack config
+	// Store Slack, Matrix, PagerDuty and webhook config
 	notifier.slack = config.Slack
+	notifier.matrix = config.Matrix
+	notifier.pagerDuty = config.PagerDuty
+	notifier.webhooks = config.Webhooks
+	notifier.batching = config.Batching
+	notifier.rateLimit = config.RateLimit
+	if config.RateLimit.MessagesPerMinute > 0 {
+		notifier.pacer.interval = time.Minute / time.Duration(config.RateLimit.MessagesPerMinute)
+	}
+	notifier.urlPolicy = config.URLPolicy
+	if len(notifier.urlPolicy.Roles) == 0 {
+		notifier.urlPolicy.Roles = []string{types.RoleCommunity}
+	}
+	notifier.channels = config.Channels
+	notifier.routes = newRoutes(config.Routes, networks)
+
+	// Load message templates if configured
+	if config.TemplatesDir != "" {
+		templates, err := loadTemplateSet(config.TemplatesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load templates: %w", err)
+		}
+		notifier.templates = templates
+	}
 
 	return notifier, nil
 }
 
 // SendNotification sends a notification to all enabled channels
 func (n *Notifier) SendNotification(msg types.NotificationMessage) error {
+	_, err := n.SendTracked(msg)
+	return err
+}
+
+// SendTracked sends a notification to all enabled channels, or the ones
+// routing rules pick, and returns references to the messages that can
+// later be edited or replied to. It only fails when no channel got the
+// message, so an alert one channel delivered is not sent again to all of
+// them; failures of the other channels are logged and show in channel
+// health
+func (n *Notifier) SendTracked(msg types.NotificationMessage) ([]types.MessageRef, error) {
 	var errors []error
+	var refs []types.MessageRef
+	delivered := 0
+	destinations := n.destinations(msg)
 
-	// Send to Telegram if enabled
-	if n.telegram != nil {
-		if err := n.sendTelegramNotification(msg); err != nil {
+	// Send to Telegram if enabled and not over the cycle's limit
+	if routed(destinations, types.ChannelTelegram) && n.acceptsTelegram(msg) && !n.isPaused("telegram", msg.Title) && n.admit("telegram", msg) && !n.skipDryRun("telegram", msg) {
+		ref, err := n.sendTelegramNotification(forChannel(msg, n.telegramAlerts))
+		n.recordDelivery("telegram", err)
+		if err != nil {
 			errors = append(errors, fmt.Errorf("telegram: %w", err))
+		} else {
+			refs = append(refs, ref)
+			delivered++
 		}
 	}
 
-	// Send to Slack if enabled
-	if n.slack.Enabled {
-		if err := n.sendSlackNotification(msg); err != nil {
+	// Send to Slack if enabled and not over the cycle's limit
+	if routed(destinations, types.ChannelSlack) && n.acceptsSlack(msg) && !n.isPaused("slack", msg.Title) && n.admit("slack", msg) && !n.skipDryRun("slack", msg) {
+		ref, err := n.sendSlackNotification(forChannel(msg, n.slack.AlertTypes))
+		n.recordDelivery("slack", err)
+		if err != nil {
 			errors = append(errors, fmt.Errorf("slack: %w", err))
+		} else {
+			refs = append(refs, ref)
+			delivered++
+		}
+	}
+
+	// Send to Matrix if enabled and not over the cycle's limit
+	if routed(destinations, types.ChannelMatrix) && n.acceptsMatrix(msg) && !n.isPaused("matrix", msg.Title) && n.admit("matrix", msg) && !n.skipDryRun("matrix", msg) {
+		ref, err := n.sendMatrixNotification(forChannel(msg, n.matrix.AlertTypes))
+		n.recordDelivery("matrix", err)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("matrix: %w", err))
+		} else {
+			refs = append(refs, ref)
+			delivered++
+		}
+	}
+
+	// Post to every webhook that receives the message. Webhooks return no
+	// message that could be edited later
+	for _, webhook := range n.webhooks {
+		if !routed(destinations, types.RouteWebhooks) || !acceptsWebhook(webhook, msg) || n.isPaused(webhookChannel(webhook), msg.Title) || n.skipDryRun(webhookChannel(webhook), msg) {
+			continue
+		}
+		err := n.sendWebhookNotification(webhook, msg)
+		n.recordDelivery(webhookChannel(webhook), err)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", webhookChannel(webhook), err))
+		} else {
+			delivered++
+		}
+	}
+
+	// Deliver to the named channels of the matching routing rules
+	routedRefs, routedDelivered, routedErrors := n.sendRouted(destinations, msg)
+	refs = append(refs, routedRefs...)
+	delivered += routedDelivered
+	errors = append(errors, routedErrors...)
+
+	if len(errors) == 0 {
+		return refs, nil
+	}
+	if delivered == 0 {
+		return refs, errors[0]
+	}
+	for _, err := range errors {
+		logrus.WithFields(logrus.Fields{"title": msg.Title, "delivered": delivered, "event": "alert_partially_failed"}).WithError(err).Warn("Message not delivered to every channel")
+	}
+	return refs, nil
+}
+
+// acceptsTelegram reports whether the Telegram channel receives msg
+func (n *Notifier) acceptsTelegram(msg types.NotificationMessage) bool {
+	return n.telegram != nil && acceptsRole(n.telegramRoles, msg.Role) &&
+		acceptsAlertType(n.telegramAlerts, msg.AlertType) && acceptsSeverity(n.telegramMin, msg.Severity) &&
+		acceptsProposalType(n.telegramTypes, msg)
+}
+
+// acceptsSlack reports whether the Slack channel receives msg
+func (n *Notifier) acceptsSlack(msg types.NotificationMessage) bool {
+	return n.slack.Enabled && acceptsRole(n.slack.Roles, msg.Role) &&
+		acceptsAlertType(n.slack.AlertTypes, msg.AlertType) && acceptsSeverity(n.slack.MinSeverity, msg.Severity) &&
+		acceptsProposalType(n.slack.ProposalTypes, msg)
+}
+
+// acceptsSeverity reports whether a channel with minimum severity min
+// receives a message of severity. Messages without a severity are not
+// proposal alerts and always pass
+func acceptsSeverity(min, severity string) bool {
+	if min == "" || severity == "" {
+		return true
+	}
+	return severityRank(severity) >= severityRank(min)
+}
+
+// severityRank orders severities from least to most severe
+func severityRank(severity string) int {
+	for i, s := range types.Severities {
+		if s == severity {
+			return i
 		}
 	}
+	return 0
+}
 
-	// Return first error if any
-	if len(errors) > 0 {
-		return errors[0]
+// acceptsRole reports whether a channel with the given roles receives a
+// message of role. Channels without roles receive everything, messages
+// without a role are community messages
+func acceptsRole(roles []string, role string) bool {
+	if len(roles) == 0 {
+		return true
 	}
+	if role == "" {
+		role = types.RoleCommunity
+	}
+	for _, r := range roles {
+		if r == role || r == types.RoleAudit {
+			return true
+		}
+	}
+	return false
+}
 
-	return nil
+// acceptsAlertType reports whether a channel subscribed to alertTypes
+// receives a message of alertType. Channels without alert types receive
+// everything, as do messages that are not proposal alerts
+func acceptsAlertType(alertTypes []string, alertType string) bool {
+	if len(alertTypes) == 0 || alertType == "" {
+		return true
+	}
+	for _, t := range alertTypes {
+		if t == alertType {
+			return true
+		}
+	}
+	return false
 }
 
-// sendTelegramNotification sends a notification to Telegram
-func (n *Notifier) sendTelegramNotification(msg types.NotificationMessage) error {
-	formattedMsg := formatTelegramMessage(msg)
+// acceptsProposalType reports whether a channel subscribed to the proposal
+// types filters receives msg. Messages that are not about one proposal are
+// always received
+func acceptsProposalType(filters []string, msg types.NotificationMessage) bool {
+	return msg.Proposal == nil || governance.MatchesProposalType(filters, *msg.Proposal)
+}
 
-	// Use the configured chat ID
-	chat := &telebot.Chat{ID: n.telegramChatID}
+// forChannel strips the tally chart from a message for channels not
+// subscribed to tally alerts
+func forChannel(msg types.NotificationMessage, alertTypes []string) types.NotificationMessage {
+	if len(msg.Chart) > 0 && !acceptsAlertType(alertTypes, types.AlertTally) {
+		msg.Chart = nil
+	}
+	return msg
+}
+
+// sendTelegramNotification sends a notification to the Telegram chat
+func (n *Notifier) sendTelegramNotification(msg types.NotificationMessage) (types.MessageRef, error) {
+	return n.sendTelegramChat(n.telegramChatID, n.telegramRoles, msg)
+}
+
+// sendTelegramChat sends a notification to a chat of the Telegram bot
+// serving roles
+func (n *Notifier) sendTelegramChat(chatID int64, roles []string, msg types.NotificationMessage) (types.MessageRef, error) {
+	msg = n.guardURLs(roles, msg)
+	formattedMsg := n.format("telegram", roles, msg, formatTelegramMessage)
+	chat := &telebot.Chat{ID: chatID}
+	destination := fmt.Sprintf("telegram %d", chatID)
+
+	// Attach the tally chart first, the message follows as text since
+	// photo captions are limited to 1024 characters
+	if len(msg.Chart) > 0 {
+		err := n.paced(destination, func() error {
+			photo := &telebot.Photo{File: telebot.FromReader(bytes.NewReader(msg.Chart))}
+			_, err := n.telegram.Send(chat, photo)
+			return err
+		})
+		if err != nil {
+			return types.MessageRef{}, fmt.Errorf("failed to send chart: %w", err)
+		}
+	}
 
-	_, err := n.telegram.Send(chat, formattedMsg, &telebot.SendOptions{
-		ParseMode: telebot.ModeHTML,
+	// Info alerts are delivered without a notification sound
+	var sent *telebot.Message
+	err := n.paced(destination, func() (err error) {
+		sent, err = n.telegram.Send(chat, formattedMsg, &telebot.SendOptions{
+			ParseMode:           telebot.ModeHTML,
+			DisableNotification: msg.Severity == types.SeverityInfo,
+		})
+		return err
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return types.MessageRef{}, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	ref := types.MessageRef{
+		Channel:   "telegram",
+		ChatID:    chat.ID,
+		MessageID: sent.ID,
+		Text:      formattedMsg,
+	}
+
+	// Send full details as plain text replies to the alert
+	for _, chunk := range splitText(msg.Details, telegramMaxLength) {
+		err := n.paced(destination, func() error {
+			_, err := n.telegram.Send(chat, chunk, &telebot.SendOptions{ReplyTo: sent})
+			return err
+		})
+		if err != nil {
+			return ref, fmt.Errorf("failed to send details: %w", err)
+		}
 	}
 
-	return nil
+	return ref, nil
 }
 
-// sendSlackNotification sends a notification to Slack
-func (n *Notifier) sendSlackNotification(msg types.NotificationMessage) error {
-	payload := map[string]interface{}{
-		"text": formatSlackMessage(msg),
+// sendSlackNotification sends a notification to Slack. Charts follow the
+// alert when posting with a bot token, incoming webhooks cannot upload files
+func (n *Notifier) sendSlackNotification(msg types.NotificationMessage) (types.MessageRef, error) {
+	msg = n.guardURLs(n.slack.Roles, msg)
+	formattedMsg := n.format("slack", n.slack.Roles, msg, formatSlackMessage)
+	if n.slack.BotToken != "" {
+		ref, err := n.postSlackAPI(formattedMsg, "")
+		if err != nil {
+			return ref, err
+		}
+
+		if len(msg.Chart) > 0 {
+			if err := n.uploadSlackChart(ref.SlackChannel, msg.Title, msg.Chart); err != nil {
+				return ref, fmt.Errorf("failed to send chart: %w", err)
+			}
+		}
+
+		// Send full details as a thread reply to the alert
+		if msg.Details != "" {
+			if _, err := n.postSlackAPI(msg.Details, ref.Timestamp); err != nil {
+				return ref, fmt.Errorf("failed to send details: %w", err)
+			}
+		}
+		return ref, nil
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+	ref := types.MessageRef{Channel: "slack"}
+	if err := n.postSlackWebhook(n.slack.WebhookURL, formattedMsg); err != nil {
+		return ref, err
 	}
 
-	resp, err := http.Post(n.slack.WebhookURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+	// Send full details as a follow-up message
+	if msg.Details != "" {
+		return ref, n.postSlackWebhook(n.slack.WebhookURL, msg.Details)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return ref, nil
+}
+
+// postSlackWebhook posts plain text to a Slack incoming webhook
+func (n *Notifier) postSlackWebhook(webhookURL, text string) error {
+	jsonData, err := json.Marshal(map[string]interface{}{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	return nil
+	return n.paced(slackWebhookDestination(webhookURL), func() error {
+		resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return rateLimited(resp)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// slackWebhookDestination identifies a Slack incoming webhook for pacing
+// without logging its URL, which is a secret
+func slackWebhookDestination(webhookURL string) string {
+	sum := sha256.Sum256([]byte(webhookURL))
+	return "slack webhook " + hex.EncodeToString(sum[:4])
+}
+
+// telegramMaxLength is the maximum length of a Telegram message
+const telegramMaxLength = 4096
+
+// splitText splits text into chunks of at most maxLen characters, breaking
+// at line ends where possible
+func splitText(text string, maxLen int) []string {
+	var chunks []string
+	runes := []rune(text)
+	for len(runes) > 0 {
+		if len(runes) <= maxLen {
+			chunks = append(chunks, string(runes))
+			break
+		}
+		cut := maxLen
+		for i := maxLen - 1; i > maxLen/2; i-- {
+			if runes[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+		chunks = append(chunks, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	return chunks
 }
 
 // formatTelegramMessage formats a message for Telegram
@@ -121,17 +445,43 @@ func formatTelegramMessage(msg types.NotificationMessage) string {
 		)
 	}
 
+	// For network-wide notifications, there is no proposal to show
+	if msg.ProposalID == 0 {
+		return fmt.Sprintf(
+			"🚨 <b>%s</b>\n\n"+
+				"<b>Network:</b> %s\n"+
+				"<b>Chain ID:</b> %s\n\n"+
+				"%s",
+			msg.Title,
+			msg.Network,
+			msg.ChainID,
+			msg.Content,
+		)
+	}
+
 	// For proposal notifications, include all details
+	severity := ""
+	if msg.Severity != "" {
+		severity = fmt.Sprintf("<b>Severity:</b> %s\n", strings.ToUpper(msg.Severity))
+	}
+	explorer := ""
+	if msg.ExplorerURL != "" {
+		explorer = fmt.Sprintf("<b>Explorer:</b> <a href=\"%s\">View proposal</a>\n", html.EscapeString(msg.ExplorerURL))
+	}
 	return fmt.Sprintf(
-		"🚨 <b>%s</b>\n\n"+
+		"%s <b>%s</b>\n\n"+
 			"<b>Network:</b> %s\n"+
 			"<b>Chain ID:</b> %s\n"+
-			"<b>Proposal ID:</b> %d\n\n"+
+			"<b>Proposal ID:</b> %d\n"+
+			"%s%s\n"+
 			"%s",
+		severityEmoji(msg.Severity),
 		msg.Title,
 		msg.Network,
 		msg.ChainID,
 		msg.ProposalID,
+		severity,
+		explorer,
 		msg.Content,
 	)
 }
@@ -147,17 +497,55 @@ func formatSlackMessage(msg types.NotificationMessage) string {
 		)
 	}
 
+	// For network-wide notifications, there is no proposal to show
+	if msg.ProposalID == 0 {
+		return fmt.Sprintf(
+			"🚨 *%s*\n\n"+
+				"*Network:* %s\n"+
+				"*Chain ID:* %s\n\n"+
+				"%s",
+			msg.Title,
+			msg.Network,
+			msg.ChainID,
+			msg.Content,
+		)
+	}
+
 	// For proposal notifications, include all details
+	severity := ""
+	if msg.Severity != "" {
+		severity = fmt.Sprintf("*Severity:* %s\n", strings.ToUpper(msg.Severity))
+	}
+	explorer := ""
+	if msg.ExplorerURL != "" {
+		explorer = fmt.Sprintf("*Explorer:* <%s|View proposal>\n", msg.ExplorerURL)
+	}
 	return fmt.Sprintf(
-		"🚨 *%s*\n\n"+
+		"%s *%s*\n\n"+
 			"*Network:* %s\n"+
 			"*Chain ID:* %s\n"+
-			"*Proposal ID:* %d\n\n"+
+			"*Proposal ID:* %d\n"+
+			"%s%s\n"+
 			"%s",
+		severityEmoji(msg.Severity),
 		msg.Title,
 		msg.Network,
 		msg.ChainID,
 		msg.ProposalID,
+		severity,
+		explorer,
 		msg.Content,
 	)
 }
+
+// severityEmoji returns the emoji heading a proposal alert of severity
+func severityEmoji(severity string) string {
+	switch severity {
+	case types.SeverityCritical:
+		return "🔴"
+	case types.SeverityInfo:
+		return "🔵"
+	default:
+		return "🚨"
+	}
+}
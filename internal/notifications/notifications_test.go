@@ -0,0 +1,43 @@
+package notifications
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// TestSendTrackedPartialFailure checks that a message one channel delivered
+// is a success, so the alert is marked sent and not repeated everywhere
+func TestSendTrackedPartialFailure(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	msg := types.NotificationMessage{Title: "Voting ends soon", Content: "Proposal #1 ends in 6 hours", ChainID: "cosmoshub-4", ProposalID: 1, AlertType: types.AlertVotingEnd}
+	tests := []struct {
+		name     string
+		webhooks []types.WebhookConfig
+		wantErr  bool
+	}{
+		{"all delivered", []types.WebhookConfig{{URL: ok.URL}, {URL: ok.URL + "/second"}}, false},
+		{"one delivered", []types.WebhookConfig{{URL: broken.URL}, {URL: ok.URL}}, false},
+		{"none delivered", []types.WebhookConfig{{URL: broken.URL}, {URL: broken.URL + "/second"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewNotifier(&types.NotificationConfig{Webhooks: tt.webhooks}, nil)
+			if err != nil {
+				t.Fatalf("NewNotifier() error = %v", err)
+			}
+			if _, err := n.SendTracked(msg); (err != nil) != tt.wantErr {
+				t.Errorf("SendTracked() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
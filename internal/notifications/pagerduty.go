@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// pagerDutyEventsURL is the endpoint of the PagerDuty Events API v2
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// Incident is a PagerDuty incident. Events with the same DedupKey update
+// the same incident
+type Incident struct {
+	DedupKey string
+	Summary  string
+	Source   string
+	Severity string
+	Details  map[string]interface{}
+	// Link is shown on the incident, such as the proposal on an explorer
+	Link string
+}
+
+// PagerDutyEnabled reports whether PagerDuty incidents are opened
+func (n *Notifier) PagerDutyEnabled() bool {
+	return n.pagerDuty.Enabled
+}
+
+// TriggerIncident opens a PagerDuty incident, or updates the open incident
+// with the same dedup key. Nothing is opened while PagerDuty is paused;
+// resolutions still go out so no incident is left open
+func (n *Notifier) TriggerIncident(incident Incident) error {
+	if n.isPaused("pagerduty", incident.Summary) || n.skipDryRun("pagerduty", types.NotificationMessage{Title: "Trigger incident: " + incident.Summary, ChainID: incident.Source, Severity: incident.Severity}) {
+		return nil
+	}
+	payload := map[string]interface{}{
+		"summary":        incident.Summary,
+		"source":         incident.Source,
+		"severity":       pagerDutySeverity(incident.Severity),
+		"timestamp":      time.Now().UTC().Format(time.RFC3339),
+		"component":      "governance",
+		"custom_details": incident.Details,
+	}
+	var links []map[string]string
+	if incident.Link != "" {
+		links = append(links, map[string]string{"href": incident.Link, "text": "View proposal"})
+	}
+	err := n.sendPagerDutyEvent("trigger", incident.DedupKey, payload, links)
+	n.recordDelivery("pagerduty", err)
+	return err
+}
+
+// ResolveIncident resolves the PagerDuty incident with dedupKey. Resolving
+// an incident that is not open is a no-op on PagerDuty's side
+func (n *Notifier) ResolveIncident(dedupKey string) error {
+	if n.skipDryRun("pagerduty", types.NotificationMessage{Title: "Resolve incident " + dedupKey}) {
+		return nil
+	}
+	err := n.sendPagerDutyEvent("resolve", dedupKey, nil, nil)
+	n.recordDelivery("pagerduty", err)
+	return err
+}
+
+// sendPagerDutyEvent sends an event to the PagerDuty Events API v2
+func (n *Notifier) sendPagerDutyEvent(action, dedupKey string, payload map[string]interface{}, links []map[string]string) error {
+	event := map[string]interface{}{
+		"routing_key":  n.pagerDuty.RoutingKey,
+		"event_action": action,
+		"dedup_key":    dedupKey,
+	}
+	if payload != nil {
+		event["payload"] = payload
+	}
+	if len(links) > 0 {
+		event["links"] = links
+	}
+
+	jsonData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	resp, err := http.Post(pagerDutyEventsURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("pagerduty %s rejected with status %d", action, resp.StatusCode)
+	}
+	return nil
+}
+
+// pagerDutySeverity maps an alert severity to a PagerDuty severity
+func pagerDutySeverity(severity string) string {
+	switch severity {
+	case types.SeverityInfo:
+		return "info"
+	case types.SeverityWarning:
+		return "warning"
+	default:
+		return "critical"
+	}
+}
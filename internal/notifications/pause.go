@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Channels returns the names of the enabled channels, as used in channel
+// health and to pause channels
+func (n *Notifier) Channels() []string {
+	var channels []string
+	if n.telegram != nil {
+		channels = append(channels, "telegram")
+	}
+	if n.slack.Enabled {
+		channels = append(channels, "slack")
+	}
+	if n.matrix.Enabled {
+		channels = append(channels, "matrix")
+	}
+	if n.pagerDuty.Enabled {
+		channels = append(channels, "pagerduty")
+	}
+	for _, webhook := range n.webhooks {
+		channels = append(channels, webhookChannel(webhook))
+	}
+	channels = append(channels, n.channelNames()...)
+	sort.Strings(channels)
+	return channels
+}
+
+// SetPaused pauses delivery to channels and resumes every other channel.
+// Messages for a paused channel are dropped, not queued
+func (n *Notifier) SetPaused(channels []string) {
+	paused := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		paused[channel] = true
+	}
+
+	n.pausedMu.Lock()
+	n.paused = paused
+	n.pausedMu.Unlock()
+}
+
+// isPaused reports whether delivery to channel is paused, logging the
+// dropped message if so
+func (n *Notifier) isPaused(channel, title string) bool {
+	n.pausedMu.RLock()
+	paused := n.paused[channel]
+	n.pausedMu.RUnlock()
+
+	if paused {
+		logrus.WithFields(logrus.Fields{"channel": channel, "title": title, "event": "channel_paused"}).Info("Message dropped, channel paused")
+	}
+	return paused
+}
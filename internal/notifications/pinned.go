@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"fmt"
+	"strconv"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"gopkg.in/telebot.v3"
+)
+
+// UpdatePinned replaces a pinned message with msg. The Telegram message in
+// refs is edited in place, or sent and pinned if there is none or it was
+// deleted. Slack webhooks can neither edit nor pin, so Slack gets msg as a
+// new message. The refs of the updated messages are returned
+func (n *Notifier) UpdatePinned(refs []types.MessageRef, msg types.NotificationMessage) ([]types.MessageRef, error) {
+	var errors []error
+	var updated []types.MessageRef
+
+	if n.skipDryRun("pinned", msg) {
+		return refs, nil
+	}
+	if n.acceptsTelegram(msg) {
+		ref, err := n.updatePinnedTelegram(refs, msg)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("telegram: %w", err))
+		}
+		if ref.MessageID != 0 {
+			updated = append(updated, ref)
+		}
+	}
+
+	if n.acceptsSlack(msg) {
+		if _, err := n.sendSlackNotification(msg); err != nil {
+			errors = append(errors, fmt.Errorf("slack: %w", err))
+		} else {
+			updated = append(updated, types.MessageRef{Channel: "slack"})
+		}
+	}
+
+	if len(errors) > 0 {
+		return updated, errors[0]
+	}
+	return updated, nil
+}
+
+// updatePinnedTelegram edits the pinned Telegram message or sends and pins
+// a new one
+func (n *Notifier) updatePinnedTelegram(refs []types.MessageRef, msg types.NotificationMessage) (types.MessageRef, error) {
+	text := n.format("telegram", n.telegramRoles, n.guardURLs(n.telegramRoles, msg), formatTelegramMessage)
+
+	for _, ref := range refs {
+		if ref.Channel != "telegram" || ref.ChatID != n.telegramChatID {
+			continue
+		}
+		if ref.Text == text {
+			return ref, nil
+		}
+		stored := telebot.StoredMessage{MessageID: strconv.Itoa(ref.MessageID), ChatID: ref.ChatID}
+		if _, err := n.telegram.Edit(stored, text, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err == nil {
+			ref.Text = text
+			return ref, nil
+		}
+		// The message was deleted or is too old to edit, pin a new one
+		break
+	}
+
+	ref, err := n.sendTelegramNotification(msg)
+	if err != nil {
+		return ref, err
+	}
+	sent := &telebot.Message{ID: ref.MessageID, Chat: &telebot.Chat{ID: ref.ChatID}}
+	if err := n.telegram.Pin(sent, telebot.Silent); err != nil {
+		return ref, fmt.Errorf("failed to pin message: %w", err)
+	}
+	return ref, nil
+}
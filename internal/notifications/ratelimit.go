@@ -0,0 +1,117 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// defaultRetryAfter is how long to wait before retrying a rate limited
+// message when the channel does not say
+const defaultRetryAfter = 5 * time.Second
+
+// rateLimitedError is returned for messages a channel rejected for being
+// sent too fast, with how long it asked to wait
+type rateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s", e.retryAfter)
+}
+
+// rateLimited returns the error of a 429 response, waiting as long as its
+// Retry-After header asks
+func rateLimited(resp *http.Response) error {
+	retryAfter := defaultRetryAfter
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return &rateLimitedError{retryAfter: retryAfter}
+}
+
+// retryAfterOf returns how long to wait before retrying a message that
+// failed with err, false when the failure is not a rate limit
+func retryAfterOf(err error) (time.Duration, bool) {
+	var limited *rateLimitedError
+	if errors.As(err, &limited) {
+		return limited.retryAfter, true
+	}
+	var flood telebot.FloodError
+	if errors.As(err, &flood) {
+		return time.Duration(flood.RetryAfter) * time.Second, true
+	}
+	return 0, false
+}
+
+// pacer spaces the messages sent to each destination, a chat, room or
+// webhook URL, by interval. Senders reserve the next free slot and wait for
+// it, so messages over the rate queue up in order
+type pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     map[string]time.Time
+}
+
+// wait blocks until the next slot of destination
+func (p *pacer) wait(destination string) {
+	p.mu.Lock()
+	now := time.Now()
+	slot := p.next[destination]
+	if slot.Before(now) {
+		slot = now
+	}
+	if p.next == nil {
+		p.next = make(map[string]time.Time)
+	}
+	p.next[destination] = slot.Add(p.interval)
+	p.mu.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
+
+// delay holds back every message to destination for d, after the channel
+// asked to slow down
+func (p *pacer) delay(destination string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.next == nil {
+		p.next = make(map[string]time.Time)
+	}
+	if until := time.Now().Add(d); p.next[destination].Before(until) {
+		p.next[destination] = until
+	}
+}
+
+// paced sends a message to destination with send, waiting for its turn
+// under notifications.rate_limit and retrying it when the channel rejects
+// it as rate limited
+func (n *Notifier) paced(destination string, send func() error) error {
+	limits := n.rateLimit
+	maxWait := time.Duration(limits.MaxWaitSeconds) * time.Second
+	for attempt := 0; ; attempt++ {
+		if limits.MessagesPerMinute > 0 {
+			n.pacer.wait(destination)
+		}
+		err := send()
+		retryAfter, limited := retryAfterOf(err)
+		if !limited || attempt >= limits.Retries || (maxWait > 0 && retryAfter > maxWait) {
+			return err
+		}
+
+		logrus.WithFields(logrus.Fields{"destination": destination, "retry_after": retryAfter, "attempt": attempt + 1}).Warn("Rate limited, retrying message")
+		if limits.MessagesPerMinute > 0 {
+			// The next wait holds the retry, and every other message to
+			// the destination, back
+			n.pacer.delay(destination, retryAfter)
+		} else {
+			time.Sleep(retryAfter)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"gopkg.in/telebot.v3"
+)
+
+// Retraction modes
+const (
+	RetractEdit  = "edit"
+	RetractReply = "reply"
+)
+
+// Retract withdraws earlier alerts of a proposal. In edit mode Telegram
+// alerts are struck through under the banner, in reply mode the notice is
+// posted as a reply to them. Slack webhooks cannot edit or thread, so Slack
+// gets the notice as a single new message in both modes, however many
+// alerts it got, and Matrix as a reply. It returns the refs whose
+// retraction failed, to be retried later
+func (n *Notifier) Retract(refs []types.MessageRef, mode, banner string, notice types.NotificationMessage) ([]types.MessageRef, error) {
+	var errors []error
+	var failed, slackRefs []types.MessageRef
+
+	if n.skipDryRun("retraction", notice) {
+		return nil, nil
+	}
+	for _, ref := range refs {
+		switch ref.Channel {
+		case "telegram":
+			if n.telegram == nil {
+				continue
+			}
+			if err := n.retractTelegram(ref, mode, banner, notice); err != nil {
+				errors = append(errors, fmt.Errorf("telegram: %w", err))
+				failed = append(failed, ref)
+			}
+		case "slack":
+			if !n.slack.Enabled {
+				continue
+			}
+			slackRefs = append(slackRefs, ref)
+		case "matrix":
+			if !n.matrix.Enabled {
+				continue
+			}
+			if err := n.replyMatrix(ref, fmt.Sprintf("%s\n\n%s", banner, notice.Content)); err != nil {
+				errors = append(errors, fmt.Errorf("matrix: %w", err))
+				failed = append(failed, ref)
+			}
+		}
+	}
+
+	// Every Slack alert went to the configured channel, which gets the
+	// notice once
+	if len(slackRefs) > 0 {
+		if _, err := n.sendSlackNotification(notice); err != nil {
+			errors = append(errors, fmt.Errorf("slack: %w", err))
+			failed = append(failed, slackRefs...)
+		}
+	}
+
+	if len(errors) > 0 {
+		return failed, errors[0]
+	}
+	return nil, nil
+}
+
+// retractTelegram edits or replies to a Telegram alert
+func (n *Notifier) retractTelegram(ref types.MessageRef, mode, banner string, notice types.NotificationMessage) error {
+	chat := &telebot.Chat{ID: ref.ChatID}
+
+	if mode == RetractEdit && ref.Text != "" {
+		stored := telebot.StoredMessage{MessageID: strconv.Itoa(ref.MessageID), ChatID: ref.ChatID}
+		text := fmt.Sprintf("<b>%s</b>\n\n<s>%s</s>", html.EscapeString(banner), ref.Text)
+		if _, err := n.telegram.Edit(stored, text, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			return fmt.Errorf("failed to edit message: %w", err)
+		}
+		return nil
+	}
+
+	original := &telebot.Message{ID: ref.MessageID, Chat: chat}
+	if _, err := n.telegram.Send(chat, n.format("telegram", n.telegramRoles, n.guardURLs(n.telegramRoles, notice), formatTelegramMessage), &telebot.SendOptions{
+		ParseMode: telebot.ModeHTML,
+		ReplyTo:   original,
+	}); err != nil {
+		return fmt.Errorf("failed to reply: %w", err)
+	}
+	return nil
+}
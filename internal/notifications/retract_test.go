@@ -0,0 +1,55 @@
+package notifications
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// TestRetract checks that Slack gets one notice however many alerts it got
+// and that only the refs whose retraction failed are returned
+func TestRetract(t *testing.T) {
+	var slackPosts atomic.Int32
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackPosts.Add(1)
+	}))
+	defer slack.Close()
+	matrix := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errcode": "M_FORBIDDEN", "error": "not in room"}`, http.StatusForbidden)
+	}))
+	defer matrix.Close()
+
+	n, err := NewNotifier(&types.NotificationConfig{
+		Slack:  types.SlackConfig{Enabled: true, WebhookURL: slack.URL},
+		Matrix: types.MatrixConfig{Enabled: true, HomeserverURL: matrix.URL, AccessToken: "token", RoomID: "!room:example.org"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewNotifier() error = %v", err)
+	}
+
+	matrixRef := types.MessageRef{Channel: "matrix", RoomID: "!room:example.org", EventID: "$alert"}
+	refs := []types.MessageRef{{Channel: "slack"}, {Channel: "slack"}, matrixRef, {Channel: "slack"}}
+	notice := types.NotificationMessage{Title: "🚫 CANCELLED - Cosmos Hub", Content: "Proposal #1 was cancelled", ChainID: "cosmoshub-4", ProposalID: 1}
+
+	failed, err := n.Retract(refs, RetractReply, "🚫 CANCELLED", notice)
+	if err == nil {
+		t.Fatal("Retract() error = nil, want the Matrix failure")
+	}
+	if len(failed) != 1 || failed[0] != matrixRef {
+		t.Errorf("Retract() failed = %v, want [%v]", failed, matrixRef)
+	}
+	if got := slackPosts.Load(); got != 1 {
+		t.Errorf("Retract() posted %d Slack notices, want 1", got)
+	}
+
+	// Retrying the failed refs leaves Slack alone
+	if _, err := n.Retract(failed, RetractReply, "🚫 CANCELLED", notice); err == nil {
+		t.Error("Retract() of the failed refs error = nil, want the Matrix failure")
+	}
+	if got := slackPosts.Load(); got != 1 {
+		t.Errorf("Retract() of the failed refs posted %d Slack notices, want 1", got)
+	}
+}
@@ -0,0 +1,162 @@
+package notifications
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// route is a routing rule with its networks resolved to chain IDs, which
+// every message carries
+type route struct {
+	types.RouteConfig
+	chainIDs []string
+}
+
+// newRoutes resolves the network names of the routing rules
+func newRoutes(configs []types.RouteConfig, networks map[string]types.NetworkConfig) []route {
+	routes := make([]route, 0, len(configs))
+	for _, config := range configs {
+		r := route{RouteConfig: config}
+		for _, name := range config.Networks {
+			if network, ok := networks[name]; ok {
+				r.chainIDs = append(r.chainIDs, network.ChainID)
+			}
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// matches reports whether a rule matches msg. Every criterion set must
+// match, so messages that are not proposal alerts only match rules without
+// alert types
+func (r route) matches(msg types.NotificationMessage) bool {
+	if len(r.AlertTypes) > 0 && !slices.Contains(r.AlertTypes, msg.AlertType) {
+		return false
+	}
+	return r.matchesProposal(msg.ChainID, msg.Proposal)
+}
+
+// matchesProposal reports whether a rule matches the network and proposal
+// type of a proposal, nil for messages about no proposal
+func (r route) matchesProposal(chainID string, proposal *types.Proposal) bool {
+	if len(r.Networks) > 0 && !slices.Contains(r.chainIDs, chainID) {
+		return false
+	}
+	if len(r.ProposalTypes) > 0 && (proposal == nil || !governance.MatchesProposalType(r.ProposalTypes, *proposal)) {
+		return false
+	}
+	return true
+}
+
+// destinations returns the channels the routing rules send msg to, nil
+// when no rule matches and the built-in channels receive it as usual
+func (n *Notifier) destinations(msg types.NotificationMessage) map[string]bool {
+	var destinations map[string]bool
+	for _, r := range n.routes {
+		if !r.matches(msg) {
+			continue
+		}
+		if destinations == nil {
+			destinations = make(map[string]bool)
+		}
+		for _, channel := range r.Channels {
+			destinations[channel] = true
+		}
+		if !r.Continue {
+			break
+		}
+	}
+	return destinations
+}
+
+// routed reports whether channel receives a message sent to destinations.
+// Channel filters still apply on top
+func routed(destinations map[string]bool, channel string) bool {
+	return destinations == nil || destinations[channel]
+}
+
+// RoutesToPagerDuty reports whether a routing rule sends a proposal of the
+// network with chainID to PagerDuty. Incidents are about proposals, so the
+// alert types of the rules do not apply
+func (n *Notifier) RoutesToPagerDuty(chainID string, proposal types.Proposal) bool {
+	for _, r := range n.routes {
+		if slices.Contains(r.Channels, types.RoutePagerDuty) && r.matchesProposal(chainID, &proposal) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendRouted delivers msg to the named channels among destinations, in
+// name order, returning how many got it. Chat channels are batched like the
+// built-in ones, and only Telegram messages can be edited later
+func (n *Notifier) sendRouted(destinations map[string]bool, msg types.NotificationMessage) ([]types.MessageRef, int, []error) {
+	var refs []types.MessageRef
+	var errors []error
+	delivered := 0
+	for _, name := range n.channelNames() {
+		if !destinations[name] || n.isPaused(name, msg.Title) {
+			continue
+		}
+		if n.channels[name].Type != types.ChannelWebhook && !n.admit(name, msg) {
+			continue
+		}
+		if n.skipDryRun(name, msg) {
+			continue
+		}
+		ref, err := n.sendChannel(n.channels[name], msg)
+		n.recordDelivery(name, err)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		delivered++
+		if ref.Channel != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, delivered, errors
+}
+
+// sendChannel sends msg to a named channel
+func (n *Notifier) sendChannel(channel types.ChannelConfig, msg types.NotificationMessage) (types.MessageRef, error) {
+	switch channel.Type {
+	case types.ChannelTelegram:
+		if n.telegram == nil {
+			return types.MessageRef{}, fmt.Errorf("telegram is not enabled")
+		}
+		return n.sendTelegramChat(channel.ChatID, nil, msg)
+	case types.ChannelSlack:
+		msg = n.guardURLs(nil, msg)
+		if err := n.postSlackWebhook(channel.URL, n.format("slack", nil, msg, formatSlackMessage)); err != nil {
+			return types.MessageRef{}, err
+		}
+		if msg.Details != "" {
+			return types.MessageRef{}, n.postSlackWebhook(channel.URL, msg.Details)
+		}
+		return types.MessageRef{}, nil
+	case types.ChannelMatrix:
+		if !n.matrix.Enabled {
+			return types.MessageRef{}, fmt.Errorf("matrix is not enabled")
+		}
+		return n.sendMatrixRoom(channel.RoomID, nil, msg)
+	default:
+		webhook := types.WebhookConfig{URL: channel.URL, Secret: channel.Secret, PayloadStyle: channel.PayloadStyle}
+		return types.MessageRef{}, n.sendWebhookNotification(webhook, msg)
+	}
+}
+
+// channelNames returns the names of the named channels, sorted
+func (n *Notifier) channelNames() []string {
+	names := make([]string, 0, len(n.channels))
+	for name := range n.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
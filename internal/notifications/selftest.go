@@ -0,0 +1,135 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// ChannelStatus is the result of a channel self-test
+type ChannelStatus struct {
+	Channel string
+	OK      bool
+	Error   error
+}
+
+// SelfTest verifies the credentials of every enabled channel with a
+// lightweight API call, without sending a message. Results count as
+// deliveries in the channel health until the next real one
+func (n *Notifier) SelfTest(ctx context.Context) []ChannelStatus {
+	var statuses []ChannelStatus
+
+	if n.telegram != nil {
+		statuses = append(statuses, checkStatus("telegram", n.testTelegram()))
+	}
+	if n.slack.Enabled {
+		statuses = append(statuses, checkStatus("slack", n.testSlack(ctx)))
+	}
+	if n.matrix.Enabled {
+		statuses = append(statuses, checkStatus("matrix", n.testMatrix(ctx)))
+	}
+
+	for _, status := range statuses {
+		n.recordDelivery(status.Channel, status.Error)
+	}
+	return statuses
+}
+
+// SendTest sends msg to every enabled channel, ignoring their role, alert
+// type and severity filters, and reports the result of each. With
+// pagerDuty, a test incident is also opened and resolved right away
+func (n *Notifier) SendTest(msg types.NotificationMessage, pagerDuty bool) []ChannelStatus {
+	var statuses []ChannelStatus
+
+	if n.telegram != nil {
+		_, err := n.sendTelegramNotification(msg)
+		statuses = append(statuses, checkStatus("telegram", err))
+	}
+	if n.slack.Enabled {
+		_, err := n.sendSlackNotification(msg)
+		statuses = append(statuses, checkStatus("slack", err))
+	}
+	if n.matrix.Enabled {
+		_, err := n.sendMatrixNotification(msg)
+		statuses = append(statuses, checkStatus("matrix", err))
+	}
+	for _, webhook := range n.webhooks {
+		statuses = append(statuses, checkStatus(webhookChannel(webhook), n.sendWebhookNotification(webhook, msg)))
+	}
+	for _, name := range n.channelNames() {
+		_, err := n.sendChannel(n.channels[name], msg)
+		statuses = append(statuses, checkStatus(name, err))
+	}
+	if pagerDuty && n.pagerDuty.Enabled {
+		statuses = append(statuses, checkStatus("pagerduty", n.testPagerDuty(msg)))
+	}
+
+	return statuses
+}
+
+// testPagerDuty opens a test incident and resolves it immediately
+func (n *Notifier) testPagerDuty(msg types.NotificationMessage) error {
+	dedupKey := fmt.Sprintf("governance-alerts-test-%d", time.Now().Unix())
+	err := n.TriggerIncident(Incident{
+		DedupKey: dedupKey,
+		Summary:  msg.Title,
+		Source:   msg.ChainID,
+		Severity: types.SeverityInfo,
+		Details:  map[string]interface{}{"content": msg.Content},
+	})
+	if err != nil {
+		return err
+	}
+	return n.ResolveIncident(dedupKey)
+}
+
+// checkStatus builds a ChannelStatus from a test error
+func checkStatus(channel string, err error) ChannelStatus {
+	return ChannelStatus{Channel: channel, OK: err == nil, Error: err}
+}
+
+// testTelegram verifies the bot token (getMe ran when the bot was created)
+// and that the bot can access the configured chat
+func (n *Notifier) testTelegram() error {
+	if n.telegram.Me == nil {
+		return fmt.Errorf("bot identity unknown, token may be invalid")
+	}
+	if _, err := n.telegram.ChatByID(n.telegramChatID); err != nil {
+		return fmt.Errorf("bot @%s cannot access chat %d: %w", n.telegram.Me.Username, n.telegramChatID, err)
+	}
+	return nil
+}
+
+// testSlack checks that the webhook URL resolves to a live webhook, or the
+// bot token with auth.test. Slack answers 404 or 410 for deleted webhooks
+// and archived channels
+func (n *Notifier) testSlack(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if n.slack.BotToken != "" {
+		if err := n.slackAPI(ctx, "auth.test", map[string]string{}, nil); err != nil {
+			return fmt.Errorf("bot token rejected: %w", err)
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, n.slack.WebhookURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook unreachable: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("webhook rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
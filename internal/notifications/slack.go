@@ -0,0 +1,320 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slackAPIURL is the base URL of the Slack Web API
+const slackAPIURL = "https://slack.com/api/"
+
+// slackEventMaxAge is how old a signed Events API request may be before it
+// is rejected as a possible replay
+const slackEventMaxAge = 5 * time.Minute
+
+// ReactionHandler handles an emoji reaction added by user to the message
+// ref, reaction is the emoji name without colons
+type ReactionHandler func(ref types.MessageRef, reaction, user string)
+
+// HandleReactions registers the handler of emoji reactions on messages
+func (n *Notifier) HandleReactions(handler ReactionHandler) {
+	n.reactionHandler = handler
+}
+
+// TracksReactions reports whether reactions on sent messages are reported,
+// which needs messages posted with a Slack bot token
+func (n *Notifier) TracksReactions() bool {
+	return n.slack.Enabled && n.slack.BotToken != "" && n.slack.SigningSecret != ""
+}
+
+// ReplySlack posts text in the thread of a Slack message
+func (n *Notifier) ReplySlack(ref types.MessageRef, text string) error {
+	if ref.Channel != "slack" || ref.Timestamp == "" || n.slack.BotToken == "" {
+		return nil
+	}
+	if n.skipDryRun("slack", types.NotificationMessage{Title: "Thread reply", Content: text}) {
+		return nil
+	}
+	_, err := n.postSlackAPI(text, ref.Timestamp)
+	return err
+}
+
+// postSlackAPI posts text to the configured channel with chat.postMessage,
+// in the thread of threadTS if set
+func (n *Notifier) postSlackAPI(text, threadTS string) (types.MessageRef, error) {
+	payload := map[string]interface{}{"channel": n.slack.Channel, "text": text}
+	if threadTS != "" {
+		payload["thread_ts"] = threadTS
+	}
+
+	var response struct {
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+	}
+	err := n.paced("slack "+n.slack.Channel, func() error {
+		return n.slackAPI(context.Background(), "chat.postMessage", payload, &response)
+	})
+	if err != nil {
+		return types.MessageRef{}, err
+	}
+
+	return types.MessageRef{
+		Channel:      "slack",
+		SlackChannel: response.Channel,
+		Timestamp:    response.TS,
+		Text:         text,
+	}, nil
+}
+
+// uploadSlackChart shares a tally chart in a Slack channel, given by ID as
+// files.completeUploadExternal requires, through the external upload flow:
+// an upload URL is requested, the PNG posted to it, then the upload shared
+func (n *Notifier) uploadSlackChart(channelID, title string, chart []byte) error {
+	return n.paced("slack "+n.slack.Channel, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var upload struct {
+			UploadURL string `json:"upload_url"`
+			FileID    string `json:"file_id"`
+		}
+		form := url.Values{"filename": {"tally.png"}, "length": {strconv.Itoa(len(chart))}}
+		if err := n.slackAPI(ctx, "files.getUploadURLExternal", form, &upload); err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, upload.UploadURL, bytes.NewReader(chart))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "image/png")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to upload chart: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("failed to upload chart: unexpected status code: %d", resp.StatusCode)
+		}
+
+		return n.slackAPI(ctx, "files.completeUploadExternal", map[string]interface{}{
+			"files":      []map[string]string{{"id": upload.FileID, "title": title}},
+			"channel_id": channelID,
+		}, nil)
+	})
+}
+
+// slackAPI calls a Slack Web API method with the bot token, decoding the
+// response into v when set. Payloads are sent as JSON, or form encoded when
+// given as url.Values for the methods that only accept forms
+func (n *Notifier) slackAPI(ctx context.Context, method string, payload interface{}, v interface{}) error {
+	contentType := "application/json; charset=utf-8"
+	var body []byte
+	if form, ok := payload.(url.Values); ok {
+		contentType = "application/x-www-form-urlencoded"
+		body = []byte(form.Encode())
+	} else {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, slackAPIURL+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+n.slack.BotToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return rateLimited(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// The Web API reports errors in the body with status 200
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("%s failed: %s", method, result.Error)
+	}
+
+	if v != nil {
+		return json.Unmarshal(raw, v)
+	}
+	return nil
+}
+
+// SlackEvents returns the handler of Slack Events API requests, which
+// reports reaction_added events on the configured channel to the reaction
+// handler. Requests must be signed with the signing secret, and are
+// rejected without one
+func (n *Notifier) SlackEvents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if !n.TracksReactions() {
+			http.Error(w, "slack bot token and signing secret not configured", http.StatusNotFound)
+			return
+		}
+		if err := verifySlackSignature(n.slack.SigningSecret, r.Header, body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var event struct {
+			Type      string `json:"type"`
+			Challenge string `json:"challenge"`
+			Event     struct {
+				Type     string `json:"type"`
+				User     string `json:"user"`
+				Reaction string `json:"reaction"`
+				Item     struct {
+					Type    string `json:"type"`
+					Channel string `json:"channel"`
+					TS      string `json:"ts"`
+				} `json:"item"`
+			} `json:"event"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "invalid event", http.StatusBadRequest)
+			return
+		}
+
+		switch event.Type {
+		case "url_verification":
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, event.Challenge)
+			return
+		case "event_callback":
+			if event.Event.Type == "reaction_added" && event.Event.Item.Type == "message" && n.reactionHandler != nil {
+				ref := types.MessageRef{
+					Channel:      "slack",
+					SlackChannel: event.Event.Item.Channel,
+					Timestamp:    event.Event.Item.TS,
+				}
+				n.reactionHandler(ref, event.Event.Reaction, "<@"+event.Event.User+">")
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// SlackCommands returns the handler of Slack slash commands, which serves
+// the bot commands that do not change state from the configured channel.
+// Requests must be signed with the signing secret. Slack expects an answer
+// within 3 seconds, so the command is acknowledged right away and its reply
+// posted to the channel once ready
+func (n *Notifier) SlackCommands() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if !n.TracksReactions() {
+			http.Error(w, "slack bot token and signing secret not configured", http.StatusNotFound)
+			return
+		}
+		if err := verifySlackSignature(n.slack.SigningSecret, r.Header, body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "invalid command", http.StatusBadRequest)
+			return
+		}
+
+		command := form.Get("command")
+		channel := strings.TrimPrefix(n.slack.Channel, "#")
+		if channel != form.Get("channel_id") && channel != form.Get("channel_name") {
+			writeSlackEphemeral(w, fmt.Sprintf("%s is only available in the alerts channel", command))
+			return
+		}
+		n.commandsMu.RLock()
+		handler, ok := n.commands[command]
+		n.commandsMu.RUnlock()
+		if !ok {
+			writeSlackEphemeral(w, fmt.Sprintf("Unknown command %s", command))
+			return
+		}
+
+		user := "<@" + form.Get("user_id") + ">"
+		args := strings.Fields(form.Get("text"))
+		logrus.WithFields(logrus.Fields{"command": command, "args": strings.Join(args, " "), "user": user, "event": "command"}).Info("Slack command")
+		go func() {
+			if _, err := n.postSlackAPI(handler(args, user), ""); err != nil {
+				logrus.WithField("command", command).WithError(err).Error("Failed to post Slack command reply")
+			}
+		}()
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// writeSlackEphemeral answers a slash command with text only its sender sees
+func writeSlackEphemeral(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"response_type": "ephemeral", "text": text})
+}
+
+// verifySlackSignature checks the v0 signature Slack computes over the
+// request timestamp and body with the signing secret
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	if secret == "" {
+		// An empty key would let anyone compute valid signatures
+		return fmt.Errorf("signing secret not configured")
+	}
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing request timestamp")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackEventMaxAge || age < -slackEventMaxAge {
+		return fmt.Errorf("stale request timestamp")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"math/big"
+	"strings"
+	"text/template"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// templateFuncs are the helpers available to message templates, besides
+// the text/template built-ins such as html and printf
+var templateFuncs = template.FuncMap{
+	"timeLeft":  func(t time.Time) string { return timeLeft(t, time.Now()) },
+	"hoursLeft": func(t time.Time) float64 { return time.Until(t).Hours() },
+	"ago":       func(t time.Time) string { return amounts.Duration(time.Since(t)) },
+	"until":     func(t time.Time) string { return amounts.In(time.Until(t)) },
+	"since":     func(t time.Time) string { return amounts.Ago(time.Since(t)) },
+	"date":      func(layout string, t time.Time) string { return t.UTC().Format(layout) },
+	"coins":     amounts.Coins,
+	"amount":    amounts.Amount,
+	"share":     tallyShare,
+	"typeName":  cosmosgov.TypeName,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"trim":      strings.TrimSpace,
+	"join":      func(sep string, list []string) string { return strings.Join(list, sep) },
+	"truncate":  truncateText,
+	"default": func(fallback string, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// timeLeft describes the time left until t, such as "2 days 5 hours", or
+// "ended" once t has passed
+func timeLeft(t, now time.Time) string {
+	if !t.After(now) {
+		return "ended"
+	}
+	return amounts.Duration(t.Sub(now))
+}
+
+// tallyShare returns the percentage of a tally option among every option
+// of the tally, 0 for an empty tally
+func tallyShare(option types.TallyOption, tally []types.TallyOption) float64 {
+	total := new(big.Float)
+	for _, o := range tally {
+		if amount, ok := new(big.Float).SetString(o.Amount); ok {
+			total.Add(total, amount)
+		}
+	}
+	amount, ok := new(big.Float).SetString(option.Amount)
+	if !ok || total.Sign() == 0 {
+		return 0
+	}
+	share, _ := new(big.Float).Quo(amount, total).Float64()
+	return share * 100
+}
+
+// truncateText shortens text to at most max runes, ending with an ellipsis
+// when shortened
+func truncateText(max int, text string) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	if max <= 1 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,288 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// templateChannels are the channels whose messages can be templated. Each
+// uses <channel>.<event>.tmpl from the templates directory for the messages
+// of an event and <channel>.tmpl for the others, when present. Events are
+// alert types, message for messages that are not proposal alerts
+var templateChannels = []string{"telegram", "slack", "matrix"}
+
+// templatePollInterval is how often the templates directory is checked for
+// changes
+const templatePollInterval = 5 * time.Second
+
+// sampleProposal is the proposal of the sample proposal alert
+var sampleProposal = types.Proposal{
+	ID:           1,
+	Title:        "Sample proposal",
+	Description:  "Sample description",
+	Status:       "PROPOSAL_STATUS_VOTING_PERIOD",
+	SubmitTime:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	DepositEnd:   time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+	VotingStart:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+	VotingEnd:    time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC),
+	Network:      "Sample Network",
+	Category:     "text",
+	TotalDeposit: []types.Coin{{Denom: "uatom", Amount: "1000000"}},
+	FinalTally:   &types.TallyResult{Yes: "1", No: "0", Abstain: "0", NoWithVeto: "0"},
+}
+
+// sampleMessages are rendered to validate templates before they are used:
+// a proposal alert with its proposal and tally, and an ops message without
+var sampleMessages = []types.NotificationMessage{
+	{
+		Title:      "Template validation",
+		Content:    "Sample content",
+		Network:    "Sample Network",
+		ChainID:    "sample-1",
+		ProposalID: 1,
+		Role:       types.RoleCommunity,
+		AlertType:  types.AlertVotingEnd,
+		Severity:   types.SeverityInfo,
+		Proposal:   &sampleProposal,
+		Tally:      []types.TallyOption{{Option: types.VoteYes, Label: "Yes", Amount: "1"}, {Option: types.VoteNo, Label: "No", Amount: "0"}},
+	},
+	{
+		Title:   "Template validation",
+		Content: "Sample content",
+		Network: "Sample Network",
+		ChainID: "sample-1",
+		Role:    types.RoleOps,
+	},
+}
+
+// templateSet holds the last good version of each template and reloads
+// them when their files change. Templates are named after their file
+// without the .tmpl extension
+type templateSet struct {
+	dir       string
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+	modTimes  map[string]time.Time
+	unknown   map[string]bool
+}
+
+// loadTemplateSet loads the templates in dir. Unlike reloads, a broken
+// template at startup is an error
+func loadTemplateSet(dir string) (*templateSet, error) {
+	set := &templateSet{
+		dir:       dir,
+		templates: make(map[string]*template.Template),
+		modTimes:  make(map[string]time.Time),
+		unknown:   make(map[string]bool),
+	}
+	if errs := set.reload(); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return set, nil
+}
+
+// reload parses the templates whose files changed since the last reload.
+// A template that fails to parse or render keeps its last good version
+func (t *templateSet) reload() []error {
+	names, errs := t.templateNames()
+
+	t.mu.Lock()
+	for name := range t.modTimes {
+		if !names[name] {
+			if _, ok := t.templates[name]; ok {
+				logrus.WithFields(logrus.Fields{"template": filepath.Join(t.dir, name+".tmpl"), "event": "channel"}).Info("Template removed")
+			}
+			delete(t.templates, name)
+			delete(t.modTimes, name)
+		}
+	}
+	t.mu.Unlock()
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		path := filepath.Join(t.dir, name+".tmpl")
+
+		info, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", path, err))
+			continue
+		}
+
+		t.mu.RLock()
+		unchanged := info.ModTime().Equal(t.modTimes[name])
+		t.mu.RUnlock()
+		if unchanged {
+			continue
+		}
+
+		tmpl, err := parseTemplate(path, templateEvent(name))
+
+		t.mu.Lock()
+		// Remember the broken version too so it is reported only once
+		t.modTimes[name] = info.ModTime()
+		if err == nil {
+			t.templates[name] = tmpl
+		}
+		t.mu.Unlock()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", path, err))
+			continue
+		}
+		logrus.WithFields(logrus.Fields{"template": path, "event": "channel"}).Info("Loaded template")
+	}
+	return errs
+}
+
+// templateNames lists the templates in the directory, none while it does
+// not exist. Files named after no channel or event are reported once, so a
+// typo does not go unnoticed
+func (t *templateSet) templateNames() (map[string]bool, []error) {
+	names := make(map[string]bool)
+	entries, err := os.ReadDir(t.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return names, []error{fmt.Errorf("templates directory %s: %w", t.dir, err)}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".tmpl")
+		if !ok || entry.IsDir() || t.unknown[name] {
+			continue
+		}
+		channel, event, _ := strings.Cut(name, ".")
+		if !slices.Contains(templateChannels, channel) {
+			t.unknown[name] = true
+			errs = append(errs, fmt.Errorf("template %s: unknown channel %q (expected %s)", entry.Name(), channel, strings.Join(templateChannels, " or ")))
+			continue
+		}
+		if event != "" && event != webhookEventMessage && !slices.Contains(types.AlertTypes, event) {
+			t.unknown[name] = true
+			errs = append(errs, fmt.Errorf("template %s: unknown event %q (expected %s or one of %s)", entry.Name(), event, webhookEventMessage, strings.Join(types.AlertTypes, ", ")))
+			continue
+		}
+		names[name] = true
+	}
+	return names, errs
+}
+
+// templateEvent returns the event of a template name, empty for the
+// template of every event of a channel
+func templateEvent(name string) string {
+	_, event, _ := strings.Cut(name, ".")
+	return event
+}
+
+// parseTemplate parses a template file and renders it once with the sample
+// messages of its event to catch references to unknown fields. Templates
+// of every event must also render messages without a proposal
+func parseTemplate(path, event string) (*template.Template, error) {
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	alertEvent := event != "" && event != webhookEventMessage
+	for _, msg := range sampleMessages {
+		// Templates of an event only render messages of that event
+		proposalAlert := msg.AlertType != ""
+		if event == webhookEventMessage && proposalAlert || alertEvent && !proposalAlert {
+			continue
+		}
+		if alertEvent {
+			msg.AlertType = event
+		}
+		if err := tmpl.Execute(io.Discard, msg); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// render renders msg with the template of channel for its event, or else
+// the template of the channel, reporting false when there is neither
+func (t *templateSet) render(channel string, msg types.NotificationMessage) (string, bool, error) {
+	event := msg.AlertType
+	if event == "" {
+		event = webhookEventMessage
+	}
+
+	t.mu.RLock()
+	tmpl, ok := t.templates[channel+"."+event]
+	if !ok {
+		tmpl, ok = t.templates[channel]
+	}
+	t.mu.RUnlock()
+	if !ok {
+		return "", false, nil
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, msg); err != nil {
+		return "", true, err
+	}
+	return b.String(), true, nil
+}
+
+// format renders msg for channel with its template if there is one, falling
+// back to the built-in format. Templates can reach the proposal text that
+// guardURLs does not rewrite, so what they render goes through the URL
+// policy of a channel serving roles
+func (n *Notifier) format(channel string, roles []string, msg types.NotificationMessage, builtin func(types.NotificationMessage) string) string {
+	if n.templates != nil {
+		text, ok, err := n.templates.render(channel, msg)
+		if ok && err == nil {
+			if n.urlPolicyApplies(roles) {
+				text = n.rewriteURLs(text, msg.ExplorerURL)
+			}
+			return text
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"channel": channel, "event": "channel"}).WithError(err).Warn("Template failed, using built-in format")
+		}
+	}
+	return builtin(msg)
+}
+
+// WatchTemplates reloads changed templates until ctx is done, calling
+// onError for templates that fail validation. It returns immediately when
+// no templates directory is configured
+func (n *Notifier) WatchTemplates(ctx context.Context, onError func(error)) {
+	if n.templates == nil {
+		return
+	}
+
+	ticker := time.NewTicker(templatePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, err := range n.templates.reload() {
+				onError(err)
+			}
+		}
+	}
+}
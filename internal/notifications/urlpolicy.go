@@ -0,0 +1,136 @@
+package notifications
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// URL policy modes
+const (
+	// URLPolicyStrip replaces links with a placeholder
+	URLPolicyStrip = "strip"
+	// URLPolicyDefang rewrites links so they are readable but not clickable
+	URLPolicyDefang = "defang"
+)
+
+// urlPattern matches links with a scheme (https://, ipfs://...), starting
+// with www. or made of a bare domain name (example.com/claim), up to
+// whitespace or characters that usually close them. Domain names end with
+// a label of letters, so amounts and versions like 1.5 or v19.0 are not
+// mistaken for them; isLink then drops file and type names such as
+// app.toml that only look like domains
+var urlPattern = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.-]*://|www\.)[^\s<>"'()\[\]{}]+|\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,63}\b(?:[:/][^\s<>"'()\[\]{}]*)?`)
+
+// strippedLink replaces links removed by the strip policy
+const strippedLink = "[link removed]"
+
+// guardURLs applies the URL policy to msg when the channel serves one of
+// the policy's roles. Proposal text is written by anyone who pays the
+// deposit, so its links are removed or defanged before public channels
+// broadcast them; the explorer link of the alert is kept
+func (n *Notifier) guardURLs(channelRoles []string, msg types.NotificationMessage) types.NotificationMessage {
+	if !n.urlPolicyApplies(channelRoles) {
+		return msg
+	}
+
+	msg.Title = n.rewriteURLs(msg.Title, msg.ExplorerURL)
+	msg.Content = n.rewriteURLs(msg.Content, msg.ExplorerURL)
+	msg.Details = n.rewriteURLs(msg.Details, msg.ExplorerURL)
+	return msg
+}
+
+// urlPolicyApplies reports whether the URL policy covers a channel serving
+// channelRoles
+func (n *Notifier) urlPolicyApplies(channelRoles []string) bool {
+	return n.urlPolicy.Mode != "" && servesRoles(channelRoles, n.urlPolicy.Roles)
+}
+
+// rewriteURLs strips or defangs the links in text except keep. Names
+// following a slash are part of a path or of a message type URL such as
+// /cosmos.gov.v1.MsgExecLegacyContent and are left alone
+func (n *Notifier) rewriteURLs(text, keep string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range urlPattern.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && text[start-1] == '/' {
+			continue
+		}
+		link := text[start:end]
+		// Trailing punctuation ends the sentence rather than the link
+		trimmed := strings.TrimRight(link, ".,;:!?")
+		if !isLink(trimmed) {
+			continue
+		}
+		if keep != "" && (trimmed == keep || trimmed == html.EscapeString(keep)) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		if n.urlPolicy.Mode == URLPolicyDefang {
+			b.WriteString(defang(trimmed))
+		} else {
+			b.WriteString(strippedLink)
+		}
+		b.WriteString(link[len(trimmed):])
+		last = end
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// isLink reports whether a match of urlPattern is a link. Those with a
+// scheme or starting with www. are; bare domain names only when they end
+// with a top-level domain of the public suffix list, so config.toml or
+// cosmos.gov.v1beta1.TextProposal are left alone
+func isLink(match string) bool {
+	if strings.Contains(match, "://") || strings.HasPrefix(strings.ToLower(match), "www.") {
+		return true
+	}
+	host, _, _ := strings.Cut(match, "/")
+	host, _, _ = strings.Cut(host, ":")
+	tld := strings.ToLower(host[strings.LastIndex(host, ".")+1:])
+	_, icann := publicsuffix.PublicSuffix(tld)
+	return icann
+}
+
+// defang rewrites a link as hxxps://example[.]com/path, which chat clients
+// do not turn into a link
+func defang(link string) string {
+	scheme, rest, found := strings.Cut(link, "://")
+	if !found {
+		scheme, rest = "", link
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	defanged := strings.ReplaceAll(host, ".", "[.]")
+	if path != "" {
+		defanged += "/" + path
+	}
+	if scheme == "" {
+		return defanged
+	}
+	return strings.Replace(strings.Replace(scheme, "http", "hxxp", 1), "HTTP", "HXXP", 1) + "://" + defanged
+}
+
+// servesRoles reports whether a channel with channelRoles receives messages
+// of any of roles. Channels without roles receive every role
+func servesRoles(channelRoles, roles []string) bool {
+	if len(channelRoles) == 0 {
+		return true
+	}
+	for _, role := range channelRoles {
+		for _, r := range roles {
+			if role == r {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package notifications
+
+import (
+	"testing"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+func TestRewriteURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		text string
+		want string
+	}{
+		// Links
+		{"scheme", URLPolicyStrip, "Claim at https://evil.example.com/airdrop now", "Claim at [link removed] now"},
+		{"other scheme", URLPolicyStrip, "Spec at ipfs://bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi", "Spec at [link removed]"},
+		{"www", URLPolicyStrip, "See www.example.org.", "See [link removed]."},
+		{"bare domain", URLPolicyStrip, "Visit example.com/claim today", "Visit [link removed] today"},
+		{"bare subdomain", URLPolicyStrip, "Docs on docs.cosmos.network, read them", "Docs on [link removed], read them"},
+		{"bare domain with port", URLPolicyStrip, "RPC at rpc.example.io:26657", "RPC at [link removed]"},
+		{"new gTLD", URLPolicyStrip, "Free tokens at cosmos-airdrop.xyz!", "Free tokens at [link removed]!"},
+		{"defang", URLPolicyDefang, "Claim at https://evil.example.com/airdrop now", "Claim at hxxps://evil[.]example[.]com/airdrop now"},
+		{"defang bare domain", URLPolicyDefang, "Visit example.com/claim", "Visit example[.]com/claim"},
+		{"explorer link kept", URLPolicyStrip, "https://www.mintscan.io/cosmos/proposals/1", "https://www.mintscan.io/cosmos/proposals/1"},
+
+		// Names that only look like domains
+		{"config file", URLPolicyStrip, "Set minimum-gas-prices in app.toml", "Set minimum-gas-prices in app.toml"},
+		{"other config file", URLPolicyStrip, "Bump timeout_commit in config.toml.", "Bump timeout_commit in config.toml."},
+		{"type name", URLPolicyStrip, "A cosmos.gov.v1beta1.TextProposal with no messages", "A cosmos.gov.v1beta1.TextProposal with no messages"},
+		{"module path", URLPolicyStrip, "Migrates x/gov.v1 params", "Migrates x/gov.v1 params"},
+		{"type URL", URLPolicyStrip, "Executes /cosmos.gov.v1.MsgExecLegacyContent", "Executes /cosmos.gov.v1.MsgExecLegacyContent"},
+		{"version", URLPolicyStrip, "Upgrade to v19.0.1 at height 1.5M", "Upgrade to v19.0.1 at height 1.5M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Notifier{urlPolicy: types.URLPolicyConfig{Mode: tt.mode}}
+			if got := n.rewriteURLs(tt.text, "https://www.mintscan.io/cosmos/proposals/1"); got != tt.want {
+				t.Errorf("rewriteURLs(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/events"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// webhookEventMessage is the event of webhook deliveries that are not
+// proposal alerts, such as ops messages
+const webhookEventMessage = "message"
+
+// automationSummaryLength is the most characters of the message content
+// in the summary of automation payloads
+const automationSummaryLength = 200
+
+// automationPayload is the flat JSON body posted to webhooks of the
+// automation style, for low-code platforms whose triggers map top-level
+// fields: every field is a short string or a number
+type automationPayload struct {
+	Event         string `json:"event"`
+	SentAt        string `json:"sent_at"`
+	Title         string `json:"title"`
+	Summary       string `json:"summary,omitempty"`
+	Severity      string `json:"severity,omitempty"`
+	Network       string `json:"network"`
+	ChainID       string `json:"chain_id"`
+	ProposalID    uint64 `json:"proposal_id,omitempty"`
+	ProposalTitle string `json:"proposal_title,omitempty"`
+	ProposalType  string `json:"proposal_type,omitempty"`
+	Status        string `json:"status,omitempty"`
+	VotingEnd     string `json:"voting_end,omitempty"`
+	HoursLeft     *int   `json:"hours_left,omitempty"`
+	URL           string `json:"url,omitempty"`
+	TrackingURL   string `json:"tracking_url,omitempty"`
+}
+
+// newAutomationPayload flattens msg into an automation payload: the first
+// line of the content as summary, statuses without their PROPOSAL_STATUS_
+// prefix, the whole hours left to vote while voting is open and the URL of
+// the first tracking link that has one
+func newAutomationPayload(event string, msg types.NotificationMessage, now time.Time) automationPayload {
+	payload := automationPayload{
+		Event:      event,
+		SentAt:     now.UTC().Format(time.RFC3339),
+		Title:      msg.Title,
+		Severity:   msg.Severity,
+		Network:    msg.Network,
+		ChainID:    msg.ChainID,
+		ProposalID: msg.ProposalID,
+		URL:        msg.ExplorerURL,
+	}
+	for _, line := range strings.Split(msg.Content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			payload.Summary = line
+			break
+		}
+	}
+	if runes := []rune(payload.Summary); len(runes) > automationSummaryLength {
+		payload.Summary = string(runes[:automationSummaryLength]) + "..."
+	}
+
+	for _, link := range msg.Links {
+		if link.URL != "" {
+			payload.TrackingURL = link.URL
+			break
+		}
+	}
+
+	if proposal := msg.Proposal; proposal != nil {
+		payload.ProposalTitle = proposal.Title
+		payload.ProposalType = proposal.Category
+		payload.Status = strings.ToLower(strings.TrimPrefix(proposal.Status, "PROPOSAL_STATUS_"))
+		if !proposal.VotingEnd.IsZero() {
+			payload.VotingEnd = proposal.VotingEnd.UTC().Format(time.RFC3339)
+			if left := proposal.VotingEnd.Sub(now); left > 0 {
+				hours := int(left.Hours())
+				payload.HoursLeft = &hours
+			}
+		}
+	}
+	return payload
+}
+
+// acceptsWebhook reports whether a webhook receives msg
+func acceptsWebhook(webhook types.WebhookConfig, msg types.NotificationMessage) bool {
+	return acceptsRole(webhook.Roles, msg.Role) &&
+		acceptsAlertType(webhook.AlertTypes, msg.AlertType) && acceptsSeverity(webhook.MinSeverity, msg.Severity) &&
+		acceptsProposalType(webhook.ProposalTypes, msg)
+}
+
+// webhookChannel names a webhook in channel health and self-tests
+func webhookChannel(webhook types.WebhookConfig) string {
+	if u, err := url.Parse(webhook.URL); err == nil && u.Host != "" {
+		return "webhook " + u.Host
+	}
+	return "webhook"
+}
+
+// sendWebhookNotification posts msg as JSON to a webhook, in its payload
+// style: a CloudEvents envelope with the message as data, or the flat
+// automation payload whose event is the alert type of proposal alerts.
+// Retries send the same body, so the same event ID
+func (n *Notifier) sendWebhookNotification(webhook types.WebhookConfig, msg types.NotificationMessage) error {
+	event, chainID := events.AlertType(msg.AlertType), msg.ChainID
+	if msg.Event != "" {
+		// Service events are about no chain
+		event, chainID = msg.Event, ""
+	}
+	contentType := events.ContentType
+	var payload interface{} = events.NewEvent(event, chainID, msg.ProposalID, msg)
+	if webhook.PayloadStyle == types.PayloadStyleAutomation {
+		event = msg.AlertType
+		if event == "" {
+			event = webhookEventMessage
+		}
+		contentType = "application/json"
+		payload = newAutomationPayload(event, msg, time.Now())
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return n.paced(webhookChannel(webhook), func() error {
+		return postWebhook(webhook, event, contentType, body)
+	})
+}
+
+// postWebhook posts a JSON body to a webhook, signed if it has a secret.
+// Any 2xx status is a success
+func postWebhook(webhook types.WebhookConfig, event, contentType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Event", event)
+	if webhook.Secret != "" {
+		req.Header.Set("X-Signature-256", signWebhook(webhook.Secret, body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return rateLimited(resp)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signWebhook returns the X-Signature-256 header of body, "sha256=" and the
+// hex HMAC-SHA256 of the body keyed with secret
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
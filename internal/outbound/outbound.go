@@ -0,0 +1,271 @@
+// Package outbound manages the auxiliary services the alerts are enriched
+// with, such as status pages. Each dependency has its own timeout, request
+// spacing, response cache and backoff, honoring Retry-After, so an outage
+// of one only degrades the alerts it enriches instead of delaying them
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrUnavailable is returned while a host of a dependency is backing off
+// after failures and no cached response is available
+var ErrUnavailable = errors.New("dependency backing off after failures")
+
+// Backoff bounds, the backoff doubles with every consecutive failure
+const (
+	minBackoff = 30 * time.Second
+	maxBackoff = 30 * time.Minute
+)
+
+// maxBodySize caps the response bodies read
+const maxBodySize = 8 << 20
+
+// Options configures a dependency. Requests time out after Timeout,
+// requests to a host are spaced by MinInterval and successful responses
+// are reused for CacheTTL
+type Options struct {
+	Timeout     time.Duration
+	MinInterval time.Duration
+	CacheTTL    time.Duration
+}
+
+// Dependency is an auxiliary service, tracked per host
+type Dependency struct {
+	name    string
+	options Options
+	client  *http.Client
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+	cache map[string]cachedResponse
+}
+
+// hostState is the rate limit, backoff and health of a host
+type hostState struct {
+	next         time.Time
+	failures     int
+	backoffUntil time.Time
+	lastSuccess  time.Time
+	lastError    string
+	lastErrorAt  time.Time
+}
+
+// cachedResponse is a successful response body
+type cachedResponse struct {
+	body    []byte
+	fetched time.Time
+}
+
+var (
+	registryMu   sync.Mutex
+	dependencies = make(map[string]*Dependency)
+)
+
+// Register returns the dependency of a name, creating it with options the
+// first time
+func Register(name string, options Options) *Dependency {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if dep, ok := dependencies[name]; ok {
+		return dep
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	dep := &Dependency{
+		name:    name,
+		options: options,
+		// The default transport is guarded by privacy mode
+		client: &http.Client{Timeout: options.Timeout},
+		hosts:  make(map[string]*hostState),
+		cache:  make(map[string]cachedResponse),
+	}
+	dependencies[name] = dep
+	return dep
+}
+
+// Get fetches rawURL. A cached response younger than the cache TTL is
+// returned without a request, and an older one while the host is backing
+// off or when the request fails, so callers degrade to stale data before
+// failing
+func (d *Dependency) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s URL: %w", d.name, err)
+	}
+	host := parsed.Host
+
+	d.mu.Lock()
+	cached, isCached := d.cache[rawURL]
+	if isCached && time.Since(cached.fetched) < d.options.CacheTTL {
+		d.mu.Unlock()
+		return cached.body, nil
+	}
+	state := d.host(host)
+	if time.Now().Before(state.backoffUntil) {
+		d.mu.Unlock()
+		if isCached {
+			return cached.body, nil
+		}
+		return nil, fmt.Errorf("%s %s: %w", d.name, host, ErrUnavailable)
+	}
+	// Reserve the next slot of the host before waiting for this one
+	wait := time.Until(state.next)
+	state.next = time.Now().Add(max(wait, 0) + d.options.MinInterval)
+	d.mu.Unlock()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	body, retryAfter, err := d.fetch(ctx, rawURL)
+	d.record(host, rawURL, body, retryAfter, err)
+	if err != nil {
+		if isCached {
+			return cached.body, nil
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
+// fetch sends a GET request, returning the Retry-After of failed responses
+func (d *Dependency) fetch(ctx context.Context, rawURL string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s request failed: %w", d.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), fmt.Errorf("%s returned status %d", d.name, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s response: %w", d.name, err)
+	}
+	return body, 0, nil
+}
+
+// record updates the health and cache of a host after a request. Failures
+// back the host off for a doubling period, or for its Retry-After when
+// longer
+func (d *Dependency) record(host, rawURL string, body []byte, retryAfter time.Duration, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.host(host)
+	now := time.Now()
+	if err == nil {
+		state.failures = 0
+		state.backoffUntil = time.Time{}
+		state.lastSuccess = now
+		d.cache[rawURL] = cachedResponse{body: body, fetched: now}
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	backoff := min(minBackoff<<min(state.failures, 10), maxBackoff)
+	backoff = max(backoff, retryAfter)
+	state.failures++
+	state.backoffUntil = now.Add(backoff)
+	state.lastError = err.Error()
+	state.lastErrorAt = now
+}
+
+// host returns the state of a host, d.mu must be held
+func (d *Dependency) host(host string) *hostState {
+	state, ok := d.hosts[host]
+	if !ok {
+		state = &hostState{}
+		d.hosts[host] = state
+	}
+	return state
+}
+
+// Health is the state of a host of a dependency
+type Health struct {
+	Dependency   string    `json:"dependency"`
+	Host         string    `json:"host"`
+	OK           bool      `json:"ok"`
+	LastSuccess  time.Time `json:"last_success,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastErrorAt  time.Time `json:"last_error_at,omitempty"`
+	BackoffUntil time.Time `json:"backoff_until,omitempty"`
+}
+
+// Healths returns the health of every host contacted, by dependency and
+// host
+func Healths() []Health {
+	registryMu.Lock()
+	deps := make([]*Dependency, 0, len(dependencies))
+	for _, dep := range dependencies {
+		deps = append(deps, dep)
+	}
+	registryMu.Unlock()
+
+	var healths []Health
+	now := time.Now()
+	for _, dep := range deps {
+		dep.mu.Lock()
+		for host, state := range dep.hosts {
+			health := Health{
+				Dependency:  dep.name,
+				Host:        host,
+				OK:          state.failures == 0,
+				LastSuccess: state.lastSuccess,
+				LastError:   state.lastError,
+				LastErrorAt: state.lastErrorAt,
+			}
+			if state.backoffUntil.After(now) {
+				health.BackoffUntil = state.backoffUntil
+			}
+			healths = append(healths, health)
+		}
+		dep.mu.Unlock()
+	}
+	sort.Slice(healths, func(i, j int) bool {
+		if healths[i].Dependency != healths[j].Dependency {
+			return healths[i].Dependency < healths[j].Dependency
+		}
+		return healths[i].Host < healths[j].Host
+	})
+	return healths
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP
+// date, returning 0 when absent or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
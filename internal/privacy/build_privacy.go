@@ -0,0 +1,6 @@
+//go:build privacy
+
+package privacy
+
+// buildEnabled forces privacy mode on in binaries built with -tags privacy
+const buildEnabled = true
@@ -0,0 +1,245 @@
+// Package privacy implements privacy mode, which guarantees that the only
+// outbound connections are to the configured LCD endpoints, notification
+// channels and explicitly allowed hosts, failing closed for anything else.
+// It is enabled with privacy.enabled in the configuration or compiled in
+// with the privacy build tag
+package privacy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// telegramAPIHost is the host of the Telegram Bot API
+const telegramAPIHost = "api.telegram.org"
+
+// slackAPIHost is the host of the Slack Web API
+const slackAPIHost = "slack.com"
+
+// slackFilesHost is the host tally charts are uploaded to with a Slack bot
+// token
+const slackFilesHost = "files.slack.com"
+
+// pagerDutyEventsHost is the host of the PagerDuty Events API
+const pagerDutyEventsHost = "events.pagerduty.com"
+
+// Hosts of the BigQuery API and of the Google OAuth token endpoint its
+// service account keys are exchanged at
+const (
+	bigQueryAPIHost = "bigquery.googleapis.com"
+	googleOAuthHost = "oauth2.googleapis.com"
+)
+
+// ErrBlocked is returned for requests to hosts outside the allow-list
+var ErrBlocked = errors.New("outbound host not allowed in privacy mode")
+
+var (
+	mu      sync.RWMutex
+	allowed map[string]bool
+)
+
+// Enabled reports whether privacy mode is on
+func Enabled(cfg types.PrivacyConfig) bool {
+	return buildEnabled || cfg.Enabled
+}
+
+// Active reports whether the allow-list has been installed. Optional
+// features that reach third-party services must check it and stay off
+func Active() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return allowed != nil
+}
+
+// AllowedHosts returns the hosts of the configured LCD, failover and
+// archive endpoints, indexers, notification channels, named routing
+// channels and secret managers, and the extra allowed hosts
+func AllowedHosts(cfg *types.Config) []string {
+	hosts := make(map[string]bool)
+	for _, host := range cfg.Privacy.AllowedHosts {
+		hosts[strings.ToLower(host)] = true
+	}
+	for _, network := range cfg.Networks {
+		endpoints := []string{network.RestEndpoint, network.Indexer.URL, network.Indexer.ProposalsURL, network.Indexer.TallyURL, network.Incidents.FeedURL, network.HeartbeatURL}
+		endpoints = append(endpoints, network.RestEndpoints...)
+		endpoints = append(endpoints, network.GRPCEndpoints...)
+		for _, endpoint := range append(endpoints, network.ArchiveEndpoints...) {
+			if host := hostOf(endpoint); host != "" {
+				hosts[host] = true
+			}
+		}
+	}
+	if cfg.Notifications.Telegram.Enabled {
+		hosts[telegramAPIHost] = true
+	}
+	if cfg.Notifications.Slack.Enabled {
+		if host := hostOf(cfg.Notifications.Slack.WebhookURL); host != "" {
+			hosts[host] = true
+		}
+		if cfg.Notifications.Slack.BotToken != "" {
+			hosts[slackAPIHost] = true
+			if cfg.Notifications.TallyCharts {
+				hosts[slackFilesHost] = true
+			}
+		}
+	}
+	if cfg.Notifications.Matrix.Enabled {
+		if host := hostOf(cfg.Notifications.Matrix.HomeserverURL); host != "" {
+			hosts[host] = true
+		}
+	}
+	if cfg.Notifications.PagerDuty.Enabled {
+		hosts[pagerDutyEventsHost] = true
+	}
+	for _, webhook := range cfg.Notifications.Webhooks {
+		if host := hostOf(webhook.URL); host != "" {
+			hosts[host] = true
+		}
+	}
+	// Named Slack and webhook channels of routing rules post to their own
+	// URLs, the other types reuse the built-in channels' hosts
+	for _, channel := range cfg.Notifications.Channels {
+		if host := hostOf(channel.URL); host != "" {
+			hosts[host] = true
+		}
+	}
+	if host := hostOf(cfg.Health.HeartbeatURL); host != "" {
+		hosts[host] = true
+	}
+	// Secrets are fetched through the guard, at startup and on reload
+	if host := hostOf(cfg.Secrets.Vault.Address); host != "" {
+		hosts[host] = true
+	}
+	if host := hostOf(cfg.Secrets.AWS.Endpoint); host != "" {
+		hosts[host] = true
+	} else if cfg.Secrets.AWS.Region != "" {
+		hosts[fmt.Sprintf("secretsmanager.%s.amazonaws.com", cfg.Secrets.AWS.Region)] = true
+	}
+	// So is the chain registry, registry.url defaulting to the public one
+	if len(cfg.NetworksFromRegistry) > 0 {
+		if host := hostOf(cfg.Registry.URL); host != "" {
+			hosts[host] = true
+		}
+	}
+	if cfg.Analytics.Enabled {
+		switch cfg.Analytics.Backend {
+		case types.AnalyticsClickHouse:
+			if host := hostOf(cfg.Analytics.ClickHouse.URL); host != "" {
+				hosts[host] = true
+			}
+		case types.AnalyticsBigQuery:
+			hosts[bigQueryAPIHost] = true
+			hosts[googleOAuthHost] = true
+		}
+	}
+
+	list := make([]string, 0, len(hosts))
+	for host := range hosts {
+		list = append(list, host)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Install restricts outbound traffic to the allowed hosts of cfg when
+// privacy mode is enabled. It guards http.DefaultTransport, which the
+// notification channels use, and every transport passed through Wrap.
+// Installing again replaces the allow-list, as config.LoadConfig installs
+// the guard before fetching remote configuration
+func Install(cfg *types.Config) {
+	if !Enabled(cfg.Privacy) {
+		return
+	}
+
+	hosts := make(map[string]bool)
+	for _, host := range AllowedHosts(cfg) {
+		hosts[host] = true
+	}
+
+	mu.Lock()
+	installed := allowed != nil
+	allowed = hosts
+	mu.Unlock()
+
+	if !installed {
+		http.DefaultTransport = &guardTransport{next: http.DefaultTransport}
+	}
+}
+
+// Update replaces the allow-list with the allowed hosts of a reloaded cfg.
+// It does nothing when privacy mode is not installed
+func Update(cfg *types.Config) {
+	if !Active() {
+		return
+	}
+
+	hosts := make(map[string]bool)
+	for _, host := range AllowedHosts(cfg) {
+		hosts[host] = true
+	}
+
+	mu.Lock()
+	allowed = hosts
+	mu.Unlock()
+}
+
+// Wrap returns next guarded by the allow-list, or next unchanged when
+// privacy mode is not installed
+func Wrap(next http.RoundTripper) http.RoundTripper {
+	if !Active() {
+		return next
+	}
+	return &guardTransport{next: next}
+}
+
+// guardTransport rejects requests to hosts outside the allow-list
+type guardTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *guardTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Hostname())
+
+	if !isAllowed(host) {
+		logrus.WithFields(logrus.Fields{"host": host, "event": "privacy_blocked"}).Warn("Blocked outbound request (privacy mode)")
+		return nil, fmt.Errorf("%w: %s (add it to privacy.allowed_hosts if it is expected)", ErrBlocked, host)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// isAllowed reports whether host is on the allow-list, directly or through
+// a *.domain wildcard covering its subdomains
+func isAllowed(host string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if allowed[host] {
+		return true
+	}
+	for domain := strings.Index(host, "."); domain >= 0; domain = strings.Index(host, ".") {
+		host = host[domain+1:]
+		if allowed["*."+host] {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOf returns the lowercased host name of a URL, or "" if invalid
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
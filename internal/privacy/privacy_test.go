@@ -0,0 +1,204 @@
+package privacy_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"governance-alerts-cosmos/internal/notifications"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/types"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+// TestRoutedChannelAllowed routes an alert to a named Slack channel and a
+// named webhook channel with privacy mode on. The channels are reached at
+// localhost and 127.0.0.1, neither of which any other setting allows
+func TestRoutedChannelAllowed(t *testing.T) {
+	var slackPosts, webhookPosts atomic.Int32
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackPosts.Add(1)
+	}))
+	defer slack.Close()
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookPosts.Add(1)
+	}))
+	defer webhook.Close()
+
+	cfg := &types.Config{
+		Networks: map[string]types.NetworkConfig{
+			"cosmoshub": {Name: "Cosmos Hub", RestEndpoint: "https://lcd.example.com", ChainID: "cosmoshub-4"},
+		},
+		Notifications: types.NotificationConfig{
+			Channels: map[string]types.ChannelConfig{
+				"ops-slack": {Type: types.ChannelSlack, URL: strings.Replace(slack.URL, "127.0.0.1", "localhost", 1)},
+				"ops-hook":  {Type: types.ChannelWebhook, URL: webhook.URL},
+			},
+			Routes: []types.RouteConfig{
+				{Networks: []string{"cosmoshub"}, Channels: []string{"ops-slack", "ops-hook"}},
+			},
+		},
+		Privacy: types.PrivacyConfig{Enabled: true},
+	}
+
+	hosts := privacy.AllowedHosts(cfg)
+	for _, want := range []string{"localhost", "127.0.0.1", "lcd.example.com"} {
+		if !slices.Contains(hosts, want) {
+			t.Errorf("AllowedHosts() = %v, want it to contain %s", hosts, want)
+		}
+	}
+
+	privacy.Install(cfg)
+	notifier, err := notifications.NewNotifier(&cfg.Notifications, cfg.Networks)
+	if err != nil {
+		t.Fatalf("NewNotifier() error = %v", err)
+	}
+	msg := types.NotificationMessage{
+		Title:      "Voting ends soon",
+		Content:    "Proposal #1 ends in 6 hours",
+		ChainID:    "cosmoshub-4",
+		ProposalID: 1,
+		AlertType:  types.AlertVotingEnd,
+	}
+	if err := notifier.SendNotification(msg); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+	if slackPosts.Load() == 0 {
+		t.Error("named Slack channel received nothing")
+	}
+	if webhookPosts.Load() == 0 {
+		t.Error("named webhook channel received nothing")
+	}
+}
+
+// hostRecorder records the host names requests were sent to
+type hostRecorder struct {
+	mu    sync.Mutex
+	hosts map[string]bool
+}
+
+func (r *hostRecorder) record(req *http.Request) {
+	host, _, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		host = req.Host
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hosts == nil {
+		r.hosts = make(map[string]bool)
+	}
+	r.hosts[host] = true
+}
+
+func (r *hostRecorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hosts := make([]string, 0, len(r.hosts))
+	for host := range r.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// TestCheckOnceOutboundHosts runs a check with the guard installed and
+// checks that it only reached the configured LCD and webhook, then that a
+// webhook dropped from the allow-list is blocked
+func TestCheckOnceOutboundHosts(t *testing.T) {
+	reached := &hostRecorder{}
+	now := time.Now().UTC()
+	lcd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached.record(r)
+		if r.URL.Path != "/cosmos/gov/v1/proposals" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"proposals": [{"id": "1", "title": "Private", "status": "PROPOSAL_STATUS_VOTING_PERIOD",
+			"voting_start_time": %q, "voting_end_time": %q}], "pagination": {}}`,
+			now.Add(2*time.Hour).Format(time.RFC3339), now.Add(74*time.Hour).Format(time.RFC3339))
+	}))
+	defer lcd.Close()
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached.record(r)
+	}))
+	defer webhook.Close()
+
+	newConfig := func() *types.Config {
+		return &types.Config{
+			Alerts: types.AlertConfig{HoursBeforeStart: 24, HoursBeforeEnd: 6, CheckIntervalMinutes: 60},
+			Networks: map[string]types.NetworkConfig{
+				"private": {Name: "Private", RestEndpoint: strings.Replace(lcd.URL, "127.0.0.1", "localhost", 1), ChainID: "private-1"},
+			},
+			Notifications: types.NotificationConfig{
+				Webhooks: []types.WebhookConfig{{URL: webhook.URL}},
+			},
+			Privacy: types.PrivacyConfig{Enabled: true},
+		}
+	}
+	blocked := logtest.NewGlobal()
+	defer blocked.Reset()
+
+	cfg := newConfig()
+	privacy.Install(cfg)
+	svc, err := service.NewService(cfg)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if err := svc.CheckOnce(context.Background()); err != nil {
+		t.Fatalf("CheckOnce() error = %v", err)
+	}
+	if got, want := reached.list(), []string{"127.0.0.1", "localhost"}; !slices.Equal(got, want) {
+		t.Errorf("CheckOnce() reached hosts %v, want %v", got, want)
+	}
+	if got := blockedHosts(blocked); len(got) != 0 {
+		t.Errorf("CheckOnce() blocked hosts %v, want none", got)
+	}
+
+	// Without the webhook in the allow-list the alert must not leave
+	reached = &hostRecorder{}
+	restricted := newConfig()
+	restricted.Notifications.Webhooks = nil
+	privacy.Update(restricted)
+	defer privacy.Update(cfg)
+
+	svc, err = service.NewService(newConfig())
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	// Failed alerts are logged and retried, the check itself succeeds
+	if err := svc.CheckOnce(context.Background()); err != nil {
+		t.Fatalf("CheckOnce() error = %v", err)
+	}
+	if got, want := reached.list(), []string{"localhost"}; !slices.Equal(got, want) {
+		t.Errorf("CheckOnce() reached hosts %v, want %v", got, want)
+	}
+	if got, want := blockedHosts(blocked), []string{"127.0.0.1"}; !slices.Equal(got, want) {
+		t.Errorf("CheckOnce() blocked hosts %v, want %v", got, want)
+	}
+}
+
+// blockedHosts returns the hosts the guard logged as blocked
+func blockedHosts(hook *logtest.Hook) []string {
+	var hosts []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Data["event"] != "privacy_blocked" {
+			continue
+		}
+		if host, ok := entry.Data["host"].(string); ok && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
@@ -0,0 +1,206 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// UpgradeChecklist is what node operators should check before voting on a
+// software upgrade proposal
+type UpgradeChecklist struct {
+	Network    string            `json:"network"`
+	ChainID    string            `json:"chain_id"`
+	ProposalID uint64            `json:"proposal_id"`
+	Title      string            `json:"title"`
+	Plan       types.UpgradePlan `json:"plan"`
+	Items      []ChecklistItem   `json:"items"`
+}
+
+// ChecklistItem is one check of an upgrade checklist. Warn is set when the
+// proposal leaves the check to the operator, such as missing binaries
+type ChecklistItem struct {
+	Check  string   `json:"check"`
+	Detail []string `json:"detail"`
+	Warn   bool     `json:"warn,omitempty"`
+}
+
+// checklistURLPattern matches links in proposal descriptions and plan info
+var checklistURLPattern = regexp.MustCompile(`https?://[^\s<>"'()\[\]{}]+`)
+
+// releaseDownloadPattern matches GitHub release asset links, capturing the
+// repository and the tag
+var releaseDownloadPattern = regexp.MustCompile(`^(https://github\.com/[^/]+/[^/]+)/releases/download/([^/]+)/`)
+
+// dependencyPattern matches description lines about libraries and tooling
+// node operators usually have to update alongside the binary
+var dependencyPattern = regexp.MustCompile(`(?i)\b(libwasmvm|wasmvm|go ?1\.\d+|golang|glibc|rocksdb|cosmovisor|cometbft|tendermint)\b`)
+
+// maxDependencyLines caps the description lines quoted as dependencies
+const maxDependencyLines = 5
+
+// BuildUpgradeChecklist builds the pre-vote checklist of a software upgrade
+// proposal, nil for other proposals. The halt time is estimated from recent
+// block times and is left out when the node cannot serve blocks
+func BuildUpgradeChecklist(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposal types.Proposal) *UpgradeChecklist {
+	if proposal.Upgrade == nil {
+		return nil
+	}
+	plan := *proposal.Upgrade
+
+	binaries := cosmosgov.PlanBinaries(plan.Info)
+	return &UpgradeChecklist{
+		Network:    networkConfig.Name,
+		ChainID:    networkConfig.ChainID,
+		ProposalID: proposal.ID,
+		Title:      proposal.Title,
+		Plan:       plan,
+		Items: []ChecklistItem{
+			binariesItem(plan, binaries),
+			releaseNotesItem(proposal, binaries),
+			haltItem(ctx, client, plan),
+			dependenciesItem(proposal.Description),
+			{
+				Check: "Cosmovisor",
+				Detail: []string{
+					fmt.Sprintf("Stage the new binary in $DAEMON_HOME/cosmovisor/upgrades/%s/bin, or enable DAEMON_ALLOW_DOWNLOAD_BINARIES", plan.Name),
+				},
+			},
+			{
+				Check:  "Vote",
+				Detail: []string{"Test the upgrade on a testnet or a node snapshot before voting"},
+			},
+		},
+	}
+}
+
+// binariesItem checks that the plan lists a binary with a checksum for
+// each platform
+func binariesItem(plan types.UpgradePlan, binaries map[string]string) ChecklistItem {
+	item := ChecklistItem{Check: "Binaries"}
+	if len(binaries) == 0 {
+		if link := checklistURLPattern.FindString(plan.Info); link != "" {
+			item.Detail = []string{"Binaries are listed at " + link}
+			return item
+		}
+		item.Detail = []string{"⚠️ The plan lists no binaries, they have to be built from source"}
+		item.Warn = true
+		return item
+	}
+
+	platforms := make([]string, 0, len(binaries))
+	for platform := range binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	for _, platform := range platforms {
+		link := binaries[platform]
+		if !strings.Contains(link, "checksum=") {
+			item.Warn = true
+			link += " (⚠️ no checksum)"
+		}
+		item.Detail = append(item.Detail, fmt.Sprintf("%s: %s", platform, link))
+	}
+	return item
+}
+
+// releaseNotesItem looks for a release or changelog link in the
+// description, falling back to the GitHub release of the binaries
+func releaseNotesItem(proposal types.Proposal, binaries map[string]string) ChecklistItem {
+	item := ChecklistItem{Check: "Release notes"}
+	seen := make(map[string]bool)
+	for _, link := range checklistURLPattern.FindAllString(proposal.Description+"\n"+proposal.Upgrade.Info, -1) {
+		link = strings.TrimRight(link, ".,;:!?")
+		lower := strings.ToLower(link)
+		if (strings.Contains(lower, "/releases/tag/") || strings.Contains(lower, "changelog")) && !seen[link] {
+			seen[link] = true
+			item.Detail = append(item.Detail, link)
+		}
+	}
+	if len(item.Detail) > 0 {
+		return item
+	}
+
+	for _, link := range binaries {
+		if m := releaseDownloadPattern.FindStringSubmatch(link); m != nil {
+			item.Detail = []string{m[1] + "/releases/tag/" + m[2]}
+			return item
+		}
+	}
+	item.Detail = []string{"⚠️ No release notes are linked, ask the chain team for them"}
+	item.Warn = true
+	return item
+}
+
+// haltItem gives the upgrade height with its estimated time
+func haltItem(ctx context.Context, client *governance.Client, plan types.UpgradePlan) ChecklistItem {
+	item := ChecklistItem{Check: "Halt height"}
+	estimate, err := client.EstimateHeight(ctx, plan.Height)
+	if err != nil {
+		item.Detail = []string{fmt.Sprintf("Block %d (time estimate unavailable: %v)", plan.Height, err)}
+		return item
+	}
+	if estimate.BlockTime == 0 {
+		item.Detail = []string{fmt.Sprintf("⚠️ Block %d is already reached", plan.Height)}
+		item.Warn = true
+		return item
+	}
+	item.Detail = []string{fmt.Sprintf("Block %d, estimated %s (average block time %s)",
+		plan.Height, formatTime(estimate.Time), estimate.BlockTime.Round(10*time.Millisecond))}
+	return item
+}
+
+// dependenciesItem quotes the description lines mentioning dependencies
+func dependenciesItem(description string) ChecklistItem {
+	item := ChecklistItem{Check: "Dependencies"}
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*#>"))
+		if line == "" || !dependencyPattern.MatchString(line) {
+			continue
+		}
+		item.Detail = append(item.Detail, line)
+		if len(item.Detail) == maxDependencyLines {
+			break
+		}
+	}
+	if len(item.Detail) == 0 {
+		item.Detail = []string{"None mentioned, check the release notes for Go, wasmvm and database changes"}
+	}
+	return item
+}
+
+// Text renders the checklist for chat channels
+func (c *UpgradeChecklist) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Upgrade %s at block %d\n", c.Plan.Name, c.Plan.Height)
+	for _, item := range c.Items {
+		fmt.Fprintf(&b, "\n☐ %s\n", item.Check)
+		for _, line := range item.Detail {
+			fmt.Fprintf(&b, "   %s\n", line)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Markdown renders the checklist as a GitHub issue body, each check a task
+// list item
+func (c *UpgradeChecklist) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Upgrade %s: %s proposal #%d\n\n", c.Plan.Name, c.Network, c.ProposalID)
+	fmt.Fprintf(&b, "%s (`%s`), upgrade `%s` at block %d.\n\n", c.Title, c.ChainID, c.Plan.Name, c.Plan.Height)
+	for _, item := range c.Items {
+		fmt.Fprintf(&b, "- [ ] **%s**\n", item.Check)
+		for _, line := range item.Detail {
+			fmt.Fprintf(&b, "  - %s\n", line)
+		}
+	}
+	return b.String()
+}
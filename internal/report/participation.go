@@ -0,0 +1,209 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// ParticipationReport is the voting power weighted participation in the
+// latest proposals of each network
+type ParticipationReport struct {
+	Time      time.Time              `json:"time"`
+	Proposals int                    `json:"proposals"`
+	Networks  []NetworkParticipation `json:"networks"`
+}
+
+// NetworkParticipation summarizes the participation of a network. Turnout
+// is the share of the bonded stake that voted, in percent, averaged
+// weighted by stake. Trend is the change in percentage points between the
+// average of the older and the newer half of the proposals
+type NetworkParticipation struct {
+	Network   string                  `json:"network"`
+	ChainID   string                  `json:"chain_id"`
+	Turnout   float64                 `json:"turnout"`
+	Trend     float64                 `json:"trend"`
+	Estimated bool                    `json:"estimated,omitempty"`
+	Proposals []ProposalParticipation `json:"proposals"`
+}
+
+// ProposalParticipation is the turnout of a single proposal in percent
+type ProposalParticipation struct {
+	ProposalID uint64    `json:"proposal_id"`
+	Title      string    `json:"title"`
+	VotingEnd  time.Time `json:"voting_end"`
+	Turnout    float64   `json:"turnout"`
+	Estimated  bool      `json:"estimated,omitempty"`
+}
+
+// NewNetworkParticipation summarizes the latest limit snapshots of a
+// network, given oldest first
+func NewNetworkParticipation(networkConfig types.NetworkConfig, snapshots []types.ParticipationSnapshot, limit int) NetworkParticipation {
+	if limit > 0 && len(snapshots) > limit {
+		snapshots = snapshots[len(snapshots)-limit:]
+	}
+	network := NetworkParticipation{Network: networkConfig.Name, ChainID: networkConfig.ChainID}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		snapshot := snapshots[i]
+		network.Proposals = append(network.Proposals, ProposalParticipation{
+			ProposalID: snapshot.ProposalID,
+			Title:      snapshot.Title,
+			VotingEnd:  snapshot.VotingEnd,
+			Turnout:    turnout(snapshot.Voted, snapshot.Bonded),
+			Estimated:  snapshot.Estimated,
+		})
+		network.Estimated = network.Estimated || snapshot.Estimated
+	}
+	network.Turnout = weightedTurnout(snapshots)
+	if half := len(snapshots) / 2; half > 0 {
+		network.Trend = weightedTurnout(snapshots[len(snapshots)-half:]) - weightedTurnout(snapshots[:len(snapshots)-half])
+	}
+	return network
+}
+
+// BackfillParticipation completes the snapshots of a network, oldest
+// first, with the latest limit finished proposals fetched from the chain,
+// from its archive endpoints once pruned. The bonded stake at the time is
+// no longer known, so backfilled snapshots are estimated against the
+// current one
+func BackfillParticipation(ctx context.Context, client *governance.Client, snapshots []types.ParticipationSnapshot, limit int) ([]types.ParticipationSnapshot, error) {
+	if len(snapshots) >= limit {
+		return snapshots, nil
+	}
+	proposals, err := client.GetFinishedProposals(ctx, limit)
+	if err != nil {
+		return snapshots, err
+	}
+	pool, err := client.GetStakingPool(ctx)
+	if err != nil {
+		return snapshots, err
+	}
+
+	known := make(map[uint64]bool, len(snapshots))
+	for _, snapshot := range snapshots {
+		known[snapshot.ProposalID] = true
+	}
+	var backfilled []types.ParticipationSnapshot
+	for _, proposal := range proposals {
+		if known[proposal.ID] || proposal.FinalTally == nil {
+			continue
+		}
+		backfilled = append(backfilled, types.ParticipationSnapshot{
+			ProposalID: proposal.ID,
+			Title:      proposal.Title,
+			VotingEnd:  proposal.VotingEnd,
+			Voted:      TallyTotal(proposal.FinalTally),
+			Bonded:     pool.BondedTokens,
+			Estimated:  true,
+		})
+	}
+
+	merged := append(backfilled, snapshots...)
+	sort.Slice(merged, func(i, j int) bool {
+		if !merged[i].VotingEnd.Equal(merged[j].VotingEnd) {
+			return merged[i].VotingEnd.Before(merged[j].VotingEnd)
+		}
+		return merged[i].ProposalID < merged[j].ProposalID
+	})
+	return merged, nil
+}
+
+// TallyTotal returns the voting power that voted in a tally, in base units
+func TallyTotal(tally *types.TallyResult) string {
+	total := parseAmount(tally.Yes) + parseAmount(tally.Abstain) + parseAmount(tally.No) + parseAmount(tally.NoWithVeto)
+	return strconv.FormatFloat(total, 'f', 0, 64)
+}
+
+// turnout returns the share of bonded that voted in percent
+func turnout(voted, bonded string) float64 {
+	b := parseAmount(bonded)
+	if b <= 0 {
+		return 0
+	}
+	return parseAmount(voted) / b * 100
+}
+
+// weightedTurnout returns the turnout of snapshots weighted by stake: the
+// voting power that voted over the bonded stake, summed over proposals
+func weightedTurnout(snapshots []types.ParticipationSnapshot) float64 {
+	var voted, bonded float64
+	for _, snapshot := range snapshots {
+		voted += parseAmount(snapshot.Voted)
+		bonded += parseAmount(snapshot.Bonded)
+	}
+	if bonded <= 0 {
+		return 0
+	}
+	return voted / bonded * 100
+}
+
+// Render renders the report as md, json or yaml
+func (r *ParticipationReport) Render(format string) (string, error) {
+	return render(r, format, r.Markdown)
+}
+
+// Markdown renders the report as a Markdown document
+func (r *ParticipationReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Governance Participation\n\n")
+	fmt.Fprintf(&b, "Share of the bonded stake that voted on the latest %d proposals of each network, as of %s.\n", r.Proposals, formatTime(r.Time))
+
+	for _, network := range r.Networks {
+		fmt.Fprintf(&b, "\n## %s (`%s`)\n\n", network.Network, network.ChainID)
+		if len(network.Proposals) == 0 {
+			fmt.Fprintf(&b, "No finished proposals recorded yet.\n")
+			continue
+		}
+		fmt.Fprintf(&b, "Average turnout **%.1f%%** over %d proposals, trend %s\n\n", network.Turnout, len(network.Proposals), formatTrend(network))
+		fmt.Fprintf(&b, "| Proposal | Voting end | Turnout |\n|----------|------------|---------|\n")
+		for _, p := range network.Proposals {
+			estimated := ""
+			if p.Estimated {
+				estimated = " *"
+			}
+			fmt.Fprintf(&b, "| #%d %s | %s | %.1f%%%s |\n", p.ProposalID, p.Title, formatTime(p.VotingEnd), p.Turnout, estimated)
+		}
+		if network.Estimated {
+			fmt.Fprintf(&b, "\n\\* estimated against the current bonded stake\n")
+		}
+	}
+	return b.String()
+}
+
+// Summary renders the report as plain text for notifications, one line
+// per network
+func (r *ParticipationReport) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Share of the bonded stake that voted on the latest %d proposals:", r.Proposals)
+	for _, network := range r.Networks {
+		if len(network.Proposals) == 0 {
+			fmt.Fprintf(&b, "\n• %s: no finished proposals recorded yet", network.Network)
+			continue
+		}
+		latest := network.Proposals[0]
+		fmt.Fprintf(&b, "\n• %s: %.1f%% over %d proposals, trend %s (latest #%d: %.1f%%)",
+			network.Network, network.Turnout, len(network.Proposals), formatTrend(network), latest.ProposalID, latest.Turnout)
+	}
+	return b.String()
+}
+
+// formatTrend formats the trend of a network in percentage points
+func formatTrend(network NetworkParticipation) string {
+	switch {
+	case len(network.Proposals) < 2:
+		return "n/a"
+	case math.Abs(network.Trend) < 0.05:
+		return "→ stable"
+	case network.Trend > 0:
+		return fmt.Sprintf("▲ +%.1f pts", network.Trend)
+	default:
+		return fmt.Sprintf("▼ %.1f pts", network.Trend)
+	}
+}
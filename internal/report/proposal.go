@@ -0,0 +1,271 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+	"gopkg.in/yaml.v3"
+)
+
+// ProposalReport holds everything known about a single proposal
+type ProposalReport struct {
+	Network   string               `json:"network"`
+	ChainID   string               `json:"chain_id"`
+	Proposal  types.Proposal       `json:"proposal"`
+	Tally     *TallySummary        `json:"tally,omitempty"`
+	VoteCount uint64               `json:"vote_count"`
+	Timeline  []TimelineEntry      `json:"timeline"`
+	Tags      []string             `json:"tags,omitempty"`
+	Notes     []types.Note         `json:"notes,omitempty"`
+	Links     []types.TrackingLink `json:"links,omitempty"`
+}
+
+// TallySummary is a tally with the share of each option in percent.
+// Amounts are in Denom, the staking denom of the network when configured
+type TallySummary struct {
+	Final   bool          `json:"final"`
+	Denom   string        `json:"denom,omitempty"`
+	Options []TallyOption `json:"options"`
+}
+
+// TallyOption is the amount and share of one vote option
+type TallyOption struct {
+	Option  string  `json:"option"`
+	Amount  string  `json:"amount"`
+	Percent float64 `json:"percent"`
+}
+
+// TimelineEntry is a point in the life of a proposal
+type TimelineEntry struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+}
+
+// BuildProposalReport fetches a proposal with its tally and vote count.
+// Tally and votes are best effort since pruned nodes may not serve them
+func BuildProposalReport(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposalID uint64) (*ProposalReport, error) {
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ProposalReport{
+		Network:  networkConfig.Name,
+		ChainID:  networkConfig.ChainID,
+		Proposal: *proposal,
+		Timeline: chainTimeline(*proposal),
+	}
+
+	// Voting proposals have a live tally, finished ones a final tally
+	if proposal.FinalTally != nil && proposal.Status != string(cosmosgov.StatusVotingPeriod) {
+		report.Tally = summarizeTally(networkConfig, proposal.FinalTally, true)
+	} else if tally, err := client.GetTally(ctx, proposalID); err == nil {
+		report.Tally = summarizeTally(networkConfig, tally, false)
+	}
+
+	if count, err := client.GetVoteCount(ctx, proposalID); err == nil {
+		report.VoteCount = count
+	}
+
+	return report, nil
+}
+
+// AddEvent adds an event recorded by the service to the timeline
+func (r *ProposalReport) AddEvent(t time.Time, event string) {
+	r.Timeline = append(r.Timeline, TimelineEntry{Time: t, Event: event})
+	sort.SliceStable(r.Timeline, func(i, j int) bool {
+		return r.Timeline[i].Time.Before(r.Timeline[j].Time)
+	})
+}
+
+// chainTimeline returns the on-chain milestones of a proposal that are set
+func chainTimeline(proposal types.Proposal) []TimelineEntry {
+	now := time.Now()
+	milestones := []struct {
+		t            time.Time
+		past, future string
+	}{
+		{proposal.SubmitTime, "Submitted", "Submitted"},
+		{proposal.DepositEnd, "Deposit period ended", "Deposit period ends"},
+		{proposal.VotingStart, "Voting started", "Voting starts"},
+		{proposal.VotingEnd, "Voting ended", "Voting ends"},
+	}
+
+	timeline := make([]TimelineEntry, 0, len(milestones))
+	for _, m := range milestones {
+		if m.t.IsZero() {
+			continue
+		}
+		event := m.future
+		if m.t.Before(now) {
+			event = m.past
+		}
+		timeline = append(timeline, TimelineEntry{Time: m.t, Event: event})
+	}
+
+	// Voting can start before the deposit period would have ended
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(timeline[j].Time)
+	})
+	return timeline
+}
+
+// summarizeTally computes the share of each vote option valid on the
+// network, named as the chain names them
+func summarizeTally(networkConfig types.NetworkConfig, tally *types.TallyResult, final bool) *TallySummary {
+	var options []TallyOption
+	for _, option := range governance.TallyOptions(networkConfig, tally) {
+		options = append(options, TallyOption{Option: option.Label, Amount: option.Amount})
+	}
+
+	total := 0.0
+	for _, o := range options {
+		total += parseAmount(o.Amount)
+	}
+	if total > 0 {
+		for i := range options {
+			options[i].Percent = parseAmount(options[i].Amount) / total * 100
+		}
+	}
+
+	return &TallySummary{Final: final, Denom: networkConfig.Denom, Options: options}
+}
+
+// parseAmount parses a base unit token amount, returning 0 if invalid
+func parseAmount(amount string) float64 {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// Render renders the report as md, json or yaml
+func (r *ProposalReport) Render(format string) (string, error) {
+	return render(r, format, r.Markdown)
+}
+
+// render renders a report as Markdown, or as JSON or YAML with the JSON
+// field names
+func render(r any, format string, markdown func() string) (string, error) {
+	switch format {
+	case "md", "markdown":
+		return markdown(), nil
+	case "json":
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode report: %w", err)
+		}
+		return string(out), nil
+	case "yaml":
+		// Go through JSON so YAML keys match the JSON field names
+		data, err := json.Marshal(r)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode report: %w", err)
+		}
+		var generic interface{}
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return "", fmt.Errorf("failed to encode report: %w", err)
+		}
+		out, err := yaml.Marshal(generic)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode report: %w", err)
+		}
+		return string(out), nil
+	default:
+		return "", fmt.Errorf("unknown format %q (expected md, json or yaml)", format)
+	}
+}
+
+// Markdown renders the report as a Markdown document for governance memos
+func (r *ProposalReport) Markdown() string {
+	p := r.Proposal
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Proposal #%d: %s\n\n", p.ID, p.Title)
+	fmt.Fprintf(&b, "| Field | Value |\n|-------|-------|\n")
+	fmt.Fprintf(&b, "| Network | %s (`%s`) |\n", r.Network, r.ChainID)
+	fmt.Fprintf(&b, "| Status | %s |\n", governance.StatusLabel(p.Status))
+	if p.Type != "" {
+		fmt.Fprintf(&b, "| Type | %s |\n", cosmosgov.TypeName(p.Type))
+	}
+	if !p.VotingStart.IsZero() {
+		fmt.Fprintf(&b, "| Voting | %s → %s |\n", formatTime(p.VotingStart), formatTime(p.VotingEnd))
+	}
+	fmt.Fprintf(&b, "| Votes cast | %d |\n", r.VoteCount)
+
+	if r.Tally != nil {
+		title := "Current tally"
+		if r.Tally.Final {
+			title = "Final tally"
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n| Option | Amount | Share |\n|--------|--------|-------|\n", title)
+		for _, o := range r.Tally.Options {
+			fmt.Fprintf(&b, "| %s | %s | %.2f%% |\n", o.Option, amounts.Amount(o.Amount, r.Tally.Denom), o.Percent)
+		}
+	}
+
+	if len(p.Messages) > 0 {
+		fmt.Fprintf(&b, "\n## Messages\n\n")
+		for _, msg := range p.Messages {
+			fmt.Fprintf(&b, "- %s\n", msg)
+		}
+	}
+
+	if len(r.Timeline) > 0 {
+		fmt.Fprintf(&b, "\n## Timeline\n\n")
+		for _, e := range r.Timeline {
+			fmt.Fprintf(&b, "- %s: %s\n", formatTime(e.Time), e.Event)
+		}
+	}
+
+	if len(p.References) > 0 {
+		fmt.Fprintf(&b, "\n## Related proposals\n\n")
+		for _, id := range p.References {
+			fmt.Fprintf(&b, "- #%d\n", id)
+		}
+	}
+
+	if len(r.Links) > 0 {
+		fmt.Fprintf(&b, "\n## Tracking\n\n")
+		for _, link := range r.Links {
+			if link.URL != "" {
+				fmt.Fprintf(&b, "- %s [%s](%s)\n", link.System, link.ID, link.URL)
+			} else {
+				fmt.Fprintf(&b, "- %s %s\n", link.System, link.ID)
+			}
+		}
+	}
+
+	if len(r.Tags) > 0 || len(r.Notes) > 0 {
+		fmt.Fprintf(&b, "\n## Team notes\n\n")
+		if len(r.Tags) > 0 {
+			fmt.Fprintf(&b, "Tags: %s\n\n", strings.Join(r.Tags, ", "))
+		}
+		for _, note := range r.Notes {
+			fmt.Fprintf(&b, "- %s (%s, %s)\n", note.Text, note.Author, formatTime(note.Time))
+		}
+	}
+
+	if p.Metadata != "" {
+		fmt.Fprintf(&b, "\n## Metadata\n\n`%s`\n", p.Metadata)
+	}
+
+	fmt.Fprintf(&b, "\n## Description\n\n%s\n", p.Description)
+	return b.String()
+}
+
+// formatTime formats a time for reports
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
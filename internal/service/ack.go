@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// onReaction acknowledges or snoozes the reminders of the proposal whose
+// alert got the configured ack or snooze reaction
+func (s *Service) onReaction(ref types.MessageRef, reaction, user string) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	reactions := s.config.Notifications.Reactions
+	if reaction != reactions.Ack && reaction != reactions.Snooze {
+		return
+	}
+
+	key, ok := s.messageProposal(ref)
+	if !ok {
+		return
+	}
+
+	var snooze time.Duration
+	if reaction == reactions.Snooze {
+		snooze = time.Duration(reactions.SnoozeHours) * time.Hour
+	}
+	reply, err := s.acknowledge(key, user, snooze)
+	if err != nil {
+		keyLog(key, eventStateFailed).WithError(err).Warn("Failed to acknowledge")
+		return
+	}
+
+	if err := s.notifier.ReplySlack(ref, reply); err != nil {
+		keyLog(key, eventAlertFailed).WithError(err).Warn("Failed to confirm acknowledgement")
+	}
+}
+
+// acknowledge records the acknowledgement of a proposal's reminders by
+// user, a snooze when snooze is positive, and returns the confirmation
+func (s *Service) acknowledge(key, user string, snooze time.Duration) (string, error) {
+	now := time.Now()
+	ack := types.Ack{User: user, Time: now}
+	reply := fmt.Sprintf("👀 Acknowledged by %s, no further reminders for this proposal.", user)
+	detail := fmt.Sprintf("Acknowledged by %s", user)
+	if snooze > 0 {
+		ack.Until = now.Add(snooze)
+		until := ack.Until.UTC().Format("2006-01-02 15:04 UTC")
+		reply = fmt.Sprintf("💤 Snoozed by %s, reminders resume at %s.", user, until)
+		detail = fmt.Sprintf("Snoozed by %s until %s", user, until)
+	}
+
+	if err := s.store.Acknowledge(key, ack); err != nil {
+		return "", fmt.Errorf("failed to record acknowledgement of %s: %w", key, err)
+	}
+	s.recordTimeline(key, types.TimelineAck, detail, now)
+	keyLog(key, eventAlertHeld).WithField("user", user).Info(reply)
+	return reply, nil
+}
+
+// messageProposal finds the proposal an alert message was sent about
+func (s *Service) messageProposal(ref types.MessageRef) (string, bool) {
+	for key, refs := range s.store.Messages() {
+		for _, sent := range refs {
+			if sent.Channel == ref.Channel && sent.SlackChannel == ref.SlackChannel && sent.Timestamp == ref.Timestamp {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// reminderHeld reports whether the reminders of a proposal are held by an
+// acknowledgement, a snooze that has not ended or a chain incident,
+// describing it for logs
+func (s *Service) reminderHeld(key string, now time.Time) (string, bool) {
+	if chainID, _, found := strings.Cut(key, "/"); found {
+		if reason, held := s.incidentHeld(chainID); held {
+			return reason, true
+		}
+	}
+
+	ack, ok := s.store.Acknowledgement(key)
+	if !ok {
+		return "", false
+	}
+	if ack.Until.IsZero() {
+		return fmt.Sprintf("acknowledged by %s", ack.User), true
+	}
+	if now.Before(ack.Until) {
+		return fmt.Sprintf("snoozed by %s until %s", ack.User, ack.Until.Format("2006-01-02 15:04")), true
+	}
+	return "", false
+}
+
+// tracksMessages reports whether sent alert messages must be recorded, for
+// retractions or to match reactions to alerts
+func (s *Service) tracksMessages() bool {
+	return s.config.Notifications.Retractions != "" || s.notifier.TracksReactions()
+}
+
+// SlackEvents returns the handler of Slack Events API requests reporting
+// reactions on alert messages, served by the current notifier
+func (s *Service) SlackEvents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.configMu.RLock()
+		notifier := s.notifier
+		s.configMu.RUnlock()
+		notifier.SlackEvents().ServeHTTP(w, r)
+	})
+}
+
+// SlackCommands returns the handler of Slack slash commands, served by the
+// current notifier
+func (s *Service) SlackCommands() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.configMu.RLock()
+		notifier := s.notifier
+		s.configMu.RUnlock()
+		notifier.SlackCommands().ServeHTTP(w, r)
+	})
+}
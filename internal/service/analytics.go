@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"governance-alerts-cosmos/internal/events"
+	"governance-alerts-cosmos/internal/store"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// startAnalytics runs the analytics sink, when enabled, until the returned
+// function is called, which waits for the last rows to be inserted
+func (s *Service) startAnalytics() func() {
+	if s.analytics == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.analytics.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// FlushAnalytics inserts the rows queued for the analytics database, for
+// runs that exit after a single check
+func (s *Service) FlushAnalytics(ctx context.Context) error {
+	return s.analytics.Flush(ctx)
+}
+
+// exportTimelineEvent sends a timeline event of a proposal to the
+// analytics sink
+func (s *Service) exportTimelineEvent(key string, event types.TimelineEvent) {
+	if s.analytics == nil {
+		return
+	}
+	chainID, proposalID, ok := store.ParseProposalKey(key)
+	if !ok {
+		return
+	}
+	data := events.TimelineData{
+		Network:    s.store.NetworkName(chainID),
+		ChainID:    chainID,
+		ProposalID: proposalID,
+		Kind:       event.Kind,
+		Detail:     event.Detail,
+	}
+	s.analytics.Export(events.NewEventAt(events.TimelineType(event.Kind), chainID, proposalID, data, event.Time))
+}
+
+// exportTally sends a tally snapshot of a proposal to the analytics sink
+func (s *Service) exportTally(networkConfig types.NetworkConfig, proposalID uint64, tally *types.TallyResult, now time.Time) {
+	if s.analytics == nil {
+		return
+	}
+	data := events.TallyData{
+		Network:    networkConfig.Name,
+		ChainID:    networkConfig.ChainID,
+		ProposalID: proposalID,
+		Yes:        tally.Yes,
+		No:         tally.No,
+		Abstain:    tally.Abstain,
+		NoWithVeto: tally.NoWithVeto,
+		Denom:      networkConfig.Denom,
+	}
+	s.analytics.Export(events.NewEventAt(events.TypeTallySnapshot, networkConfig.ChainID, proposalID, data, now))
+}
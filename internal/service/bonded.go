@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// bondedShift is a sharp change of bonded stake seen while proposals were
+// in voting period, noted in their tally alerts since quorum is computed
+// against bonded stake
+type bondedShift struct {
+	previous float64
+	current  float64
+	at       time.Time
+	affected map[uint64]bool
+}
+
+// checkBondedStake polls the staking pool of a network with proposals in
+// voting period and records a shift for them when bonded stake moved
+// sharply since the previous check
+func (s *Service) checkBondedStake(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposals []types.Proposal, now time.Time) error {
+	pool, err := client.GetStakingPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	bonded := parseAmount(pool.BondedTokens)
+	if bonded <= 0 {
+		return fmt.Errorf("invalid bonded tokens %q", pool.BondedTokens)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.bondedTokens[networkConfig.ChainID]
+	s.bondedTokens[networkConfig.ChainID] = bonded
+	if !ok {
+		return nil
+	}
+
+	changePercent := (bonded - previous) / previous * 100
+	if math.Abs(changePercent) < s.config.Alerts.BondedChangeThresholdPercent {
+		return nil
+	}
+
+	affected := make(map[uint64]bool)
+	for _, proposal := range proposals {
+		if !proposal.VotingStart.After(now) && proposal.VotingEnd.After(now) {
+			affected[proposal.ID] = true
+		}
+	}
+	if len(affected) == 0 {
+		return nil
+	}
+	s.bondedShifts[networkConfig.ChainID] = append(s.bondedShifts[networkConfig.ChainID], bondedShift{
+		previous: previous,
+		current:  bonded,
+		at:       now,
+		affected: affected,
+	})
+
+	networkLog(networkConfig, eventCheck).WithField("proposals", len(affected)).Infof("Bonded stake changed by %+.2f%%, noting it in tally alerts", changePercent)
+	return nil
+}
+
+// bondedNote notes the bonded stake shifts that happened while a proposal
+// was in voting period with the turnout of tally before and after each,
+// empty if none did
+func (s *Service) bondedNote(chainID string, proposalID uint64, tally *types.TallyResult) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	voted := 0.0
+	if tally != nil {
+		voted = tallyTotal(tally)
+	}
+	var note string
+	for _, shift := range s.bondedShifts[chainID] {
+		if !shift.affected[proposalID] {
+			continue
+		}
+		note += fmt.Sprintf("\n\nℹ️ Bonded stake changed by %+.2f%% during voting (%s)",
+			(shift.current-shift.previous)/shift.previous*100, shift.at.Format("2006-01-02 15:04 MST"))
+		if voted > 0 {
+			note += fmt.Sprintf(", moving the turnout of this tally from %.2f%% to %.2f%% of bonded stake", voted/shift.previous*100, voted/shift.current*100)
+		}
+	}
+	return note
+}
+
+// forgetBondedShifts drops a proposal from the noted bonded stake shifts
+// once its outcome is known
+func (s *Service) forgetBondedShifts(chainID string, proposalID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shifts := s.bondedShifts[chainID][:0]
+	for _, shift := range s.bondedShifts[chainID] {
+		delete(shift.affected, proposalID)
+		if len(shift.affected) > 0 {
+			shifts = append(shifts, shift)
+		}
+	}
+	s.bondedShifts[chainID] = shifts
+}
+
+// forgetBondedStake drops the bonded stake baseline of a network once no
+// proposals are in voting period
+func (s *Service) forgetBondedStake(chainID string) {
+	s.mu.Lock()
+	delete(s.bondedTokens, chainID)
+	s.mu.Unlock()
+}
+
+// tallyTotal returns the total voted amount of a tally
+func tallyTotal(tally *types.TallyResult) float64 {
+	return parseAmount(tally.Yes) + parseAmount(tally.Abstain) + parseAmount(tally.No) + parseAmount(tally.NoWithVeto)
+}
+
+// parseAmount parses a base unit token amount, returning 0 if invalid
+func parseAmount(amount string) float64 {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// botCommandTimeout bounds the chain queries of a bot command
+const botCommandTimeout = 30 * time.Second
+
+// proposalsCommand handles /proposals [network], listing the proposals in
+// voting period found by the last check of each network
+func (s *Service) proposalsCommand(args []string, user string) string {
+	filter := ""
+	if len(args) > 0 {
+		s.configMu.RLock()
+		name, _, ok := config.LookupNetwork(s.config, args[0])
+		s.configMu.RUnlock()
+		if !ok {
+			return fmt.Sprintf("Unknown network %q", args[0])
+		}
+		filter = name
+	}
+
+	now := time.Now()
+	var b strings.Builder
+	for _, network := range s.Status().Networks {
+		if filter != "" && network.Network != filter {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n%s (%s)", network.Name, network.ChainID)
+		if network.Failing {
+			b.WriteString(" ⚠️ checks failing")
+		}
+		if len(network.Proposals) == 0 {
+			b.WriteString("\nNo proposals in voting period")
+			continue
+		}
+		for _, proposal := range network.Proposals {
+			fmt.Fprintf(&b, "\n• #%d %s, voting ends %s", proposal.ID, proposal.Title, proposal.VotingEnd.UTC().Format("2006-01-02 15:04 UTC"))
+			if left := proposal.VotingEnd.Sub(now); left > 0 {
+				fmt.Fprintf(&b, " (%s)", amounts.In(left))
+			}
+			if proposal.Held != "" {
+				fmt.Fprintf(&b, ", %s", proposal.Held)
+			}
+		}
+	}
+	return "🗳️ Active proposals" + b.String()
+}
+
+// proposalCommand handles /proposal <network> <id>, showing the details and
+// current tally of a proposal queried from the chain
+func (s *Service) proposalCommand(args []string, user string) string {
+	const usage = "Usage: /proposal <network> <proposal id>"
+	if len(args) < 2 {
+		return usage
+	}
+	s.configMu.RLock()
+	name, networkConfig, ok := config.LookupNetwork(s.config, args[0])
+	client := s.clients[name]
+	s.configMu.RUnlock()
+	if !ok || client == nil {
+		return fmt.Sprintf("Unknown network %q", args[0])
+	}
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return usage
+	}
+	key := proposalKey(networkConfig.ChainID, proposalID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), botCommandTimeout)
+	defer cancel()
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	if errors.Is(err, governance.ErrNotFound) {
+		return fmt.Sprintf("Proposal %d not found on %s", proposalID, networkConfig.Name)
+	}
+	if err != nil {
+		return fmt.Sprintf("Failed to fetch proposal %d: %v", proposalID, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📋 %s #%d: %s\n", networkConfig.Name, proposal.ID, proposal.Title)
+	fmt.Fprintf(&b, "\nStatus: %s", governance.StatusLabel(proposal.Status))
+	if proposal.Category != "" {
+		fmt.Fprintf(&b, "\nType: %s", proposal.Category)
+	}
+	if !proposal.VotingStart.IsZero() {
+		fmt.Fprintf(&b, "\nVoting: %s → %s", proposal.VotingStart.UTC().Format("2006-01-02 15:04"), proposal.VotingEnd.UTC().Format("2006-01-02 15:04 UTC"))
+	} else if !proposal.DepositEnd.IsZero() {
+		fmt.Fprintf(&b, "\nDeposit period ends: %s", proposal.DepositEnd.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+
+	tally := proposal.FinalTally
+	if proposal.Status == string(cosmosgov.StatusVotingPeriod) || tally == nil {
+		if live, err := client.GetTally(ctx, proposal.ID); err == nil {
+			tally = live
+		}
+	}
+	if tally != nil && tallyTotal(tally) > 0 {
+		fmt.Fprintf(&b, "\nTally: %s", formatTallyShares(networkConfig, tally))
+	}
+	if mute, muted := s.store.Muted(key); muted {
+		fmt.Fprintf(&b, "\n\n🔇 Muted by %s on %s", mute.User, mute.Time.UTC().Format("2006-01-02 15:04 UTC"))
+	} else if held, ok := s.reminderHeld(key, time.Now()); ok {
+		fmt.Fprintf(&b, "\n\nReminders held: %s", held)
+	}
+	b.WriteString(s.notesText(key))
+	if url := s.explorerURL(networkConfig, proposal.ID); url != "" {
+		fmt.Fprintf(&b, "\n\n%s", url)
+	}
+	return b.String()
+}
+
+// muteCommand handles /mute <network> <id>, suppressing every further
+// alert of the proposal
+func (s *Service) muteCommand(args []string, user string) string {
+	key, err := s.commandProposal(args)
+	if err != nil {
+		return "Usage: /mute <network> <proposal id>"
+	}
+	now := time.Now()
+	if err := s.store.Mute(key, types.Ack{User: user, Time: now}); err != nil {
+		return fmt.Sprintf("Failed to mute %s: %v", key, err)
+	}
+	s.recordTimeline(key, types.TimelineAck, fmt.Sprintf("Muted by %s", user), now)
+	keyLog(key, eventAlertHeld).WithField("user", user).Info("Proposal muted")
+	return fmt.Sprintf("🔇 Muted %s, no further alerts for this proposal. Use /unmute to undo.", key)
+}
+
+// unmuteCommand handles /unmute <network> <id>
+func (s *Service) unmuteCommand(args []string, user string) string {
+	key, err := s.commandProposal(args)
+	if err != nil {
+		return "Usage: /unmute <network> <proposal id>"
+	}
+	wasMuted, err := s.store.Unmute(key)
+	if err != nil {
+		return fmt.Sprintf("Failed to unmute %s: %v", key, err)
+	}
+	if !wasMuted {
+		return fmt.Sprintf("%s is not muted", key)
+	}
+	s.recordTimeline(key, types.TimelineAck, fmt.Sprintf("Unmuted by %s", user), time.Now())
+	keyLog(key, eventAlertHeld).WithField("user", user).Info("Proposal unmuted")
+	return fmt.Sprintf("🔔 Unmuted %s, alerts resume.", key)
+}
+
+// statusCommand handles /status, summarizing the health of the service
+func (s *Service) statusCommand(args []string, user string) string {
+	health := s.Health()
+	now := health.Time
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🩺 Service %s, up %s", strings.ToUpper(health.Status), formatDuration(now.Sub(health.StartedAt)))
+	if !health.LastCycle.IsZero() {
+		fmt.Fprintf(&b, "\nLast check cycle: %s ago", formatDuration(now.Sub(health.LastCycle)))
+	}
+
+	stale := make(map[string]bool, len(health.Networks))
+	for _, network := range health.Networks {
+		stale[network.Network] = network.Stale
+	}
+	s.configMu.RLock()
+	schedules := s.schedules()
+	s.configMu.RUnlock()
+
+	b.WriteString("\n\nNetworks:")
+	for _, schedule := range schedules {
+		state := "✅"
+		if stale[schedule.Network] {
+			state = "⚠️ stale"
+		}
+		fmt.Fprintf(&b, "\n• %s %s, %s", schedule.Network, state, schedule.text(now))
+	}
+
+	if len(health.Channels) > 0 {
+		b.WriteString("\n\nChannels:")
+		channels := append([]ChannelHealth(nil), health.Channels...)
+		sort.Slice(channels, func(i, j int) bool { return channels[i].Channel < channels[j].Channel })
+		for _, channel := range channels {
+			switch {
+			case channel.Paused:
+				fmt.Fprintf(&b, "\n• %s ⏸️ paused", channel.Channel)
+			case channel.Reachable:
+				fmt.Fprintf(&b, "\n• %s ✅", channel.Channel)
+			default:
+				fmt.Fprintf(&b, "\n• %s ❌ %s", channel.Channel, channel.LastError)
+			}
+		}
+	}
+
+	for _, dependency := range health.Dependencies {
+		if !dependency.OK {
+			fmt.Fprintf(&b, "\n\n⚠️ %s (%s) failing: %s", dependency.Dependency, dependency.Host, dependency.LastError)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// defaultVerifyChainIDEvery is how many checks pass between chain ID
+// verifications when the network does not configure it
+const defaultVerifyChainIDEvery = 10
+
+// verifyChainID compares the chain ID of every endpoint of a network with
+// the configured one, at the first check of a client and every
+// verify_chain_id_every checks after. The identity of each endpoint is
+// recorded, and the ops channels are alerted when an endpoint starts
+// serving another chain and when it serves the configured one again
+func (s *Service) verifyChainID(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig) {
+	every := networkConfig.VerifyChainIDEvery
+	if every == 0 {
+		every = defaultVerifyChainIDEvery
+	}
+	if every < 0 {
+		return
+	}
+	s.mu.Lock()
+	cycles := s.cycles
+	s.mu.Unlock()
+	if client.ChainIDVerified() && cycles%every != 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, identity := range client.VerifyChainID(ctx) {
+		log := networkLog(networkConfig, eventCheck).WithField("endpoint", identity.Endpoint)
+		if identity.Err != nil {
+			log.WithError(identity.Err).Warn("Failed to verify endpoint chain ID")
+			continue
+		}
+
+		mismatch := identity.Mismatch(networkConfig.ChainID)
+		previous, known := s.store.EndpointIdentity(networkConfig.ChainID, identity.Endpoint)
+		record := types.EndpointIdentity{Endpoint: identity.Endpoint, ChainID: identity.ChainID, VerifiedAt: now, Mismatch: mismatch}
+		if err := s.store.SetEndpointIdentity(networkConfig.ChainID, record); err != nil {
+			networkLog(networkConfig, eventStateFailed).WithError(err).Warn("Failed to record endpoint identity")
+		}
+
+		switch {
+		case mismatch && (!known || !previous.Mismatch || previous.ChainID != identity.ChainID):
+			log.WithField("reported_chain_id", identity.ChainID).Error("Endpoint reports another chain ID, no longer using it")
+			s.reportChainIDMismatch(networkConfig, identity)
+		case mismatch:
+			log.WithField("reported_chain_id", identity.ChainID).Debug("Endpoint still reports another chain ID")
+		case known && previous.Mismatch:
+			log.Info("Endpoint reports the configured chain ID again")
+			s.reportChainIDMismatch(networkConfig, identity)
+		default:
+			log.Debug("Endpoint chain ID verified")
+		}
+	}
+}
+
+// reportChainIDMismatch tells the ops channels that an endpoint serves
+// another chain than configured, or the configured one again
+func (s *Service) reportChainIDMismatch(networkConfig types.NetworkConfig, identity governance.EndpointIdentity) {
+	msg := types.NotificationMessage{
+		Title: fmt.Sprintf("🚨 Chain ID Mismatch - %s", networkConfig.Name),
+		Content: fmt.Sprintf("Endpoint %s reports chain ID %s instead of the configured %s. The provider may have switched its backend, "+
+			"for example from mainnet to a testnet.\n\nThe endpoint is no longer used until it reports %s again.",
+			identity.Endpoint, identity.ChainID, networkConfig.ChainID, networkConfig.ChainID),
+		Severity: types.SeverityCritical,
+	}
+	if !identity.Mismatch(networkConfig.ChainID) {
+		msg = types.NotificationMessage{
+			Title:   fmt.Sprintf("✅ Chain ID Verified - %s", networkConfig.Name),
+			Content: fmt.Sprintf("Endpoint %s reports the configured chain ID %s again and is used again.", identity.Endpoint, networkConfig.ChainID),
+		}
+	}
+	msg.Network = networkConfig.Name
+	msg.ChainID = networkConfig.ChainID
+	msg.Role = types.RoleOps
+
+	if err := s.notifier.SendNotification(msg); err != nil {
+		networkLog(networkConfig, eventAlertFailed).WithError(err).Warn("Failed to send chain ID notification")
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// snapshotRefresh is how often the last seen time of an unchanged
+// snapshot is written, so the state is not rewritten at every check
+const snapshotRefresh = 24 * time.Hour
+
+// checkProposalChanges compares the proposals found with their snapshots
+// from the previous check and sends what changed: title and description
+// edits, status transitions and moved deadlines. Proposals seen for the
+// first time only get their snapshot. A snapshot is replaced once the
+// update is sent, so held or failed updates are sent at a later check
+func (s *Service) checkProposalChanges(networkConfig types.NetworkConfig, proposals []types.Proposal, now time.Time) {
+	for _, proposal := range proposals {
+		key := proposalKey(networkConfig.ChainID, proposal.ID)
+		current := proposalSnapshot(proposal, now)
+		previous, ok := s.store.Snapshot(key)
+		changes := snapshotChanges(previous, current)
+
+		if ok && len(changes) > 0 && alertEnabled(networkConfig, types.AlertProposalUpdated) {
+			if s.holdForQuietHours(now, proposal.VotingEnd) {
+				proposalAlertLog(networkConfig, proposal.ID, eventAlertHeld, types.AlertProposalUpdated).Info("Proposal update held for quiet hours")
+				continue
+			}
+			if err := s.sendProposalUpdate(networkConfig, proposal, changes); err != nil {
+				proposalAlertLog(networkConfig, proposal.ID, eventAlertFailed, types.AlertProposalUpdated).WithError(err).Error("Failed to send proposal update")
+				continue
+			}
+		}
+		if ok && len(changes) > 0 {
+			s.recordTimeline(key, types.TimelineUpdate, strings.Join(changes, "; "), now)
+		}
+
+		if !ok || len(changes) > 0 || now.Sub(previous.Seen) > snapshotRefresh {
+			if err := s.store.SetSnapshot(key, current); err != nil {
+				keyLog(key, eventStateFailed).WithError(err).Warn("Failed to record proposal snapshot")
+			}
+		}
+	}
+}
+
+// sendProposalUpdate sends the changes of a proposal since the last check
+func (s *Service) sendProposalUpdate(networkConfig types.NetworkConfig, proposal types.Proposal, changes []string) error {
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	content := fmt.Sprintf("Proposal \"%s\" changed since the last check:\n• %s", proposal.Title, strings.Join(changes, "\n• "))
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("✏️ Governance Proposal Updated - %s", proposal.Network),
+		Content:     content + s.notesText(key),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleCommunity,
+		AlertType:   types.AlertProposalUpdated,
+		Severity:    s.alertSeverity(networkConfig, proposal, types.AlertProposalUpdated),
+		Proposal:    &proposal,
+	}
+	if err := s.sendProposalAlert(msg); err != nil {
+		return err
+	}
+
+	proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertProposalUpdated).WithField("changes", len(changes)).Info("Sent proposal update")
+	return nil
+}
+
+// proposalSnapshot returns the snapshot of a proposal seen at now
+func proposalSnapshot(proposal types.Proposal, now time.Time) types.ProposalSnapshot {
+	hash := sha256.Sum256([]byte(proposal.Description))
+	return types.ProposalSnapshot{
+		Title:             proposal.Title,
+		DescriptionHash:   hex.EncodeToString(hash[:8]),
+		DescriptionLength: len([]rune(proposal.Description)),
+		Status:            proposal.Status,
+		DepositEnd:        proposal.DepositEnd,
+		VotingStart:       proposal.VotingStart,
+		VotingEnd:         proposal.VotingEnd,
+		Seen:              now,
+	}
+}
+
+// snapshotChanges describes what changed between two snapshots. Deadlines
+// that were unset before, such as voting times set when voting starts, are
+// not changes
+func snapshotChanges(previous, current types.ProposalSnapshot) []string {
+	var changes []string
+	if previous.Title != current.Title {
+		changes = append(changes, fmt.Sprintf("Title: \"%s\" → \"%s\"", previous.Title, current.Title))
+	}
+	if previous.DescriptionHash != current.DescriptionHash {
+		changes = append(changes, fmt.Sprintf("Description edited (%d → %d characters)", previous.DescriptionLength, current.DescriptionLength))
+	}
+	if previous.Status != current.Status {
+		changes = append(changes, fmt.Sprintf("Status: %s → %s", governance.StatusLabel(previous.Status), governance.StatusLabel(current.Status)))
+	}
+	for _, deadline := range []struct {
+		name              string
+		previous, current time.Time
+	}{
+		{"Deposit end", previous.DepositEnd, current.DepositEnd},
+		{"Voting start", previous.VotingStart, current.VotingStart},
+		{"Voting end", previous.VotingEnd, current.VotingEnd},
+	} {
+		if deadline.previous.IsZero() || deadline.current.IsZero() || deadline.previous.Equal(deadline.current) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", deadline.name,
+			deadline.previous.UTC().Format("2006-01-02 15:04 MST"), deadline.current.UTC().Format("2006-01-02 15:04 MST")))
+	}
+	return changes
+}
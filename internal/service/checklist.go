@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/report"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// checkUpgradeChecklist sends the pre-vote checklist of a software upgrade
+// proposal to ops channels once it enters voting period
+func (s *Service) checkUpgradeChecklist(ctx context.Context, client *governance.Client, proposal types.Proposal, networkConfig types.NetworkConfig, now time.Time) {
+	if proposal.Upgrade == nil || proposal.VotingStart.After(now) || !alertEnabled(networkConfig, types.AlertUpgradeChecklist) {
+		return
+	}
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	if s.store.WasSent(key, types.AlertUpgradeChecklist) {
+		return
+	}
+
+	checklist := report.BuildUpgradeChecklist(ctx, client, networkConfig, proposal)
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("📋 Upgrade Checklist - %s", proposal.Network),
+		Content:     fmt.Sprintf("Proposal \"%s\" is in voting period. Check before voting:\n\n%s", proposal.Title, checklist.Text()),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleOps,
+		AlertType:   types.AlertUpgradeChecklist,
+		Severity:    s.alertSeverity(networkConfig, proposal, types.AlertUpgradeChecklist),
+		Proposal:    &proposal,
+	}
+	if err := s.sendProposalAlert(msg); err != nil {
+		proposalAlertLog(networkConfig, proposal.ID, eventAlertFailed, types.AlertUpgradeChecklist).WithError(err).Error("Failed to send upgrade checklist")
+		return
+	}
+
+	proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertUpgradeChecklist).Infof("Sent upgrade checklist for %s", proposal.Upgrade.Name)
+	s.markSent(key, types.AlertUpgradeChecklist)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// registerCommands registers the bot commands and reaction handler of the
+// service
+func (s *Service) registerCommands() {
+	s.notifier.HandleAdminCommand("/note", s.noteCommand)
+	s.notifier.HandleAdminCommand("/tag", s.tagCommand)
+	s.notifier.HandleAdminCommand("/link", s.linkCommand)
+	s.notifier.HandleAdminCommand("/unlink", s.unlinkCommand)
+	s.notifier.HandleCommand("/notes", s.notesCommand)
+	s.notifier.HandleCommand("/proposals", s.proposalsCommand)
+	s.notifier.HandleCommand("/proposal", s.proposalCommand)
+	s.notifier.HandleCommand("/tally", s.tallyCommand)
+	s.notifier.HandleCommand("/votes", s.votesCommand)
+	s.notifier.HandleAdminCommand("/mute", s.muteCommand)
+	s.notifier.HandleAdminCommand("/unmute", s.unmuteCommand)
+	s.notifier.HandleCommand("/status", s.statusCommand)
+	s.notifier.HandleReactions(s.onReaction)
+}
+
+// runCommands registers the bot commands and serves them until ctx is done
+func (s *Service) runCommands(ctx context.Context) {
+	s.registerCommands()
+	s.notifier.StartCommands(ctx)
+}
+
+// commandProposal resolves the "<network> <id>" arguments of a command to
+// a proposal key
+func (s *Service) commandProposal(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("expected <network> <proposal id>")
+	}
+	s.configMu.RLock()
+	_, networkConfig, ok := config.LookupNetwork(s.config, args[0])
+	s.configMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown network %q", args[0])
+	}
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid proposal ID %q", args[1])
+	}
+	return proposalKey(networkConfig.ChainID, proposalID), nil
+}
+
+// noteCommand handles /note <network> <id> <text>
+func (s *Service) noteCommand(args []string, user string) string {
+	key, err := s.commandProposal(args)
+	if err != nil || len(args) < 3 {
+		return "Usage: /note <network> <proposal id> <text>"
+	}
+
+	note := types.Note{Text: strings.Join(args[2:], " "), Author: user, Time: time.Now()}
+	if err := s.store.AddNote(key, note); err != nil {
+		return fmt.Sprintf("Failed to save note: %v", err)
+	}
+	return fmt.Sprintf("Note added to %s", key)
+}
+
+// tagCommand handles /tag <network> <id> <tag>...
+func (s *Service) tagCommand(args []string, user string) string {
+	key, err := s.commandProposal(args)
+	if err != nil || len(args) < 3 {
+		return "Usage: /tag <network> <proposal id> <tag>..."
+	}
+
+	if err := s.store.AddTags(key, args[2:]); err != nil {
+		return fmt.Sprintf("Failed to save tags: %v", err)
+	}
+	return fmt.Sprintf("Tags of %s: %s", key, strings.Join(s.store.Tags(key), ", "))
+}
+
+// notesCommand handles /notes <network> <id>
+func (s *Service) notesCommand(args []string, user string) string {
+	key, err := s.commandProposal(args)
+	if err != nil {
+		return "Usage: /notes <network> <proposal id>"
+	}
+
+	text := strings.TrimSpace(s.notesText(key))
+	if text == "" {
+		return fmt.Sprintf("No notes, tags or links for %s", key)
+	}
+	return text
+}
+
+// notesText returns the team notes, tags and tracking links of a proposal
+// for alerts, or "" if there are none
+func (s *Service) notesText(key string) string {
+	notes := s.store.Notes(key)
+	tags := s.store.Tags(key)
+	links := s.linksText(key)
+	if len(notes) == 0 && len(tags) == 0 && links == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(links)
+	if len(tags) > 0 {
+		fmt.Fprintf(&b, "\n\nTags: %s", strings.Join(tags, ", "))
+	}
+	if len(notes) > 0 {
+		b.WriteString("\n\nTeam notes:")
+		for _, note := range notes {
+			fmt.Fprintf(&b, "\n• %s (%s, %s)", note.Text, note.Author, note.Time.Format("2006-01-02"))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// checkDepositExpiry alerts about proposals whose deposit period ends within
+// alerts.hours_before_deposit_end short of the min deposit, so they can be
+// topped up before they are deleted, and once more when such a proposal
+// reaches its min deposit and enters voting
+func (s *Service) checkDepositExpiry(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, active []types.Proposal) {
+	if s.config.Alerts.HoursBeforeDepositEnd <= 0 {
+		return
+	}
+
+	now := s.now(client)
+	for _, proposal := range active {
+		key := proposalKey(networkConfig.ChainID, proposal.ID)
+		switch proposal.Status {
+		case string(cosmosgov.StatusDepositPeriod):
+			if alertEnabled(networkConfig, types.AlertDepositExpiry) && !s.store.WasSent(key, types.AlertDepositExpiry) {
+				s.checkDepositEnding(ctx, client, networkConfig, proposal, key, now)
+			}
+		case string(cosmosgov.StatusVotingPeriod):
+			// Only proposals whose expiry was announced get the follow-up
+			if alertEnabled(networkConfig, types.AlertDepositReached) && s.store.WasSent(key, types.AlertDepositExpiry) && !s.store.WasSent(key, types.AlertDepositReached) {
+				s.sendDepositReached(networkConfig, proposal, key)
+			}
+		}
+	}
+}
+
+// checkDepositEnding sends the deposit expiry alert of a proposal in deposit
+// period when it is due and the proposal still lacks deposit
+func (s *Service) checkDepositEnding(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposal types.Proposal, key string, now time.Time) {
+	timeUntilEnd := proposal.DepositEnd.Sub(now)
+	threshold := time.Duration(s.config.Alerts.HoursBeforeDepositEnd) * time.Hour
+	if !isAlertDue(timeUntilEnd, threshold, s.checkInterval()) {
+		return
+	}
+
+	log := proposalLog(networkConfig, proposal.ID, eventProposal)
+	params := s.govParams(ctx, client, networkConfig)
+	if params == nil || len(params.MinDeposit) == 0 {
+		alertLog(log, eventAlertSkipped, types.AlertDepositExpiry).Debug("Deposit expiry not checked, min deposit unknown")
+		return
+	}
+	missing := missingDeposit(proposal.TotalDeposit, params.MinDeposit)
+	if len(missing) == 0 {
+		return
+	}
+	if s.holdForQuietHours(now, proposal.DepositEnd) {
+		alertLog(log, eventAlertHeld, types.AlertDepositExpiry).Infof("Deposit expiry alert held for quiet hours (%.1f hours until deposit end)", timeUntilEnd.Hours())
+		return
+	}
+
+	// Unfunded spam is not amplified to community channels
+	funded := s.communityFunded(ctx, client, networkConfig, proposal)
+	role := types.RoleCommunity
+	if !funded {
+		role = types.RoleAudit
+	}
+
+	deposits := s.fetchDeposits(ctx, client, networkConfig, proposal.ID)
+	msg := types.NotificationMessage{
+		Title: fmt.Sprintf("💰 Governance Proposal Deposit Ending - %s", proposal.Network),
+		Content: fmt.Sprintf("Proposal \"%s\" needs %s more deposit within %s (deposit period ends %s), or it is deleted without a vote.\n\n%s%sDescription: %s%s",
+			proposal.Title, amounts.Coins(missing), amounts.Duration(timeUntilEnd), proposal.DepositEnd.Format("2006-01-02 15:04 MST"),
+			s.depositText(ctx, client, networkConfig, proposal, deposits), typeLine(proposal), s.alertDescription(proposal), s.notesText(key)),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        role,
+		AlertType:   types.AlertDepositExpiry,
+		Severity:    s.alertSeverity(networkConfig, proposal, types.AlertDepositExpiry),
+		Details:     s.alertDetails(proposal, networkConfig.Name),
+		Proposal:    &proposal,
+	}
+	if err := s.sendProposalAlert(msg); err != nil {
+		alertLog(log, eventAlertFailed, types.AlertDepositExpiry).WithError(err).Error("Failed to send deposit expiry alert")
+		return
+	}
+	alertLog(log, eventAlertSent, types.AlertDepositExpiry).WithField("role", role).Infof("Sent deposit expiry alert (%.1f hours until deposit end)", timeUntilEnd.Hours())
+	s.markSent(key, types.AlertDepositExpiry)
+	if !funded {
+		s.markSent(key, types.AlertDepositExpiry+internalOnly)
+	}
+}
+
+// sendDepositReached tells that a proposal whose deposit expiry was
+// announced reached its min deposit and entered voting, to the channels
+// that got the expiry alert
+func (s *Service) sendDepositReached(networkConfig types.NetworkConfig, proposal types.Proposal, key string) {
+	log := proposalLog(networkConfig, proposal.ID, eventProposal)
+	role := types.RoleCommunity
+	if s.store.WasSent(key, types.AlertDepositExpiry+internalOnly) {
+		role = types.RoleAudit
+	}
+	msg := types.NotificationMessage{
+		Title: fmt.Sprintf("✅ Governance Proposal Deposit Reached - %s", proposal.Network),
+		Content: fmt.Sprintf("Proposal \"%s\" reached its min deposit with %s and entered voting, open until %s.%s",
+			proposal.Title, amounts.Coins(proposal.TotalDeposit), proposal.VotingEnd.Format("2006-01-02 15:04 MST"), s.notesText(key)),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        role,
+		AlertType:   types.AlertDepositReached,
+		Severity:    s.alertSeverity(networkConfig, proposal, types.AlertDepositReached),
+		Details:     s.alertDetails(proposal, networkConfig.Name),
+		Proposal:    &proposal,
+	}
+	if err := s.sendProposalAlert(msg); err != nil {
+		alertLog(log, eventAlertFailed, types.AlertDepositReached).WithError(err).Error("Failed to send deposit reached alert")
+		return
+	}
+	alertLog(log, eventAlertSent, types.AlertDepositReached).Info("Sent deposit reached alert")
+	s.markSent(key, types.AlertDepositReached)
+}
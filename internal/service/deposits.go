@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// maxListedDepositors is the number of depositors named in an alert
+const maxListedDepositors = 5
+
+// fetchDeposits fetches the deposits of a proposal and records its
+// depositors. Nodes delete deposits once voting ends, so they have to be
+// recorded while the proposal is active to judge its depositors later.
+// Returns nil if they cannot be fetched
+func (s *Service) fetchDeposits(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposalID uint64) []types.Deposit {
+	deposits, err := client.GetDeposits(ctx, proposalID)
+	if err != nil {
+		proposalLog(networkConfig, proposalID, eventFetchFailed).WithError(err).Warn("Failed to fetch deposits")
+		return nil
+	}
+
+	depositors := make([]string, 0, len(deposits))
+	for _, deposit := range deposits {
+		depositors = append(depositors, deposit.Depositor)
+	}
+	key := proposalKey(networkConfig.ChainID, proposalID)
+	if err := s.store.SetDepositors(key, depositors); err != nil {
+		keyLog(key, eventStateFailed).WithError(err).Warn("Failed to record depositors")
+	}
+	return deposits
+}
+
+// recordDepositors records the final depositors of a proposal in voting
+// period once, deposits made after its new proposal alert included
+func (s *Service) recordDepositors(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposal types.Proposal) {
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	s.mu.Lock()
+	fetched := s.depositsFetched[key]
+	s.mu.Unlock()
+	if fetched || s.fetchDeposits(ctx, client, networkConfig, proposal.ID) == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.depositsFetched[key] = true
+	s.mu.Unlock()
+}
+
+// settleDepositors drops the recorded depositors of a proposal that left
+// voting period, counting it against them if it was vetoed as spam
+func (s *Service) settleDepositors(networkConfig types.NetworkConfig, proposalID uint64, proposal *types.Proposal) {
+	key := proposalKey(networkConfig.ChainID, proposalID)
+	spam := proposal != nil && isSpam(proposal)
+	if err := s.store.SettleDepositors(key, spam); err != nil {
+		keyLog(key, eventStateFailed).WithError(err).Warn("Failed to settle depositors")
+	}
+	if spam {
+		proposalLog(networkConfig, proposalID, eventProposal).Info("Flagged the depositors of spam proposal")
+	}
+
+	s.mu.Lock()
+	delete(s.depositsFetched, key)
+	s.mu.Unlock()
+}
+
+// spamDepositors returns the depositors that funded proposals vetoed as spam
+// before, with the number of such proposals
+func (s *Service) spamDepositors(deposits []types.Deposit) map[string]int {
+	flagged := make(map[string]int)
+	for _, deposit := range deposits {
+		if count := s.store.SpamCount(deposit.Depositor); count > 0 {
+			flagged[deposit.Depositor] = count
+		}
+	}
+	return flagged
+}
+
+// fromSpamDepositors reports whether a proposal should not be announced
+// because every depositor reached the spam depositor threshold
+func (s *Service) fromSpamDepositors(deposits []types.Deposit) bool {
+	threshold := s.config.Alerts.SpamDepositorThreshold
+	if threshold <= 0 || len(deposits) == 0 {
+		return false
+	}
+	for _, deposit := range deposits {
+		if s.store.SpamCount(deposit.Depositor) < threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// depositText describes who funded a proposal and, in deposit period, how
+// much is missing to reach the min deposit
+func (s *Service) depositText(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposal types.Proposal, deposits []types.Deposit) string {
+	var lines []string
+
+	if proposal.Status == string(cosmosgov.StatusDepositPeriod) {
+		deposited := amounts.Coins(proposal.TotalDeposit)
+		if deposited == "" {
+			deposited = "nothing"
+		}
+		line := "Deposited: " + deposited
+		if params := s.govParams(ctx, client, networkConfig); params != nil && len(params.MinDeposit) > 0 {
+			line += fmt.Sprintf(" of %s minimum", amounts.Coins(params.MinDeposit))
+			if missing := missingDeposit(proposal.TotalDeposit, params.MinDeposit); len(missing) > 0 {
+				line += fmt.Sprintf(", %s to go", amounts.Coins(missing))
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if len(deposits) > 0 {
+		listed := make([]string, 0, maxListedDepositors)
+		for i, deposit := range deposits {
+			if i == maxListedDepositors {
+				listed = append(listed, fmt.Sprintf("%d more", len(deposits)-i))
+				break
+			}
+			listed = append(listed, fmt.Sprintf("%s (%s)", deposit.Depositor, amounts.Coins(deposit.Amount)))
+		}
+		lines = append(lines, "Depositors: "+strings.Join(listed, ", "))
+	}
+
+	if flagged := s.spamDepositors(deposits); len(flagged) > 0 {
+		proposals := 0
+		for _, count := range flagged {
+			proposals += count
+		}
+		lines = append(lines, fmt.Sprintf("⚠️ %d of the depositors funded %d proposals vetoed as spam before", len(flagged), proposals))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n\n"
+}
+
+// internalOnly suffixes the store alert types of alerts that only went to
+// audit channels because the proposal lacked the deposit community channels
+// require
+const internalOnly = ":internal"
+
+// communityFunded reports whether a proposal has the deposit alerts need to
+// reach community channels, alerts.community_min_deposit_percent of the min
+// deposit. Proposals past deposit period always have, as do those of
+// networks whose min deposit is unknown
+func (s *Service) communityFunded(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposal types.Proposal) bool {
+	percent := s.config.Alerts.CommunityMinDepositPercent
+	if percent <= 0 || proposal.Status != string(cosmosgov.StatusDepositPeriod) {
+		return true
+	}
+	params := s.govParams(ctx, client, networkConfig)
+	if params == nil || len(params.MinDeposit) == 0 {
+		return true
+	}
+
+	required := make([]types.Coin, 0, len(params.MinDeposit))
+	for _, coin := range params.MinDeposit {
+		amount, ok := new(big.Int).SetString(coin.Amount, 10)
+		if !ok {
+			continue
+		}
+		amount.Mul(amount, big.NewInt(int64(percent)))
+		amount.Div(amount, big.NewInt(100))
+		required = append(required, types.Coin{Denom: coin.Denom, Amount: amount.String()})
+	}
+	return len(missingDeposit(proposal.TotalDeposit, required)) == 0
+}
+
+// missingDeposit returns, per denom, how much a total deposit lacks to reach
+// the min deposit
+func missingDeposit(total, minimum []types.Coin) []types.Coin {
+	deposited := make(map[string]*big.Int, len(total))
+	for _, coin := range total {
+		if amount, ok := new(big.Int).SetString(coin.Amount, 10); ok {
+			deposited[coin.Denom] = amount
+		}
+	}
+
+	var missing []types.Coin
+	for _, coin := range minimum {
+		amount, ok := new(big.Int).SetString(coin.Amount, 10)
+		if !ok {
+			continue
+		}
+		if have, ok := deposited[coin.Denom]; ok {
+			amount.Sub(amount, have)
+		}
+		if amount.Sign() > 0 {
+			missing = append(missing, types.Coin{Denom: coin.Denom, Amount: amount.String()})
+		}
+	}
+	return missing
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// alertDescription returns the proposal description cut to the configured
+// maximum length
+func (s *Service) alertDescription(proposal types.Proposal) string {
+	maxLen := s.config.Notifications.Details.MaxDescriptionLength
+	if maxLen <= 0 {
+		return proposal.Description
+	}
+	return truncateString(proposal.Description, maxLen)
+}
+
+// alertDetails returns the full-detail follow-up for a proposal, or an
+// empty string if follow-ups are disabled for its network or type
+func (s *Service) alertDetails(proposal types.Proposal, networkName string) string {
+	config := s.config.Notifications.Details
+	if !config.Followup {
+		return ""
+	}
+	if len(config.Networks) > 0 && !slices.Contains(config.Networks, networkName) {
+		return ""
+	}
+	if !governance.MatchesProposalType(config.Types, proposal) {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Details of proposal #%d \"%s\"\n\n", proposal.ID, proposal.Title)
+	fmt.Fprintf(&b, "Status: %s\n", proposal.Status)
+	fmt.Fprintf(&b, "Voting: %s → %s\n", proposal.VotingStart.Format("2006-01-02 15:04 MST"), proposal.VotingEnd.Format("2006-01-02 15:04 MST"))
+	if len(proposal.Messages) > 0 {
+		fmt.Fprintf(&b, "\nMessages:\n")
+		for _, msg := range proposal.Messages {
+			fmt.Fprintf(&b, "• %s\n", msg)
+		}
+	}
+	if proposal.Metadata != "" {
+		fmt.Fprintf(&b, "\nMetadata: %s\n", proposal.Metadata)
+	}
+	fmt.Fprintf(&b, "\nDescription:\n%s", proposal.Description)
+	return b.String()
+}
+
+// alertEnabled reports whether alerts of alertType are enabled for a network
+func alertEnabled(networkConfig types.NetworkConfig, alertType string) bool {
+	return !slices.Contains(networkConfig.DisabledAlerts, alertType)
+}
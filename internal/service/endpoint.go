@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// reportEndpointStatus sends an ops alert when a network's checks start
+// failing and when they recover, instead of on every failed check
+func (s *Service) reportEndpointStatus(networkName string, checkErr error) {
+	networkConfig := s.config.Networks[networkName]
+
+	s.mu.Lock()
+	wasFailing := s.failingNetworks[networkName]
+	if checkErr != nil {
+		s.failingNetworks[networkName] = true
+	} else {
+		delete(s.failingNetworks, networkName)
+	}
+	s.mu.Unlock()
+
+	var msg types.NotificationMessage
+	switch {
+	case checkErr != nil && !wasFailing:
+		msg = types.NotificationMessage{
+			Title:   fmt.Sprintf("🔌 Endpoint Failure - %s", networkConfig.Name),
+			Content: fmt.Sprintf("Checking proposals failed for %s:\n%v%s\n\nAlerts for this network are paused until the endpoint recovers.", primaryEndpoint(networkConfig), checkErr, failureHint(checkErr)),
+		}
+	case checkErr == nil && wasFailing:
+		msg = types.NotificationMessage{
+			Title:   fmt.Sprintf("✅ Endpoint Recovered - %s", networkConfig.Name),
+			Content: fmt.Sprintf("Checking proposals succeeded again for %s.", primaryEndpoint(networkConfig)),
+		}
+	default:
+		return
+	}
+
+	msg.Network = networkConfig.Name
+	msg.ChainID = networkConfig.ChainID
+	msg.Role = types.RoleOps
+
+	if err := s.notifier.SendNotification(msg); err != nil {
+		networkLog(networkConfig, eventAlertFailed).WithError(err).Warn("Failed to send endpoint status notification")
+	}
+}
+
+// failureHint explains the kind of a failed check to the operators, "" when
+// it is unknown
+func failureHint(err error) string {
+	switch {
+	case errors.Is(err, governance.ErrRateLimited):
+		return "\n\nThe endpoint is rate limiting requests. Raise check_interval_minutes or add rest_endpoints with a higher quota."
+	case errors.Is(err, governance.ErrSchema):
+		return "\n\nThe endpoint answered in an unexpected format, possibly after a chain upgrade changed its API."
+	case errors.Is(err, governance.ErrStale):
+		return "\n\nThe endpoint served data from a node behind the others."
+	case errors.Is(err, governance.ErrNoTrustedEndpoint):
+		return "\n\nEvery endpoint reports another chain ID than the configured one."
+	default:
+		return ""
+	}
+}
+
+// primaryEndpoint returns the primary endpoint of a network for messages
+func primaryEndpoint(networkConfig types.NetworkConfig) string {
+	if networkConfig.Transport == types.TransportGRPC && len(networkConfig.GRPCEndpoints) > 0 {
+		return networkConfig.GRPCEndpoints[0]
+	}
+	return networkConfig.RestEndpoint
+}
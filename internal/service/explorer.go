@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"governance-alerts-cosmos/internal/outbound"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// explorerProbeInterval is how long an explorer that answered a probe is
+// considered up before it is probed again
+const explorerProbeInterval = 15 * time.Minute
+
+// explorerProbeTimeout bounds a probe so slow explorers do not hold up the
+// check of their network
+const explorerProbeTimeout = 5 * time.Second
+
+// explorers probes block explorers with proposal links. Its backoff keeps
+// explorers that are down from being probed on every check
+var explorers = outbound.Register("explorers", outbound.Options{
+	Timeout:     explorerProbeTimeout,
+	MinInterval: time.Second,
+	CacheTTL:    explorerProbeInterval,
+})
+
+// explorerHealth records, per chain ID, the explorer URL templates whose
+// last probe failed
+type explorerHealth struct {
+	mu   sync.Mutex
+	down map[string]map[string]bool
+}
+
+// explorerTemplates returns the explorer URL templates of a network, the
+// primary explorer_url_template first and then the explorer_url_templates
+// fallbacks
+func explorerTemplates(networkConfig types.NetworkConfig) []string {
+	var templates []string
+	if networkConfig.ExplorerURLTemplate != "" {
+		templates = append(templates, networkConfig.ExplorerURLTemplate)
+	}
+	return append(templates, networkConfig.ExplorerURLTemplates...)
+}
+
+// expandExplorerTemplate returns the link of a proposal on an explorer
+func expandExplorerTemplate(template string, networkConfig types.NetworkConfig, proposalID uint64) string {
+	return strings.NewReplacer(
+		"{id}", strconv.FormatUint(proposalID, 10),
+		"{chain_id}", networkConfig.ChainID,
+	).Replace(template)
+}
+
+// explorerURL returns the explorer link of a proposal from the first of the
+// network's explorers that is not known to be down, the primary when all
+// are, empty when none is configured
+func (s *Service) explorerURL(networkConfig types.NetworkConfig, proposalID uint64) string {
+	templates := explorerTemplates(networkConfig)
+	if len(templates) == 0 {
+		return ""
+	}
+
+	s.explorers.mu.Lock()
+	down := s.explorers.down[networkConfig.ChainID]
+	s.explorers.mu.Unlock()
+	for _, template := range templates {
+		if !down[template] {
+			return expandExplorerTemplate(template, networkConfig, proposalID)
+		}
+	}
+	return expandExplorerTemplate(templates[0], networkConfig, proposalID)
+}
+
+// probeExplorers checks the explorers of a network with several of them by
+// fetching the link of its latest proposal, so alerts link to one that is
+// up and indexes the chain. Any answer other than 200 counts as down. In
+// privacy mode explorers are not contacted and the primary is used
+func (s *Service) probeExplorers(ctx context.Context, networkConfig types.NetworkConfig, proposals []types.Proposal) {
+	templates := explorerTemplates(networkConfig)
+	if len(templates) < 2 || len(proposals) == 0 || privacy.Active() {
+		return
+	}
+
+	var latest uint64
+	for _, proposal := range proposals {
+		latest = max(latest, proposal.ID)
+	}
+
+	down := make(map[string]bool)
+	for _, template := range templates {
+		probeCtx, cancel := context.WithTimeout(ctx, explorerProbeTimeout)
+		_, err := explorers.Get(probeCtx, expandExplorerTemplate(template, networkConfig, latest))
+		cancel()
+		if err != nil {
+			down[template] = true
+			networkLog(networkConfig, eventFetchFailed).WithField("explorer", template).WithError(err).Debug("Explorer probe failed")
+		}
+	}
+
+	s.explorers.mu.Lock()
+	defer s.explorers.mu.Unlock()
+	if s.explorers.down == nil {
+		s.explorers.down = make(map[string]map[string]bool)
+	}
+	for template := range down {
+		if !s.explorers.down[networkConfig.ChainID][template] {
+			networkLog(networkConfig, eventCheck).WithField("explorer", template).Warn("Explorer down or not indexing the chain, linking alerts to the next explorer")
+		}
+	}
+	for template := range s.explorers.down[networkConfig.ChainID] {
+		if !down[template] {
+			networkLog(networkConfig, eventCheck).WithField("explorer", template).Info("Explorer back up")
+		}
+	}
+	s.explorers.down[networkConfig.ChainID] = down
+}
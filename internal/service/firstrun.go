@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/store"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// First-run modes for proposals already in voting when the service starts
+const (
+	firstRunAlertAll      = "alert_all"
+	firstRunDigestOnly    = "digest_only"
+	firstRunTrackSilently = "track_silently"
+)
+
+// handleFirstRun applies the first-run mode to the proposals found in
+// voting at the first check of a chain without recorded state, when the
+// state file was missing or a network was added. Proposals without any
+// recorded alert have their reminders marked as sent so they do not flood
+// the channels, and with digest_only they are listed in a single message
+// instead. Outcome notifications are still sent for them. Later starts
+// find the chain baselined and leave pending reminders alone
+func (s *Service) handleFirstRun(networkConfig types.NetworkConfig, voting []types.Proposal, now time.Time) {
+	if s.store.Baselined(networkConfig.ChainID, store.BaselineFirstRun) {
+		return
+	}
+	if err := s.store.MarkBaselined(networkConfig.ChainID, store.BaselineFirstRun, now); err != nil {
+		networkLog(networkConfig, eventStateFailed).WithError(err).Warn("Failed to record first run")
+	}
+
+	mode := s.config.Alerts.FirstRun
+	if mode == "" || mode == firstRunAlertAll {
+		return
+	}
+
+	var existing []types.Proposal
+	for _, proposal := range voting {
+		key := proposalKey(networkConfig.ChainID, proposal.ID)
+		if s.store.Known(key) {
+			continue
+		}
+		existing = append(existing, proposal)
+		for _, alertType := range []string{types.AlertNewProposal, types.AlertVotingStart, types.AlertVotingEnd} {
+			s.markSent(key, alertType)
+		}
+	}
+	if len(existing) == 0 {
+		return
+	}
+
+	networkLog(networkConfig, eventAlertSkipped).WithField("proposals", len(existing)).Info("First run: proposals already in voting tracked without reminders")
+	if mode == firstRunTrackSilently {
+		return
+	}
+
+	if err := s.sendFirstRunDigest(networkConfig, existing, now); err != nil {
+		networkLog(networkConfig, eventAlertFailed).WithError(err).Warn("Failed to send first-run digest")
+	}
+}
+
+// sendFirstRunDigest lists the proposals already in voting in one message
+func (s *Service) sendFirstRunDigest(networkConfig types.NetworkConfig, proposals []types.Proposal, now time.Time) error {
+	lines := make([]string, 0, len(proposals))
+	for _, proposal := range proposals {
+		lines = append(lines, fmt.Sprintf("• #%d %s — voting ends %s (%s)",
+			proposal.ID, proposal.Title, proposal.VotingEnd.Format("2006-01-02 15:04 MST"), amounts.In(proposal.VotingEnd.Sub(now))))
+	}
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("📚 Proposals Already in Voting - %s", networkConfig.Name),
+		Content:     fmt.Sprintf("%d proposals were already in voting when monitoring started. No individual reminders will be sent for them:\n%s", len(proposals), strings.Join(lines, "\n")),
+		Network:     networkConfig.Name,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleCommunity,
+	}
+	return s.notifier.SendNotification(msg)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrUnknownChannel is returned for channels that are not enabled
+var ErrUnknownChannel = errors.New("unknown channel")
+
+// Flags returns the runtime flags
+func (s *Service) Flags() types.RuntimeFlags {
+	return s.store.Flags()
+}
+
+// SetTallyPolling enables or disables the tally fetches made while
+// proposals are in voting period: tally charts, tally updates and bonded
+// stake tracking
+func (s *Service) SetTallyPolling(enabled bool) (types.RuntimeFlags, error) {
+	return s.updateFlags(fmt.Sprintf("tally polling enabled=%t", enabled), func(flags *types.RuntimeFlags) error {
+		flags.TallyPollingDisabled = !enabled
+		return nil
+	})
+}
+
+// PauseChannel pauses or resumes delivery to a channel, named as in
+// channel health
+func (s *Service) PauseChannel(channel string, paused bool) (types.RuntimeFlags, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	// A paused channel removed from the configuration can still be resumed
+	// to clear its flag
+	known := slices.Contains(s.notifier.Channels(), channel)
+	if !known && (paused || !slices.Contains(s.store.Flags().PausedChannels, channel)) {
+		return types.RuntimeFlags{}, fmt.Errorf("%w %q", ErrUnknownChannel, channel)
+	}
+
+	flags, err := s.updateFlags(fmt.Sprintf("channel %s paused=%t", channel, paused), func(flags *types.RuntimeFlags) error {
+		flags.PausedChannels = setMember(flags.PausedChannels, channel, paused)
+		return nil
+	})
+	if err != nil {
+		return flags, err
+	}
+	s.notifier.SetPaused(flags.PausedChannels)
+	return flags, nil
+}
+
+// SetDryRun switches the proposal alerts of a network to dry-run, logging
+// them instead of sending them, or back. Alerts due during a dry run count
+// as handled and are not sent afterwards
+func (s *Service) SetDryRun(networkName string, enabled bool) (types.RuntimeFlags, error) {
+	s.configMu.RLock()
+	_, networkConfig, ok := config.LookupNetwork(s.config, networkName)
+	s.configMu.RUnlock()
+	if !ok {
+		return types.RuntimeFlags{}, fmt.Errorf("%w %q", ErrUnknownNetwork, networkName)
+	}
+
+	return s.updateFlags(fmt.Sprintf("network %s dry_run=%t", networkConfig.Name, enabled), func(flags *types.RuntimeFlags) error {
+		flags.DryRunNetworks = setMember(flags.DryRunNetworks, networkConfig.ChainID, enabled)
+		return nil
+	})
+}
+
+// updateFlags applies change to the runtime flags and persists them
+func (s *Service) updateFlags(description string, change func(*types.RuntimeFlags) error) (types.RuntimeFlags, error) {
+	s.flagsMu.Lock()
+	defer s.flagsMu.Unlock()
+
+	flags := s.store.Flags()
+	if err := change(&flags); err != nil {
+		return flags, err
+	}
+	if err := s.store.SetFlags(flags); err != nil {
+		return flags, fmt.Errorf("failed to record flags: %w", err)
+	}
+	logrus.WithFields(logrus.Fields{"event": eventService}).Infof("Runtime flag changed: %s", description)
+	return flags, nil
+}
+
+// tallyPolling reports whether tallies are fetched during voting
+func (s *Service) tallyPolling() bool {
+	return !s.store.Flags().TallyPollingDisabled
+}
+
+// dryRun reports whether the proposal alerts of a chain are only logged
+func (s *Service) dryRun(chainID string) bool {
+	return slices.Contains(s.store.Flags().DryRunNetworks, chainID)
+}
+
+// setMember adds value to list or removes it from list
+func setMember(list []string, value string, member bool) []string {
+	kept := make([]string, 0, len(list)+1)
+	for _, item := range list {
+		if item != value {
+			kept = append(kept, item)
+		}
+	}
+	if member {
+		kept = append(kept, value)
+	}
+	return kept
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"governance-alerts-cosmos/internal/lifecycle"
+	"governance-alerts-cosmos/internal/outbound"
+)
+
+// Overall health statuses
+const (
+	HealthStarting    = "starting"
+	HealthOK          = "ok"
+	HealthDegraded    = "degraded"
+	HealthUnavailable = "unavailable"
+)
+
+// Health is the state of the service reported to orchestration probes.
+// Live is false when the monitoring loop stopped completing check cycles,
+// Ready when the first cycle is done, at least one network was checked
+// recently and at least one channel is reachable. Auxiliary dependencies
+// and components beside the monitoring loop never affect readiness, their
+// failures only degrade the status
+type Health struct {
+	Status       string             `json:"status"`
+	Live         bool               `json:"live"`
+	Ready        bool               `json:"ready"`
+	Time         time.Time          `json:"time"`
+	StartedAt    time.Time          `json:"started_at"`
+	LastCycle    time.Time          `json:"last_cycle,omitempty"`
+	Networks     []NetworkHealth    `json:"networks"`
+	Channels     []ChannelHealth    `json:"channels"`
+	Dependencies []outbound.Health  `json:"dependencies,omitempty"`
+	Components   []lifecycle.Status `json:"components,omitempty"`
+}
+
+// NetworkHealth is the last successful poll of a network
+type NetworkHealth struct {
+	Network     string    `json:"network"`
+	ChainID     string    `json:"chain_id"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	Stale       bool      `json:"stale"`
+}
+
+// ChannelHealth is whether a notification channel is reachable, as of its
+// self-test or last delivery
+type ChannelHealth struct {
+	Channel   string `json:"channel"`
+	Reachable bool   `json:"reachable"`
+	Paused    bool   `json:"paused,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Health returns the health of the service as of now
+func (s *Service) Health() Health {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	now := time.Now()
+	staleAfter := s.staleAfter()
+
+	s.mu.Lock()
+	health := Health{Time: now, StartedAt: s.startedAt, LastCycle: s.lastCycle}
+	for name, networkConfig := range s.config.Networks {
+		lastSuccess := s.tracked[name].checkedAt
+		health.Networks = append(health.Networks, NetworkHealth{
+			Network:     name,
+			ChainID:     networkConfig.ChainID,
+			LastSuccess: lastSuccess,
+			Stale:       now.Sub(lastSuccess) > staleAfter,
+		})
+	}
+	s.mu.Unlock()
+	sort.Slice(health.Networks, func(i, j int) bool { return health.Networks[i].Network < health.Networks[j].Network })
+
+	for _, channel := range s.notifier.ChannelHealth() {
+		c := ChannelHealth{Channel: channel.Channel, Reachable: channel.OK(), Paused: channel.Paused}
+		if !c.Reachable {
+			c.LastError = channel.LastError
+		}
+		health.Channels = append(health.Channels, c)
+	}
+	health.Dependencies = outbound.Healths()
+	dependenciesOK := true
+	for _, dependency := range health.Dependencies {
+		dependenciesOK = dependenciesOK && dependency.OK
+	}
+	if s.components != nil {
+		health.Components = s.components()
+	}
+	componentsOK := true
+	for _, component := range health.Components {
+		componentsOK = componentsOK && component.State == lifecycle.StateRunning
+	}
+
+	// The loop is stuck when no cycle completed for longer than a network
+	// may stay stale, counting from startup until the first cycle
+	lastProgress := health.LastCycle
+	if lastProgress.IsZero() {
+		lastProgress = health.StartedAt
+	}
+	health.Live = now.Sub(lastProgress) <= staleAfter
+
+	fresh := 0
+	for _, network := range health.Networks {
+		if !network.Stale {
+			fresh++
+		}
+	}
+	reachable := len(health.Channels) == 0
+	allReachable := true
+	for _, channel := range health.Channels {
+		if channel.Paused {
+			continue
+		}
+		if channel.Reachable {
+			reachable = true
+		} else {
+			allReachable = false
+		}
+	}
+	health.Ready = health.Live && !health.LastCycle.IsZero() && (fresh > 0 || len(health.Networks) == 0) && reachable
+
+	switch {
+	case health.Live && health.LastCycle.IsZero():
+		health.Status = HealthStarting
+	case !health.Ready:
+		health.Status = HealthUnavailable
+	case fresh < len(health.Networks) || !allReachable || !dependenciesOK || !componentsOK:
+		health.Status = HealthDegraded
+	default:
+		health.Status = HealthOK
+	}
+	return health
+}
+
+// SetComponents sets where health reports the state of the components of
+// the service, such as its servers, from
+func (s *Service) SetComponents(statuses func() []lifecycle.Status) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.components = statuses
+}
+
+// staleAfter returns how old the last successful check of a network may
+// be before it is reported stale
+func (s *Service) staleAfter() time.Duration {
+	if s.config.Health.StaleMinutes > 0 {
+		return time.Duration(s.config.Health.StaleMinutes) * time.Minute
+	}
+	return 3 * s.checkInterval()
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/incidents"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// incidentFetchTimeout bounds the incidents feed and latest block requests
+const incidentFetchTimeout = 15 * time.Second
+
+// checkIncidents refreshes the active incidents of a network from its
+// status page feed and its latest block. A feed that cannot be read keeps
+// the incidents seen last, so a flaky status page does not flap the notes
+func (s *Service) checkIncidents(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, now time.Time) {
+	cfg := networkConfig.Incidents
+	if cfg.FeedURL == "" && cfg.HaltMinutes <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, incidentFetchTimeout)
+	defer cancel()
+
+	s.mu.Lock()
+	previous := s.incidents[networkConfig.ChainID]
+	s.mu.Unlock()
+
+	var active []types.ChainIncident
+	if cfg.FeedURL != "" {
+		feed, err := incidents.Fetch(ctx, cfg.FeedURL, cfg.Format, now)
+		if err != nil {
+			networkLog(networkConfig, eventFetchFailed).WithError(err).Warn("Failed to fetch incidents feed")
+			for _, incident := range previous {
+				if incident.Status != haltStatus {
+					active = append(active, incident)
+				}
+			}
+		} else {
+			active = feed
+		}
+	}
+	if cfg.HaltMinutes > 0 {
+		height, blockTime, err := client.LatestBlock(ctx)
+		if err != nil {
+			networkLog(networkConfig, eventFetchFailed).WithError(err).Warn("Failed to fetch latest block for halt detection")
+		} else if age := now.Sub(blockTime); age > time.Duration(cfg.HaltMinutes)*time.Minute {
+			active = append(active, types.ChainIncident{
+				Name:   fmt.Sprintf("no new block since height %d, %s ago", height, age.Round(time.Minute)),
+				Status: haltStatus,
+			})
+		}
+	}
+
+	s.mu.Lock()
+	s.incidents[networkConfig.ChainID] = active
+	s.mu.Unlock()
+
+	if len(active) > 0 && len(previous) == 0 {
+		networkLog(networkConfig, eventIncident).Warnf("Chain reports an incident: %s", incidentSummary(active))
+	} else if len(active) == 0 && len(previous) > 0 {
+		networkLog(networkConfig, eventIncident).Info("Chain incidents cleared")
+	}
+}
+
+// haltStatus is the status of halts detected from the latest block
+const haltStatus = "halted"
+
+// activeIncidents returns the incidents of a chain seen on the last check
+func (s *Service) activeIncidents(chainID string) []types.ChainIncident {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.incidents[chainID]
+}
+
+// incidentNote is appended to the alerts of a chain with active incidents,
+// so a governance reminder sent during a halt is not confusing
+func (s *Service) incidentNote(networkConfig types.NetworkConfig) string {
+	active := s.activeIncidents(networkConfig.ChainID)
+	if len(active) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nℹ️ Note: %s reports degraded service: %s. Voting times may shift.", networkConfig.Name, incidentSummary(active))
+}
+
+// incidentHeld reports whether the reminders of a chain are held while it
+// has active incidents, describing them for logs
+func (s *Service) incidentHeld(chainID string) (string, bool) {
+	var hold bool
+	for _, networkConfig := range s.config.Networks {
+		if networkConfig.ChainID == chainID {
+			hold = networkConfig.Incidents.HoldReminders
+		}
+	}
+	active := s.activeIncidents(chainID)
+	if !hold || len(active) == 0 {
+		return "", false
+	}
+	return "chain incident: " + incidentSummary(active), true
+}
+
+// incidentSummary describes incidents on one line
+func incidentSummary(active []types.ChainIncident) string {
+	parts := make([]string, 0, len(active))
+	for _, incident := range active {
+		part := fmt.Sprintf("%s (%s)", incident.Name, incident.Status)
+		if incident.URL != "" {
+			part += " " + incident.URL
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
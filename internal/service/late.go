@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/metrics"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// Late alert metrics
+const (
+	metricLateAlerts    = "governance_late_alerts_total"
+	metricAlertLateness = "governance_alert_lateness_seconds"
+)
+
+func init() {
+	metrics.Register(metricLateAlerts, "Threshold alerts sent later than intended because of quiet hours or downtime.", metrics.Counter)
+	metrics.Register(metricAlertLateness, "Lateness of the last threshold alert sent later than intended.", metrics.Gauge)
+}
+
+// markSeen records when a proposal was first seen, across restarts, and
+// returns that time
+func (s *Service) markSeen(key string, now time.Time) time.Time {
+	first, err := s.store.MarkSeen(key, now)
+	if err != nil {
+		keyLog(key, eventStateFailed).WithError(err).Warn("Failed to record first sighting")
+	}
+	if first.Equal(now) {
+		s.recordTimeline(key, types.TimelineFirstSeen, "First seen by the service", now)
+	}
+	return first
+}
+
+// recordDepositSightings records the first sighting of the proposals still
+// in deposit period, which only checks fetching deposit period proposals
+// see. A proposal seen before its voting started gets its start alert late
+// when quiet hours or downtime held it past voting start
+func (s *Service) recordDepositSightings(networkConfig types.NetworkConfig, proposals []types.Proposal, now time.Time) {
+	for _, proposal := range proposals {
+		if proposal.Status == string(cosmosgov.StatusDepositPeriod) {
+			s.markSeen(proposalKey(networkConfig.ChainID, proposal.ID), now)
+		}
+	}
+}
+
+// alertLateness returns how much later than intended an alert about an
+// event at eventTime is sent. It was intended threshold before the event,
+// or when the proposal was first seen if that is later. Up to one check
+// interval counts as on time, anything beyond was delayed by quiet hours or
+// downtime
+func (s *Service) alertLateness(eventTime time.Time, threshold time.Duration, firstSeen, now time.Time) time.Duration {
+	intended := eventTime.Add(-threshold)
+	if firstSeen.After(intended) {
+		intended = firstSeen
+	}
+
+	lateness := now.Sub(intended)
+	if lateness <= s.checkInterval() {
+		return 0
+	}
+	return lateness
+}
+
+// lateNotice returns the line flagging a late alert, empty if on time
+func lateNotice(lateness time.Duration) string {
+	if lateness <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("⏱️ This reminder is %s late.\n\n", amounts.Duration(lateness))
+}
+
+// formatDuration formats a duration in its two largest units among days,
+// hours and minutes, such as "2d 5h" or "3h"
+func formatDuration(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// recordLateAlert records the lateness of an alert in the state and metrics
+func (s *Service) recordLateAlert(networkConfig types.NetworkConfig, key, alertType string, lateness time.Duration) {
+	if lateness <= 0 {
+		return
+	}
+
+	labels := metrics.Labels{"network": networkConfig.Name, "chain_id": networkConfig.ChainID, "alert_type": alertType}
+	metrics.Add(metricLateAlerts, labels, 1)
+	metrics.Set(metricAlertLateness, labels, lateness.Seconds())
+
+	if err := s.store.MarkLate(key, alertType, lateness); err != nil {
+		keyLog(key, eventStateFailed).WithField("alert_type", alertType).WithError(err).Warn("Failed to record alert lateness")
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/metrics"
+	"governance-alerts-cosmos/internal/store"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Latency metrics
+const (
+	metricDetectionLatency = "governance_proposal_detection_latency_seconds"
+	metricAlertLatency     = "governance_alert_latency_seconds"
+	metricSLOBreaches      = "governance_alert_latency_slo_breaches_total"
+)
+
+func init() {
+	metrics.Register(metricDetectionLatency, "Time from voting start to the first observation of the last new proposal in voting period.", metrics.Gauge)
+	metrics.Register(metricAlertLatency, "Time from when the last alert of each type was due to its delivery.", metrics.Gauge)
+	metrics.Register(metricSLOBreaches, "Alerts delivered later than the configured latency SLO after they were due.", metrics.Counter)
+}
+
+// proposalKey identifies a proposal across networks
+func proposalKey(chainID string, proposalID uint64) string {
+	return store.ProposalKey(chainID, proposalID)
+}
+
+// observeProposal records when a proposal was first seen. Proposals already
+// in voting at the first check are excluded from latency measurement
+func (s *Service) observeProposal(proposal types.Proposal, networkConfig types.NetworkConfig, now time.Time) {
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+
+	s.mu.Lock()
+	_, seen := s.firstSeen[key]
+	if !seen {
+		s.firstSeen[key] = now
+		if s.cycles == 0 {
+			s.preexisting[key] = true
+		}
+	}
+	preexisting := s.preexisting[key]
+	s.mu.Unlock()
+
+	if seen || preexisting || proposal.VotingStart.IsZero() {
+		return
+	}
+
+	labels := metrics.Labels{"network": networkConfig.Name, "chain_id": networkConfig.ChainID}
+	metrics.Set(metricDetectionLatency, labels, now.Sub(proposal.VotingStart).Seconds())
+}
+
+// recordAlertDelivered measures how long after it was due an alert of
+// alertType was delivered and alerts the ops channel if it exceeds the
+// configured SLO. Alerts due before the proposal was submitted, such as a
+// reminder longer than the voting period, count as due at submission.
+// Proposals already on chain at the first check are not measured
+func (s *Service) recordAlertDelivered(proposal types.Proposal, networkConfig types.NetworkConfig, alertType string, due, now time.Time) {
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+
+	s.mu.Lock()
+	preexisting := s.preexisting[key]
+	s.mu.Unlock()
+
+	if preexisting || due.IsZero() {
+		return
+	}
+	if appeared := proposalAppeared(proposal); due.Before(appeared) {
+		due = appeared
+	}
+
+	latency := now.Sub(due)
+	if latency < 0 {
+		latency = 0
+	}
+	labels := metrics.Labels{"network": networkConfig.Name, "chain_id": networkConfig.ChainID, "alert_type": alertType}
+	metrics.Set(metricAlertLatency, labels, latency.Seconds())
+
+	slo := time.Duration(s.config.Alerts.LatencySLOMinutes) * time.Minute
+	if slo <= 0 || latency <= slo {
+		return
+	}
+
+	metrics.Add(metricSLOBreaches, labels, 1)
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("🐢 Alert Latency SLO Breach - %s", networkConfig.Name),
+		Content:     fmt.Sprintf("The %s alert for proposal #%d was delivered %s after it was due, above the %s SLO.", alertType, proposal.ID, latency.Round(time.Minute), slo),
+		Network:     networkConfig.Name,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleOps,
+	}
+	if err := s.notifier.SendNotification(msg); err != nil {
+		proposalLog(networkConfig, proposal.ID, eventAlertFailed).WithError(err).Warn("Failed to send latency SLO notification")
+	}
+}
+
+// proposalAppeared returns when a proposal appeared on chain: its submit
+// time, or its voting start on chains that do not report one
+func proposalAppeared(proposal types.Proposal) time.Time {
+	if !proposal.SubmitTime.IsZero() {
+		return proposal.SubmitTime
+	}
+	return proposal.VotingStart
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidLink is returned for tracking links missing their system or ID,
+// or with a URL that is not http(s)
+var ErrInvalidLink = errors.New("invalid link")
+
+// ErrUnknownLink is returned when removing a link a proposal does not have
+var ErrUnknownLink = errors.New("unknown link")
+
+// LinkProposal links a proposal to an artifact tracking it outside the
+// chain, so later alerts and reports point to it. Linking the same system
+// and ID again updates the link. The links of the proposal are returned
+func (s *Service) LinkProposal(networkName string, proposalID uint64, link types.TrackingLink) ([]types.TrackingLink, error) {
+	key, err := s.linkKey(networkName, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	link.System = strings.ToLower(strings.TrimSpace(link.System))
+	link.ID = strings.TrimSpace(link.ID)
+	if link.System == "" || link.ID == "" {
+		return nil, fmt.Errorf("%w: system and id are required", ErrInvalidLink)
+	}
+	if link.URL != "" {
+		if u, err := url.Parse(link.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: url must be an http or https URL, got %q", ErrInvalidLink, link.URL)
+		}
+	}
+	if link.Time.IsZero() {
+		link.Time = time.Now()
+	}
+
+	if err := s.store.AddLink(key, link); err != nil {
+		return nil, fmt.Errorf("failed to save link: %w", err)
+	}
+	keyLog(key, eventService).WithFields(logrus.Fields{"system": link.System, "id": link.ID, "user": link.Author}).Info("Proposal linked")
+	return s.store.Links(key), nil
+}
+
+// UnlinkProposal removes the link of a proposal to an artifact, returning
+// the remaining links
+func (s *Service) UnlinkProposal(networkName string, proposalID uint64, system, id string) ([]types.TrackingLink, error) {
+	key, err := s.linkKey(networkName, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := s.store.RemoveLink(key, strings.ToLower(strings.TrimSpace(system)), strings.TrimSpace(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save links: %w", err)
+	}
+	if !removed {
+		return nil, fmt.Errorf("%w %s %s", ErrUnknownLink, system, id)
+	}
+	return s.store.Links(key), nil
+}
+
+// Links returns the links of a proposal to the artifacts tracking it
+func (s *Service) Links(networkName string, proposalID uint64) ([]types.TrackingLink, error) {
+	key, err := s.linkKey(networkName, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	links := s.store.Links(key)
+	if links == nil {
+		links = []types.TrackingLink{}
+	}
+	return links, nil
+}
+
+// linkKey resolves a network name or alias and a proposal ID to the
+// proposal's state key
+func (s *Service) linkKey(networkName string, proposalID uint64) (string, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	_, networkConfig, ok := config.LookupNetwork(s.config, networkName)
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownNetwork, networkName)
+	}
+	return proposalKey(networkConfig.ChainID, proposalID), nil
+}
+
+// linkCommand handles /link <network> <id> <system> <artifact id> [url]
+func (s *Service) linkCommand(args []string, user string) string {
+	if len(args) < 4 {
+		return "Usage: /link <network> <proposal id> <system> <artifact id> [url]"
+	}
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("Invalid proposal ID %q", args[1])
+	}
+
+	link := types.TrackingLink{System: args[2], ID: args[3], Author: user}
+	if len(args) > 4 {
+		link.URL = args[4]
+	}
+	links, err := s.LinkProposal(args[0], proposalID, link)
+	if err != nil {
+		return fmt.Sprintf("Failed to link: %v", err)
+	}
+	return fmt.Sprintf("Links of %s #%d: %s", args[0], proposalID, linkList(links))
+}
+
+// unlinkCommand handles /unlink <network> <id> <system> <artifact id>
+func (s *Service) unlinkCommand(args []string, user string) string {
+	if len(args) < 4 {
+		return "Usage: /unlink <network> <proposal id> <system> <artifact id>"
+	}
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("Invalid proposal ID %q", args[1])
+	}
+
+	links, err := s.UnlinkProposal(args[0], proposalID, args[2], args[3])
+	if err != nil {
+		return fmt.Sprintf("Failed to unlink: %v", err)
+	}
+	if len(links) == 0 {
+		return fmt.Sprintf("No links left for %s #%d", args[0], proposalID)
+	}
+	return fmt.Sprintf("Links of %s #%d: %s", args[0], proposalID, linkList(links))
+}
+
+// linksText returns the tracking links of a proposal for alerts, or "" if
+// there are none
+func (s *Service) linksText(key string) string {
+	links := s.store.Links(key)
+	if len(links) == 0 {
+		return ""
+	}
+	return "\n\nTracked in: " + linkList(links)
+}
+
+// linkList lists links on one line as "system id (url)"
+func linkList(links []types.TrackingLink) string {
+	parts := make([]string, 0, len(links))
+	for _, link := range links {
+		part := link.System + " " + link.ID
+		if link.URL != "" {
+			part += " (" + link.URL + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Events of structured log entries, in the event field
+const (
+	eventCheck        = "check"
+	eventProposal     = "proposal"
+	eventAlertSent    = "alert_sent"
+	eventAlertHeld    = "alert_held"
+	eventAlertSkipped = "alert_skipped"
+	eventAlertFailed  = "alert_failed"
+	eventFetchFailed  = "fetch_failed"
+	eventStateFailed  = "state_failed"
+	eventIncident     = "incident"
+	eventChannel      = "channel"
+	eventService      = "service"
+)
+
+// eventLog returns a log entry for an event
+func eventLog(event string) *logrus.Entry {
+	return logrus.WithField("event", event)
+}
+
+// networkLog returns a log entry for an event on a network
+func networkLog(networkConfig types.NetworkConfig, event string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		"network":  networkConfig.Name,
+		"chain_id": networkConfig.ChainID,
+		"event":    event,
+	})
+}
+
+// proposalLog returns a log entry for an event on a proposal
+func proposalLog(networkConfig types.NetworkConfig, proposalID uint64, event string) *logrus.Entry {
+	return networkLog(networkConfig, event).WithField("proposal_id", proposalID)
+}
+
+// keyLog returns a log entry for an event on a proposal known only by its
+// state key
+func keyLog(key, event string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{"proposal": key, "event": event})
+}
+
+// alertLog returns entry with the event and alert type of an alert decision
+func alertLog(entry *logrus.Entry, event, alertType string) *logrus.Entry {
+	return entry.WithFields(logrus.Fields{"event": event, "alert_type": alertType})
+}
+
+// proposalAlertLog returns a log entry for an event of a proposal alert
+func proposalAlertLog(networkConfig types.NetworkConfig, proposalID uint64, event, alertType string) *logrus.Entry {
+	return proposalLog(networkConfig, proposalID, event).WithField("alert_type", alertType)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// lookupCacheTTL is how long the replies of chain lookups by bot commands
+// are reused, so a busy chat does not hammer the endpoints
+const lookupCacheTTL = time.Minute
+
+// lookupCache holds the recent replies of chain lookups by key
+type lookupCache struct {
+	mu      sync.Mutex
+	replies map[string]cachedLookup
+}
+
+// cachedLookup is a lookup reply and when it was queried
+type cachedLookup struct {
+	reply string
+	at    time.Time
+}
+
+// get returns the reply of key and when it was queried, querying it with
+// fetch unless a recent one is cached. Failed lookups are not cached
+func (c *lookupCache) get(key string, now time.Time, fetch func() (string, bool)) (string, time.Time, bool) {
+	c.mu.Lock()
+	cached, ok := c.replies[key]
+	c.mu.Unlock()
+	if ok && now.Sub(cached.at) < lookupCacheTTL {
+		return cached.reply, cached.at, true
+	}
+
+	reply, ok := fetch()
+	if !ok {
+		return reply, now, false
+	}
+	c.mu.Lock()
+	if c.replies == nil {
+		c.replies = make(map[string]cachedLookup)
+	}
+	for k, v := range c.replies {
+		if now.Sub(v.at) >= lookupCacheTTL {
+			delete(c.replies, k)
+		}
+	}
+	c.replies[key] = cachedLookup{reply: reply, at: now}
+	c.mu.Unlock()
+	return reply, now, true
+}
+
+// lookupTarget resolves the "<network> <id>" arguments of a lookup
+// command, returning the reply explaining why they do not resolve if so
+func (s *Service) lookupTarget(args []string) (types.NetworkConfig, *governance.Client, uint64, string) {
+	s.configMu.RLock()
+	name, networkConfig, ok := config.LookupNetwork(s.config, args[0])
+	client := s.clients[name]
+	s.configMu.RUnlock()
+	if !ok || client == nil {
+		return networkConfig, nil, 0, fmt.Sprintf("Unknown network %q", args[0])
+	}
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return networkConfig, nil, 0, fmt.Sprintf("Invalid proposal ID %q", args[1])
+	}
+	return networkConfig, client, proposalID, ""
+}
+
+// lookupReply runs a cached lookup, fetch returning its reply and whether
+// it succeeded, and appends when the data of successful ones was queried
+func (s *Service) lookupReply(key string, fetch func(ctx context.Context) (string, bool)) string {
+	reply, at, ok := s.lookups.get(key, time.Now(), func() (string, bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), botCommandTimeout)
+		defer cancel()
+		return fetch(ctx)
+	})
+	if !ok {
+		return reply
+	}
+	return fmt.Sprintf("%s\n\nAs of %s", reply, at.UTC().Format("15:04:05 UTC"))
+}
+
+// tallyCommand handles /tally <network> <id>, showing the current tally of
+// a proposal with turnout against quorum
+func (s *Service) tallyCommand(args []string, user string) string {
+	if len(args) < 2 {
+		return "Usage: /tally <network> <proposal id>"
+	}
+	networkConfig, client, proposalID, problem := s.lookupTarget(args)
+	if problem != "" {
+		return problem
+	}
+
+	key := fmt.Sprintf("tally:%s:%d", networkConfig.ChainID, proposalID)
+	return s.lookupReply(key, func(ctx context.Context) (string, bool) {
+		proposal, err := client.GetProposalDetails(ctx, proposalID)
+		if errors.Is(err, governance.ErrNotFound) {
+			return fmt.Sprintf("Proposal %d not found on %s", proposalID, networkConfig.Name), false
+		}
+		if err != nil {
+			return fmt.Sprintf("Failed to fetch proposal %d: %v", proposalID, err), false
+		}
+
+		tally := proposal.FinalTally
+		live := proposal.Status == string(cosmosgov.StatusVotingPeriod)
+		if live || tally == nil {
+			if tally, err = client.GetTally(ctx, proposalID); err != nil {
+				return fmt.Sprintf("Failed to fetch tally of proposal %d: %v", proposalID, err), false
+			}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "📊 %s #%d: %s\n", networkConfig.Name, proposal.ID, proposal.Title)
+		fmt.Fprintf(&b, "\nStatus: %s", governance.StatusLabel(proposal.Status))
+		if live {
+			fmt.Fprintf(&b, ", voting ends %s", amounts.In(time.Until(proposal.VotingEnd)))
+		}
+		b.WriteString("\n")
+		total := tallyTotal(tally)
+		for _, option := range governance.TallyOptions(networkConfig, tally) {
+			share := 0.0
+			if total > 0 {
+				share = parseAmount(option.Amount) / total * 100
+			}
+			fmt.Fprintf(&b, "\n%s: %s (%.2f%%)", option.Label, amounts.Amount(option.Amount, networkConfig.Denom), share)
+		}
+		if live {
+			b.WriteString(s.tallyOutlook(ctx, client, networkConfig, tally))
+		}
+		return b.String(), true
+	})
+}
+
+// votesCommand handles /votes <network> <id> [address], showing how many
+// votes a proposal got and how the tracked voters, or the given validator
+// or account, voted
+func (s *Service) votesCommand(args []string, user string) string {
+	if len(args) < 2 {
+		return "Usage: /votes <network> <proposal id> [validator or account address]"
+	}
+	networkConfig, client, proposalID, problem := s.lookupTarget(args)
+	if problem != "" {
+		return problem
+	}
+
+	voters := voterAddresses(networkConfig)
+	if len(args) > 2 {
+		voter := args[2]
+		// Validators vote with the account of their operator address
+		if account, err := cosmosgov.OperatorAccount(voter); err == nil {
+			voter = account
+		}
+		voters = []string{voter}
+	}
+
+	key := fmt.Sprintf("votes:%s:%d:%s", networkConfig.ChainID, proposalID, strings.Join(voters, ","))
+	return s.lookupReply(key, func(ctx context.Context) (string, bool) {
+		count, err := client.GetVoteCount(ctx, proposalID)
+		if err != nil {
+			return fmt.Sprintf("Failed to fetch votes of proposal %d: %v", proposalID, err), false
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "🗳️ %s #%d: %d votes cast", networkConfig.Name, proposalID, count)
+		for _, voter := range voters {
+			vote, err := client.GetVote(ctx, proposalID, voter)
+			switch {
+			case err != nil:
+				fmt.Fprintf(&b, "\n• %s: failed to fetch vote: %v", voter, err)
+			case vote == nil:
+				fmt.Fprintf(&b, "\n• %s: has not voted", voter)
+			default:
+				fmt.Fprintf(&b, "\n• %s: %s", voter, formatVote(networkConfig, *vote))
+			}
+		}
+		return b.String(), true
+	})
+}
+
+// formatVote describes the options of a vote, with their weights when the
+// vote is split
+func formatVote(networkConfig types.NetworkConfig, vote types.Vote) string {
+	options := make([]string, 0, len(vote.Options))
+	for _, option := range vote.Options {
+		label := governance.VoteLabel(networkConfig, strings.ToLower(strings.TrimPrefix(option.Option, "VOTE_OPTION_")))
+		if label == "" {
+			label = option.Option
+		}
+		if len(vote.Options) > 1 {
+			label += fmt.Sprintf(" %.0f%%", parseAmount(option.Weight)*100)
+		}
+		options = append(options, label)
+	}
+	return strings.Join(options, ", ")
+}
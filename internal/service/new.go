@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/store"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// votingProposals returns the proposals in voting period
+func votingProposals(proposals []types.Proposal) []types.Proposal {
+	voting := make([]types.Proposal, 0, len(proposals))
+	for _, proposal := range proposals {
+		if proposal.Status == string(cosmosgov.StatusVotingPeriod) {
+			voting = append(voting, proposal)
+		}
+	}
+	return voting
+}
+
+// checkNewProposals alerts once about every proposal not seen before. On
+// the first successful check of a chain with new proposal alerts, at the
+// first start or once a network is added, proposals already on chain are
+// recorded silently instead of flooding the channels. Proposals only
+// funded by spam depositors are skipped if configured. A proposal short of
+// the deposit community channels require is announced to audit channels
+// only, and to everyone once its deposit gets there
+func (s *Service) checkNewProposals(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposals []types.Proposal) {
+	if !alertEnabled(networkConfig, types.AlertNewProposal) {
+		return
+	}
+
+	now := s.now(client)
+	baseline := !s.store.Baselined(networkConfig.ChainID, store.BaselineNewProposal)
+	if baseline {
+		defer func() {
+			if err := s.store.MarkBaselined(networkConfig.ChainID, store.BaselineNewProposal, now); err != nil {
+				networkLog(networkConfig, eventStateFailed).WithError(err).Warn("Failed to record new proposal baseline")
+			}
+		}()
+		networkLog(networkConfig, eventAlertSkipped).WithField("proposals", len(proposals)).Info("First check: proposals already on chain recorded without new proposal alerts")
+	}
+
+	for _, proposal := range proposals {
+		key := proposalKey(networkConfig.ChainID, proposal.ID)
+		if s.store.WasSent(key, types.AlertNewProposal) {
+			continue
+		}
+		if baseline {
+			s.markSent(key, types.AlertNewProposal)
+			continue
+		}
+
+		deadline := proposal.VotingEnd
+		if proposal.Status == string(cosmosgov.StatusDepositPeriod) {
+			deadline = proposal.DepositEnd
+		}
+		if s.holdForQuietHours(now, deadline) {
+			proposalAlertLog(networkConfig, proposal.ID, eventAlertHeld, types.AlertNewProposal).Info("New proposal notification held for quiet hours")
+			continue
+		}
+
+		funded := s.communityFunded(ctx, client, networkConfig, proposal)
+		if !funded && s.store.WasSent(key, types.AlertNewProposal+internalOnly) {
+			continue
+		}
+
+		deposits := s.fetchDeposits(ctx, client, networkConfig, proposal.ID)
+		if s.fromSpamDepositors(deposits) {
+			proposalAlertLog(networkConfig, proposal.ID, eventAlertSkipped, types.AlertNewProposal).Info("New proposal skipped, only funded by spam depositors")
+			s.markSent(key, types.AlertNewProposal)
+			continue
+		}
+
+		role := types.RoleCommunity
+		if !funded {
+			role = types.RoleAudit
+		}
+		if err := s.sendNewProposalNotification(ctx, client, proposal, networkConfig, key, deposits, role); err != nil {
+			proposalAlertLog(networkConfig, proposal.ID, eventAlertFailed, types.AlertNewProposal).WithError(err).Error("Failed to send new proposal notification")
+			continue
+		}
+		if !funded {
+			proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertNewProposal).Info("Sent new proposal notification to audit channels, deposit below community_min_deposit_percent")
+			s.markSent(key, types.AlertNewProposal+internalOnly)
+			continue
+		}
+		proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertNewProposal).Info("Sent new proposal notification")
+		s.markSent(key, types.AlertNewProposal)
+	}
+}
+
+// sendNewProposalNotification announces a proposal that just appeared along
+// with who funded it to the channels of a role
+func (s *Service) sendNewProposalNotification(ctx context.Context, client *governance.Client, proposal types.Proposal, networkConfig types.NetworkConfig, key string, deposits []types.Deposit, role string) error {
+	stage := fmt.Sprintf("Voting is open until %s.", proposal.VotingEnd.Format("2006-01-02 15:04 MST"))
+	if proposal.Status == string(cosmosgov.StatusDepositPeriod) {
+		stage = fmt.Sprintf("It is in deposit period until %s.", proposal.DepositEnd.Format("2006-01-02 15:04 MST"))
+	}
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("🆕 New Governance Proposal - %s", proposal.Network),
+		Content:     fmt.Sprintf("Proposal \"%s\" was submitted. %s\n\n%s%sDescription: %s%s", proposal.Title, stage, s.depositText(ctx, client, networkConfig, proposal, deposits), typeLine(proposal), s.alertDescription(proposal), s.notesText(key)),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        role,
+		AlertType:   types.AlertNewProposal,
+		Severity:    s.alertSeverity(networkConfig, proposal, types.AlertNewProposal),
+		Details:     s.alertDetails(proposal, networkConfig.Name),
+		Proposal:    &proposal,
+	}
+
+	if err := s.sendProposalAlert(msg); err != nil {
+		return fmt.Errorf("failed to send new proposal notification: %w", err)
+	}
+	s.recordAlertDelivered(proposal, networkConfig, types.AlertNewProposal, proposalAppeared(proposal), time.Now())
+	return nil
+}
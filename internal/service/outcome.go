@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// checkOutcomes sends the result of proposals that were in voting period
+// at the previous check and have left it since
+func (s *Service) checkOutcomes(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, voting []types.Proposal) {
+	current := make(map[uint64]bool, len(voting))
+	for _, proposal := range voting {
+		current[proposal.ID] = true
+	}
+
+	s.mu.Lock()
+	previous := s.votingLast[networkConfig.ChainID]
+	s.votingLast[networkConfig.ChainID] = current
+	s.mu.Unlock()
+
+	for proposalID := range previous {
+		if current[proposalID] {
+			continue
+		}
+		s.resolveIncidents(networkConfig, proposalID)
+		s.forgetWhaleVotes(networkConfig.ChainID, proposalID)
+
+		if !alertEnabled(networkConfig, types.AlertOutcome) {
+			s.checkFinishedProposal(ctx, client, networkConfig, proposalID)
+			s.forgetParamsChanges(networkConfig.ChainID, proposalID)
+			s.forgetBondedShifts(networkConfig.ChainID, proposalID)
+			continue
+		}
+
+		key := proposalKey(networkConfig.ChainID, proposalID)
+		if s.store.WasSent(key, types.AlertOutcome) {
+			continue
+		}
+
+		if err := s.sendOutcome(ctx, client, networkConfig, proposalID); err != nil {
+			proposalAlertLog(networkConfig, proposalID, eventAlertFailed, types.AlertOutcome).WithError(err).Error("Failed to send outcome")
+			// Retry at the next check
+			s.mu.Lock()
+			s.votingLast[networkConfig.ChainID][proposalID] = true
+			s.mu.Unlock()
+			continue
+		}
+		s.markSent(key, types.AlertOutcome)
+		s.forgetParamsChanges(networkConfig.ChainID, proposalID)
+		s.forgetBondedShifts(networkConfig.ChainID, proposalID)
+	}
+}
+
+// sendOutcome sends the final status and tally of a proposal
+func (s *Service) sendOutcome(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposalID uint64) error {
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	if errors.Is(err, governance.ErrNotFound) {
+		// Cancelled proposals are handled by retractions
+		s.settleDepositors(networkConfig, proposalID, nil)
+		s.recordRemoved(networkConfig, proposalID, time.Now())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.settleDepositors(networkConfig, proposalID, proposal)
+	s.recordStatus(proposalKey(networkConfig.ChainID, proposalID), proposal.Status, time.Now())
+
+	var title string
+	switch cosmosgov.ProposalStatus(proposal.Status) {
+	case cosmosgov.StatusPassed:
+		title = "✅ Proposal Passed"
+	case cosmosgov.StatusRejected:
+		title = "❌ Proposal Rejected"
+	case cosmosgov.StatusFailed:
+		title = "⚠️ Proposal Failed"
+	default:
+		return fmt.Errorf("proposal %d left voting period with status %s", proposalID, proposal.Status)
+	}
+
+	tally := finalTally(ctx, client, *proposal)
+	s.recordTally(networkConfig, proposalID, tally, time.Now())
+	s.recordParticipation(ctx, client, networkConfig, *proposal, tally)
+
+	content := fmt.Sprintf("Proposal \"%s\" %s.", proposal.Title, governance.StatusLabel(proposal.Status))
+	if tally != nil {
+		content += "\n\nFinal tally:\n" + formatTallyShares(networkConfig, tally)
+		if params := s.govParams(ctx, client, networkConfig); params != nil {
+			content += "\n" + formatThresholds(params)
+		}
+	}
+	content += s.paramsNote(networkConfig.ChainID, proposalID)
+	content += s.bondedNote(networkConfig.ChainID, proposalID, tally)
+
+	// Later alerts are judged against the new params
+	if cosmosgov.ProposalStatus(proposal.Status) == cosmosgov.StatusPassed && changesGovParams(*proposal) {
+		s.refreshGovParams(ctx, client, networkConfig, *proposal)
+	}
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("%s - %s", title, proposal.Network),
+		Content:     content + s.notesText(proposalKey(networkConfig.ChainID, proposalID)),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleCommunity,
+		AlertType:   types.AlertOutcome,
+		Severity:    s.alertSeverity(networkConfig, *proposal, types.AlertOutcome),
+		Chart:       s.tallyChart(networkConfig, *proposal, tally),
+		Proposal:    proposal,
+	}
+
+	if err := s.sendProposalAlert(msg); err != nil {
+		return fmt.Errorf("failed to send outcome notification: %w", err)
+	}
+	proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertOutcome).WithField("status", governance.StatusLabel(proposal.Status)).Info("Sent outcome")
+	return nil
+}
+
+// finalTally returns the final tally of a finished proposal. Some chains
+// only fill it later, the tally endpoint is used meanwhile
+func finalTally(ctx context.Context, client *governance.Client, proposal types.Proposal) *types.TallyResult {
+	tally := proposal.FinalTally
+	if tally == nil || tallyTotal(tally) == 0 {
+		if live, err := client.GetTally(ctx, proposal.ID); err == nil {
+			tally = live
+		}
+	}
+	return tally
+}
+
+// formatTallyShares formats the share of each vote option valid on the
+// network, named as the chain names them
+func formatTallyShares(networkConfig types.NetworkConfig, tally *types.TallyResult) string {
+	total := tallyTotal(tally)
+	options := governance.TallyOptions(networkConfig, tally)
+	shares := make([]string, 0, len(options))
+	for _, option := range options {
+		share := 0.0
+		if total > 0 {
+			share = parseAmount(option.Amount) / total * 100
+		}
+		shares = append(shares, fmt.Sprintf("%s %.2f%%", option.Label, share))
+	}
+	return strings.Join(shares, " · ")
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/notifications"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// pagerDutyPrefix prefixes the store alert types of PagerDuty incidents,
+// resolved incidents additionally end in pagerDutyResolved
+const (
+	pagerDutyPrefix   = "pagerduty:"
+	pagerDutyResolved = ":resolved"
+)
+
+// checkPagerDuty opens PagerDuty incidents for a proposal in voting period
+// that matches the configured criteria, and resolves unvoted incidents once
+// the voter has voted
+func (s *Service) checkPagerDuty(ctx context.Context, client *governance.Client, proposal types.Proposal, networkConfig types.NetworkConfig, now time.Time) {
+	if !s.notifier.PagerDutyEnabled() {
+		return
+	}
+	config := s.config.Notifications.PagerDuty
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	severity := s.alertSeverity(networkConfig, proposal, types.AlertVotingEnd)
+
+	if reason := s.pageReason(networkConfig, proposal, severity); reason != "" && !s.store.WasSent(key, pagerDutyPrefix+"proposal") {
+		s.triggerIncident(key, "proposal", notifications.Incident{
+			Summary:  fmt.Sprintf("%s proposal #%d %s: %s", networkConfig.Name, proposal.ID, reason, proposal.Title),
+			Source:   networkConfig.ChainID,
+			Severity: severity,
+			Link:     s.explorerURL(networkConfig, proposal.ID),
+			Details: map[string]interface{}{
+				"network":     networkConfig.Name,
+				"proposal_id": proposal.ID,
+				"type":        cosmosgov.TypeName(proposal.Type),
+				"voting_end":  proposal.VotingEnd.Format(time.RFC3339),
+			},
+		})
+	}
+
+	window := time.Duration(config.UnvotedHoursBeforeEnd) * time.Hour
+	if window <= 0 || proposal.VotingStart.After(now) || proposal.VotingEnd.Sub(now) > window {
+		return
+	}
+	for _, voter := range voterAddresses(networkConfig) {
+		alertType := pagerDutyPrefix + "unvoted:" + voter
+		open := s.store.WasSent(key, alertType)
+		if open && s.store.WasSent(key, alertType+pagerDutyResolved) {
+			continue
+		}
+
+		voted, err := client.HasVoted(ctx, proposal.ID, voter)
+		if err != nil {
+			proposalLog(networkConfig, proposal.ID, eventFetchFailed).WithField("voter", voter).WithError(err).Error("Failed to check vote")
+			continue
+		}
+		switch {
+		case voted && open:
+			s.resolveIncident(key, alertType)
+		case !voted && !open:
+			s.triggerIncident(key, strings.TrimPrefix(alertType, pagerDutyPrefix), notifications.Incident{
+				Summary:  fmt.Sprintf("%s has not voted on %s proposal #%d, voting ends %s", voter, networkConfig.Name, proposal.ID, amounts.In(proposal.VotingEnd.Sub(now))),
+				Source:   networkConfig.ChainID,
+				Severity: types.SeverityCritical,
+				Link:     s.explorerURL(networkConfig, proposal.ID),
+				Details: map[string]interface{}{
+					"network":     networkConfig.Name,
+					"proposal_id": proposal.ID,
+					"voter":       voter,
+					"voting_end":  proposal.VotingEnd.Format(time.RFC3339),
+				},
+			})
+		}
+	}
+}
+
+// pageReason describes why a proposal opens an incident, empty if it does
+// not match the type or severity criteria nor a routing rule
+func (s *Service) pageReason(networkConfig types.NetworkConfig, proposal types.Proposal, severity string) string {
+	config := s.config.Notifications.PagerDuty
+	if s.notifier.RoutesToPagerDuty(networkConfig.ChainID, proposal) {
+		return "matches a routing rule"
+	}
+	if len(config.Types) > 0 && governance.MatchesProposalType(config.Types, proposal) {
+		return "is a " + cosmosgov.TypeName(proposal.Type)
+	}
+	if config.MinSeverity != "" && severity != "" && severityIndex(severity) >= severityIndex(config.MinSeverity) {
+		return "is " + severity
+	}
+	return ""
+}
+
+// severityIndex orders severities from least to most severe
+func severityIndex(severity string) int {
+	for i, s := range types.Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// triggerIncident opens the incident named name of a proposal, recording
+// it so it is opened once and resolved later
+func (s *Service) triggerIncident(key, name string, incident notifications.Incident) {
+	incident.DedupKey = incidentDedupKey(key, name)
+	if err := s.notifier.TriggerIncident(incident); err != nil {
+		keyLog(key, eventIncident).WithField("dedup_key", incident.DedupKey).WithError(err).Error("Failed to open PagerDuty incident")
+		return
+	}
+	keyLog(key, eventIncident).WithField("dedup_key", incident.DedupKey).Info("Opened PagerDuty incident")
+	s.markSent(key, pagerDutyPrefix+name)
+}
+
+// resolveIncident resolves a recorded incident of a proposal
+func (s *Service) resolveIncident(key, alertType string) {
+	dedupKey := incidentDedupKey(key, strings.TrimPrefix(alertType, pagerDutyPrefix))
+	if err := s.notifier.ResolveIncident(dedupKey); err != nil {
+		keyLog(key, eventIncident).WithField("dedup_key", dedupKey).WithError(err).Error("Failed to resolve PagerDuty incident")
+		return
+	}
+	keyLog(key, eventIncident).WithField("dedup_key", dedupKey).Info("Resolved PagerDuty incident")
+	s.markSent(key, alertType+pagerDutyResolved)
+}
+
+// resolveIncidents resolves every open incident of a proposal that left
+// voting period
+func (s *Service) resolveIncidents(networkConfig types.NetworkConfig, proposalID uint64) {
+	if !s.notifier.PagerDutyEnabled() {
+		return
+	}
+	key := proposalKey(networkConfig.ChainID, proposalID)
+	for _, alertType := range s.store.SentAlerts(key) {
+		if !strings.HasPrefix(alertType, pagerDutyPrefix) || strings.HasSuffix(alertType, pagerDutyResolved) {
+			continue
+		}
+		if !s.store.WasSent(key, alertType+pagerDutyResolved) {
+			s.resolveIncident(key, alertType)
+		}
+	}
+}
+
+// incidentDedupKey is the PagerDuty dedup key of an incident of a proposal
+func incidentDedupKey(key, name string) string {
+	return "governance-alerts/" + key + "/" + name
+}
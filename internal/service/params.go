@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// paramsMessageTypes are the message types of proposals that can change the
+// gov module parameters
+var paramsMessageTypes = []string{
+	"/cosmos.gov.v1.MsgUpdateParams",
+	"/cosmos.params.v1beta1.ParameterChangeProposal",
+}
+
+// paramsChange is a change of the gov params by a passed proposal, noted
+// in the tally alerts of the proposals that were in voting period then
+type paramsChange struct {
+	proposalID uint64
+	changes    []string
+	affected   map[uint64]bool
+}
+
+// govParams returns the cached gov params of a network, fetching them on
+// first use. Returns nil if they cannot be fetched
+func (s *Service) govParams(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig) *types.GovParams {
+	s.mu.Lock()
+	params := s.params[networkConfig.ChainID]
+	s.mu.Unlock()
+	if params != nil {
+		return params
+	}
+
+	params, err := client.GetGovParams(ctx)
+	if err != nil {
+		networkLog(networkConfig, eventFetchFailed).WithError(err).Warn("Failed to fetch gov params")
+		return nil
+	}
+
+	s.mu.Lock()
+	s.params[networkConfig.ChainID] = params
+	s.mu.Unlock()
+	return params
+}
+
+// changesGovParams reports whether a proposal carries a message that can
+// change the gov params
+func changesGovParams(proposal types.Proposal) bool {
+	for _, messageType := range proposal.MessageTypes {
+		for _, paramsType := range paramsMessageTypes {
+			if messageType == paramsType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkFinishedProposal refreshes the gov params and settles the depositors
+// after a proposal left voting period when outcome alerts, which do this
+// otherwise, are disabled
+func (s *Service) checkFinishedProposal(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposalID uint64) {
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	if errors.Is(err, governance.ErrNotFound) {
+		s.settleDepositors(networkConfig, proposalID, nil)
+		s.recordRemoved(networkConfig, proposalID, time.Now())
+		return
+	}
+	if err != nil {
+		proposalLog(networkConfig, proposalID, eventFetchFailed).WithError(err).Warn("Failed to fetch proposal")
+		return
+	}
+	s.settleDepositors(networkConfig, proposalID, proposal)
+	s.recordStatus(proposalKey(networkConfig.ChainID, proposalID), proposal.Status, time.Now())
+	switch cosmosgov.ProposalStatus(proposal.Status) {
+	case cosmosgov.StatusPassed, cosmosgov.StatusRejected, cosmosgov.StatusFailed:
+		s.recordParticipation(ctx, client, networkConfig, *proposal, finalTally(ctx, client, *proposal))
+	}
+	if cosmosgov.ProposalStatus(proposal.Status) == cosmosgov.StatusPassed && changesGovParams(*proposal) {
+		s.refreshGovParams(ctx, client, networkConfig, *proposal)
+	}
+}
+
+// refreshGovParams re-fetches the gov params of a network after proposal
+// passed and, if they changed, tells the ops channels and notes the change
+// in later tally alerts of the proposals still in voting period
+func (s *Service) refreshGovParams(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposal types.Proposal) {
+	params, err := client.GetGovParams(ctx)
+	if err != nil {
+		proposalLog(networkConfig, proposal.ID, eventFetchFailed).WithError(err).Warn("Failed to refresh gov params")
+		s.mu.Lock()
+		delete(s.params, networkConfig.ChainID)
+		s.mu.Unlock()
+		return
+	}
+
+	s.mu.Lock()
+	previous := s.params[networkConfig.ChainID]
+	s.params[networkConfig.ChainID] = params
+	s.mu.Unlock()
+
+	if previous == nil {
+		return
+	}
+	changes := diffGovParams(previous, params)
+	if len(changes) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	affected := make(map[uint64]bool, len(s.votingLast[networkConfig.ChainID]))
+	for id := range s.votingLast[networkConfig.ChainID] {
+		affected[id] = true
+	}
+	if len(affected) > 0 {
+		s.paramsChanges[networkConfig.ChainID] = append(s.paramsChanges[networkConfig.ChainID], paramsChange{
+			proposalID: proposal.ID,
+			changes:    changes,
+			affected:   affected,
+		})
+	}
+	s.mu.Unlock()
+
+	content := fmt.Sprintf("Proposal #%d changed the gov parameters:\n• %s", proposal.ID, strings.Join(changes, "\n• "))
+	if len(affected) > 0 {
+		content += fmt.Sprintf("\n\n%d proposals in voting period keep their voting end time; the new quorum and thresholds apply when they are tallied.", len(affected))
+	}
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("⚙️ Gov Params Changed - %s", networkConfig.Name),
+		Content:     content,
+		Network:     networkConfig.Name,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleOps,
+	}
+	if err := s.notifier.SendNotification(msg); err != nil {
+		proposalLog(networkConfig, proposal.ID, eventAlertFailed).WithError(err).Warn("Failed to send gov params change notification")
+	}
+}
+
+// diffGovParams describes the parameters that differ between two sets
+func diffGovParams(previous, current *types.GovParams) []string {
+	var changes []string
+	if previous.VotingPeriod != current.VotingPeriod {
+		changes = append(changes, fmt.Sprintf("Voting period: %s → %s", amounts.Duration(previous.VotingPeriod), amounts.Duration(current.VotingPeriod)))
+	}
+	if previous.MaxDepositPeriod != current.MaxDepositPeriod {
+		changes = append(changes, fmt.Sprintf("Max deposit period: %s → %s", amounts.Duration(previous.MaxDepositPeriod), amounts.Duration(current.MaxDepositPeriod)))
+	}
+	for _, param := range []struct{ name, previous, current string }{
+		{"Quorum", previous.Quorum, current.Quorum},
+		{"Threshold", previous.Threshold, current.Threshold},
+		{"Veto threshold", previous.VetoThreshold, current.VetoThreshold},
+	} {
+		if parseAmount(param.previous) != parseAmount(param.current) {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", param.name, formatFraction(param.previous), formatFraction(param.current)))
+		}
+	}
+	return changes
+}
+
+// paramsNote notes the gov params changes that happened while a proposal
+// was in voting period, empty if none did
+func (s *Service) paramsNote(chainID string, proposalID uint64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var note string
+	for _, change := range s.paramsChanges[chainID] {
+		if change.affected[proposalID] {
+			note += fmt.Sprintf("\n\nℹ️ Gov params changed by proposal #%d during voting: %s", change.proposalID, strings.Join(change.changes, ", "))
+		}
+	}
+	return note
+}
+
+// forgetParamsChanges drops a proposal from the noted params changes once
+// its outcome is known
+func (s *Service) forgetParamsChanges(chainID string, proposalID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes := s.paramsChanges[chainID][:0]
+	for _, change := range s.paramsChanges[chainID] {
+		delete(change.affected, proposalID)
+		if len(change.affected) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	s.paramsChanges[chainID] = changes
+}
+
+// formatThresholds formats the quorum and thresholds a tally is judged by
+func formatThresholds(params *types.GovParams) string {
+	return fmt.Sprintf("Quorum %s · Threshold %s · Veto %s",
+		formatFraction(params.Quorum), formatFraction(params.Threshold), formatFraction(params.VetoThreshold))
+}
+
+// formatFraction formats a decimal fraction as a percentage
+func formatFraction(fraction string) string {
+	return fmt.Sprintf("%.2f%%", parseAmount(fraction)*100)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/report"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// recordParticipation records the turnout of a finished proposal against
+// the bonded stake at the time, for the participation reports
+func (s *Service) recordParticipation(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposal types.Proposal, tally *types.TallyResult) {
+	if tally == nil {
+		return
+	}
+	pool, err := client.GetStakingPool(ctx)
+	if err != nil {
+		proposalLog(networkConfig, proposal.ID, eventFetchFailed).WithError(err).Warn("Failed to fetch bonded stake for participation")
+		return
+	}
+
+	snapshot := types.ParticipationSnapshot{
+		ProposalID: proposal.ID,
+		Title:      proposal.Title,
+		VotingEnd:  proposal.VotingEnd,
+		Voted:      report.TallyTotal(tally),
+		Bonded:     pool.BondedTokens,
+	}
+	if err := s.store.AddParticipation(networkConfig.ChainID, snapshot); err != nil {
+		proposalLog(networkConfig, proposal.ID, eventStateFailed).WithError(err).Warn("Failed to record participation")
+	}
+}
+
+// ParticipationReport returns the participation in the latest proposals of
+// every network, recorded as their voting ended
+func (s *Service) ParticipationReport(now time.Time) *report.ParticipationReport {
+	limit := s.config.Reports.ParticipationProposals
+	r := &report.ParticipationReport{Time: now, Proposals: limit}
+	for _, networkConfig := range s.config.Networks {
+		r.Networks = append(r.Networks, report.NewNetworkParticipation(networkConfig, s.store.Participation(networkConfig.ChainID), limit))
+	}
+	sort.Slice(r.Networks, func(i, j int) bool { return r.Networks[i].Network < r.Networks[j].Network })
+	return r
+}
+
+// maybeSendParticipationReport posts the participation digest to the ops
+// channels once the configured period has elapsed, then starts a new
+// period
+func (s *Service) maybeSendParticipationReport(now time.Time) {
+	period := reliabilityPeriod(s.config.Reports.Participation)
+	if period == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	due := now.Sub(s.participationSince) >= period
+	if due {
+		s.participationSince = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("🗳️ Governance Participation Report (%s)", s.config.Reports.Participation),
+		Content:     s.ParticipationReport(now).Summary(),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleOps,
+	}
+	if err := s.notifier.SendNotification(msg); err != nil {
+		eventLog(eventAlertFailed).WithError(err).Warn("Failed to send participation report")
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// heartbeatTimeout bounds each heartbeat ping
+const heartbeatTimeout = 10 * time.Second
+
+// heartbeatClient sends the heartbeat pings
+var heartbeatClient = &http.Client{Timeout: heartbeatTimeout}
+
+// pingHeartbeats pings the heartbeat URLs after a check cycle that started
+// at started: the service's when every network was checked and each
+// network's when that network was. A missing ping makes the dead man's
+// switch service page, so nothing is pinged in dry run
+func (s *Service) pingHeartbeats(ctx context.Context, started time.Time, checkErr error) {
+	if s.config.DryRun || ctx.Err() != nil {
+		return
+	}
+
+	urls := make(map[string]string)
+	if checkErr == nil && s.config.Health.HeartbeatURL != "" {
+		urls["service"] = s.config.Health.HeartbeatURL
+	}
+	s.mu.Lock()
+	for name, networkConfig := range s.config.Networks {
+		if networkConfig.HeartbeatURL == "" {
+			continue
+		}
+		if failure, ok := s.checkFailures[name]; ok && !failure.at.Before(started) {
+			continue
+		}
+		urls[name] = networkConfig.HeartbeatURL
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for name, heartbeatURL := range urls {
+		wg.Add(1)
+		go func(name, heartbeatURL string) {
+			defer wg.Done()
+			if err := pingHeartbeat(ctx, heartbeatURL); err != nil {
+				eventLog(eventCheck).WithField("heartbeat", name).WithError(err).Warn("Failed to ping heartbeat")
+				return
+			}
+			eventLog(eventCheck).WithField("heartbeat", name).Debug("Pinged heartbeat")
+		}(name, heartbeatURL)
+	}
+	wg.Wait()
+}
+
+// pingHeartbeat sends a GET request to a heartbeat URL
+func pingHeartbeat(ctx context.Context, heartbeatURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, heartbeatURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := heartbeatClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// ErrUnknownNetwork is returned for a network that is not configured
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// RecheckResult describes the outcome of a proposal recheck
+type RecheckResult struct {
+	Network    string   `json:"network"`
+	ProposalID uint64   `json:"proposal_id"`
+	Status     string   `json:"status"`
+	Reset      bool     `json:"reset"`
+	Sent       []string `json:"sent"`
+}
+
+// Recheck re-fetches a proposal and re-runs the alert decisions for it
+// right away. With reset, the alerts already recorded for the proposal are
+// forgotten first so they fire again, for instance after a configuration
+// fix
+func (s *Service) Recheck(ctx context.Context, networkName string, proposalID uint64, reset bool) (*RecheckResult, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	name, networkConfig, ok := config.LookupNetwork(s.config, networkName)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownNetwork, networkName)
+	}
+	client := s.clients[name]
+
+	// Do not race the scheduled check of the network
+	lock := s.networkLocks[name]
+	lock.Lock()
+	defer lock.Unlock()
+
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := proposalKey(networkConfig.ChainID, proposalID)
+	if reset {
+		if err := s.store.Forget(key); err != nil {
+			return nil, fmt.Errorf("failed to reset alerts: %w", err)
+		}
+	}
+	before := s.store.SentAlerts(key)
+
+	proposalLog(networkConfig, proposalID, eventCheck).WithField("reset", reset).Info("Rechecking proposal")
+	switch cosmosgov.ProposalStatus(proposal.Status) {
+	case cosmosgov.StatusVotingPeriod:
+		if err := s.checkProposal(ctx, *proposal, []types.Proposal{*proposal}, client, networkConfig); err != nil {
+			return nil, err
+		}
+		s.checkDepositExpiry(ctx, client, networkConfig, []types.Proposal{*proposal})
+	case cosmosgov.StatusDepositPeriod:
+		if s.config.Alerts.NotifyOnNewProposal {
+			s.checkNewProposals(ctx, client, networkConfig, []types.Proposal{*proposal})
+		}
+		s.checkDepositExpiry(ctx, client, networkConfig, []types.Proposal{*proposal})
+	default:
+		if alertEnabled(networkConfig, types.AlertOutcome) && !s.store.WasSent(key, types.AlertOutcome) {
+			if err := s.sendOutcome(ctx, client, networkConfig, proposalID); err != nil {
+				return nil, err
+			}
+			s.markSent(key, types.AlertOutcome)
+		}
+	}
+
+	result := &RecheckResult{
+		Network:    name,
+		ProposalID: proposalID,
+		Status:     proposal.Status,
+		Reset:      reset,
+		Sent:       []string{},
+	}
+	for _, alertType := range s.store.SentAlerts(key) {
+		if !slices.Contains(before, alertType) {
+			result.Sent = append(result.Sent, alertType)
+		}
+	}
+	return result, nil
+}
+
+// CheckResult describes the outcome of an on-demand network check
+type CheckResult struct {
+	Network   string        `json:"network"`
+	ChainID   string        `json:"chain_id"`
+	Proposals int           `json:"proposals"`
+	Alerts    []RecentAlert `json:"alerts"`
+}
+
+// CheckNetwork runs the check of a single network right away, outside the
+// schedule, and returns the proposals in voting period and alerts it sent
+func (s *Service) CheckNetwork(ctx context.Context, networkName string) (*CheckResult, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	name, networkConfig, ok := config.LookupNetwork(s.config, networkName)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownNetwork, networkName)
+	}
+
+	started := time.Now()
+	networkLog(networkConfig, eventCheck).Info("Checking network on demand")
+	err := s.checkNetworkProposals(ctx, name, s.clients[name])
+	s.reportEndpointStatus(name, err)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CheckResult{Network: name, ChainID: networkConfig.ChainID, Alerts: []RecentAlert{}}
+	s.mu.Lock()
+	result.Proposals = len(s.tracked[name].proposals)
+	for _, alert := range s.recentAlerts {
+		if alert.Network == networkConfig.Name && !alert.Time.Before(started) {
+			result.Alerts = append(result.Alerts, alert)
+		}
+	}
+	s.mu.Unlock()
+	return result, nil
+}
+
+// CycleResult is the outcome of a check cycle across every network: what
+// each checked network has in voting period and was alerted about, the
+// networks that could not be checked and how many alerts failed to send
+type CycleResult struct {
+	Networks     []CheckResult     `json:"networks"`
+	Failed       map[string]string `json:"failed,omitempty"`
+	FailedAlerts int               `json:"failed_alerts"`
+}
+
+// CheckAll runs a single check cycle across every network, as the
+// monitoring loop does on each tick, and returns its outcome
+func (s *Service) CheckAll(ctx context.Context) *CycleResult {
+	s.mu.Lock()
+	failedBefore := s.failedAlerts
+	s.mu.Unlock()
+
+	started := time.Now()
+	if err := s.checkProposals(ctx); err != nil {
+		eventLog(eventCheck).WithError(err).Error("Check failed")
+	}
+
+	names := make([]string, 0, len(s.config.Networks))
+	for name := range s.config.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := &CycleResult{Networks: []CheckResult{}, FailedAlerts: s.failedAlerts - failedBefore}
+	for _, name := range names {
+		networkConfig := s.config.Networks[name]
+		if failure, ok := s.checkFailures[name]; ok && !failure.at.Before(started) {
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[name] = failure.err
+			continue
+		}
+
+		checked := CheckResult{Network: name, ChainID: networkConfig.ChainID, Proposals: len(s.tracked[name].proposals), Alerts: []RecentAlert{}}
+		for _, alert := range s.recentAlerts {
+			if alert.Network == networkConfig.Name && !alert.Time.Before(started) {
+				checked.Alerts = append(checked.Alerts, alert)
+			}
+		}
+		result.Networks = append(result.Networks, checked)
+	}
+	return result
+}
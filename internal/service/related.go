@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// relatedProposalsText describes the proposals a proposal references and the
+// tracked proposals that reference it, with their current status
+func (s *Service) relatedProposalsText(ctx context.Context, proposal types.Proposal, tracked []types.Proposal, client *governance.Client) string {
+	byID := make(map[uint64]types.Proposal, len(tracked))
+	for _, p := range tracked {
+		byID[p.ID] = p
+	}
+
+	text := ""
+
+	// Proposals this one depends on or mentions
+	for _, id := range proposal.References {
+		status := ""
+		if p, ok := byID[id]; ok {
+			status = p.Status
+		} else if fetched, err := client.CheckProposalStatus(ctx, id); err == nil {
+			status = fetched
+		} else {
+			eventLog(eventFetchFailed).WithFields(logrus.Fields{"network": proposal.Network, "proposal_id": id}).WithError(err).Warn("Failed to fetch referenced proposal")
+			text += fmt.Sprintf("\n• References #%d", id)
+			continue
+		}
+		text += fmt.Sprintf("\n• References #%d (%s)", id, governance.StatusLabel(status))
+	}
+
+	// Tracked proposals mentioning this one
+	for _, p := range tracked {
+		for _, id := range p.References {
+			if id == proposal.ID {
+				text += fmt.Sprintf("\n• Referenced by #%d %s (%s)", p.ID, p.Title, governance.StatusLabel(p.Status))
+				break
+			}
+		}
+	}
+
+	if text == "" {
+		return ""
+	}
+	return "\n\nRelated proposals:" + text
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// reliabilityPeriod returns the interval between reliability reports, 0
+// when they are disabled
+func reliabilityPeriod(period string) time.Duration {
+	switch period {
+	case "daily":
+		return 24 * time.Hour
+	case "weekly":
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// maybeSendReliabilityReport posts the endpoint reliability report to the
+// ops channels once the configured period has elapsed, then starts a new
+// period
+func (s *Service) maybeSendReliabilityReport(now time.Time) {
+	period := reliabilityPeriod(s.config.Reports.Reliability)
+	if period == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	due := now.Sub(s.reliabilitySince) >= period
+	since := s.reliabilitySince
+	if due {
+		s.reliabilitySince = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	stats := make([]governance.EndpointStats, 0, len(s.clients))
+	for _, client := range s.clients {
+		stats = append(stats, client.EndpointStats(true)...)
+	}
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("📊 Endpoint Reliability Report (%s)", s.config.Reports.Reliability),
+		Content:     formatReliabilityReport(stats, since, now),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleOps,
+	}
+	if err := s.notifier.SendNotification(msg); err != nil {
+		eventLog(eventAlertFailed).WithError(err).Warn("Failed to send reliability report")
+	}
+}
+
+// formatReliabilityReport lists endpoints from least to most reliable
+func formatReliabilityReport(stats []governance.EndpointStats, since, now time.Time) string {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].SuccessRate() != stats[j].SuccessRate() {
+			return stats[i].SuccessRate() < stats[j].SuccessRate()
+		}
+		return stats[i].Network < stats[j].Network
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s → %s\n", since.Format("2006-01-02 15:04"), now.Format("2006-01-02 15:04 MST"))
+	for _, st := range stats {
+		fmt.Fprintf(&b, "\n• %s (%s)\n  success %.2f%% of %d requests, p95 %s, %d schema errors",
+			st.Network, st.Endpoint, st.SuccessRate(), st.Requests, st.P95Latency.Round(time.Millisecond), st.SchemaErrors)
+	}
+	return b.String()
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/notifications"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrStopped is returned by Reload once the service stopped
+var ErrStopped = errors.New("service stopped")
+
+// reloadRequest asks the monitoring loop to apply a new configuration
+type reloadRequest struct {
+	config *types.Config
+	done   chan error
+}
+
+// Reload applies a new configuration without a restart. It is applied by
+// the monitoring loop between checks: networks are added or removed,
+// changed networks get a new client, and the notifier is recreated when
+// the notification settings changed. On error the running configuration
+// is kept
+func (s *Service) Reload(cfg *types.Config) error {
+	done := make(chan error, 1)
+	select {
+	case s.reloads <- reloadRequest{config: cfg, done: done}:
+	case <-s.stopChan:
+		return ErrStopped
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-s.stopChan:
+		return ErrStopped
+	}
+}
+
+// startNotifier serves the notifier's bot commands and template reloads
+// until ctx is done or stopNotifier is called for a new notifier
+func (s *Service) startNotifier(ctx context.Context) {
+	notifierCtx, cancel := context.WithCancel(ctx)
+	s.cancelNotifier = cancel
+	s.notifierDone.Add(2)
+	go func() {
+		defer s.notifierDone.Done()
+		s.notifier.WatchTemplates(notifierCtx, s.reportTemplateError)
+	}()
+	go func() {
+		defer s.notifierDone.Done()
+		s.runCommands(notifierCtx)
+	}()
+}
+
+// stopNotifier stops serving bot commands and template reloads, waiting
+// for the Telegram poller to finish its long poll so no poller outlives
+// its notifier
+func (s *Service) stopNotifier() {
+	s.cancelNotifier()
+	s.notifierDone.Wait()
+}
+
+// applyConfig swaps in a new configuration, returning the networks added
+// and whether the notifier was recreated. Everything is built before
+// anything is swapped so a failure leaves the service untouched
+func (s *Service) applyConfig(cfg *types.Config) ([]string, bool, error) {
+	quiet, err := parseQuietHours(cfg.Alerts.QuietHours)
+	if err != nil {
+		return nil, false, err
+	}
+
+	notifier := s.notifier
+	notifierChanged := !reflect.DeepEqual(cfg.Notifications, s.config.Notifications)
+	if notifierChanged {
+		if notifier, err = notifications.NewNotifier(&cfg.Notifications, cfg.Networks); err != nil {
+			return nil, false, fmt.Errorf("failed to create notifier: %w", err)
+		}
+		notifier.SetPaused(s.store.Flags().PausedChannels)
+		notifier.SetDryRun(s.config.DryRun)
+	}
+
+	// Unchanged networks keep their client and its endpoint state. Locks
+	// are kept by name so a running recheck is not raced
+	clients := make(map[string]*governance.Client, len(cfg.Networks))
+	networkLocks := make(map[string]*sync.Mutex, len(cfg.Networks))
+	var added, changed, removed []string
+	for name, networkConfig := range cfg.Networks {
+		previous, existed := s.config.Networks[name]
+		if existed && reflect.DeepEqual(previous, networkConfig) {
+			clients[name] = s.clients[name]
+		} else {
+			client, err := governance.NewClient(networkConfig)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to create client for %s: %w", name, err)
+			}
+			clients[name] = client
+			if existed {
+				changed = append(changed, name)
+			} else {
+				added = append(added, name)
+			}
+		}
+
+		networkLocks[name] = s.networkLocks[name]
+		if networkLocks[name] == nil {
+			networkLocks[name] = &sync.Mutex{}
+		}
+	}
+	for name := range s.config.Networks {
+		if _, ok := cfg.Networks[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	for _, setting := range restartRequired(s.config, cfg) {
+		logrus.WithFields(logrus.Fields{"setting": setting, "event": eventService}).Warn("Setting changed, takes effect after a restart")
+	}
+	// Settings only read at startup keep their running value
+	cfg.State = s.config.State
+	cfg.API = s.config.API
+	cfg.Privacy = s.config.Privacy
+	cfg.Performance.HTTP = s.config.Performance.HTTP
+	cfg.DryRun = s.config.DryRun
+
+	previousClients := s.clients
+	s.configMu.Lock()
+	s.config = cfg
+	s.clients = clients
+	s.networkLocks = networkLocks
+	s.notifier = notifier
+	s.quietHours = quiet
+	s.configMu.Unlock()
+	amounts.Install(cfg.Formatting)
+	notifier.SetSortBy(cfg.Formatting.SortBy)
+
+	// Tear down the clients that were replaced or removed
+	for name, client := range previousClients {
+		if clients[name] != client {
+			if err := client.Close(); err != nil {
+				logrus.WithFields(logrus.Fields{"network": name, "event": eventService}).WithError(err).Warn("Failed to close client")
+			}
+		}
+	}
+
+	s.mu.Lock()
+	for _, name := range removed {
+		delete(s.tracked, name)
+		delete(s.failingNetworks, name)
+		delete(s.checkFailures, name)
+	}
+	s.mu.Unlock()
+
+	s.recordNetworkNames()
+
+	sort.Strings(added)
+	sort.Strings(changed)
+	sort.Strings(removed)
+	logrus.WithFields(logrus.Fields{
+		"added":    strings.Join(added, ","),
+		"changed":  strings.Join(changed, ","),
+		"removed":  strings.Join(removed, ","),
+		"notifier": notifierChanged,
+		"event":    eventService,
+	}).Info("Configuration reloaded")
+	return added, notifierChanged, nil
+}
+
+// restartRequired lists the changed settings that are only read at startup
+func restartRequired(previous, next *types.Config) []string {
+	var settings []string
+	if !reflect.DeepEqual(previous.State, next.State) {
+		settings = append(settings, "state")
+	}
+	if !reflect.DeepEqual(previous.API, next.API) {
+		settings = append(settings, "api")
+	}
+	if !reflect.DeepEqual(previous.Privacy, next.Privacy) {
+		settings = append(settings, "privacy")
+	}
+	if previous.Performance.HTTP != next.Performance.HTTP {
+		settings = append(settings, "performance.http")
+	}
+	if previous.Health.Listen != next.Health.Listen {
+		settings = append(settings, "health.listen")
+	}
+	if previous.DryRun != next.DryRun {
+		settings = append(settings, "dry_run")
+	}
+	return settings
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// spamVetoShare is the share of NoWithVeto votes above which a rejected
+// proposal is considered spam, the SDK's default veto threshold
+const spamVetoShare = 0.334
+
+// sendProposalAlert sends a proposal alert, remembering where it was sent
+// when retractions or reactions are enabled. Alerts collapsed into an
+// overflow summary or only posted to webhooks still count as recent alerts
+func (s *Service) sendProposalAlert(msg types.NotificationMessage) error {
+	// Alerts dropped here are recorded as handled by the callers
+	if _, networkConfig, ok := config.LookupNetwork(s.config, msg.ChainID); ok {
+		if msg.Proposal != nil && !governance.MatchesProposalType(networkConfig.ProposalTypes, *msg.Proposal) {
+			proposalAlertLog(networkConfig, msg.ProposalID, eventAlertSkipped, msg.AlertType).WithField("category", msg.Proposal.Category).Debug("Alert skipped, proposal type not in proposal_types")
+			return nil
+		}
+		if mute, muted := s.store.Muted(proposalKey(msg.ChainID, msg.ProposalID)); muted {
+			proposalAlertLog(networkConfig, msg.ProposalID, eventAlertSkipped, msg.AlertType).WithField("user", mute.User).Debug("Alert skipped, proposal muted")
+			return nil
+		}
+		if s.dryRun(networkConfig.ChainID) {
+			proposalAlertLog(networkConfig, msg.ProposalID, eventAlertSkipped, msg.AlertType).Infof("Dry run, alert not sent: %s", msg.Title)
+			return nil
+		}
+		msg.Content += s.incidentNote(networkConfig)
+	}
+	if msg.ProposalID != 0 {
+		msg.Links = s.store.Links(proposalKey(msg.ChainID, msg.ProposalID))
+	}
+
+	refs, err := s.notifier.SendTracked(msg)
+	if err == nil || len(refs) > 0 {
+		s.recordRecentAlert(msg)
+	}
+	if err != nil {
+		s.mu.Lock()
+		s.failedAlerts++
+		s.mu.Unlock()
+	}
+	s.recordAlertTimeline(msg, err)
+	if s.tracksMessages() {
+		key := proposalKey(msg.ChainID, msg.ProposalID)
+		if storeErr := s.store.AddMessages(key, refs); storeErr != nil {
+			keyLog(key, eventStateFailed).WithError(storeErr).Warn("Failed to record messages")
+		}
+	}
+	return err
+}
+
+// checkRetractions looks at alerted proposals that left the active list
+// since the last check. Proposals that vanished from chain were cancelled
+// and rejected proposals vetoed as spam get their alerts retracted; all
+// others ended normally and are forgotten. This runs on the first check
+// after a proposal left the active list, long before nodes prune it. With
+// retractions disabled, the messages recorded for reactions are forgotten
+func (s *Service) checkRetractions(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, active []types.Proposal) {
+	if !s.tracksMessages() {
+		return
+	}
+
+	activeIDs := make(map[uint64]bool, len(active))
+	for _, proposal := range active {
+		activeIDs[proposal.ID] = true
+	}
+
+	prefix := networkConfig.ChainID + "/"
+	for key, refs := range s.store.Messages() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		proposalID, err := strconv.ParseUint(strings.TrimPrefix(key, prefix), 10, 64)
+		if err != nil || activeIDs[proposalID] {
+			continue
+		}
+
+		// Messages that could not be retracted are kept for the next check,
+		// the others forgotten so they are not retracted twice
+		var remaining []types.MessageRef
+		if s.config.Notifications.Retractions != "" {
+			remaining = s.retract(ctx, client, networkConfig, proposalID, refs)
+		}
+		if len(remaining) > 0 && len(remaining) == len(refs) {
+			continue
+		}
+
+		if err := s.store.KeepMessages(key, remaining); err != nil {
+			keyLog(key, eventStateFailed).WithError(err).Warn("Failed to forget messages")
+		}
+	}
+}
+
+// retract retracts the alerts of a proposal that was cancelled or vetoed as
+// spam, returning the messages to retry at the next check
+func (s *Service) retract(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, proposalID uint64, refs []types.MessageRef) []types.MessageRef {
+	banner, reason := "", ""
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	switch {
+	case errors.Is(err, governance.ErrNotFound):
+		banner = "🚫 CANCELLED"
+		reason = "was cancelled and removed from chain"
+	case err != nil:
+		proposalLog(networkConfig, proposalID, eventFetchFailed).WithError(err).Warn("Failed to check proposal for retraction")
+		return refs
+	case isSpam(proposal):
+		banner = "🚫 SPAM"
+		reason = "was vetoed as spam"
+	}
+
+	if banner == "" {
+		return nil
+	}
+
+	notice := types.NotificationMessage{
+		Title:       fmt.Sprintf("%s - %s", banner, networkConfig.Name),
+		Content:     fmt.Sprintf("Proposal #%d %s after it was announced. Please disregard the earlier alerts.", proposalID, reason),
+		Network:     networkConfig.Name,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposalID,
+		ExplorerURL: s.explorerURL(networkConfig, proposalID),
+		Role:        types.RoleCommunity,
+	}
+	failed, err := s.notifier.Retract(refs, s.config.Notifications.Retractions, banner, notice)
+	if err != nil {
+		proposalLog(networkConfig, proposalID, eventAlertFailed).WithError(err).WithField("failed", len(failed)).Warn("Failed to retract alerts")
+		return failed
+	}
+	proposalLog(networkConfig, proposalID, eventAlertSent).WithField("banner", banner).Info("Retracted alerts")
+	return nil
+}
+
+// isSpam reports whether a proposal was rejected with a veto majority
+func isSpam(proposal *types.Proposal) bool {
+	if proposal.Status != string(cosmosgov.StatusRejected) || proposal.FinalTally == nil {
+		return false
+	}
+	total := tallyTotal(proposal.FinalTally)
+	return total > 0 && parseAmount(proposal.FinalTally.NoWithVeto)/total > spamVetoShare
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/metrics"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// heartbeatPeriod is how often the heartbeat message is sent when enabled
+const heartbeatPeriod = time.Hour
+
+// Scheduling metrics, as Unix timestamps so alert rules compare them with
+// time()
+const (
+	metricLastSuccess = "governance_network_last_success_timestamp_seconds"
+	metricLastError   = "governance_network_last_error_timestamp_seconds"
+	metricNextCheck   = "governance_network_next_check_timestamp_seconds"
+)
+
+func init() {
+	metrics.Register(metricLastSuccess, "Time of the last successful check of the network.", metrics.Gauge)
+	metrics.Register(metricLastError, "Time of the last failed check of the network.", metrics.Gauge)
+	metrics.Register(metricNextCheck, "Time the next check of the network is scheduled.", metrics.Gauge)
+}
+
+// checkFailure is the last failed check of a network
+type checkFailure struct {
+	err string
+	at  time.Time
+}
+
+// networkSchedule is when a network was and will be checked
+type networkSchedule struct {
+	Network     string
+	Name        string
+	LastSuccess time.Time
+	LastError   string
+	LastErrorAt time.Time
+	NextCheck   time.Time
+}
+
+// recordCheckResult records the outcome of a check of a network for the
+// status outputs and metrics
+func (s *Service) recordCheckResult(networkName string, checkErr error) {
+	networkConfig := s.config.Networks[networkName]
+	labels := metrics.Labels{"network": networkConfig.Name, "chain_id": networkConfig.ChainID}
+	now := time.Now()
+
+	if checkErr == nil {
+		metrics.Set(metricLastSuccess, labels, float64(now.Unix()))
+		return
+	}
+	s.mu.Lock()
+	s.checkFailures[networkName] = checkFailure{err: checkErr.Error(), at: now}
+	s.mu.Unlock()
+	metrics.Set(metricLastError, labels, float64(now.Unix()))
+}
+
+// scheduleNext records when the monitoring loop checks the networks next.
+// Only the loop calls it, which also owns config changes
+func (s *Service) scheduleNext(next time.Time) {
+	s.mu.Lock()
+	s.nextCheck = next
+	s.mu.Unlock()
+
+	for _, networkConfig := range s.config.Networks {
+		labels := metrics.Labels{"network": networkConfig.Name, "chain_id": networkConfig.ChainID}
+		metrics.Set(metricNextCheck, labels, float64(next.Unix()))
+	}
+}
+
+// schedules returns when every network was last checked, last failed and
+// is checked next, sorted by network. Callers hold configMu or run in the
+// monitoring loop
+func (s *Service) schedules() []networkSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules := make([]networkSchedule, 0, len(s.config.Networks))
+	for name, networkConfig := range s.config.Networks {
+		failure := s.checkFailures[name]
+		schedules = append(schedules, networkSchedule{
+			Network:     name,
+			Name:        networkConfig.Name,
+			LastSuccess: s.tracked[name].checkedAt,
+			LastError:   failure.err,
+			LastErrorAt: failure.at,
+			NextCheck:   s.nextCheck,
+		})
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Network < schedules[j].Network })
+	return schedules
+}
+
+// text describes the schedule of a network on one line, relative to now
+func (n networkSchedule) text(now time.Time) string {
+	parts := []string{"never checked"}
+	if !n.LastSuccess.IsZero() {
+		parts[0] = fmt.Sprintf("checked %s ago", formatDuration(now.Sub(n.LastSuccess)))
+	}
+	if next := n.NextCheck.Sub(now); !n.NextCheck.IsZero() {
+		if next > 0 {
+			parts = append(parts, fmt.Sprintf("next in %s", formatDuration(next)))
+		} else {
+			parts = append(parts, "next check due")
+		}
+	}
+	// Errors older than the last success are history, still worth a look
+	if n.LastErrorAt.After(n.LastSuccess) {
+		parts = append(parts, fmt.Sprintf("failing since %s ago: %s", formatDuration(now.Sub(n.LastErrorAt)), n.LastError))
+	} else if !n.LastErrorAt.IsZero() {
+		parts = append(parts, fmt.Sprintf("last error %s ago", formatDuration(now.Sub(n.LastErrorAt))))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// maybeSendHeartbeat posts the schedule of every network to the ops
+// channels once an hour, so a silent chat means a dead service rather than
+// a quiet governance week
+func (s *Service) maybeSendHeartbeat(now time.Time) {
+	if !s.config.Reports.Heartbeat {
+		return
+	}
+
+	s.mu.Lock()
+	due := now.Sub(s.heartbeatSince) >= heartbeatPeriod
+	if due {
+		s.heartbeatSince = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Up %s, checking every %s.\n", formatDuration(now.Sub(s.startedAt)), formatDuration(s.checkInterval()))
+	for _, schedule := range s.schedules() {
+		fmt.Fprintf(&b, "\n• %s: %s", schedule.Name, schedule.text(now))
+	}
+
+	msg := types.NotificationMessage{
+		Title:       "💓 Governance Alerts Heartbeat",
+		Content:     b.String(),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleOps,
+	}
+	if err := s.notifier.SendNotification(msg); err != nil {
+		eventLog(eventAlertFailed).WithError(err).Warn("Failed to send heartbeat")
+	}
+}
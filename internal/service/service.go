@@ -2,66 +2,209 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/analytics"
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/events"
 	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/lifecycle"
+	"governance-alerts-cosmos/internal/metrics"
 	"governance-alerts-cosmos/internal/notifications"
+	"governance-alerts-cosmos/internal/store"
 	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+	"github.com/sirupsen/logrus"
 )
 
 // Service represents the governance alerts service
 type Service struct {
-	config   *types.Config
-	notifier *notifications.Notifier
-	clients  map[string]*governance.Client
-	stopChan chan struct{}
+	config                *types.Config
+	notifier              *notifications.Notifier
+	clients               map[string]*governance.Client
+	stopChan              chan struct{}
+	mu                    sync.Mutex
+	bondedTokens          map[string]float64
+	staleReported         map[string]bool
+	failingNetworks       map[string]bool
+	quietHours            *quietHours
+	firstSeen             map[string]time.Time
+	preexisting           map[string]bool
+	cycles                int
+	startedAt             time.Time
+	lastCycle             time.Time
+	store                 *store.Store
+	votingLast            map[string]map[uint64]bool
+	upgrades              map[string]types.UpgradeEstimate
+	upgradeSignature      string
+	upgradeTimelineSynced bool
+	reliabilitySince      time.Time
+	participationSince    time.Time
+	heartbeatSince        time.Time
+	nextCheck             time.Time
+	checkFailures         map[string]checkFailure
+	networkLocks          map[string]*sync.Mutex
+	params                map[string]*types.GovParams
+	paramsChanges         map[string][]paramsChange
+	bondedShifts          map[string][]bondedShift
+	depositsFetched       map[string]bool
+	tracked               map[string]trackedNetwork
+	whales                map[string]*whaleVotes
+	recentAlerts          []RecentAlert
+	failedAlerts          int
+	explorers             explorerHealth
+	lookups               lookupCache
+	analytics             *analytics.Sink
+	incidents             map[string][]types.ChainIncident
+	reloads               chan reloadRequest
+	cancelNotifier        context.CancelFunc
+	notifierDone          sync.WaitGroup
+	components            func() []lifecycle.Status
+	// configMu guards config, clients, networkLocks, notifier and
+	// quietHours, which a reload swaps, against readers outside the
+	// monitoring loop
+	configMu sync.RWMutex
+	// flagsMu serializes runtime flag changes
+	flagsMu sync.Mutex
 }
 
 // NewService creates a new governance alerts service
 func NewService(config *types.Config) (*Service, error) {
+	amounts.Install(config.Formatting)
+	governance.ConfigureTransport(config.Performance.HTTP)
+
 	// Initialize notifier
-	notifier, err := notifications.NewNotifier(&config.Notifications)
+	notifier, err := notifications.NewNotifier(&config.Notifications, config.Networks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create notifier: %w", err)
 	}
 
 	// Initialize governance clients for each network
 	clients := make(map[string]*governance.Client)
+	networkLocks := make(map[string]*sync.Mutex)
 	for name, networkConfig := range config.Networks {
 		client, err := governance.NewClient(networkConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create client for %s: %w", name, err)
 		}
 		clients[name] = client
+		networkLocks[name] = &sync.Mutex{}
+	}
+
+	// Parse quiet hours
+	quiet, err := parseQuietHours(config.Alerts.QuietHours)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the record of alerts already sent. A dry run evaluates alerts
+	// against it without recording anything
+	open := store.Open
+	if config.DryRun {
+		open = store.OpenReadOnly
 	}
+	state, err := open(config.State.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state: %w", err)
+	}
+
+	s := &Service{
+		config:             config,
+		notifier:           notifier,
+		clients:            clients,
+		stopChan:           make(chan struct{}),
+		bondedTokens:       make(map[string]float64),
+		staleReported:      make(map[string]bool),
+		failingNetworks:    make(map[string]bool),
+		quietHours:         quiet,
+		firstSeen:          make(map[string]time.Time),
+		preexisting:        make(map[string]bool),
+		store:              state,
+		votingLast:         make(map[string]map[uint64]bool),
+		params:             make(map[string]*types.GovParams),
+		paramsChanges:      make(map[string][]paramsChange),
+		bondedShifts:       make(map[string][]bondedShift),
+		depositsFetched:    make(map[string]bool),
+		tracked:            make(map[string]trackedNetwork),
+		whales:             make(map[string]*whaleVotes),
+		upgrades:           make(map[string]types.UpgradeEstimate),
+		incidents:          make(map[string][]types.ChainIncident),
+		reliabilitySince:   time.Now(),
+		participationSince: time.Now(),
+		heartbeatSince:     time.Now(),
+		checkFailures:      make(map[string]checkFailure),
+		startedAt:          time.Now(),
+		networkLocks:       networkLocks,
+		reloads:            make(chan reloadRequest),
+	}
+	s.recordNetworkNames()
 
-	return &Service{
-		config:   config,
-		notifier: notifier,
-		clients:  clients,
-		stopChan: make(chan struct{}),
-	}, nil
+	// Nothing is recorded in dry run, so nothing is exported either
+	if config.Analytics.Enabled && !config.DryRun {
+		if s.analytics, err = analytics.New(config.Analytics); err != nil {
+			return nil, fmt.Errorf("failed to create analytics sink: %w", err)
+		}
+	}
+	notifier.SetPaused(state.Flags().PausedChannels)
+	notifier.SetSortBy(config.Formatting.SortBy)
+	notifier.SetDryRun(config.DryRun)
+	return s, nil
+}
+
+// recordNetworkNames records the configured name of every chain ID.
+// State is keyed by chain ID, renamed networks keep theirs
+func (s *Service) recordNetworkNames() {
+	names := make(map[string]string, len(s.config.Networks))
+	for name, networkConfig := range s.config.Networks {
+		names[networkConfig.ChainID] = name
+	}
+	renames, err := s.store.RecordNetworks(names)
+	if err != nil {
+		eventLog(eventStateFailed).WithError(err).Warn("Failed to record network names")
+	}
+	for _, rename := range renames {
+		logrus.WithFields(logrus.Fields{"chain_id": rename.ChainID, "from": rename.From, "to": rename.To, "event": eventService}).
+			Info("Network renamed, keeping its state (list the old name in aliases to keep using it in commands)")
+	}
 }
 
 // Run starts the governance alerts service
 func (s *Service) Run(ctx context.Context) error {
+	// Verify channel credentials before the first real alert
+	s.selfTestChannels(ctx)
+
+	// Serve bot commands and pick up template edits without a restart,
+	// until a reload replaces the notifier
+	s.startNotifier(ctx)
+	defer s.stopNotifier()
+
+	// Export to the analytics database, inserting the last rows on stop
+	defer s.startAnalytics()()
+
 	// Send startup notification if enabled
 	if s.config.Alerts.NotifyOnStartup {
 		if err := s.sendStartupNotification(); err != nil {
-			fmt.Printf("Warning: failed to send startup notification: %v\n", err)
+			eventLog(eventAlertFailed).WithError(err).Warn("Failed to send startup notification")
 		}
 	}
 
-	fmt.Println("Starting Governance Alerts Service...")
+	eventLog(eventService).Info("Starting Governance Alerts Service")
 
 	// Start monitoring loop
-	ticker := time.NewTicker(time.Duration(s.config.Alerts.CheckIntervalMinutes) * time.Minute)
+	ticker := time.NewTicker(s.checkInterval())
 	defer ticker.Stop()
+	s.scheduleNext(time.Now().Add(s.checkInterval()))
 
 	// Initial check
 	if err := s.checkProposals(ctx); err != nil {
-		fmt.Printf("Error during initial check: %v\n", err)
+		eventLog(eventCheck).WithError(err).Error("Initial check failed")
 	}
 
 	// Main loop
@@ -71,33 +214,109 @@ func (s *Service) Run(ctx context.Context) error {
 			return ctx.Err()
 		case <-s.stopChan:
 			return nil
-		case <-ticker.C:
+		case tick := <-ticker.C:
+			s.scheduleNext(tick.Add(s.checkInterval()))
 			if err := s.checkProposals(ctx); err != nil {
-				fmt.Printf("Error checking proposals: %v\n", err)
+				eventLog(eventCheck).WithError(err).Error("Check failed")
+			}
+		case req := <-s.reloads:
+			added, notifierChanged, err := s.applyConfig(req.config)
+			req.done <- err
+			if err != nil {
+				continue
+			}
+			if notifierChanged {
+				s.stopNotifier()
+				s.startNotifier(ctx)
+				s.selfTestChannels(ctx)
+			}
+			ticker.Reset(s.checkInterval())
+			s.scheduleNext(time.Now().Add(s.checkInterval()))
+
+			// Networks just added are checked right away, the others on
+			// the next tick
+			if len(added) > 0 && s.config.Alerts.CheckOnReload {
+				eventLog(eventCheck).WithField("networks", strings.Join(added, ",")).Info("Checking added networks")
+				if err := s.checkNetworks(ctx, added); err != nil {
+					eventLog(eventCheck).WithError(err).Error("Check of added networks failed")
+				}
 			}
 		}
 	}
 }
 
+// reportTemplateError warns the ops channels about a template that failed
+// to reload and is kept at its last good version
+func (s *Service) reportTemplateError(err error) {
+	eventLog(eventChannel).WithError(err).Warn("Template rejected")
+
+	msg := types.NotificationMessage{
+		Title:       "📝 Message Template Rejected",
+		Content:     fmt.Sprintf("A changed template failed validation and the last good version stays in use:\n%v", err),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleOps,
+	}
+	if err := s.notifier.SendNotification(msg); err != nil {
+		eventLog(eventAlertFailed).WithError(err).Warn("Failed to report template error")
+	}
+}
+
 // Stop stops the service
 func (s *Service) Stop() {
 	close(s.stopChan)
 }
 
+// selfTestChannels verifies every enabled channel and reports broken ones
+// to the log and the ops channels
+func (s *Service) selfTestChannels(ctx context.Context) {
+	var broken []string
+	for _, status := range s.notifier.SelfTest(ctx) {
+		if status.OK {
+			eventLog(eventChannel).WithField("channel", status.Channel).Info("Channel self-test passed")
+			continue
+		}
+		eventLog(eventChannel).WithField("channel", status.Channel).WithError(status.Error).Error("Channel self-test failed")
+		broken = append(broken, fmt.Sprintf("• %s: %v", status.Channel, status.Error))
+	}
+
+	if len(broken) == 0 {
+		return
+	}
+
+	msg := types.NotificationMessage{
+		Title:       "🔧 Notification Channel Self-Test Failed",
+		Content:     fmt.Sprintf("The following channels failed their startup check and may not deliver alerts:\n%s", strings.Join(broken, "\n")),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleOps,
+	}
+	if err := s.notifier.SendNotification(msg); err != nil {
+		eventLog(eventAlertFailed).WithError(err).Warn("Failed to report broken channels")
+	}
+}
+
 // sendStartupNotification sends a notification when the service starts
 func (s *Service) sendStartupNotification() error {
 	networks := make([]string, 0, len(s.config.Networks))
 	for _, network := range s.config.Networks {
 		networks = append(networks, fmt.Sprintf("%s (%s)", network.Name, network.ChainID))
 	}
+	sort.Strings(networks)
 
 	msg := types.NotificationMessage{
 		Title:       "🚀 Governance Alerts Service Started",
-		Content:     fmt.Sprintf("Service is now monitoring %d networks:\n• %s", len(networks), networks[0]),
+		Content:     fmt.Sprintf("Service is now monitoring %d networks, checking every %s starting now:\n• %s", len(networks), formatDuration(s.checkInterval()), networks[0]),
 		Network:     "Governance Alerts",
 		ChainID:     "Service",
 		ProposalID:  0,
 		ExplorerURL: "",
+		Role:        types.RoleOps,
+		Event:       events.TypeServiceStarted,
 	}
 
 	// Add additional networks if more than one
@@ -110,37 +329,187 @@ func (s *Service) sendStartupNotification() error {
 	return s.notifier.SendNotification(msg)
 }
 
+// CheckOnce runs a single check cycle across all networks
+func (s *Service) CheckOnce(ctx context.Context) error {
+	return s.checkProposals(ctx)
+}
+
 // checkProposals checks all networks for proposals
 func (s *Service) checkProposals(ctx context.Context) error {
-	fmt.Printf("Checking proposals at %s\n", time.Now().Format(time.RFC3339))
+	eventLog(eventCheck).Info("Checking proposals")
+
+	names := make([]string, 0, len(s.clients))
+	for name := range s.clients {
+		names = append(names, name)
+	}
+	start := time.Now()
+	checkErr := s.checkNetworks(ctx, names)
+	if elapsed := time.Since(start); elapsed > s.checkInterval() {
+		eventLog(eventCheck).WithField("duration", elapsed.Round(time.Second).String()).
+			Warn("Check cycle took longer than the check interval, raise performance.max_concurrent_networks or lower performance.network_timeout_seconds")
+	}
 
-	for name, client := range s.clients {
-		if err := s.checkNetworkProposals(ctx, name, client); err != nil {
-			fmt.Printf("Error checking proposals for %s: %v\n", name, err)
+	s.mu.Lock()
+	s.cycles++
+	s.lastCycle = time.Now()
+	s.mu.Unlock()
+
+	s.maybeSendReliabilityReport(time.Now())
+	s.maybeSendParticipationReport(time.Now())
+	s.maybeSendHeartbeat(time.Now())
+	s.pingHeartbeats(ctx, start, checkErr)
+	s.updateUpgradeTimeline(ctx, time.Now())
+
+	// Forget alerts of proposals long finished
+	if err := s.store.Prune(time.Now().Add(-stateRetention)); err != nil {
+		eventLog(eventStateFailed).WithError(err).Warn("Failed to prune state")
+	}
+
+	// Export metrics for the node exporter textfile collector
+	if s.config.Metrics.Textfile != "" {
+		if err := metrics.WriteTextfile(s.config.Metrics.Textfile); err != nil {
+			eventLog(eventService).WithError(err).Warn("Failed to write metrics textfile")
+		}
+	}
+
+	return checkErr
+}
+
+// checkNetworks checks the named networks for proposals, running at most
+// max_concurrent_networks checks at the same time, each bounded by
+// network_timeout_seconds. The failures of all networks are returned
+// together once every check is done
+func (s *Service) checkNetworks(ctx context.Context, names []string) error {
+	s.notifier.BeginCycle()
+
+	// The checks change the state for every alert, write it once at the end
+	s.store.BeginBatch()
+	defer func() {
+		if err := s.store.EndBatch(); err != nil {
+			eventLog(eventStateFailed).WithError(err).Warn("Failed to save state")
 		}
+	}()
+
+	// Configurations built in code may leave the limits unset, which would
+	// block every check or cancel it right away
+	concurrency := s.config.Performance.MaxConcurrentNetworks
+	if concurrency <= 0 {
+		concurrency = config.DefaultMaxConcurrentNetworks
+	}
+	timeout := time.Duration(s.config.Performance.NetworkTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = config.DefaultNetworkTimeoutSeconds * time.Second
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var errsMu sync.Mutex
+	var errs []error
+
+	for _, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string, client *governance.Client) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			networkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			err := s.checkNetworkProposals(networkCtx, name, client)
+			if err != nil {
+				if errors.Is(networkCtx.Err(), context.DeadlineExceeded) {
+					err = fmt.Errorf("check timed out after %s: %w", timeout, err)
+				}
+				networkLog(s.config.Networks[name], eventCheck).WithError(err).Error("Failed to check proposals")
+				errsMu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				errsMu.Unlock()
+			}
+			s.recordCheckResult(name, err)
+			s.reportEndpointStatus(name, err)
+		}(name, s.clients[name])
 	}
 
+	wg.Wait()
+
+	// Summarize the alerts held back from channels over the cycle's limit
+	if err := s.notifier.EndCycle(); err != nil {
+		eventLog(eventAlertFailed).WithError(err).Error("Failed to send overflow summary")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d networks failed: %w", len(errs), len(names), errors.Join(errs...))
+	}
 	return nil
 }
 
 // checkNetworkProposals checks proposals for a specific network
 func (s *Service) checkNetworkProposals(ctx context.Context, networkName string, client *governance.Client) error {
-	proposals, err := client.GetVotingProposals(ctx)
+	networkConfig := s.config.Networks[networkName]
+
+	lock := s.networkLocks[networkName]
+	lock.Lock()
+	defer lock.Unlock()
+
+	ctx = client.ConsistentReads(ctx)
+	s.verifyChainID(ctx, client, networkConfig)
+	s.checkIncidents(ctx, client, networkConfig, s.now(client))
+
+	// New proposal and deposit expiry alerts also need proposals still in
+	// deposit period. Proposals are handled most urgent first, so are their
+	// alerts
+	var proposals []types.Proposal
+	var err error
+	if s.config.Alerts.NotifyOnNewProposal || s.config.Alerts.HoursBeforeDepositEnd > 0 {
+		var active []types.Proposal
+		if active, err = client.GetActiveProposals(ctx); err == nil {
+			s.sortByUrgency(networkName, networkConfig, active)
+			s.recordStatuses(networkConfig, active, s.now(client))
+			s.recordDepositSightings(networkConfig, active, s.now(client))
+			s.checkProposalChanges(networkConfig, active, s.now(client))
+			proposals = votingProposals(active)
+			s.handleFirstRun(networkConfig, proposals, s.now(client))
+			if s.config.Alerts.NotifyOnNewProposal {
+				s.checkNewProposals(ctx, client, networkConfig, active)
+			}
+			s.checkDepositExpiry(ctx, client, networkConfig, active)
+			s.checkRetractions(ctx, client, networkConfig, active)
+		}
+	} else if proposals, err = client.GetVotingProposals(ctx); err == nil {
+		s.sortByUrgency(networkName, networkConfig, proposals)
+		s.recordStatuses(networkConfig, proposals, s.now(client))
+		s.checkProposalChanges(networkConfig, proposals, s.now(client))
+		s.handleFirstRun(networkConfig, proposals, s.now(client))
+		s.checkRetractions(ctx, client, networkConfig, proposals)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get proposals: %w", err)
 	}
 
+	s.checkOutcomes(ctx, client, networkConfig, proposals)
+	s.recordTracked(networkName, proposals)
+	s.probeExplorers(ctx, networkConfig, proposals)
+
 	if len(proposals) == 0 {
-		fmt.Printf("  No active proposals found for %s\n", networkName)
+		networkLog(networkConfig, eventCheck).Info("No active proposals found")
+		s.forgetBondedStake(networkConfig.ChainID)
 		return nil
 	}
 
-	fmt.Printf("  Found %d active proposals for %s\n", len(proposals), networkName)
+	networkLog(networkConfig, eventCheck).WithField("proposals", len(proposals)).Info("Found active proposals")
+
+	// Track bonded stake while proposals are in voting period, before their
+	// tally alerts so these note a shift seen in this check
+	if s.config.Alerts.BondedChangeThresholdPercent > 0 && s.tallyPolling() {
+		if err := s.checkBondedStake(ctx, client, networkConfig, proposals, s.now(client)); err != nil {
+			networkLog(networkConfig, eventFetchFailed).WithError(err).Error("Failed to check bonded stake")
+		}
+	}
 
-	networkConfig := s.config.Networks[networkName]
 	for _, proposal := range proposals {
-		if err := s.checkProposal(ctx, proposal, client, networkConfig); err != nil {
-			fmt.Printf("Error checking proposal %d: %v\n", proposal.ID, err)
+		if err := s.checkProposal(ctx, proposal, proposals, client, networkConfig); err != nil {
+			proposalLog(networkConfig, proposal.ID, eventCheck).WithError(err).Error("Failed to check proposal")
 		}
 	}
 
@@ -148,75 +517,205 @@ func (s *Service) checkNetworkProposals(ctx context.Context, networkName string,
 }
 
 // checkProposal checks a specific proposal and sends notifications if needed
-func (s *Service) checkProposal(ctx context.Context, proposal types.Proposal, client *governance.Client, networkConfig types.NetworkConfig) error {
-	now := time.Now()
+func (s *Service) checkProposal(ctx context.Context, proposal types.Proposal, tracked []types.Proposal, client *governance.Client, networkConfig types.NetworkConfig) error {
+	now := s.now(client)
+
+	// Related proposals are only looked up when an alert is sent
+	related, relatedLoaded := "", false
+	relatedText := func() string {
+		if !relatedLoaded {
+			related = s.relatedProposalsText(ctx, proposal, tracked, client)
+			relatedLoaded = true
+		}
+		return related
+	}
 
 	// Log proposal details
-	fmt.Printf("  📋 Proposal %d: %s\n", proposal.ID, proposal.Title)
-	fmt.Printf("     Description: %s\n", truncateString(proposal.Description, 100))
-	fmt.Printf("     Network: %s (%s)\n", proposal.Network, networkConfig.ChainID)
-	fmt.Printf("     Voting: %s → %s\n",
-		proposal.VotingStart.Format("2006-01-02 15:04:05"),
-		proposal.VotingEnd.Format("2006-01-02 15:04:05"))
+	log := proposalLog(networkConfig, proposal.ID, eventProposal)
+	log.WithFields(logrus.Fields{
+		"title":        proposal.Title,
+		"type":         cosmosgov.TypeName(proposal.Type),
+		"voting_start": proposal.VotingStart,
+		"voting_end":   proposal.VotingEnd,
+	}).Info("Checking proposal")
+	log.Debugf("Description: %s", truncateString(proposal.Description, 100))
+
+	s.observeProposal(proposal, networkConfig, now)
+
+	// Voting should be over, treat it as a data anomaly instead of a reminder
+	if s.isStale(proposal, now) {
+		if err := s.reportStaleProposal(proposal, networkConfig, now); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	firstSeen := s.markSeen(key, now)
+	s.recordDepositors(ctx, client, networkConfig, proposal)
+
+	// A start alert delayed past voting start by quiet hours or downtime is
+	// still sent, flagged as late, while voting is open. Only proposals seen
+	// in deposit period, when deposit period proposals are fetched, can
+	// have missed it
+	startMissed := !proposal.VotingStart.After(now) && proposal.VotingEnd.After(now) && firstSeen.Before(proposal.VotingStart)
 
 	// Check if we should notify about voting start
-	if proposal.VotingStart.After(now) {
+	if (proposal.VotingStart.After(now) || startMissed) && alertEnabled(networkConfig, types.AlertVotingStart) {
 		timeUntilStart := proposal.VotingStart.Sub(now)
 		hoursUntilStart := timeUntilStart.Hours()
 
-		if hoursUntilStart <= float64(s.config.Alerts.HoursBeforeStart) && hoursUntilStart > 0 {
+		threshold := time.Duration(s.config.Alerts.HoursBeforeStart) * time.Hour
+		due := startMissed || isAlertDue(timeUntilStart, threshold, s.checkInterval())
+		holdUntil := proposal.VotingStart
+		if startMissed {
+			holdUntil = proposal.VotingEnd
+		}
+		if due && s.store.WasSent(key, types.AlertVotingStart) {
+			log.Debug("Start notification already sent")
+		} else if due && s.holdForQuietHours(now, holdUntil) {
+			alertLog(log, eventAlertHeld, types.AlertVotingStart).Infof("Start notification held for quiet hours (%.1f hours until start)", hoursUntilStart)
+		} else if reason, held := s.reminderHeld(key, now); due && held {
+			alertLog(log, eventAlertHeld, types.AlertVotingStart).Infof("Start notification held, %s", reason)
+		} else if due {
+			lateness := s.alertLateness(proposal.VotingStart, threshold, firstSeen, now)
+			stage := fmt.Sprintf("will start voting %s", amounts.In(timeUntilStart))
+			if startMissed {
+				stage = fmt.Sprintf("started voting %s", amounts.Ago(timeUntilStart))
+			}
+
 			msg := types.NotificationMessage{
 				Title:       fmt.Sprintf("🚨 Governance Proposal Voting Starting Soon - %s", proposal.Network),
-				Content:     fmt.Sprintf("Proposal \"%s\" will start voting in %.1f hours.\n\nDescription: %s", proposal.Title, hoursUntilStart, proposal.Description),
+				Content:     fmt.Sprintf("%sProposal \"%s\" %s.\n\n%sDescription: %s%s%s", lateNotice(lateness), proposal.Title, stage, typeLine(proposal), s.alertDescription(proposal), relatedText(), s.notesText(key)),
 				Network:     proposal.Network,
 				ChainID:     networkConfig.ChainID,
 				ProposalID:  proposal.ID,
-				ExplorerURL: "",
+				ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+				Role:        types.RoleCommunity,
+				AlertType:   types.AlertVotingStart,
+				Severity:    s.alertSeverity(networkConfig, proposal, types.AlertVotingStart),
+				Details:     s.alertDetails(proposal, networkConfig.Name),
+				Proposal:    &proposal,
+			}
+			if startMissed {
+				msg.Title = fmt.Sprintf("🚨 Governance Proposal Voting Started - %s", proposal.Network)
 			}
 
-			if err := s.notifier.SendNotification(msg); err != nil {
+			if err := s.sendProposalAlert(msg); err != nil {
 				return fmt.Errorf("failed to send start notification: %w", err)
 			}
 
-			fmt.Printf("     ✅ Sent start notification (%.1f hours until start)\n", hoursUntilStart)
+			alertLog(log, eventAlertSent, types.AlertVotingStart).Infof("Sent start notification (%.1f hours until start)", hoursUntilStart)
+			s.recordAlertDelivered(proposal, networkConfig, types.AlertVotingStart, proposal.VotingStart.Add(-threshold), time.Now())
+			s.markSent(key, types.AlertVotingStart)
+			s.recordLateAlert(networkConfig, key, types.AlertVotingStart, lateness)
 		} else {
-			fmt.Printf("     ⏰ Start notification not needed (%.1f hours until start)\n", hoursUntilStart)
+			log.Debugf("Start notification not needed (%.1f hours until start)", hoursUntilStart)
 		}
 	}
 
 	// Check if we should notify about voting end
-	if proposal.VotingEnd.After(now) {
+	if proposal.VotingEnd.After(now) && alertEnabled(networkConfig, types.AlertVotingEnd) {
 		timeUntilEnd := proposal.VotingEnd.Sub(now)
 		hoursUntilEnd := timeUntilEnd.Hours()
 
-		if hoursUntilEnd <= float64(s.config.Alerts.HoursBeforeEnd) && hoursUntilEnd > 0 {
+		// Only the most urgent due reminder is sent, so a late start does
+		// not send every reminder at once
+		reminders := s.endReminders()
+		level := -1
+		for i, before := range reminders {
+			if isAlertDue(timeUntilEnd, before, s.checkInterval()) {
+				level = i
+			}
+		}
+		due := level >= 0
+		var threshold time.Duration
+		if due {
+			threshold = reminders[level]
+		}
+		if due && (s.store.WasSent(key, types.AlertVotingEnd) || s.store.WasSent(key, endReminderKey(threshold))) {
+			log.Debug("End notification already sent")
+		} else if due && s.holdForQuietHours(now, proposal.VotingEnd) {
+			alertLog(log, eventAlertHeld, types.AlertVotingEnd).Infof("End notification held for quiet hours (%.1f hours until end)", hoursUntilEnd)
+		} else if reason, held := s.reminderHeld(key, now); due && held {
+			alertLog(log, eventAlertHeld, types.AlertVotingEnd).Infof("End notification held, %s", reason)
+		} else if due {
+			lateness := s.alertLateness(proposal.VotingEnd, threshold, firstSeen, now)
+			chart, tally := s.currentTally(ctx, client, proposal, networkConfig)
 			msg := types.NotificationMessage{
 				Title:       fmt.Sprintf("⏰ Governance Proposal Voting Ending Soon - %s", proposal.Network),
-				Content:     fmt.Sprintf("Proposal \"%s\" will end voting in %.1f hours.\n\nDescription: %s", proposal.Title, hoursUntilEnd, proposal.Description),
+				Content:     fmt.Sprintf("%sProposal \"%s\" will end voting %s.\n\n%sDescription: %s%s%s", lateNotice(lateness), proposal.Title, amounts.In(timeUntilEnd), typeLine(proposal), s.alertDescription(proposal), relatedText(), s.notesText(key)),
 				Network:     proposal.Network,
 				ChainID:     networkConfig.ChainID,
 				ProposalID:  proposal.ID,
-				ExplorerURL: "",
+				ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+				Role:        types.RoleCommunity,
+				AlertType:   types.AlertVotingEnd,
+				Severity:    s.alertSeverity(networkConfig, proposal, types.AlertVotingEnd),
+				Chart:       chart,
+				Details:     s.alertDetails(proposal, networkConfig.Name),
+				Proposal:    &proposal,
+				Tally:       tally,
+			}
+
+			last := level == len(reminders)-1
+			if last && len(reminders) > 1 {
+				msg.Title = fmt.Sprintf("🚨 Last Call: Governance Proposal Voting Ending - %s", proposal.Network)
 			}
 
-			if err := s.notifier.SendNotification(msg); err != nil {
+			if err := s.sendProposalAlert(msg); err != nil {
 				return fmt.Errorf("failed to send end notification: %w", err)
 			}
 
-			fmt.Printf("     ✅ Sent end notification (%.1f hours until end)\n", hoursUntilEnd)
+			alertLog(log, eventAlertSent, types.AlertVotingEnd).WithField("reminder", formatDuration(threshold)).Infof("Sent end notification (%.1f hours until end)", hoursUntilEnd)
+			s.recordAlertDelivered(proposal, networkConfig, types.AlertVotingEnd, proposal.VotingEnd.Add(-threshold), time.Now())
+			s.markSent(key, endReminderKey(threshold))
+			if last {
+				s.markSent(key, types.AlertVotingEnd)
+			}
+			s.recordLateAlert(networkConfig, key, types.AlertVotingEnd, lateness)
 		} else {
-			fmt.Printf("     ⏰ End notification not needed (%.1f hours until end)\n", hoursUntilEnd)
+			log.Debugf("End notification not needed (%.1f hours until end)", hoursUntilEnd)
 		}
 	}
 
-	fmt.Printf("     ---\n")
+	s.checkVoteReminders(ctx, client, proposal, networkConfig, now)
+	s.checkWhaleVotes(ctx, client, proposal, networkConfig, now)
+	s.checkTallyUpdate(ctx, client, proposal, networkConfig, now)
+	s.checkUpgradeChecklist(ctx, client, proposal, networkConfig, now)
+	s.checkPagerDuty(ctx, client, proposal, networkConfig, now)
 	return nil
 }
 
-// truncateString truncates a string to the specified length
+// maxAlertMessages caps how many decoded messages an alert lists
+const maxAlertMessages = 3
+
+// typeLine returns the proposal type line of an alert followed by the
+// decoded messages, empty if the type is unknown
+func typeLine(proposal types.Proposal) string {
+	if proposal.Type == "" {
+		return ""
+	}
+
+	line := fmt.Sprintf("Type: %s\n", cosmosgov.TypeName(proposal.Type))
+	for i, msg := range proposal.Messages {
+		if i == maxAlertMessages {
+			line += fmt.Sprintf("• and %d more messages\n", len(proposal.Messages)-maxAlertMessages)
+			break
+		}
+		// Undecoded messages only repeat the type name
+		if strings.Contains(msg, ": ") {
+			line += fmt.Sprintf("• %s\n", msg)
+		}
+	}
+	return line
+}
+
+// truncateString truncates a string to the specified number of characters
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen] + "..."
+	return string(runes[:maxLen]) + "..."
 }
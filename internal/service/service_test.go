@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// TestCheckOnceConfigLiteral checks that a configuration built in code,
+// without the defaults of the config file loader, is checked with the
+// default concurrency and network timeout
+func TestCheckOnceConfigLiteral(t *testing.T) {
+	var listed atomic.Int32
+	now := time.Now().UTC()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cosmos/gov/v1/proposals" {
+			http.NotFound(w, r)
+			return
+		}
+		listed.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"proposals": [{"id": "1", "title": "Literal", "status": "PROPOSAL_STATUS_VOTING_PERIOD",
+			"voting_start_time": %q, "voting_end_time": %q}], "pagination": {}}`,
+			now.Add(-time.Hour).Format(time.RFC3339), now.Add(72*time.Hour).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	cfg := &types.Config{
+		Alerts: types.AlertConfig{HoursBeforeStart: 24, HoursBeforeEnd: 6, CheckIntervalMinutes: 60},
+		Networks: map[string]types.NetworkConfig{
+			"literal": {Name: "Literal", RestEndpoint: server.URL, ChainID: "literal-1"},
+		},
+	}
+	svc, err := NewService(cfg)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := svc.CheckOnce(ctx); err != nil {
+		t.Fatalf("CheckOnce() error = %v", err)
+	}
+	if listed.Load() == 0 {
+		t.Error("CheckOnce() did not list the proposals of the network")
+	}
+}
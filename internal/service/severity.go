@@ -0,0 +1,25 @@
+package service
+
+import (
+	"sort"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// alertSeverity returns the severity of an alert of alertType about a
+// proposal from the first matching severity policy, info if none matches.
+// Alerts carry no severity when no policy is configured
+func (s *Service) alertSeverity(networkConfig types.NetworkConfig, proposal types.Proposal, alertType string) string {
+	return governance.Severity(s.config.Alerts.SeverityPolicies, networkConfig.ChainID, proposal, alertType)
+}
+
+// sortByUrgency sorts the proposals of a network by the configured sort
+// keys, so alerts and digests list the most urgent first
+func (s *Service) sortByUrgency(networkName string, networkConfig types.NetworkConfig, proposals []types.Proposal) {
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return governance.MoreUrgent(s.config.Formatting.SortBy,
+			governance.ProposalUrgency(networkName, networkConfig.ChainID, proposals[i], s.config.Alerts.SeverityPolicies),
+			governance.ProposalUrgency(networkName, networkConfig.ChainID, proposals[j], s.config.Alerts.SeverityPolicies))
+	})
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// isStale reports whether a proposal is still reported in voting period
+// past its voting end time plus the configured grace period
+func (s *Service) isStale(proposal types.Proposal, now time.Time) bool {
+	grace := time.Duration(s.config.Alerts.StaleGraceMinutes) * time.Minute
+	return proposal.Status == "PROPOSAL_STATUS_VOTING_PERIOD" && now.After(proposal.VotingEnd.Add(grace))
+}
+
+// reportStaleProposal alerts once per proposal that the endpoint still
+// reports it in voting period after voting ended
+func (s *Service) reportStaleProposal(proposal types.Proposal, networkConfig types.NetworkConfig, now time.Time) error {
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+
+	s.mu.Lock()
+	reported := s.staleReported[key]
+	s.staleReported[key] = true
+	s.mu.Unlock()
+
+	if reported {
+		proposalLog(networkConfig, proposal.ID, eventProposal).Debug("Still stale, already reported")
+		return nil
+	}
+
+	overdue := now.Sub(proposal.VotingEnd)
+	msg := types.NotificationMessage{
+		Title: fmt.Sprintf("⚠️ Governance Data Anomaly - %s", proposal.Network),
+		Content: fmt.Sprintf("Proposal \"%s\" is still reported in voting period although voting ended %s (%s).\n\n"+
+			"The endpoint may be lagging or the chain may be halted. No voting reminders will be sent for this proposal until its status is updated.",
+			proposal.Title, amounts.Ago(overdue), proposal.VotingEnd.Format("2006-01-02 15:04:05 MST")),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleOps,
+	}
+
+	if err := s.notifier.SendNotification(msg); err != nil {
+		return fmt.Errorf("failed to send anomaly notification: %w", err)
+	}
+
+	proposalLog(networkConfig, proposal.ID, eventAlertSent).Warnf("Sent data anomaly notification (voting ended %.1f hours ago)", overdue.Hours())
+	return nil
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"time"
+)
+
+// stateRetention is how long sent alerts are remembered, well beyond any
+// voting period
+const stateRetention = 90 * 24 * time.Hour
+
+// markSent records a sent alert, warning if it could not be persisted
+func (s *Service) markSent(key, alertType string) {
+	if err := s.store.MarkSent(key, alertType, time.Now()); err != nil {
+		keyLog(key, eventStateFailed).WithField("alert_type", alertType).WithError(err).Warn("Failed to record sent alert")
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/notifications"
+	"governance-alerts-cosmos/internal/outbound"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// maxRecentAlerts is how many recent alerts the status keeps
+const maxRecentAlerts = 100
+
+// Status is a snapshot of the service for dashboards
+type Status struct {
+	Time         time.Time                     `json:"time"`
+	Networks     []NetworkStatus               `json:"networks"`
+	Channels     []notifications.ChannelHealth `json:"channels"`
+	Dependencies []outbound.Health             `json:"dependencies,omitempty"`
+	Alerts       []RecentAlert                 `json:"alerts"`
+}
+
+// NetworkStatus is the state of a monitored network as of its last check.
+// CheckedAt is the last successful check, LastError the last failed one
+// even after a recovery and NextCheck when the monitoring loop checks next
+type NetworkStatus struct {
+	Network     string            `json:"network"`
+	Name        string            `json:"name"`
+	ChainID     string            `json:"chain_id"`
+	Failing     bool              `json:"failing"`
+	CheckedAt   time.Time         `json:"checked_at,omitempty"`
+	LastError   string            `json:"last_error,omitempty"`
+	LastErrorAt time.Time         `json:"last_error_at,omitempty"`
+	NextCheck   time.Time         `json:"next_check,omitempty"`
+	Proposals   []TrackedProposal `json:"proposals"`
+}
+
+// TrackedProposal is a proposal in voting period of a network. Severity
+// is that of its voting end alert
+type TrackedProposal struct {
+	ID        uint64               `json:"id"`
+	Title     string               `json:"title"`
+	Status    string               `json:"status"`
+	VotingEnd time.Time            `json:"voting_end"`
+	Severity  string               `json:"severity,omitempty"`
+	Held      string               `json:"held,omitempty"`
+	Links     []types.TrackingLink `json:"links,omitempty"`
+}
+
+// RecentAlert is a proposal alert sent recently
+type RecentAlert struct {
+	Time       time.Time `json:"time"`
+	Network    string    `json:"network"`
+	ProposalID uint64    `json:"proposal_id"`
+	AlertType  string    `json:"alert_type"`
+	Title      string    `json:"title"`
+}
+
+// trackedNetwork is the result of the last successful check of a network
+type trackedNetwork struct {
+	proposals []types.Proposal
+	checkedAt time.Time
+}
+
+// recordTracked records the proposals of a network found by a check
+func (s *Service) recordTracked(networkName string, proposals []types.Proposal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracked[networkName] = trackedNetwork{proposals: proposals, checkedAt: time.Now()}
+}
+
+// recordRecentAlert remembers a sent proposal alert for the status
+func (s *Service) recordRecentAlert(msg types.NotificationMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recentAlerts = append(s.recentAlerts, RecentAlert{
+		Time:       time.Now(),
+		Network:    msg.Network,
+		ProposalID: msg.ProposalID,
+		AlertType:  msg.AlertType,
+		Title:      msg.Title,
+	})
+	if len(s.recentAlerts) > maxRecentAlerts {
+		s.recentAlerts = s.recentAlerts[len(s.recentAlerts)-maxRecentAlerts:]
+	}
+}
+
+// Status returns a snapshot of the tracked proposals, channel health and
+// recent alerts
+func (s *Service) Status() Status {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	now := time.Now()
+	status := Status{Time: now, Channels: s.notifier.ChannelHealth(), Dependencies: outbound.Healths()}
+
+	s.mu.Lock()
+	for name, networkConfig := range s.config.Networks {
+		tracked := s.tracked[name]
+		failure := s.checkFailures[name]
+		network := NetworkStatus{
+			Network:     name,
+			Name:        networkConfig.Name,
+			ChainID:     networkConfig.ChainID,
+			Failing:     s.failingNetworks[name],
+			CheckedAt:   tracked.checkedAt,
+			LastError:   failure.err,
+			LastErrorAt: failure.at,
+			NextCheck:   s.nextCheck,
+			Proposals:   make([]TrackedProposal, 0, len(tracked.proposals)),
+		}
+		for _, proposal := range tracked.proposals {
+			network.Proposals = append(network.Proposals, TrackedProposal{
+				ID:        proposal.ID,
+				Title:     proposal.Title,
+				Status:    proposal.Status,
+				VotingEnd: proposal.VotingEnd,
+				Severity:  s.alertSeverity(networkConfig, proposal, types.AlertVotingEnd),
+			})
+		}
+		status.Networks = append(status.Networks, network)
+	}
+	// Newest first
+	for i := len(s.recentAlerts) - 1; i >= 0; i-- {
+		status.Alerts = append(status.Alerts, s.recentAlerts[i])
+	}
+	s.mu.Unlock()
+
+	// Acknowledgements live in the store, which has its own lock
+	for i := range status.Networks {
+		network := &status.Networks[i]
+		for j := range network.Proposals {
+			key := proposalKey(network.ChainID, network.Proposals[j].ID)
+			network.Proposals[j].Held, _ = s.reminderHeld(key, now)
+			network.Proposals[j].Links = s.store.Links(key)
+		}
+		sort.Slice(network.Proposals, func(a, b int) bool {
+			return governance.MoreUrgent(s.config.Formatting.SortBy, network.Proposals[a].urgency(network.Network), network.Proposals[b].urgency(network.Network))
+		})
+	}
+
+	// Networks are grouped in the order of their most urgent proposal,
+	// those without proposals last by name
+	sort.Slice(status.Networks, func(i, j int) bool {
+		a, b := status.Networks[i], status.Networks[j]
+		if len(a.Proposals) == 0 || len(b.Proposals) == 0 {
+			if len(a.Proposals) != len(b.Proposals) {
+				return len(b.Proposals) == 0
+			}
+			return a.Network < b.Network
+		}
+		return governance.MoreUrgent(s.config.Formatting.SortBy, a.Proposals[0].urgency(a.Network), b.Proposals[0].urgency(b.Network))
+	})
+	return status
+}
+
+// urgency returns what a tracked proposal of network is sorted by
+func (p TrackedProposal) urgency(network string) governance.Urgency {
+	return governance.Urgency{Network: network, ID: p.ID, Deadline: p.VotingEnd, Severity: p.Severity}
+}
+
+// Acknowledge acknowledges the reminders of a proposal for good, or snoozes
+// them when snooze is positive, returning the confirmation
+func (s *Service) Acknowledge(networkName string, proposalID uint64, user string, snooze time.Duration) (string, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	_, networkConfig, ok := config.LookupNetwork(s.config, networkName)
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownNetwork, networkName)
+	}
+	return s.acknowledge(proposalKey(networkConfig.ChainID, proposalID), user, snooze)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/notifications"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// currentTally fetches the current tally of a proposal for its alert when
+// tally charts or message templates can show it. It returns the tally as a
+// PNG, nil if charts are disabled or on failure, and its options, nil when
+// nothing shows them or on failure
+func (s *Service) currentTally(ctx context.Context, client *governance.Client, proposal types.Proposal, networkConfig types.NetworkConfig) ([]byte, []types.TallyOption) {
+	charts := s.config.Notifications.TallyCharts && alertEnabled(networkConfig, types.AlertTally)
+	if (!charts && s.config.Notifications.TemplatesDir == "") || !s.tallyPolling() {
+		return nil, nil
+	}
+
+	tally, err := client.GetTally(ctx, proposal.ID)
+	if err != nil {
+		proposalLog(networkConfig, proposal.ID, eventFetchFailed).WithError(err).Warn("Failed to fetch tally")
+		return nil, nil
+	}
+	s.recordTally(networkConfig, proposal.ID, tally, time.Now())
+	return s.tallyChart(networkConfig, proposal, tally), governance.TallyOptions(networkConfig, tally)
+}
+
+// tallyChart renders a tally of a proposal as a PNG for its alert, nil if
+// tally charts are disabled or on failure
+func (s *Service) tallyChart(networkConfig types.NetworkConfig, proposal types.Proposal, tally *types.TallyResult) []byte {
+	if tally == nil || !s.config.Notifications.TallyCharts || !alertEnabled(networkConfig, types.AlertTally) {
+		return nil
+	}
+
+	chart, err := notifications.RenderTallyChart(fmt.Sprintf("%s #%d", proposal.Network, proposal.ID), governance.TallyOptions(networkConfig, tally))
+	if err != nil {
+		proposalLog(networkConfig, proposal.ID, eventAlertFailed).WithError(err).Warn("Failed to render tally chart")
+		return nil
+	}
+	return chart
+}
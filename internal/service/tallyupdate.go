@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// tallyUpdateKey returns the sent alert key of a tally update milestone
+func tallyUpdateKey(milestone string) string {
+	return types.AlertTally + ":" + milestone
+}
+
+// dueTallyMilestones returns the tally update milestones of a proposal in
+// voting period reached by now: each share of the voting period left and
+// each interval elapsed since voting started
+func (s *Service) dueTallyMilestones(proposal types.Proposal, now time.Time) []string {
+	updates := s.config.Alerts.TallyUpdates
+	period := proposal.VotingEnd.Sub(proposal.VotingStart)
+	if period <= 0 || proposal.VotingStart.After(now) || !proposal.VotingEnd.After(now) {
+		return nil
+	}
+
+	var milestones []string
+	remaining := float64(proposal.VotingEnd.Sub(now)) / float64(period) * 100
+	for _, percent := range updates.RemainingPercent {
+		if remaining <= float64(percent) {
+			milestones = append(milestones, fmt.Sprintf("%d%%", percent))
+		}
+	}
+	if updates.IntervalHours > 0 {
+		interval := time.Duration(updates.IntervalHours) * time.Hour
+		for elapsed := interval; elapsed <= now.Sub(proposal.VotingStart); elapsed += interval {
+			milestones = append(milestones, fmt.Sprintf("%dh", int(elapsed.Hours())))
+		}
+	}
+	return milestones
+}
+
+// checkTallyUpdate sends a snapshot of the tally of a proposal in voting
+// period when it reaches a tally update milestone. Milestones reached
+// together, such as those passed while the service was down, send a single
+// snapshot
+func (s *Service) checkTallyUpdate(ctx context.Context, client *governance.Client, proposal types.Proposal, networkConfig types.NetworkConfig, now time.Time) {
+	if !alertEnabled(networkConfig, types.AlertTally) || !s.tallyPolling() {
+		return
+	}
+
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	var pending []string
+	for _, milestone := range s.dueTallyMilestones(proposal, now) {
+		if !s.store.WasSent(key, tallyUpdateKey(milestone)) {
+			pending = append(pending, milestone)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+	log := proposalAlertLog(networkConfig, proposal.ID, eventAlertHeld, types.AlertTally)
+	if s.holdForQuietHours(now, proposal.VotingEnd) {
+		log.Info("Tally update held for quiet hours")
+		return
+	}
+	if reason, held := s.reminderHeld(key, now); held {
+		log.Infof("Tally update held, %s", reason)
+		return
+	}
+
+	tally, err := client.GetTally(ctx, proposal.ID)
+	if err != nil {
+		proposalLog(networkConfig, proposal.ID, eventFetchFailed).WithError(err).Warn("Failed to fetch tally for update")
+		return
+	}
+	s.recordTally(networkConfig, proposal.ID, tally, now)
+
+	timeUntilEnd := proposal.VotingEnd.Sub(now)
+	remaining := float64(timeUntilEnd) / float64(proposal.VotingEnd.Sub(proposal.VotingStart)) * 100
+	content := fmt.Sprintf("Proposal \"%s\" has %.0f%% of its voting period left, voting ends %s (%s).\n\nCurrent tally:\n%s",
+		proposal.Title, remaining, amounts.In(timeUntilEnd), proposal.VotingEnd.Format("2006-01-02 15:04 MST"), formatTallyShares(networkConfig, tally))
+	content += s.tallyOutlook(ctx, client, networkConfig, tally)
+	content += s.paramsNote(networkConfig.ChainID, proposal.ID)
+	content += s.bondedNote(networkConfig.ChainID, proposal.ID, tally)
+
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("📊 Governance Proposal Tally Update - %s", proposal.Network),
+		Content:     content + s.notesText(key),
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleCommunity,
+		AlertType:   types.AlertTally,
+		Severity:    s.alertSeverity(networkConfig, proposal, types.AlertTally),
+		Chart:       s.tallyChart(networkConfig, proposal, tally),
+		Proposal:    &proposal,
+		Tally:       governance.TallyOptions(networkConfig, tally),
+	}
+	if err := s.sendProposalAlert(msg); err != nil {
+		proposalAlertLog(networkConfig, proposal.ID, eventAlertFailed, types.AlertTally).WithError(err).Error("Failed to send tally update")
+		return
+	}
+
+	proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertTally).WithField("milestones", pending).Infof("Sent tally update (%.0f%% of voting left)", remaining)
+	for _, milestone := range pending {
+		s.markSent(key, tallyUpdateKey(milestone))
+	}
+}
+
+// tallyOutlook describes turnout against quorum and whether a tally would
+// pass if voting ended now, empty without the gov params of the network
+func (s *Service) tallyOutlook(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, tally *types.TallyResult) string {
+	params := s.govParams(ctx, client, networkConfig)
+	if params == nil {
+		return ""
+	}
+	pool, err := client.GetStakingPool(ctx)
+	if err != nil {
+		networkLog(networkConfig, eventFetchFailed).WithError(err).Warn("Failed to fetch staking pool")
+		return ""
+	}
+	bonded := parseAmount(pool.BondedTokens)
+	if bonded <= 0 {
+		return ""
+	}
+
+	voted := tallyTotal(tally)
+	turnout := voted / bonded
+	quorum := parseAmount(params.Quorum)
+	outlook := fmt.Sprintf("\n\nTurnout: %.2f%% of bonded stake (quorum %s)", turnout*100, formatFraction(params.Quorum))
+
+	// Abstain counts toward quorum but not toward the threshold
+	nonAbstain := voted - parseAmount(tally.Abstain)
+	switch {
+	case turnout < quorum:
+		outlook += "\n⚠️ Below quorum, the proposal would fail if voting ended now"
+	case voted > 0 && parseAmount(tally.NoWithVeto)/voted > parseAmount(params.VetoThreshold):
+		outlook += "\n⛔ Over the veto threshold, the proposal would be vetoed if voting ended now"
+	case nonAbstain > 0 && parseAmount(tally.Yes)/nonAbstain > parseAmount(params.Threshold):
+		outlook += "\n✅ On track to pass"
+	default:
+		outlook += "\n❌ Below the pass threshold, the proposal would be rejected if voting ended now"
+	}
+	return outlook
+}
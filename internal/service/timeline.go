@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// recordTimeline appends an event to the timeline of a proposal, warning
+// if it could not be persisted
+func (s *Service) recordTimeline(key, kind, detail string, at time.Time) {
+	event := types.TimelineEvent{Time: at, Kind: kind, Detail: detail}
+	if err := s.store.AddTimelineEvent(key, event); err != nil {
+		keyLog(key, eventStateFailed).WithField("kind", kind).WithError(err).Warn("Failed to record timeline event")
+	}
+	s.exportTimelineEvent(key, event)
+}
+
+// recordStatuses records the status of each proposal in its timeline when
+// it differs from the last one recorded
+func (s *Service) recordStatuses(networkConfig types.NetworkConfig, proposals []types.Proposal, now time.Time) {
+	for _, proposal := range proposals {
+		s.recordStatus(proposalKey(networkConfig.ChainID, proposal.ID), proposal.Status, now)
+	}
+}
+
+// recordStatus records the status of a proposal in its timeline when it
+// changed since the last one recorded
+func (s *Service) recordStatus(key, status string, now time.Time) {
+	detail := governance.StatusLabel(status)
+	if last, ok := s.store.LastTimelineEvent(key, types.TimelineStatus); ok && last.Detail == detail {
+		return
+	}
+	s.recordTimeline(key, types.TimelineStatus, detail, now)
+}
+
+// removedFromChain is the timeline status of proposals that vanished from
+// chain, such as cancelled ones
+const removedFromChain = "Removed from chain"
+
+// recordRemoved records in its timeline that a proposal vanished from chain
+func (s *Service) recordRemoved(networkConfig types.NetworkConfig, proposalID uint64, now time.Time) {
+	key := proposalKey(networkConfig.ChainID, proposalID)
+	if last, ok := s.store.LastTimelineEvent(key, types.TimelineStatus); ok && last.Detail == removedFromChain {
+		return
+	}
+	s.recordTimeline(key, types.TimelineStatus, removedFromChain, now)
+}
+
+// recordTally records a tally snapshot in the timeline of a proposal,
+// unless the tally did not move since the last snapshot
+func (s *Service) recordTally(networkConfig types.NetworkConfig, proposalID uint64, tally *types.TallyResult, now time.Time) {
+	if tally == nil {
+		return
+	}
+	key := proposalKey(networkConfig.ChainID, proposalID)
+	detail := formatTallyShares(networkConfig, tally)
+	if last, ok := s.store.LastTimelineEvent(key, types.TimelineTally); ok && last.Detail == detail {
+		return
+	}
+	s.recordTimeline(key, types.TimelineTally, detail, now)
+	s.exportTally(networkConfig, proposalID, tally, now)
+}
+
+// recordAlertTimeline records the delivery of a proposal alert, or its
+// failure, in the proposal's timeline
+func (s *Service) recordAlertTimeline(msg types.NotificationMessage, err error) {
+	if msg.AlertType == "" || msg.ProposalID == 0 {
+		return
+	}
+	detail := fmt.Sprintf("%s alert sent", msg.AlertType)
+	if err != nil {
+		detail = fmt.Sprintf("%s alert failed: %v", msg.AlertType, err)
+	}
+	s.recordTimeline(proposalKey(msg.ChainID, msg.ProposalID), types.TimelineAlert, detail, time.Now())
+}
+
+// Timeline returns what the service observed and did about a proposal,
+// oldest first
+func (s *Service) Timeline(networkName string, proposalID uint64) ([]types.TimelineEvent, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	_, networkConfig, ok := config.LookupNetwork(s.config, networkName)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownNetwork, networkName)
+	}
+	timeline := s.store.Timeline(proposalKey(networkConfig.ChainID, proposalID))
+	if timeline == nil {
+		timeline = []types.TimelineEvent{}
+	}
+	return timeline, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// maxClockSkew is the host/endpoint clock difference above which the
+// endpoint's clock is used for threshold math
+const maxClockSkew = 30 * time.Second
+
+// isAlertDue reports whether an alert for an event timeUntil away is due.
+// It is due once inside the threshold, or earlier if the event would pass
+// before the next check because the window is shorter than the interval.
+// Events in the past are never due
+func isAlertDue(timeUntil, threshold, interval time.Duration) bool {
+	if timeUntil <= 0 {
+		return false
+	}
+	return timeUntil <= threshold || timeUntil <= interval
+}
+
+// checkInterval returns the configured interval between checks
+func (s *Service) checkInterval() time.Duration {
+	return time.Duration(s.config.Alerts.CheckIntervalMinutes) * time.Minute
+}
+
+// endReminders returns how long before voting ends the end reminders are
+// sent, earliest first: reminder_hours_before_end, or hours_before_end
+// alone when unset
+func (s *Service) endReminders() []time.Duration {
+	hours := s.config.Alerts.ReminderHoursBeforeEnd
+	if len(hours) == 0 {
+		hours = []int{s.config.Alerts.HoursBeforeEnd}
+	}
+	hours = append([]int(nil), hours...)
+	sort.Sort(sort.Reverse(sort.IntSlice(hours)))
+
+	var reminders []time.Duration
+	for i, h := range hours {
+		if i > 0 && h == hours[i-1] {
+			continue
+		}
+		reminders = append(reminders, time.Duration(h)*time.Hour)
+	}
+	return reminders
+}
+
+// endReminderKey identifies an end reminder in the store. The plain voting
+// end alert type records the last reminder, so state written before
+// reminders were configurable and proposals tracked silently on first run
+// get none
+func endReminderKey(before time.Duration) string {
+	return fmt.Sprintf("%s:%dh", types.AlertVotingEnd, int(before.Hours()))
+}
+
+// now returns the current time corrected for the clock skew observed
+// between this host and the network's endpoint
+func (s *Service) now(client *governance.Client) time.Time {
+	now := time.Now()
+	skew := client.ClockSkew()
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		eventLog(eventCheck).WithField("skew", skew.Round(time.Second).String()).Warn("Clock skew detected against endpoint, using endpoint time")
+		return now.Add(skew)
+	}
+	return now
+}
+
+// quietHours represents a daily window in a time zone during which
+// non-urgent alerts are held back
+type quietHours struct {
+	startMinute int
+	endMinute   int
+	location    *time.Location
+}
+
+// parseQuietHours parses the quiet hours configuration, returning nil if
+// quiet hours are disabled
+func parseQuietHours(config types.QuietHoursConfig) (*quietHours, error) {
+	if config.Start == "" && config.End == "" {
+		return nil, nil
+	}
+
+	start, err := parseClock(config.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	end, err := parseClock(config.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+
+	location := time.UTC
+	if config.Timezone != "" {
+		location, err = time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours timezone: %w", err)
+		}
+	}
+
+	return &quietHours{startMinute: start, endMinute: end, location: location}, nil
+}
+
+// parseClock parses a HH:MM wall clock time into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether t falls inside quiet hours. Wall clock minutes
+// are compared in the configured zone so DST shifts move the window with
+// local time instead of by a fixed offset
+func (q *quietHours) contains(t time.Time) bool {
+	local := t.In(q.location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if q.startMinute == q.endMinute {
+		return false
+	}
+	if q.startMinute < q.endMinute {
+		return minute >= q.startMinute && minute < q.endMinute
+	}
+	// Window wraps around midnight
+	return minute >= q.startMinute || minute < q.endMinute
+}
+
+// end returns when the quiet hours containing t end. time.Date normalizes
+// wall clock times that fall in a DST gap, so the end is always valid
+func (q *quietHours) end(t time.Time) time.Time {
+	local := t.In(q.location)
+	end := time.Date(local.Year(), local.Month(), local.Day(), q.endMinute/60, q.endMinute%60, 0, 0, q.location)
+	if !end.After(local) {
+		end = time.Date(local.Year(), local.Month(), local.Day()+1, q.endMinute/60, q.endMinute%60, 0, 0, q.location)
+	}
+	return end
+}
+
+// holdForQuietHours reports whether an alert about an event at eventTime
+// should be held back because of quiet hours. Alerts whose event would pass
+// before quiet hours end are never held
+func (s *Service) holdForQuietHours(now, eventTime time.Time) bool {
+	if s.quietHours == nil || !s.quietHours.contains(now) {
+		return false
+	}
+	return eventTime.After(s.quietHours.end(now))
+}
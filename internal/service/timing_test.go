@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+func TestIsAlertDue(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeUntil time.Duration
+		threshold time.Duration
+		interval  time.Duration
+		want      bool
+	}{
+		{"event passed", -time.Minute, 6 * time.Hour, time.Hour, false},
+		{"event now", 0, 6 * time.Hour, time.Hour, false},
+		{"event now without threshold", 0, 0, 0, false},
+		{"outside threshold and interval", 7 * time.Hour, 6 * time.Hour, time.Hour, false},
+		{"just outside threshold", 6*time.Hour + time.Second, 6 * time.Hour, time.Hour, false},
+		{"at threshold", 6 * time.Hour, 6 * time.Hour, time.Hour, true},
+		{"inside threshold", 3 * time.Hour, 6 * time.Hour, time.Hour, true},
+		{"just before event", time.Second, 6 * time.Hour, time.Hour, true},
+		{"outside threshold, inside interval", 90 * time.Minute, time.Hour, 2 * time.Hour, true},
+		{"at interval beyond threshold", 2 * time.Hour, time.Hour, 2 * time.Hour, true},
+		{"outside interval beyond threshold", 2*time.Hour + time.Second, time.Hour, 2 * time.Hour, false},
+		{"zero threshold, inside interval", 30 * time.Minute, 0, time.Hour, true},
+		{"zero threshold and interval", 30 * time.Minute, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAlertDue(tt.timeUntil, tt.threshold, tt.interval); got != tt.want {
+				t.Errorf("isAlertDue(%s, %s, %s) = %v, want %v", tt.timeUntil, tt.threshold, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+// berlin has DST switches on 2024-03-31 (02:00 CET to 03:00 CEST) and
+// 2024-10-27 (03:00 CEST back to 02:00 CET)
+var berlin = mustLoadLocation("Europe/Berlin")
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+func mustQuietHours(t *testing.T, start, end string, location *time.Location) *quietHours {
+	t.Helper()
+	q, err := parseQuietHours(types.QuietHoursConfig{Start: start, End: end, Timezone: location.String()})
+	if err != nil {
+		t.Fatalf("parseQuietHours(%s, %s) error = %v", start, end, err)
+	}
+	return q
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		location   *time.Location
+		at         time.Time
+		want       bool
+	}{
+		{"daytime window, inside", "09:00", "17:00", time.UTC, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), true},
+		{"daytime window, at start", "09:00", "17:00", time.UTC, time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC), true},
+		{"daytime window, at end", "09:00", "17:00", time.UTC, time.Date(2024, 6, 1, 17, 0, 0, 0, time.UTC), false},
+		{"daytime window, before", "09:00", "17:00", time.UTC, time.Date(2024, 6, 1, 8, 59, 0, 0, time.UTC), false},
+		{"wraps midnight, evening", "22:00", "07:00", time.UTC, time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC), true},
+		{"wraps midnight, at midnight", "22:00", "07:00", time.UTC, time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC), true},
+		{"wraps midnight, morning", "22:00", "07:00", time.UTC, time.Date(2024, 6, 2, 6, 59, 0, 0, time.UTC), true},
+		{"wraps midnight, at end", "22:00", "07:00", time.UTC, time.Date(2024, 6, 2, 7, 0, 0, 0, time.UTC), false},
+		{"wraps midnight, afternoon", "22:00", "07:00", time.UTC, time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC), false},
+		{"empty window", "08:00", "08:00", time.UTC, time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), false},
+		// 06:30 CEST is 04:30 UTC in summer, outside in UTC terms
+		{"zone, summer morning", "22:00", "07:00", berlin, time.Date(2024, 6, 2, 4, 30, 0, 0, time.UTC), true},
+		{"zone, summer after end", "22:00", "07:00", berlin, time.Date(2024, 6, 2, 5, 0, 0, 0, time.UTC), false},
+		// The window follows the wall clock across both DST switches
+		{"spring forward, before end", "22:00", "07:00", berlin, time.Date(2024, 3, 31, 4, 59, 0, 0, time.UTC), true},
+		{"spring forward, at end", "22:00", "07:00", berlin, time.Date(2024, 3, 31, 5, 0, 0, 0, time.UTC), false},
+		{"fall back, before end", "22:00", "07:00", berlin, time.Date(2024, 10, 27, 5, 59, 0, 0, time.UTC), true},
+		{"fall back, at end", "22:00", "07:00", berlin, time.Date(2024, 10, 27, 6, 0, 0, 0, time.UTC), false},
+		// 02:30 does not exist on 2024-03-31, the clock reads 03:30 CEST
+		{"spring forward gap", "02:00", "03:00", berlin, time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC), false},
+		// 02:30 happens twice on 2024-10-27, both are quiet
+		{"fall back, first 02:30", "02:00", "03:00", berlin, time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC), true},
+		{"fall back, second 02:30", "02:00", "03:00", berlin, time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := mustQuietHours(t, tt.start, tt.end, tt.location)
+			if got := q.contains(tt.at); got != tt.want {
+				t.Errorf("contains(%s) = %v, want %v", tt.at.In(tt.location), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHoursEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		location   *time.Location
+		at         time.Time
+		want       time.Time
+	}{
+		{"same day", "00:00", "07:00", time.UTC, time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 7, 0, 0, 0, time.UTC)},
+		{"wraps midnight, next day", "22:00", "07:00", time.UTC, time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC), time.Date(2024, 6, 2, 7, 0, 0, 0, time.UTC)},
+		{"wraps midnight, after midnight", "22:00", "07:00", time.UTC, time.Date(2024, 6, 2, 1, 0, 0, 0, time.UTC), time.Date(2024, 6, 2, 7, 0, 0, 0, time.UTC)},
+		{"wraps midnight, month end", "22:00", "07:00", time.UTC, time.Date(2024, 6, 30, 22, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 7, 0, 0, 0, time.UTC)},
+		// The night before the switch is an hour shorter, 07:00 CEST is 05:00 UTC
+		{"spring forward", "22:00", "07:00", berlin, time.Date(2024, 3, 30, 22, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 5, 0, 0, 0, time.UTC)},
+		// And an hour longer in autumn, 07:00 CET is 06:00 UTC
+		{"fall back", "22:00", "07:00", berlin, time.Date(2024, 10, 26, 21, 0, 0, 0, time.UTC), time.Date(2024, 10, 27, 6, 0, 0, 0, time.UTC)},
+		// An end in the DST gap moves to the same offset after the switch
+		{"end in gap", "01:00", "02:30", berlin, time.Date(2024, 3, 31, 0, 30, 0, 0, time.UTC), time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := mustQuietHours(t, tt.start, tt.end, tt.location)
+			if got := q.end(tt.at); !got.Equal(tt.want) {
+				t.Errorf("end(%s) = %s, want %s", tt.at.In(tt.location), got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldForQuietHours(t *testing.T) {
+	s := &Service{quietHours: mustQuietHours(t, "22:00", "07:00", time.UTC)}
+	now := time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)
+
+	if !s.holdForQuietHours(now, now.Add(24*time.Hour)) {
+		t.Error("holdForQuietHours() = false for an event after quiet hours, want true")
+	}
+	if s.holdForQuietHours(now, now.Add(4*time.Hour)) {
+		t.Error("holdForQuietHours() = true for an event before quiet hours end, want false")
+	}
+	if s.holdForQuietHours(now.Add(-2*time.Hour), now.Add(24*time.Hour)) {
+		t.Error("holdForQuietHours() = true outside quiet hours, want false")
+	}
+}
+
+// TestNowClockSkew checks that the endpoint's clock is used once it is off
+// by more than maxClockSkew
+func TestNowClockSkew(t *testing.T) {
+	tests := []struct {
+		name string
+		skew time.Duration
+		want time.Duration
+	}{
+		{"in sync", 0, 0},
+		{"small skew ignored", 20 * time.Second, 0},
+		{"endpoint ahead", 5 * time.Minute, 5 * time.Minute},
+		{"endpoint behind", -5 * time.Minute, -5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Date", time.Now().Add(tt.skew).UTC().Format(http.TimeFormat))
+				fmt.Fprint(w, `{"proposals": [], "pagination": {}}`)
+			}))
+			defer server.Close()
+
+			client, err := governance.NewClient(types.NetworkConfig{Name: "Skewed", RestEndpoint: server.URL, ChainID: "skewed-1"})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			if _, err := client.GetActiveProposals(context.Background()); err != nil {
+				t.Fatalf("GetActiveProposals() error = %v", err)
+			}
+
+			// Date headers have a resolution of a second
+			offset := (&Service{}).now(client).Sub(time.Now())
+			if diff := offset - tt.want; diff < -2*time.Second || diff > 2*time.Second {
+				t.Errorf("now() is %s off the local clock, want %s", offset.Round(time.Second), tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertLateness(t *testing.T) {
+	s := &Service{config: &types.Config{Alerts: types.AlertConfig{CheckIntervalMinutes: 60}}}
+	event := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	threshold := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		firstSeen time.Time
+		now       time.Time
+		want      time.Duration
+	}{
+		{"on time", event.Add(-72 * time.Hour), event.Add(-threshold), 0},
+		{"within a check interval", event.Add(-72 * time.Hour), event.Add(-threshold + 59*time.Minute), 0},
+		{"held back", event.Add(-72 * time.Hour), event.Add(-threshold + 5*time.Hour), 5 * time.Hour},
+		// A proposal first seen inside the threshold is not late for it
+		{"first seen inside threshold", event.Add(-6 * time.Hour), event.Add(-6 * time.Hour), 0},
+		{"late after first sighting", event.Add(-6 * time.Hour), event.Add(-3 * time.Hour), 3 * time.Hour},
+		// A start missed during downtime is late from its intended time
+		{"missed start", event.Add(-72 * time.Hour), event.Add(2 * time.Hour), 26 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.alertLateness(event, threshold, tt.firstSeen, tt.now); got != tt.want {
+				t.Errorf("alertLateness() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// timingNetwork serves one proposal in voting period on a fake LCD and
+// records the alerts the service posts to a webhook
+type timingNetwork struct {
+	service *Service
+	key     string
+	mu      sync.Mutex
+	alerts  []types.NotificationMessage
+}
+
+func newTimingNetwork(t *testing.T, votingStart, votingEnd time.Time) *timingNetwork {
+	t.Helper()
+	lcd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := r.URL.Query().Get("proposal_status")
+		if r.URL.Path != "/cosmos/gov/v1/proposals" || (status != "" && status != "PROPOSAL_STATUS_VOTING_PERIOD") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"proposals": [{"id": "7", "title": "Timing", "status": "PROPOSAL_STATUS_VOTING_PERIOD",
+			"voting_start_time": %q, "voting_end_time": %q}], "pagination": {}}`,
+			votingStart.UTC().Format(time.RFC3339), votingEnd.UTC().Format(time.RFC3339))
+	}))
+	t.Cleanup(lcd.Close)
+
+	network := &timingNetwork{key: proposalKey("timing-1", 7)}
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			Data types.NotificationMessage `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid webhook payload: %v", err)
+			return
+		}
+		network.mu.Lock()
+		network.alerts = append(network.alerts, event.Data)
+		network.mu.Unlock()
+	}))
+	t.Cleanup(webhook.Close)
+
+	service, err := NewService(&types.Config{
+		Alerts: types.AlertConfig{HoursBeforeStart: 24, HoursBeforeEnd: 6, CheckIntervalMinutes: 60},
+		Networks: map[string]types.NetworkConfig{
+			"timing": {Name: "Timing", RestEndpoint: lcd.URL, ChainID: "timing-1"},
+		},
+		Notifications: types.NotificationConfig{
+			Webhooks: []types.WebhookConfig{{URL: webhook.URL, AlertTypes: []string{types.AlertVotingStart, types.AlertVotingEnd}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	network.service = service
+	return network
+}
+
+// startAlerts returns the voting start alerts sent so far
+func (n *timingNetwork) startAlerts() []types.NotificationMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var alerts []types.NotificationMessage
+	for _, alert := range n.alerts {
+		if alert.AlertType == types.AlertVotingStart {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+func TestStartAlertTiming(t *testing.T) {
+	now := time.Now()
+
+	t.Run("missed start", func(t *testing.T) {
+		// Seen in deposit period, then voting started while the alert was held
+		network := newTimingNetwork(t, now.Add(-2*time.Hour), now.Add(72*time.Hour))
+		if _, err := network.service.store.MarkSeen(network.key, now.Add(-30*time.Hour)); err != nil {
+			t.Fatalf("MarkSeen() error = %v", err)
+		}
+		if err := network.service.CheckOnce(context.Background()); err != nil {
+			t.Fatalf("CheckOnce() error = %v", err)
+		}
+
+		alerts := network.startAlerts()
+		if len(alerts) != 1 {
+			t.Fatalf("sent %d start alerts, want 1", len(alerts))
+		}
+		if !strings.Contains(alerts[0].Title, "Voting Started") {
+			t.Errorf("start alert title = %q, want it to say voting started", alerts[0].Title)
+		}
+		if !strings.Contains(alerts[0].Content, "late") {
+			t.Errorf("start alert content = %q, want it flagged late", alerts[0].Content)
+		}
+
+		// The next check does not repeat it
+		if err := network.service.CheckOnce(context.Background()); err != nil {
+			t.Fatalf("CheckOnce() error = %v", err)
+		}
+		if got := len(network.startAlerts()); got != 1 {
+			t.Errorf("sent %d start alerts after a second check, want 1", got)
+		}
+	})
+
+	t.Run("first seen after start", func(t *testing.T) {
+		// Never seen before voting started, so no start alert can be due
+		network := newTimingNetwork(t, now.Add(-2*time.Hour), now.Add(72*time.Hour))
+		if err := network.service.CheckOnce(context.Background()); err != nil {
+			t.Fatalf("CheckOnce() error = %v", err)
+		}
+		if alerts := network.startAlerts(); len(alerts) != 0 {
+			t.Errorf("sent start alerts %v at the first check after voting started, want none", alerts)
+		}
+	})
+
+	t.Run("first seen inside threshold", func(t *testing.T) {
+		// Found at the first check two hours before voting starts, on time
+		network := newTimingNetwork(t, now.Add(2*time.Hour), now.Add(74*time.Hour))
+		if err := network.service.CheckOnce(context.Background()); err != nil {
+			t.Fatalf("CheckOnce() error = %v", err)
+		}
+
+		alerts := network.startAlerts()
+		if len(alerts) != 1 {
+			t.Fatalf("sent %d start alerts, want 1", len(alerts))
+		}
+		if strings.Contains(alerts[0].Content, "late") {
+			t.Errorf("start alert content = %q, want it on time", alerts[0].Content)
+		}
+	})
+}
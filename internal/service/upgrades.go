@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// upgradeTimelinePin names the pinned upgrade timeline in the state
+const upgradeTimelinePin = "upgrade_timeline"
+
+// upgradeEstimateResolution is the precision at which estimate shifts
+// regenerate the timeline, finer shifts are noise between checks
+const upgradeEstimateResolution = 10 * time.Minute
+
+// updateUpgradeTimeline regenerates the pinned upgrade timeline in the ops
+// channels when an upgrade is scheduled or done, or an estimate shifts.
+// Networks that fail to answer keep their last known upgrade
+func (s *Service) updateUpgradeTimeline(ctx context.Context, now time.Time) {
+	if !s.config.Reports.UpgradeTimeline {
+		return
+	}
+
+	for name, client := range s.clients {
+		chainID := s.config.Networks[name].ChainID
+		estimate, err := client.ScheduledUpgrade(ctx)
+		if err != nil {
+			networkLog(s.config.Networks[name], eventFetchFailed).WithError(err).Warn("Failed to fetch scheduled upgrade")
+			continue
+		}
+		s.mu.Lock()
+		if estimate == nil {
+			delete(s.upgrades, chainID)
+		} else {
+			s.upgrades[chainID] = *estimate
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	upgrades := make([]types.UpgradeEstimate, 0, len(s.upgrades))
+	for _, upgrade := range s.upgrades {
+		upgrades = append(upgrades, upgrade)
+	}
+	s.mu.Unlock()
+	sort.Slice(upgrades, func(i, j int) bool {
+		return upgrades[i].Time.Before(upgrades[j].Time)
+	})
+
+	// A timeline pinned before a restart is refreshed once, nothing is
+	// pinned while no upgrade was ever scheduled
+	signature := upgradeSignature(upgrades)
+	pinned := s.store.PinnedMessages(upgradeTimelinePin)
+	if s.upgradeTimelineSynced && signature == s.upgradeSignature {
+		return
+	}
+	if len(upgrades) == 0 && len(pinned) == 0 {
+		return
+	}
+
+	msg := types.NotificationMessage{
+		Title:       "🛠️ Upcoming Upgrades",
+		Content:     formatUpgradeTimeline(upgrades, now),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleOps,
+		AlertType:   types.AlertUpgradeCountdown,
+	}
+	refs, err := s.notifier.UpdatePinned(pinned, msg)
+	if len(refs) > 0 {
+		if err := s.store.SetPinnedMessages(upgradeTimelinePin, refs); err != nil {
+			eventLog(eventStateFailed).WithError(err).Warn("Failed to record upgrade timeline message")
+		}
+	}
+	if err != nil {
+		eventLog(eventAlertFailed).WithError(err).Warn("Failed to update upgrade timeline")
+		return
+	}
+	s.upgradeSignature = signature
+	s.upgradeTimelineSynced = true
+}
+
+// upgradeSignature identifies the content of the timeline, with estimates
+// rounded so that only real shifts change it
+func upgradeSignature(upgrades []types.UpgradeEstimate) string {
+	parts := make([]string, 0, len(upgrades))
+	for _, upgrade := range upgrades {
+		parts = append(parts, fmt.Sprintf("%s|%s|%d|%d", upgrade.ChainID, upgrade.Name, upgrade.Height, upgrade.Time.Round(upgradeEstimateResolution).Unix()))
+	}
+	return strings.Join(parts, ";")
+}
+
+// formatUpgradeTimeline formats the upgrades scheduled across networks,
+// soonest first
+func formatUpgradeTimeline(upgrades []types.UpgradeEstimate, now time.Time) string {
+	if len(upgrades) == 0 {
+		return fmt.Sprintf("No upgrades scheduled on monitored networks.\n\nUpdated %s.", now.UTC().Format("2006-01-02 15:04 MST"))
+	}
+
+	var b strings.Builder
+	for _, upgrade := range upgrades {
+		fmt.Fprintf(&b, "• %s (%s): %s at height %d\n", upgrade.Network, upgrade.ChainID, upgrade.Name, upgrade.Height)
+		if upgrade.Time.After(now) {
+			fmt.Fprintf(&b, "  ~%s (%s)\n", upgrade.Time.UTC().Format("2006-01-02 15:04 MST"), amounts.In(upgrade.Time.Sub(now)))
+		} else {
+			fmt.Fprintf(&b, "  Upgrade height reached, waiting for the upgrade\n")
+		}
+	}
+	fmt.Fprintf(&b, "\nUpdated %s. Estimates extrapolate recent block times.", now.UTC().Format("2006-01-02 15:04 MST"))
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+)
+
+// voteReminderLevels are the escalating reminders sent to voters that have
+// not voted, by time left until voting ends
+var voteReminderLevels = []struct {
+	before time.Duration
+	title  string
+}{
+	{24 * time.Hour, "🗳️ You Have Not Voted"},
+	{6 * time.Hour, "⚠️ YOU HAVE NOT VOTED"},
+	{1 * time.Hour, "🚨 LAST CALL: YOU HAVE NOT VOTED"},
+}
+
+// voterAddresses returns the addresses whose votes are tracked on a
+// network, the validator's account address first
+func voterAddresses(networkConfig types.NetworkConfig) []string {
+	var voters []string
+	if networkConfig.ValidatorAddress != "" {
+		// Validated at load time
+		account, _ := cosmosgov.OperatorAccount(networkConfig.ValidatorAddress)
+		voters = append(voters, account)
+	}
+	return append(voters, networkConfig.VoterAddresses...)
+}
+
+// voteReminderKey identifies a reminder level for a voter in the store
+func voteReminderKey(voter string, before time.Duration) string {
+	return fmt.Sprintf("%s:%s:%dh", types.AlertVoteReminder, voter, int(before.Hours()))
+}
+
+// checkVoteReminders reminds tracked voters that have not voted on a
+// proposal as its voting end approaches. Only the most urgent due level is
+// sent, so a late start does not send every level at once
+func (s *Service) checkVoteReminders(ctx context.Context, client *governance.Client, proposal types.Proposal, networkConfig types.NetworkConfig, now time.Time) {
+	voters := voterAddresses(networkConfig)
+	if len(voters) == 0 || !alertEnabled(networkConfig, types.AlertVoteReminder) {
+		return
+	}
+	if proposal.VotingStart.After(now) {
+		return
+	}
+
+	timeUntilEnd := proposal.VotingEnd.Sub(now)
+	level := -1
+	for i, l := range voteReminderLevels {
+		if isAlertDue(timeUntilEnd, l.before, s.checkInterval()) {
+			level = i
+		}
+	}
+	if level < 0 {
+		return
+	}
+
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	if reason, held := s.reminderHeld(key, now); held {
+		proposalAlertLog(networkConfig, proposal.ID, eventAlertHeld, types.AlertVoteReminder).Infof("Vote reminders held, %s", reason)
+		return
+	}
+	for _, voter := range voters {
+		alertType := voteReminderKey(voter, voteReminderLevels[level].before)
+		if s.store.WasSent(key, alertType) {
+			continue
+		}
+
+		voted, err := client.HasVoted(ctx, proposal.ID, voter)
+		if err != nil {
+			proposalLog(networkConfig, proposal.ID, eventFetchFailed).WithField("voter", voter).WithError(err).Error("Failed to check vote")
+			continue
+		}
+		if voted {
+			// Record every level so the vote is not queried again
+			for _, l := range voteReminderLevels {
+				s.markSent(key, voteReminderKey(voter, l.before))
+			}
+			continue
+		}
+
+		msg := types.NotificationMessage{
+			Title:       fmt.Sprintf("%s - %s", voteReminderLevels[level].title, proposal.Network),
+			Content:     fmt.Sprintf("%s has not voted on proposal #%d \"%s\".\n\nVoting ends %s (%s).", voter, proposal.ID, proposal.Title, amounts.In(timeUntilEnd), proposal.VotingEnd.Format("2006-01-02 15:04 MST")),
+			Network:     proposal.Network,
+			ChainID:     networkConfig.ChainID,
+			ProposalID:  proposal.ID,
+			ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+			Role:        types.RoleOps,
+			AlertType:   types.AlertVoteReminder,
+			Severity:    s.alertSeverity(networkConfig, proposal, types.AlertVoteReminder),
+			Proposal:    &proposal,
+		}
+		if err := s.sendProposalAlert(msg); err != nil {
+			proposalAlertLog(networkConfig, proposal.ID, eventAlertFailed, types.AlertVoteReminder).WithField("voter", voter).WithError(err).Error("Failed to send vote reminder")
+			continue
+		}
+		proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertVoteReminder).WithField("voter", voter).Infof("Sent vote reminder (%.1f hours until end)", timeUntilEnd.Hours())
+		s.markSent(key, alertType)
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/types"
+)
+
+// whaleVotes is what the service follows of the votes of large validators
+// on a network: the vote tracker of its current client, the voting power of
+// the active set, refreshed once per check interval, and the latest vote of
+// each whale by proposal for the scoreboard
+type whaleVotes struct {
+	client    *governance.Client
+	tracker   *governance.VoteTracker
+	fetchedAt time.Time
+	whales    map[string]whale
+	votes     map[uint64]map[string]types.Vote
+}
+
+// whale is a validator holding at least alerts.whale_votes.min_power_percent
+// of bonded stake
+type whale struct {
+	moniker string
+	power   float64
+}
+
+// whaleVoteKey returns the alert type recording that a vote of a whale was
+// sent, so restarts and sweeps reporting the same vote again stay quiet
+func whaleVoteKey(vote types.Vote) string {
+	options := make([]string, 0, len(vote.Options))
+	for _, option := range vote.Options {
+		options = append(options, option.Option+"="+option.Weight)
+	}
+	return fmt.Sprintf("%s:%s:%s", types.AlertWhaleVote, vote.Voter, strings.Join(options, ","))
+}
+
+// checkWhaleVotes alerts on votes cast or changed by whales on a proposal in
+// voting period since the previous check, with the whales' voting power per
+// option so far. The vote tracker only fetches votes when the count moved
+func (s *Service) checkWhaleVotes(ctx context.Context, client *governance.Client, proposal types.Proposal, networkConfig types.NetworkConfig, now time.Time) {
+	config := s.config.Alerts.WhaleVotes
+	if config.MinPowerPercent <= 0 || !alertEnabled(networkConfig, types.AlertWhaleVote) {
+		return
+	}
+	// The active set is only served by the LCD
+	if networkConfig.Transport == types.TransportGRPC || proposal.VotingStart.After(now) {
+		return
+	}
+
+	state, err := s.whaleVotes(ctx, client, networkConfig, now)
+	if err != nil {
+		networkLog(networkConfig, eventFetchFailed).WithError(err).Warn("Failed to fetch bonded validators")
+		return
+	}
+
+	// A failed sync still returns the votes of the pages fetched
+	changed, err := state.tracker.Sync(ctx, proposal.ID)
+	if err != nil {
+		proposalLog(networkConfig, proposal.ID, eventFetchFailed).WithError(err).Warn("Failed to sync votes")
+	}
+
+	key := proposalKey(networkConfig.ChainID, proposal.ID)
+	var lines, sent []string
+	for _, vote := range changed {
+		w, ok := state.whales[vote.Voter]
+		if !ok {
+			continue
+		}
+		previous, voted := state.votes[proposal.ID][vote.Voter]
+		if state.votes[proposal.ID] == nil {
+			state.votes[proposal.ID] = make(map[string]types.Vote)
+		}
+		state.votes[proposal.ID][vote.Voter] = vote
+
+		alertType := whaleVoteKey(vote)
+		if s.store.WasSent(key, alertType) {
+			continue
+		}
+		verb := "voted"
+		if voted && whaleVoteKey(previous) != alertType {
+			verb = fmt.Sprintf("changed its vote from %s to", formatVote(networkConfig, previous))
+		}
+		lines = append(lines, fmt.Sprintf("• %s (%.2f%%) %s %s", w.moniker, w.power, verb, formatVote(networkConfig, vote)))
+		sent = append(sent, alertType)
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	content := fmt.Sprintf("Large validators voted on proposal #%d \"%s\":\n%s", proposal.ID, proposal.Title, strings.Join(lines, "\n"))
+	content += "\n\n" + whaleScoreboard(networkConfig, state.votes[proposal.ID], state.whales)
+	msg := types.NotificationMessage{
+		Title:       fmt.Sprintf("🐋 Whale Vote - %s", proposal.Network),
+		Content:     content,
+		Network:     proposal.Network,
+		ChainID:     networkConfig.ChainID,
+		ProposalID:  proposal.ID,
+		ExplorerURL: s.explorerURL(networkConfig, proposal.ID),
+		Role:        types.RoleCommunity,
+		AlertType:   types.AlertWhaleVote,
+		Severity:    s.alertSeverity(networkConfig, proposal, types.AlertWhaleVote),
+		Proposal:    &proposal,
+	}
+	if err := s.sendProposalAlert(msg); err != nil {
+		proposalAlertLog(networkConfig, proposal.ID, eventAlertFailed, types.AlertWhaleVote).WithError(err).Error("Failed to send whale vote alert")
+		return
+	}
+
+	proposalAlertLog(networkConfig, proposal.ID, eventAlertSent, types.AlertWhaleVote).WithField("votes", len(lines)).Info("Sent whale vote alert")
+	for _, alertType := range sent {
+		s.markSent(key, alertType)
+	}
+}
+
+// whaleVotes returns the whale vote state of a network, with a new vote
+// tracker when a reload replaced its client and the active set refreshed
+// once per check interval
+func (s *Service) whaleVotes(ctx context.Context, client *governance.Client, networkConfig types.NetworkConfig, now time.Time) (*whaleVotes, error) {
+	s.mu.Lock()
+	state, ok := s.whales[networkConfig.ChainID]
+	if !ok || state.client != client {
+		state = &whaleVotes{
+			client:  client,
+			tracker: governance.NewVoteTracker(client, s.config.Alerts.WhaleVotes.MaxPages),
+			votes:   make(map[uint64]map[string]types.Vote),
+		}
+		s.whales[networkConfig.ChainID] = state
+	}
+	s.mu.Unlock()
+
+	if state.whales != nil && now.Sub(state.fetchedAt) < s.checkInterval() {
+		return state, nil
+	}
+
+	validators, err := client.GetBondedValidators(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bonded := 0.0
+	for _, validator := range validators {
+		bonded += parseAmount(validator.Tokens)
+	}
+	if bonded <= 0 {
+		return nil, fmt.Errorf("no bonded tokens in the active set")
+	}
+
+	whales := make(map[string]whale)
+	for _, validator := range validators {
+		power := parseAmount(validator.Tokens) / bonded * 100
+		if power >= s.config.Alerts.WhaleVotes.MinPowerPercent {
+			whales[validator.Account] = whale{moniker: validator.Moniker, power: power}
+		}
+	}
+	state.whales = whales
+	state.fetchedAt = now
+	return state, nil
+}
+
+// whaleScoreboard sums the voting power of the whales that voted on a
+// proposal by option, split votes counting for each option by weight
+func whaleScoreboard(networkConfig types.NetworkConfig, votes map[string]types.Vote, whales map[string]whale) string {
+	byOption := make(map[string]float64)
+	voted, total := 0.0, 0.0
+	for _, w := range whales {
+		total += w.power
+	}
+	for voter, vote := range votes {
+		w, ok := whales[voter]
+		if !ok {
+			continue
+		}
+		voted += w.power
+		for _, option := range vote.Options {
+			label := formatVote(networkConfig, types.Vote{Options: []types.VoteOption{option}})
+			weight := parseAmount(option.Weight)
+			// Legacy single option votes carry no weight
+			if len(vote.Options) == 1 {
+				weight = 1
+			}
+			byOption[label] += w.power * weight
+		}
+	}
+
+	labels := make([]string, 0, len(byOption))
+	for label := range byOption {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool { return byOption[labels[i]] > byOption[labels[j]] })
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, fmt.Sprintf("%s %.2f%%", label, byOption[label]))
+	}
+	return fmt.Sprintf("Whales voted so far: %.2f%% of %.2f%% of voting power (%s)", voted, total, strings.Join(parts, ", "))
+}
+
+// forgetWhaleVotes drops the votes followed for a proposal that left its
+// voting period
+func (s *Service) forgetWhaleVotes(chainID string, proposalID uint64) {
+	s.mu.Lock()
+	state, ok := s.whales[chainID]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	state.tracker.Forget(proposalID)
+	delete(state.votes, proposalID)
+}
@@ -0,0 +1,919 @@
+// Package store persists service state across restarts
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"governance-alerts-cosmos/internal/types"
+)
+
+// Store records which alerts have been sent so each is sent exactly once.
+// It is kept in memory and, when opened with a path, written atomically to
+// a JSON file after every change, or once at the end of a batch of changes
+type Store struct {
+	path     string
+	readOnly bool
+	mu       sync.Mutex
+	data     storeData
+	// batches counts the open batches, dirty whether a change was held
+	// back by them
+	batches int
+	dirty   bool
+}
+
+// storeData is the persisted form of the store
+type storeData struct {
+	Version  int                           `json:"version"`
+	Sent     map[string]time.Time          `json:"sent"`
+	Messages map[string][]types.MessageRef `json:"messages,omitempty"`
+	Notes    map[string][]types.Note       `json:"notes,omitempty"`
+	// Links of each proposal to the artifacts tracking it elsewhere
+	Links  map[string][]types.TrackingLink `json:"links,omitempty"`
+	Tags   map[string][]string             `json:"tags,omitempty"`
+	Seen   map[string]time.Time            `json:"seen,omitempty"`
+	Late   map[string]time.Duration        `json:"late,omitempty"`
+	Pinned map[string][]types.MessageRef   `json:"pinned,omitempty"`
+	Acks   map[string]types.Ack            `json:"acks,omitempty"`
+	// Proposals whose alerts were muted, by who and when
+	Muted map[string]types.Ack `json:"muted,omitempty"`
+	// Depositors of each proposal and how many proposals of each depositor
+	// were vetoed as spam
+	Depositors map[string][]string `json:"depositors,omitempty"`
+	Flagged    map[string]int      `json:"flagged_depositors,omitempty"`
+	// Networks maps each chain ID to the network name it was last
+	// configured under
+	Networks map[string]string `json:"networks,omitempty"`
+	// Timelines of what the service observed and did for each proposal
+	Timelines map[string][]types.TimelineEvent `json:"timelines,omitempty"`
+	// Snapshots of the proposals found at the last check, to detect
+	// edits between checks
+	Snapshots map[string]types.ProposalSnapshot `json:"snapshots,omitempty"`
+	// Identities of the endpoints of each chain ID at their last
+	// verification, keyed by chain ID and endpoint
+	Identities map[string]types.EndpointIdentity `json:"identities,omitempty"`
+	// Participation of the latest finished proposals of each chain ID,
+	// oldest first. It is kept past the retention for trends
+	Participation map[string][]types.ParticipationSnapshot `json:"participation,omitempty"`
+	// Baselines records, by chain ID and kind, when the proposals already
+	// on chain were first recorded, so they are only treated as existing
+	// ones once per chain and not on every start
+	Baselines map[string]time.Time `json:"baselines,omitempty"`
+	// Flags toggled at runtime through the API
+	Flags types.RuntimeFlags `json:"flags"`
+}
+
+// LateAlert is an alert sent later than intended
+type LateAlert struct {
+	AlertType string
+	SentAt    time.Time
+	Lateness  time.Duration
+}
+
+// maxParticipationSnapshots caps the participation history of a chain,
+// the oldest proposals are dropped first
+const maxParticipationSnapshots = 100
+
+// maxTimelineEvents caps the timeline of a proposal, the oldest events are
+// dropped first
+const maxTimelineEvents = 200
+
+// storeVersion is the current version of the file format. Version 2
+// records the network name of each chain ID, version 3 the baselines of
+// each chain ID
+const storeVersion = 3
+
+// NetworkRename is a network configured under a new name since the state
+// was last written
+type NetworkRename struct {
+	ChainID string
+	From    string
+	To      string
+}
+
+// Open loads the store at path, creating it on the first write. An empty
+// path gives a store that only lives in memory
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: storeData{
+			Version:       storeVersion,
+			Sent:          make(map[string]time.Time),
+			Messages:      make(map[string][]types.MessageRef),
+			Notes:         make(map[string][]types.Note),
+			Links:         make(map[string][]types.TrackingLink),
+			Tags:          make(map[string][]string),
+			Seen:          make(map[string]time.Time),
+			Late:          make(map[string]time.Duration),
+			Pinned:        make(map[string][]types.MessageRef),
+			Acks:          make(map[string]types.Ack),
+			Muted:         make(map[string]types.Ack),
+			Depositors:    make(map[string][]string),
+			Flagged:       make(map[string]int),
+			Networks:      make(map[string]string),
+			Timelines:     make(map[string][]types.TimelineEvent),
+			Snapshots:     make(map[string]types.ProposalSnapshot),
+			Identities:    make(map[string]types.EndpointIdentity),
+			Participation: make(map[string][]types.ParticipationSnapshot),
+			Baselines:     make(map[string]time.Time),
+		},
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if s.data.Version > storeVersion {
+		return nil, fmt.Errorf("state file %s has unsupported version %d", path, s.data.Version)
+	}
+	if s.data.Sent == nil {
+		s.data.Sent = make(map[string]time.Time)
+	}
+	if s.data.Messages == nil {
+		s.data.Messages = make(map[string][]types.MessageRef)
+	}
+	if s.data.Notes == nil {
+		s.data.Notes = make(map[string][]types.Note)
+	}
+	if s.data.Links == nil {
+		s.data.Links = make(map[string][]types.TrackingLink)
+	}
+	if s.data.Tags == nil {
+		s.data.Tags = make(map[string][]string)
+	}
+	if s.data.Seen == nil {
+		s.data.Seen = make(map[string]time.Time)
+	}
+	if s.data.Late == nil {
+		s.data.Late = make(map[string]time.Duration)
+	}
+	if s.data.Pinned == nil {
+		s.data.Pinned = make(map[string][]types.MessageRef)
+	}
+	if s.data.Acks == nil {
+		s.data.Acks = make(map[string]types.Ack)
+	}
+	if s.data.Muted == nil {
+		s.data.Muted = make(map[string]types.Ack)
+	}
+	if s.data.Depositors == nil {
+		s.data.Depositors = make(map[string][]string)
+	}
+	if s.data.Flagged == nil {
+		s.data.Flagged = make(map[string]int)
+	}
+	if s.data.Networks == nil {
+		s.data.Networks = make(map[string]string)
+	}
+	if s.data.Timelines == nil {
+		s.data.Timelines = make(map[string][]types.TimelineEvent)
+	}
+	if s.data.Snapshots == nil {
+		s.data.Snapshots = make(map[string]types.ProposalSnapshot)
+	}
+	if s.data.Identities == nil {
+		s.data.Identities = make(map[string]types.EndpointIdentity)
+	}
+	if s.data.Participation == nil {
+		s.data.Participation = make(map[string][]types.ParticipationSnapshot)
+	}
+	if s.data.Baselines == nil {
+		s.data.Baselines = make(map[string]time.Time)
+	}
+	s.migrate()
+	return s, nil
+}
+
+// OpenReadOnly loads the store at path like Open, but changes only live in
+// memory and the file is never written
+func OpenReadOnly(path string) (*Store, error) {
+	s, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	s.readOnly = true
+	return s, nil
+}
+
+// migrate upgrades state written by an older version, saved with the next
+// change. Version 1 already keyed proposals by chain ID and only lacks the
+// network names, which RecordNetworks fills in. Before version 3 a chain
+// with any recorded proposal had its first run already, and one with a
+// new proposal alert its new proposals baselined
+func (s *Store) migrate() {
+	if s.data.Version < 2 {
+		s.data.Version = 2
+	}
+	if s.data.Version < 3 {
+		baseline := func(chainID, kind string, at time.Time) {
+			if first, ok := s.data.Baselines[baselineKey(chainID, kind)]; !ok || at.Before(first) {
+				s.data.Baselines[baselineKey(chainID, kind)] = at
+			}
+		}
+		for key, at := range s.data.Seen {
+			if chainID, _, ok := strings.Cut(key, "/"); ok {
+				baseline(chainID, BaselineFirstRun, at)
+			}
+		}
+		for key, at := range s.data.Sent {
+			parts := strings.SplitN(key, "/", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			baseline(parts[0], BaselineFirstRun, at)
+			if strings.HasPrefix(parts[2], types.AlertNewProposal) {
+				baseline(parts[0], BaselineNewProposal, at)
+			}
+		}
+		s.data.Version = 3
+	}
+}
+
+// Kinds of baselines
+const (
+	// BaselineFirstRun is recorded once the proposals in voting at the
+	// first check of a chain were handled by the first-run mode
+	BaselineFirstRun = "first_run"
+	// BaselineNewProposal is recorded once the proposals on chain at the
+	// first check of a chain with new proposal alerts were recorded
+	// without alerting
+	BaselineNewProposal = "new_proposal"
+)
+
+// baselineKey identifies a baseline of a chain ID
+func baselineKey(chainID, kind string) string {
+	return chainID + "/" + kind
+}
+
+// Baselined reports whether the baseline of kind was recorded for a chain
+func (s *Store) Baselined(chainID, kind string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data.Baselines[baselineKey(chainID, kind)]
+	return ok
+}
+
+// MarkBaselined records the baseline of kind for a chain, keeping the
+// first time it was recorded
+func (s *Store) MarkBaselined(chainID, kind string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.Baselines[baselineKey(chainID, kind)]; ok {
+		return nil
+	}
+	s.data.Baselines[baselineKey(chainID, kind)] = at
+	return s.save()
+}
+
+// RecordNetworks records the configured network name of each chain ID,
+// given as a chain ID to name map, and returns the networks renamed since
+// the last run. Their state is kept since it is keyed by chain ID
+func (s *Store) RecordNetworks(names map[string]string) ([]NetworkRename, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var renames []NetworkRename
+	changed := false
+	for chainID, name := range names {
+		previous, ok := s.data.Networks[chainID]
+		if ok && previous == name {
+			continue
+		}
+		if ok {
+			renames = append(renames, NetworkRename{ChainID: chainID, From: previous, To: name})
+		}
+		s.data.Networks[chainID] = name
+		changed = true
+	}
+	sort.Slice(renames, func(i, j int) bool { return renames[i].ChainID < renames[j].ChainID })
+
+	if !changed {
+		return renames, nil
+	}
+	return renames, s.save()
+}
+
+// NetworkName returns the network name last recorded for a chain ID
+func (s *Store) NetworkName(chainID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Networks[chainID]
+}
+
+// ProposalKey identifies a proposal across networks in the store
+func ProposalKey(chainID string, proposalID uint64) string {
+	return fmt.Sprintf("%s/%d", chainID, proposalID)
+}
+
+// ParseProposalKey splits a proposal key into its chain ID and proposal ID
+func ParseProposalKey(key string) (string, uint64, bool) {
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return "", 0, false
+	}
+	proposalID, err := strconv.ParseUint(key[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:i], proposalID, true
+}
+
+// alertKey identifies an alert of a proposal
+func alertKey(proposalKey, alertType string) string {
+	return proposalKey + "/" + alertType
+}
+
+// WasSent reports whether an alert of alertType was sent for the proposal
+func (s *Store) WasSent(proposalKey, alertType string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data.Sent[alertKey(proposalKey, alertType)]
+	return ok
+}
+
+// SentAlerts returns the alert types recorded for a proposal
+func (s *Store) SentAlerts(proposalKey string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := proposalKey + "/"
+	var alertTypes []string
+	for key := range s.data.Sent {
+		if alertType, ok := strings.CutPrefix(key, prefix); ok {
+			alertTypes = append(alertTypes, alertType)
+		}
+	}
+	sort.Strings(alertTypes)
+	return alertTypes
+}
+
+// Forget clears the alerts, acknowledgement and mute recorded for a
+// proposal so they can be sent again. Notes, tags, links and sent messages
+// are kept
+func (s *Store) Forget(proposalKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data.Acks, proposalKey)
+	delete(s.data.Muted, proposalKey)
+	prefix := proposalKey + "/"
+	for key := range s.data.Sent {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.data.Sent, key)
+			delete(s.data.Late, key)
+		}
+	}
+	return s.save()
+}
+
+// Known reports whether the proposal was seen or any alert was recorded
+// for it
+func (s *Store) Known(proposalKey string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.Seen[proposalKey]; ok {
+		return true
+	}
+	prefix := proposalKey + "/"
+	for key := range s.data.Sent {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkSent records that an alert of alertType was sent for the proposal
+func (s *Store) MarkSent(proposalKey, alertType string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Sent[alertKey(proposalKey, alertType)] = at
+	return s.save()
+}
+
+// MarkLate records that an alert of alertType was sent lateness later than
+// intended
+func (s *Store) MarkLate(proposalKey, alertType string, lateness time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Late[alertKey(proposalKey, alertType)] = lateness
+	return s.save()
+}
+
+// LateAlerts returns the alerts of a proposal that were sent late
+func (s *Store) LateAlerts(proposalKey string) []LateAlert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := proposalKey + "/"
+	var late []LateAlert
+	for key, lateness := range s.data.Late {
+		if alertType, ok := strings.CutPrefix(key, prefix); ok {
+			late = append(late, LateAlert{AlertType: alertType, SentAt: s.data.Sent[key], Lateness: lateness})
+		}
+	}
+	return late
+}
+
+// MarkSeen records when a proposal was first seen and returns that time
+func (s *Store) MarkSeen(proposalKey string, at time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if first, ok := s.data.Seen[proposalKey]; ok {
+		return first, nil
+	}
+	s.data.Seen[proposalKey] = at
+	return at, s.save()
+}
+
+// AddTimelineEvent appends an event to the timeline of a proposal
+func (s *Store) AddTimelineEvent(proposalKey string, event types.TimelineEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	timeline := append(s.data.Timelines[proposalKey], event)
+	if len(timeline) > maxTimelineEvents {
+		timeline = timeline[len(timeline)-maxTimelineEvents:]
+	}
+	s.data.Timelines[proposalKey] = timeline
+	return s.save()
+}
+
+// Timeline returns the timeline of a proposal, oldest first
+func (s *Store) Timeline(proposalKey string) []types.TimelineEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.TimelineEvent(nil), s.data.Timelines[proposalKey]...)
+}
+
+// LastTimelineEvent returns the latest event of kind in the timeline of a
+// proposal
+func (s *Store) LastTimelineEvent(proposalKey, kind string) (types.TimelineEvent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	timeline := s.data.Timelines[proposalKey]
+	for i := len(timeline) - 1; i >= 0; i-- {
+		if timeline[i].Kind == kind {
+			return timeline[i], true
+		}
+	}
+	return types.TimelineEvent{}, false
+}
+
+// AddMessages records messages sent about a proposal
+func (s *Store) AddMessages(proposalKey string, refs []types.MessageRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Messages[proposalKey] = append(s.data.Messages[proposalKey], refs...)
+	return s.save()
+}
+
+// Messages returns the recorded messages of every proposal
+func (s *Store) Messages() map[string][]types.MessageRef {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := make(map[string][]types.MessageRef, len(s.data.Messages))
+	for key, refs := range s.data.Messages {
+		messages[key] = append([]types.MessageRef(nil), refs...)
+	}
+	return messages
+}
+
+// ForgetMessages drops the recorded messages of a proposal
+func (s *Store) ForgetMessages(proposalKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.Messages[proposalKey]; !ok {
+		return nil
+	}
+	delete(s.data.Messages, proposalKey)
+	return s.save()
+}
+
+// KeepMessages replaces the recorded messages of a proposal with refs, a
+// subset still to be handled, dropping them all when refs is empty
+func (s *Store) KeepMessages(proposalKey string, refs []types.MessageRef) error {
+	if len(refs) == 0 {
+		return s.ForgetMessages(proposalKey)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Messages[proposalKey] = append([]types.MessageRef(nil), refs...)
+	return s.save()
+}
+
+// Acknowledge records the acknowledgement of a proposal's alerts,
+// replacing an earlier one
+func (s *Store) Acknowledge(proposalKey string, ack types.Ack) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Acks[proposalKey] = ack
+	return s.save()
+}
+
+// Acknowledgement returns the acknowledgement of a proposal's alerts
+func (s *Store) Acknowledgement(proposalKey string) (types.Ack, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ack, ok := s.data.Acks[proposalKey]
+	return ack, ok
+}
+
+// Mute suppresses every further alert of a proposal, recording who muted
+// it and when
+func (s *Store) Mute(proposalKey string, mute types.Ack) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Muted[proposalKey] = mute
+	return s.save()
+}
+
+// Unmute lets the alerts of a proposal through again, reporting whether it
+// was muted
+func (s *Store) Unmute(proposalKey string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.Muted[proposalKey]; !ok {
+		return false, nil
+	}
+	delete(s.data.Muted, proposalKey)
+	return true, s.save()
+}
+
+// Muted returns who muted the alerts of a proposal and when
+func (s *Store) Muted(proposalKey string) (types.Ack, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mute, ok := s.data.Muted[proposalKey]
+	return mute, ok
+}
+
+// Flags returns the runtime flags
+func (s *Store) Flags() types.RuntimeFlags {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flags := s.data.Flags
+	flags.PausedChannels = append([]string{}, flags.PausedChannels...)
+	flags.DryRunNetworks = append([]string{}, flags.DryRunNetworks...)
+	return flags
+}
+
+// SetFlags records the runtime flags, replacing the previous ones
+func (s *Store) SetFlags(flags types.RuntimeFlags) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Flags = flags
+	return s.save()
+}
+
+// Snapshot returns the snapshot of a proposal taken at its last check
+func (s *Store) Snapshot(proposalKey string) (types.ProposalSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, ok := s.data.Snapshots[proposalKey]
+	return snapshot, ok
+}
+
+// SetSnapshot records the snapshot of a proposal, replacing the previous
+// one
+func (s *Store) SetSnapshot(proposalKey string, snapshot types.ProposalSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Snapshots[proposalKey] = snapshot
+	return s.save()
+}
+
+// identityKey identifies an endpoint of a chain
+func identityKey(chainID, endpoint string) string {
+	return chainID + " " + endpoint
+}
+
+// EndpointIdentity returns the identity of an endpoint of a chain at its
+// last verification
+func (s *Store) EndpointIdentity(chainID, endpoint string) (types.EndpointIdentity, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	identity, ok := s.data.Identities[identityKey(chainID, endpoint)]
+	return identity, ok
+}
+
+// SetEndpointIdentity records the identity of an endpoint of a chain
+func (s *Store) SetEndpointIdentity(chainID string, identity types.EndpointIdentity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Identities[identityKey(chainID, identity.Endpoint)] = identity
+	return s.save()
+}
+
+// AddParticipation records the participation of a finished proposal of a
+// chain, replacing an earlier snapshot of the same proposal
+func (s *Store) AddParticipation(chainID string, snapshot types.ParticipationSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := s.data.Participation[chainID]
+	for i, existing := range history {
+		if existing.ProposalID == snapshot.ProposalID {
+			history = append(history[:i], history[i+1:]...)
+			break
+		}
+	}
+	history = append(history, snapshot)
+	sort.Slice(history, func(i, j int) bool {
+		if !history[i].VotingEnd.Equal(history[j].VotingEnd) {
+			return history[i].VotingEnd.Before(history[j].VotingEnd)
+		}
+		return history[i].ProposalID < history[j].ProposalID
+	})
+	if len(history) > maxParticipationSnapshots {
+		history = history[len(history)-maxParticipationSnapshots:]
+	}
+	s.data.Participation[chainID] = history
+	return s.save()
+}
+
+// Participation returns the participation history of a chain, oldest
+// proposal first
+func (s *Store) Participation(chainID string) []types.ParticipationSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.ParticipationSnapshot(nil), s.data.Participation[chainID]...)
+}
+
+// SetDepositors records the depositors of a proposal
+func (s *Store) SetDepositors(proposalKey string, depositors []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Depositors[proposalKey] = depositors
+	return s.save()
+}
+
+// Depositors returns the recorded depositors of a proposal, nil if none
+// were recorded
+func (s *Store) Depositors(proposalKey string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.data.Depositors[proposalKey]...)
+}
+
+// SettleDepositors drops the recorded depositors of a finished proposal,
+// counting it against each of them first if it was vetoed as spam
+func (s *Store) SettleDepositors(proposalKey string, spam bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	depositors, ok := s.data.Depositors[proposalKey]
+	if !ok {
+		return nil
+	}
+	if spam {
+		for _, depositor := range depositors {
+			s.data.Flagged[depositor]++
+		}
+	}
+	delete(s.data.Depositors, proposalKey)
+	return s.save()
+}
+
+// SpamCount returns how many proposals funded by depositor were vetoed as
+// spam
+func (s *Store) SpamCount(depositor string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Flagged[depositor]
+}
+
+// PinnedMessages returns the messages of a pinned service message such as
+// the upgrade timeline
+func (s *Store) PinnedMessages(name string) []types.MessageRef {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.MessageRef(nil), s.data.Pinned[name]...)
+}
+
+// SetPinnedMessages records the messages of a pinned service message
+func (s *Store) SetPinnedMessages(name string, refs []types.MessageRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Pinned[name] = refs
+	return s.save()
+}
+
+// AddNote attaches a note to a proposal
+func (s *Store) AddNote(proposalKey string, note types.Note) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Notes[proposalKey] = append(s.data.Notes[proposalKey], note)
+	return s.save()
+}
+
+// Notes returns the notes of a proposal, oldest first
+func (s *Store) Notes(proposalKey string) []types.Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Note(nil), s.data.Notes[proposalKey]...)
+}
+
+// AddLink links a proposal to an artifact tracking it, replacing the link
+// to the same artifact
+func (s *Store) AddLink(proposalKey string, link types.TrackingLink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links := s.data.Links[proposalKey]
+	for i, existing := range links {
+		if existing.System == link.System && existing.ID == link.ID {
+			links[i] = link
+			return s.save()
+		}
+	}
+	s.data.Links[proposalKey] = append(links, link)
+	return s.save()
+}
+
+// RemoveLink removes the link of a proposal to an artifact, reporting
+// whether there was one
+func (s *Store) RemoveLink(proposalKey, system, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links := s.data.Links[proposalKey]
+	for i, link := range links {
+		if link.System == system && link.ID == id {
+			s.data.Links[proposalKey] = append(links[:i:i], links[i+1:]...)
+			if len(s.data.Links[proposalKey]) == 0 {
+				delete(s.data.Links, proposalKey)
+			}
+			return true, s.save()
+		}
+	}
+	return false, nil
+}
+
+// Links returns the links of a proposal, oldest first
+func (s *Store) Links(proposalKey string) []types.TrackingLink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.TrackingLink(nil), s.data.Links[proposalKey]...)
+}
+
+// AddTags tags a proposal, ignoring tags it already has
+func (s *Store) AddTags(proposalKey string, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		if !slices.Contains(s.data.Tags[proposalKey], tag) {
+			s.data.Tags[proposalKey] = append(s.data.Tags[proposalKey], tag)
+		}
+	}
+	return s.save()
+}
+
+// Tags returns the tags of a proposal
+func (s *Store) Tags(proposalKey string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.data.Tags[proposalKey]...)
+}
+
+// Prune forgets alerts sent before cutoff so the store does not grow
+// forever
+func (s *Store) Prune(cutoff time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruned := false
+	for key, at := range s.data.Sent {
+		if at.Before(cutoff) {
+			delete(s.data.Sent, key)
+			delete(s.data.Late, key)
+			pruned = true
+		}
+	}
+	for key, at := range s.data.Seen {
+		if at.Before(cutoff) {
+			delete(s.data.Seen, key)
+			pruned = true
+		}
+	}
+	for key, ack := range s.data.Acks {
+		if ack.Time.Before(cutoff) {
+			delete(s.data.Acks, key)
+			pruned = true
+		}
+	}
+	for key, mute := range s.data.Muted {
+		if mute.Time.Before(cutoff) {
+			delete(s.data.Muted, key)
+			pruned = true
+		}
+	}
+	for key, snapshot := range s.data.Snapshots {
+		if snapshot.Seen.Before(cutoff) {
+			delete(s.data.Snapshots, key)
+			pruned = true
+		}
+	}
+	for key, identity := range s.data.Identities {
+		if identity.VerifiedAt.Before(cutoff) {
+			delete(s.data.Identities, key)
+			pruned = true
+		}
+	}
+	for key, timeline := range s.data.Timelines {
+		if len(timeline) == 0 || timeline[len(timeline)-1].Time.Before(cutoff) {
+			delete(s.data.Timelines, key)
+			pruned = true
+		}
+	}
+	if !pruned {
+		return nil
+	}
+	return s.save()
+}
+
+// BeginBatch holds the writes of the following changes back until
+// EndBatch, so a check cycle rewriting the state for every alert writes the
+// file once. Batches nest, the file is written when the outermost ends
+func (s *Store) BeginBatch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches++
+}
+
+// EndBatch ends a batch started with BeginBatch, writing the changes held
+// back once no batch is open
+func (s *Store) EndBatch() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.batches > 0 {
+		s.batches--
+	}
+	if s.batches > 0 || !s.dirty {
+		return nil
+	}
+	// Changes that failed to be written are retried with the next one
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// save writes the store to a temporary file and renames it over the state
+// file so a crash never leaves a truncated file behind
+func (s *Store) save() error {
+	if s.path == "" || s.readOnly {
+		return nil
+	}
+	if s.batches > 0 {
+		s.dirty = true
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
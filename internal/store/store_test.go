@@ -0,0 +1,53 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestBatch checks that changes made in a batch are written once when the
+// outermost batch ends
+func TestBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	s.BeginBatch()
+	s.BeginBatch()
+	if err := s.MarkSent("cosmoshub-4/1", "voting_start", time.Now()); err != nil {
+		t.Fatalf("MarkSent() error = %v", err)
+	}
+	if err := s.EndBatch(); err != nil {
+		t.Fatalf("EndBatch() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("state file written inside a batch, Stat() error = %v", err)
+	}
+	if err := s.EndBatch(); err != nil {
+		t.Fatalf("EndBatch() error = %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !reopened.WasSent("cosmoshub-4/1", "voting_start") {
+		t.Error("WasSent() = false after the batch ended, want true")
+	}
+
+	// Outside a batch every change is written right away
+	if err := s.MarkSent("cosmoshub-4/2", "voting_start", time.Now()); err != nil {
+		t.Fatalf("MarkSent() error = %v", err)
+	}
+	if reopened, err = Open(path); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !reopened.WasSent("cosmoshub-4/2", "voting_start") {
+		t.Error("WasSent() = false after an unbatched change, want true")
+	}
+}
@@ -6,69 +6,938 @@ import (
 
 // Proposal represents a governance proposal
 type Proposal struct {
-	ID          uint64    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	VotingStart time.Time `json:"voting_start"`
-	VotingEnd   time.Time `json:"voting_end"`
-	Network     string    `json:"network"`
+	ID           uint64       `json:"id"`
+	Title        string       `json:"title"`
+	Description  string       `json:"description"`
+	Status       string       `json:"status"`
+	SubmitTime   time.Time    `json:"submit_time"`
+	DepositEnd   time.Time    `json:"deposit_end"`
+	VotingStart  time.Time    `json:"voting_start"`
+	VotingEnd    time.Time    `json:"voting_end"`
+	Network      string       `json:"network"`
+	Type         string       `json:"type,omitempty"`
+	Category     string       `json:"category,omitempty"`
+	References   []uint64     `json:"references,omitempty"`
+	Messages     []string     `json:"messages,omitempty"`
+	MessageTypes []string     `json:"message_types,omitempty"`
+	Metadata     string       `json:"metadata,omitempty"`
+	FinalTally   *TallyResult `json:"final_tally,omitempty"`
+	TotalDeposit []Coin       `json:"total_deposit,omitempty"`
+	Upgrade      *UpgradePlan `json:"upgrade,omitempty"`
 }
 
-// NetworkConfig represents network configuration
+// ChainIncident is an incident a network reports on its status page, or a
+// halt detected on chain
+type ChainIncident struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	URL    string `json:"url,omitempty"`
+}
+
+// UpgradePlan is the plan of a software upgrade proposal. Info usually
+// lists the binaries to upgrade to, or links to a file that does
+type UpgradePlan struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+	Info   string `json:"info,omitempty"`
+}
+
+// Coin is an amount of a denom in base units
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// Deposit is a deposit made on a proposal
+type Deposit struct {
+	Depositor string `json:"depositor"`
+	Amount    []Coin `json:"amount"`
+}
+
+// TallyResult represents the current vote tally of a proposal, amounts are
+// in the staking denom's base units
+type TallyResult struct {
+	Yes        string `json:"yes"`
+	Abstain    string `json:"abstain"`
+	No         string `json:"no"`
+	NoWithVeto string `json:"no_with_veto"`
+}
+
+// Vote options of the gov module tally
+const (
+	VoteYes        = "yes"
+	VoteNo         = "no"
+	VoteAbstain    = "abstain"
+	VoteNoWithVeto = "no_with_veto"
+)
+
+// VoteOptions lists the tally options of the gov module in display order
+var VoteOptions = []string{VoteYes, VoteNo, VoteAbstain, VoteNoWithVeto}
+
+// TallyOption represents the amount of a vote option in a tally. Option is
+// the gov module option (yes, no, abstain or no_with_veto) and Label the
+// name the chain gives it
+type TallyOption struct {
+	Option string `json:"option"`
+	Label  string `json:"label"`
+	Amount string `json:"amount"`
+}
+
+// Vote represents a vote cast on a proposal
+type Vote struct {
+	ProposalID uint64       `json:"proposal_id"`
+	Voter      string       `json:"voter"`
+	Options    []VoteOption `json:"options"`
+}
+
+// VoteOption represents a vote option with its weight
+type VoteOption struct {
+	Option string `json:"option"`
+	Weight string `json:"weight"`
+}
+
+// UpgradeEstimate represents a scheduled software upgrade and when it is
+// expected to happen
+type UpgradeEstimate struct {
+	Network   string        `json:"network"`
+	ChainID   string        `json:"chain_id"`
+	Name      string        `json:"name"`
+	Height    int64         `json:"height"`
+	Time      time.Time     `json:"time"`
+	BlockTime time.Duration `json:"block_time"`
+}
+
+// GovParams represents the gov module parameters of a network. Quorum and
+// thresholds are decimal fractions as returned by the chain
+type GovParams struct {
+	MinDeposit       []Coin        `json:"min_deposit"`
+	VotingPeriod     time.Duration `json:"voting_period"`
+	MaxDepositPeriod time.Duration `json:"max_deposit_period"`
+	Quorum           string        `json:"quorum"`
+	Threshold        string        `json:"threshold"`
+	VetoThreshold    string        `json:"veto_threshold"`
+}
+
+// Validator represents a validator of the active set. Account is the
+// address it votes with and Tokens its bonded tokens in base units
+type Validator struct {
+	OperatorAddress string `json:"operator_address"`
+	Account         string `json:"account"`
+	Moniker         string `json:"moniker"`
+	Tokens          string `json:"tokens"`
+}
+
+// StakingPool represents the staking pool of a network
+type StakingPool struct {
+	BondedTokens    string `json:"bonded_tokens"`
+	NotBondedTokens string `json:"not_bonded_tokens"`
+}
+
+// NetworkConfig represents network configuration. ArchiveEndpoints are
+// only queried for historical data the live endpoint pruned, and
+// DisabledAlerts lists proposal alert types never sent for the network.
+// ValidatorAddress (an operator address) and VoterAddresses are checked for
+// votes before voting ends. RestEndpoints are failover endpoints tried after
+// RestEndpoint, the primary. State is keyed by ChainID, so a network can be
+// renamed freely; Aliases are former names still accepted by commands.
+// ExplorerURLTemplate links alerts to the proposal on a block explorer,
+// with {id} replaced by the proposal ID and {chain_id} by the chain ID;
+// ExplorerURLTemplates are fallback explorers linked while the ones
+// before them are down or do not index the chain.
+// VoteOptions lists the vote options valid on the chain, in display order,
+// for chains that dropped some; VoteOptionLabels renames options in
+// tallies and charts. ProposalTypes limits proposal alerts to proposals of
+// these categories (software_upgrade, parameter_change...) or message
+// types (MsgSoftwareUpgrade...). Transport grpc queries GRPCEndpoints, the
+// first being the primary, instead of the REST endpoints. Every
+// VerifyChainIDEvery checks (default 10, -1 disables) each endpoint's chain
+// ID is compared with ChainID and mismatching endpoints are no longer used.
+// HeartbeatURL is pinged after every check cycle in which the network was
+// checked successfully
 type NetworkConfig struct {
-	Name         string `mapstructure:"name"`
-	RestEndpoint string `mapstructure:"rest_endpoint"`
-	ChainID      string `mapstructure:"chain_id"`
+	Name                 string            `mapstructure:"name"`
+	Aliases              []string          `mapstructure:"aliases"`
+	RestEndpoint         string            `mapstructure:"rest_endpoint"`
+	RestEndpoints        []string          `mapstructure:"rest_endpoints"`
+	ChainID              string            `mapstructure:"chain_id"`
+	TLS                  TLSConfig         `mapstructure:"tls"`
+	Sticky               StickyConfig      `mapstructure:"sticky"`
+	Auth                 AuthConfig        `mapstructure:"auth"`
+	Indexer              IndexerConfig     `mapstructure:"indexer"`
+	ArchiveEndpoints     []string          `mapstructure:"archive_endpoints"`
+	DisabledAlerts       []string          `mapstructure:"disabled_alerts"`
+	ValidatorAddress     string            `mapstructure:"validator_address"`
+	VoterAddresses       []string          `mapstructure:"voter_addresses"`
+	StatusFilter         bool              `mapstructure:"status_filter"`
+	PageLimit            int               `mapstructure:"page_limit"`
+	ExplorerURLTemplate  string            `mapstructure:"explorer_url_template"`
+	ExplorerURLTemplates []string          `mapstructure:"explorer_url_templates"`
+	VoteOptions          []string          `mapstructure:"vote_options"`
+	VoteOptionLabels     map[string]string `mapstructure:"vote_option_labels"`
+	ProposalTypes        []string          `mapstructure:"proposal_types"`
+	ProposalIDs          []uint64          `mapstructure:"proposal_ids"`
+	Denom                string            `mapstructure:"denom"`
+	Retry                RetryConfig       `mapstructure:"retry"`
+	Incidents            IncidentsConfig   `mapstructure:"incidents"`
+	Transport            string            `mapstructure:"transport"`
+	GRPCEndpoints        []string          `mapstructure:"grpc_endpoints"`
+	VerifyChainIDEvery   int               `mapstructure:"verify_chain_id_every"`
+	HeartbeatURL         string            `mapstructure:"heartbeat_url"`
+}
+
+// Network transports
+const (
+	TransportREST = "rest"
+	TransportGRPC = "grpc"
+)
+
+// RetryConfig represents how requests failing on every endpoint of a
+// network with a rate limit, server error or timeout are retried. Attempts
+// counts the first try (default 3, 1 disables retries); the backoff starts
+// at BackoffMS (default 500) and doubles up to MaxBackoffSeconds (default
+// 30), with jitter. A longer Retry-After from the node is honored
+type RetryConfig struct {
+	Attempts          int `mapstructure:"attempts"`
+	BackoffMS         int `mapstructure:"backoff_ms"`
+	MaxBackoffSeconds int `mapstructure:"max_backoff_seconds"`
+}
+
+// IncidentsConfig represents how incidents of a network are detected: from
+// its status page feed (format statuspage or rss, guessed from the response
+// when empty) and from its latest block being older than HaltMinutes.
+// Alerts sent during an incident carry a note, and reminders are held with
+// HoldReminders
+type IncidentsConfig struct {
+	FeedURL       string `mapstructure:"feed_url"`
+	Format        string `mapstructure:"format"`
+	HaltMinutes   int    `mapstructure:"halt_minutes"`
+	HoldReminders bool   `mapstructure:"hold_reminders"`
+}
+
+// IndexerConfig represents an indexer API (Numia, SubQuery or custom) read
+// instead of the LCD for proposal lists and tallies. Type rest fetches
+// ProposalsURL and TallyURL ({id} is replaced by the proposal ID); type
+// graphql posts ProposalsQuery and TallyQuery ($id variable) to URL.
+// Responses must carry "proposals" or "tally" with LCD field names, which
+// GraphQL aliases can map to
+type IndexerConfig struct {
+	Type           string            `mapstructure:"type"`
+	URL            string            `mapstructure:"url"`
+	ProposalsURL   string            `mapstructure:"proposals_url"`
+	TallyURL       string            `mapstructure:"tally_url"`
+	ProposalsQuery string            `mapstructure:"proposals_query"`
+	TallyQuery     string            `mapstructure:"tally_query"`
+	Headers        map[string]string `mapstructure:"headers"`
+}
+
+// AuthConfig represents authentication for REST endpoints behind API
+// gateways. Type is one of basic, bearer or query
+type AuthConfig struct {
+	Type         string `mapstructure:"type"`
+	Username     string `mapstructure:"username"`
+	Password     string `mapstructure:"password"`
+	PasswordFile string `mapstructure:"password_file"`
+	Token        string `mapstructure:"token"`
+	TokenFile    string `mapstructure:"token_file"`
+	QueryParam   string `mapstructure:"query_param"`
+}
+
+// StickyConfig represents session affinity settings for endpoints that are
+// load balancers fronting nodes at different heights. Header is sent with
+// Value (a random session ID when empty) on every request, responses whose
+// block height is lower than the previous one can be rejected, and the
+// reads of each check can be pinned to the height of its first response
+type StickyConfig struct {
+	Header                 string `mapstructure:"header"`
+	Value                  string `mapstructure:"value"`
+	RejectHeightRegression bool   `mapstructure:"reject_height_regression"`
+	PinHeight              bool   `mapstructure:"pin_height"`
+}
+
+// TLSConfig represents TLS settings for private endpoints. Certificates and
+// keys can be given as file paths or as inline PEM
+type TLSConfig struct {
+	CAFile     string `mapstructure:"ca_file"`
+	CAPEM      string `mapstructure:"ca_pem"`
+	CertFile   string `mapstructure:"cert_file"`
+	CertPEM    string `mapstructure:"cert_pem"`
+	KeyFile    string `mapstructure:"key_file"`
+	KeyPEM     string `mapstructure:"key_pem"`
+	ServerName string `mapstructure:"server_name"`
 }
 
-// AlertConfig represents alert configuration
+// AlertConfig represents alert configuration. FirstRun decides what happens
+// to proposals already in voting when the service starts without any record
+// of them: alert_all (default), digest_only or track_silently.
+// SpamDepositorThreshold skips new proposal alerts when every depositor
+// funded at least that many proposals vetoed as spam, 0 disables it.
+// ReminderHoursBeforeEnd replaces the single HoursBeforeEnd reminder with
+// escalating ones, e.g. [72, 24, 6, 1], each sent once.
+// HoursBeforeDepositEnd alerts about proposals that still lack the min
+// deposit that long before their deposit period ends, 0 disables it.
+// CommunityMinDepositPercent holds the alerts of proposals in deposit
+// period back from community channels until their deposit reaches that
+// share of the min deposit, audit channels get them from submission; 0
+// disables it
 type AlertConfig struct {
-	HoursBeforeStart     int  `mapstructure:"hours_before_start"`
-	HoursBeforeEnd       int  `mapstructure:"hours_before_end"`
-	CheckIntervalMinutes int  `mapstructure:"check_interval_minutes"`
-	NotifyOnStartup      bool `mapstructure:"notify_on_startup"`
+	HoursBeforeStart             int                `mapstructure:"hours_before_start"`
+	HoursBeforeEnd               int                `mapstructure:"hours_before_end"`
+	ReminderHoursBeforeEnd       []int              `mapstructure:"reminder_hours_before_end"`
+	HoursBeforeDepositEnd        int                `mapstructure:"hours_before_deposit_end"`
+	CheckIntervalMinutes         int                `mapstructure:"check_interval_minutes"`
+	NotifyOnStartup              bool               `mapstructure:"notify_on_startup"`
+	NotifyOnNewProposal          bool               `mapstructure:"notify_on_new_proposal"`
+	BondedChangeThresholdPercent float64            `mapstructure:"bonded_change_threshold_percent"`
+	StaleGraceMinutes            int                `mapstructure:"stale_grace_minutes"`
+	QuietHours                   QuietHoursConfig   `mapstructure:"quiet_hours"`
+	LatencySLOMinutes            int                `mapstructure:"latency_slo_minutes"`
+	FirstRun                     string             `mapstructure:"first_run"`
+	SeverityPolicies             []SeverityPolicy   `mapstructure:"severity_policies"`
+	SpamDepositorThreshold       int                `mapstructure:"spam_depositor_threshold"`
+	CommunityMinDepositPercent   int                `mapstructure:"community_min_deposit_percent"`
+	CheckOnReload                bool               `mapstructure:"check_on_reload"`
+	TallyUpdates                 TallyUpdatesConfig `mapstructure:"tally_updates"`
+	WhaleVotes                   WhaleVotesConfig   `mapstructure:"whale_votes"`
+}
+
+// TallyUpdatesConfig represents tally snapshots sent while proposals are in
+// voting period: when RemainingPercent of the voting period is left (e.g.
+// 75, 50, 25) and every IntervalHours since voting started. Both are
+// optional, the tally alert type disables them per network or channel
+type TallyUpdatesConfig struct {
+	RemainingPercent []int `mapstructure:"remaining_percent"`
+	IntervalHours    int   `mapstructure:"interval_hours"`
+}
+
+// WhaleVotesConfig represents alerts on votes cast or changed by validators
+// holding at least MinPowerPercent of bonded stake (0 disables them). Votes
+// are synced incrementally, at most MaxPages pages of votes per proposal
+// and check (0 means no limit)
+type WhaleVotesConfig struct {
+	MinPowerPercent float64 `mapstructure:"min_power_percent"`
+	MaxPages        int     `mapstructure:"max_pages"`
 }
 
-// NotificationConfig represents notification settings
+// SeverityPolicy assigns a severity to proposal alerts on the listed chain
+// IDs, of the listed proposal types (MsgUpdateParams, TextProposal...) and
+// alert types. Empty lists match all, the first matching policy wins and
+// alerts no policy matches are info
+type SeverityPolicy struct {
+	ChainIDs   []string `mapstructure:"chain_ids"`
+	Types      []string `mapstructure:"types"`
+	AlertTypes []string `mapstructure:"alert_types"`
+	Severity   string   `mapstructure:"severity"`
+}
+
+// QuietHoursConfig represents a daily window (HH:MM in Timezone) during
+// which non-urgent alerts are held back
+type QuietHoursConfig struct {
+	Start    string `mapstructure:"start"`
+	End      string `mapstructure:"end"`
+	Timezone string `mapstructure:"timezone"`
+}
+
+// NotificationConfig represents notification settings. TemplatesDir holds
+// optional telegram.tmpl, slack.tmpl and matrix.tmpl message templates, reloaded on
+// change. Retractions (edit or reply) withdraws alerts of proposals that
+// are cancelled or vetoed as spam, empty disables it
 type NotificationConfig struct {
-	Telegram TelegramConfig `mapstructure:"telegram"`
-	Slack    SlackConfig    `mapstructure:"slack"`
+	Telegram     TelegramConfig           `mapstructure:"telegram"`
+	Slack        SlackConfig              `mapstructure:"slack"`
+	Matrix       MatrixConfig             `mapstructure:"matrix"`
+	TallyCharts  bool                     `mapstructure:"tally_charts"`
+	Details      DetailsConfig            `mapstructure:"details"`
+	TemplatesDir string                   `mapstructure:"templates_dir"`
+	Retractions  string                   `mapstructure:"retractions"`
+	Reactions    ReactionsConfig          `mapstructure:"reactions"`
+	PagerDuty    PagerDutyConfig          `mapstructure:"pagerduty"`
+	Webhooks     []WebhookConfig          `mapstructure:"webhooks"`
+	Batching     BatchingConfig           `mapstructure:"batching"`
+	RateLimit    RateLimitConfig          `mapstructure:"rate_limit"`
+	URLPolicy    URLPolicyConfig          `mapstructure:"url_policy"`
+	Channels     map[string]ChannelConfig `mapstructure:"channels"`
+	Routes       []RouteConfig            `mapstructure:"routes"`
+}
+
+// ChannelConfig is a named destination of routing rules: another chat of
+// the Telegram bot (ChatID), another Slack incoming webhook (URL), another
+// room of the Matrix user (RoomID) or a generic webhook (URL, Secret and
+// PayloadStyle as in WebhookConfig)
+type ChannelConfig struct {
+	Type         string `mapstructure:"type"`
+	ChatID       int64  `mapstructure:"chat_id"`
+	RoomID       string `mapstructure:"room_id"`
+	URL          string `mapstructure:"url"`
+	URLFile      string `mapstructure:"url_file"`
+	Secret       string `mapstructure:"secret"`
+	SecretFile   string `mapstructure:"secret_file"`
+	PayloadStyle string `mapstructure:"payload_style"`
+}
+
+// Channel types, also the names of the built-in channels in routing rules
+// with RouteWebhooks and RoutePagerDuty
+const (
+	ChannelTelegram = "telegram"
+	ChannelSlack    = "slack"
+	ChannelMatrix   = "matrix"
+	ChannelWebhook  = "webhook"
+)
+
+// Built-in destinations of routing rules besides telegram and slack: every
+// entry of webhooks, and PagerDuty incidents for the matched proposals
+const (
+	RouteWebhooks  = "webhooks"
+	RoutePagerDuty = "pagerduty"
+)
+
+// RouteConfig is a routing rule sending the messages it matches to
+// Channels, named channels or built-in ones. Networks (network names),
+// ProposalTypes and AlertTypes must all match when set. Rules are tried in
+// order and the first match wins, unless Continue adds the channels of the
+// next matching rules. Messages no rule matches go to the built-in channels
+type RouteConfig struct {
+	Networks      []string `mapstructure:"networks"`
+	ProposalTypes []string `mapstructure:"proposal_types"`
+	AlertTypes    []string `mapstructure:"alert_types"`
+	Channels      []string `mapstructure:"channels"`
+	Continue      bool     `mapstructure:"continue"`
+}
+
+// URLPolicyConfig keeps links found in proposal text off the channels
+// serving Roles (community when empty): mode strip removes them, defang
+// rewrites them as hxxps://example[.]com so they are not clickable. The
+// explorer link of an alert is never touched. Empty mode disables it
+type URLPolicyConfig struct {
+	Mode  string   `mapstructure:"mode"`
+	Roles []string `mapstructure:"roles"`
+}
+
+// BatchingConfig limits the proposal alerts a chat channel receives per
+// check cycle. Alerts over MaxPerCycle are collapsed into one summary
+// linking to SummaryURL, such as the dashboard or the status API. Zero
+// disables batching
+type BatchingConfig struct {
+	MaxPerCycle int    `mapstructure:"max_per_cycle"`
+	SummaryURL  string `mapstructure:"summary_url"`
+}
+
+// RateLimitConfig paces the messages sent to each chat, room and webhook
+// to MessagesPerMinute, messages over the rate waiting their turn. Zero
+// disables pacing. Messages a channel rejects as rate limited (429) are
+// retried up to Retries times after the wait it asks for, unless that is
+// longer than MaxWaitSeconds
+type RateLimitConfig struct {
+	MessagesPerMinute int `mapstructure:"messages_per_minute"`
+	Retries           int `mapstructure:"retries"`
+	MaxWaitSeconds    int `mapstructure:"max_wait_seconds"`
 }
 
-// TelegramConfig represents Telegram notification settings
+// WebhookConfig represents a generic webhook that receives every alert as
+// JSON. With Secret set, the body is signed with HMAC-SHA256 in the
+// X-Signature-256 header. ProposalTypes limits its proposal alerts like
+// the chat channels'. PayloadStyle picks the full payload or the flat one
+// of automation platforms
+type WebhookConfig struct {
+	URL           string   `mapstructure:"url"`
+	URLFile       string   `mapstructure:"url_file"`
+	Secret        string   `mapstructure:"secret"`
+	SecretFile    string   `mapstructure:"secret_file"`
+	Roles         []string `mapstructure:"roles"`
+	AlertTypes    []string `mapstructure:"alert_types"`
+	ProposalTypes []string `mapstructure:"proposal_types"`
+	MinSeverity   string   `mapstructure:"min_severity"`
+	PayloadStyle  string   `mapstructure:"payload_style"`
+}
+
+// Webhook payload styles
+const (
+	PayloadStyleFull       = "full"
+	PayloadStyleAutomation = "automation"
+)
+
+// PagerDutyConfig represents PagerDuty incidents opened through the Events
+// API v2 for proposals of the listed types (short names such as
+// MsgSoftwareUpgrade), proposals whose alerts reach MinSeverity, and
+// tracked voters that have not voted UnvotedHoursBeforeEnd hours before
+// voting ends. Incidents are resolved when the proposal leaves voting
+type PagerDutyConfig struct {
+	Enabled               bool     `mapstructure:"enabled"`
+	RoutingKey            string   `mapstructure:"routing_key"`
+	RoutingKeyFile        string   `mapstructure:"routing_key_file"`
+	Types                 []string `mapstructure:"types"`
+	MinSeverity           string   `mapstructure:"min_severity"`
+	UnvotedHoursBeforeEnd int      `mapstructure:"unvoted_hours_before_end"`
+}
+
+// DetailsConfig represents how much proposal detail alerts carry. Long
+// descriptions are cut to MaxDescriptionLength characters (0 keeps them
+// whole) and, with Followup, the full details are sent as a follow-up
+// message for the listed networks and proposal types (empty lists match all)
+type DetailsConfig struct {
+	MaxDescriptionLength int      `mapstructure:"max_description_length"`
+	Followup             bool     `mapstructure:"followup"`
+	Networks             []string `mapstructure:"networks"`
+	Types                []string `mapstructure:"types"`
+}
+
+// Channel roles. Proposal alerts go to community channels, service health
+// and operational alerts to ops channels, and audit channels get everything
+const (
+	RoleCommunity = "community"
+	RoleOps       = "ops"
+	RoleAudit     = "audit"
+)
+
+// Proposal alert types. Channels can subscribe to a subset of them and
+// networks can disable some of them entirely
+const (
+	AlertNewProposal      = "new_proposal"
+	AlertVotingStart      = "voting_start"
+	AlertVotingEnd        = "voting_end"
+	AlertTally            = "tally"
+	AlertOutcome          = "outcome"
+	AlertUpgradeCountdown = "upgrade_countdown"
+	AlertVoteReminder     = "vote_reminder"
+	AlertUpgradeChecklist = "upgrade_checklist"
+	AlertProposalUpdated  = "proposal_updated"
+	AlertDepositExpiry    = "deposit_expiry"
+	AlertDepositReached   = "deposit_reached"
+	AlertWhaleVote        = "whale_vote"
+)
+
+// AlertTypes lists every proposal alert type
+var AlertTypes = []string{
+	AlertNewProposal,
+	AlertVotingStart,
+	AlertVotingEnd,
+	AlertTally,
+	AlertOutcome,
+	AlertUpgradeCountdown,
+	AlertVoteReminder,
+	AlertUpgradeChecklist,
+	AlertProposalUpdated,
+	AlertDepositExpiry,
+	AlertDepositReached,
+	AlertWhaleVote,
+}
+
+// Proposal alert severities, from least to most severe
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Severities lists every severity from least to most severe
+var Severities = []string{SeverityInfo, SeverityWarning, SeverityCritical}
+
+// TelegramConfig represents Telegram notification settings. MinSeverity
+// drops proposal alerts below it and ProposalTypes, when set, alerts about
+// proposals of other categories or message types. Admins (user IDs) and,
+// with ChatAdmins, the administrators of the chat are then the only users
+// allowed bot commands that change state
 type TelegramConfig struct {
-	Enabled  bool   `mapstructure:"enabled"`
-	BotToken string `mapstructure:"bot_token"`
-	ChatID   int64  `mapstructure:"chat_id"`
+	Enabled       bool     `mapstructure:"enabled"`
+	BotToken      string   `mapstructure:"bot_token"`
+	BotTokenFile  string   `mapstructure:"bot_token_file"`
+	ChatID        int64    `mapstructure:"chat_id"`
+	Roles         []string `mapstructure:"roles"`
+	AlertTypes    []string `mapstructure:"alert_types"`
+	ProposalTypes []string `mapstructure:"proposal_types"`
+	MinSeverity   string   `mapstructure:"min_severity"`
+	Admins        []int64  `mapstructure:"admins"`
+	ChatAdmins    bool     `mapstructure:"chat_admins"`
 }
 
-// SlackConfig represents Slack notification settings
+// SlackConfig represents Slack notification settings. MinSeverity drops
+// proposal alerts below it and ProposalTypes, when set, alerts about
+// proposals of other categories or message types. With BotToken, messages are posted to Channel
+// through the Web API instead of WebhookURL, which identifies them so
+// reactions reported by the Events API (verified with SigningSecret) can be
+// matched to alerts
 type SlackConfig struct {
-	Enabled    bool   `mapstructure:"enabled"`
-	WebhookURL string `mapstructure:"webhook_url"`
+	Enabled           bool     `mapstructure:"enabled"`
+	WebhookURL        string   `mapstructure:"webhook_url"`
+	WebhookURLFile    string   `mapstructure:"webhook_url_file"`
+	BotToken          string   `mapstructure:"bot_token"`
+	BotTokenFile      string   `mapstructure:"bot_token_file"`
+	Channel           string   `mapstructure:"channel"`
+	SigningSecret     string   `mapstructure:"signing_secret"`
+	SigningSecretFile string   `mapstructure:"signing_secret_file"`
+	Roles             []string `mapstructure:"roles"`
+	AlertTypes        []string `mapstructure:"alert_types"`
+	ProposalTypes     []string `mapstructure:"proposal_types"`
+	MinSeverity       string   `mapstructure:"min_severity"`
+}
+
+// MatrixConfig represents Matrix notification settings: messages are sent
+// to RoomID on HomeserverURL as the user of AccessToken, which must have
+// joined the room. Filters work as for Slack
+type MatrixConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	HomeserverURL   string   `mapstructure:"homeserver_url"`
+	AccessToken     string   `mapstructure:"access_token"`
+	AccessTokenFile string   `mapstructure:"access_token_file"`
+	RoomID          string   `mapstructure:"room_id"`
+	Roles           []string `mapstructure:"roles"`
+	AlertTypes      []string `mapstructure:"alert_types"`
+	ProposalTypes   []string `mapstructure:"proposal_types"`
+	MinSeverity     string   `mapstructure:"min_severity"`
+}
+
+// ReactionsConfig maps emoji reactions on alert messages to actions. Ack
+// stops further reminders for the proposal, Snooze holds them for
+// SnoozeHours. Emojis are given by name, e.g. "eyes" or "zzz"
+type ReactionsConfig struct {
+	Ack         string `mapstructure:"ack"`
+	Snooze      string `mapstructure:"snooze"`
+	SnoozeHours int    `mapstructure:"snooze_hours"`
 }
 
-// LoggingConfig represents logging settings
+// LoggingConfig represents logging settings. Level is used unless the
+// --log-level flag is given, Format is text (default) or json
 type LoggingConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
 }
 
-// Config represents the main configuration structure
+// PerformanceConfig represents polling performance settings. Each network
+// check is cancelled after NetworkTimeoutSeconds, so a hung endpoint does
+// not hold a worker for the whole cycle
+type PerformanceConfig struct {
+	MaxConcurrentNetworks int                 `mapstructure:"max_concurrent_networks"`
+	NetworkTimeoutSeconds int                 `mapstructure:"network_timeout_seconds"`
+	HTTP                  HTTPTransportConfig `mapstructure:"http"`
+}
+
+// HTTPTransportConfig tunes the connections to LCD endpoints, pooled across
+// networks. Responses are asked gzip compressed and HTTP/2 is negotiated
+// over TLS unless disabled. MaxIdleConnsPerHost connections (default 32,
+// 256 in total) stay open per host for IdleConnTimeoutSeconds (default
+// 90), MaxConnsPerHost caps them (0 is unlimited) and KeepAliveSeconds is
+// the TCP keep-alive period (default 30)
+type HTTPTransportConfig struct {
+	DisableCompression     bool `mapstructure:"disable_compression"`
+	DisableHTTP2           bool `mapstructure:"disable_http2"`
+	MaxIdleConns           int  `mapstructure:"max_idle_conns"`
+	MaxIdleConnsPerHost    int  `mapstructure:"max_idle_conns_per_host"`
+	MaxConnsPerHost        int  `mapstructure:"max_conns_per_host"`
+	IdleConnTimeoutSeconds int  `mapstructure:"idle_conn_timeout_seconds"`
+	KeepAliveSeconds       int  `mapstructure:"keep_alive_seconds"`
+}
+
+// FormattingConfig represents how token amounts are displayed in
+// messages and reports. Locale picks the separators (en 1,234.5, de
+// 1.234,5, fr 1 234,5, ch 1'234.5, plain 1234.5), for de and fr also the
+// words of durations, and Precision the decimals kept. Abbreviate shortens
+// amounts from a million up (1.2M ATOM). Denoms maps base denoms to their
+// display unit; denoms prefixed with u or a are assumed to have 6 or 18
+// decimals when not listed. SortBy orders proposals in lists, digests,
+// alert summaries and the dashboard by these keys in turn, network and ID
+// breaking the remaining ties
+type FormattingConfig struct {
+	Locale     string                 `mapstructure:"locale"`
+	Precision  int                    `mapstructure:"precision"`
+	Abbreviate bool                   `mapstructure:"abbreviate"`
+	Denoms     map[string]DenomConfig `mapstructure:"denoms"`
+	SortBy     []string               `mapstructure:"sort_by"`
+}
+
+// Sort keys of proposals: the next deadline (end of deposit or voting
+// period, soonest first), the severity (most severe first), the network
+// name and the proposal ID
+const (
+	SortDeadline = "deadline"
+	SortSeverity = "severity"
+	SortNetwork  = "network"
+	SortID       = "id"
+)
+
+// SortKeys lists every sort key
+var SortKeys = []string{SortDeadline, SortSeverity, SortNetwork, SortID}
+
+// DenomConfig is the display unit of a base denom: uatom is displayed as
+// ATOM with exponent 6
+type DenomConfig struct {
+	Display  string `mapstructure:"display"`
+	Exponent int    `mapstructure:"exponent"`
+}
+
+// MetricsConfig represents metrics export settings
+type MetricsConfig struct {
+	Textfile string `mapstructure:"textfile"`
+}
+
+// ReportsConfig represents periodic reports to the ops channels.
+// Reliability is daily, weekly or empty to disable the endpoint
+// reliability report. UpgradeTimeline keeps a pinned message listing the
+// upgrades scheduled across networks. Participation is daily, weekly or
+// empty to disable the voting power weighted participation digest over
+// the latest ParticipationProposals proposals of each network (default 10).
+// Heartbeat posts when every network was last and is next checked once an
+// hour
+type ReportsConfig struct {
+	Reliability            string `mapstructure:"reliability"`
+	UpgradeTimeline        bool   `mapstructure:"upgrade_timeline"`
+	Participation          string `mapstructure:"participation"`
+	ParticipationProposals int    `mapstructure:"participation_proposals"`
+	Heartbeat              bool   `mapstructure:"heartbeat"`
+}
+
+// StateConfig represents persistent state settings. Path is the JSON file
+// recording sent alerts, state is kept in memory only when empty
+type StateConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// PrivacyConfig represents privacy mode settings. When enabled, outbound
+// connections are limited to the configured LCDs and notification channels
+// plus AllowedHosts, host names or *.domain wildcards
+type PrivacyConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	AllowedHosts []string `mapstructure:"allowed_hosts"`
+}
+
+// APIConfig represents the operator HTTP API. It is disabled when Listen
+// is empty and requires Token as a bearer token when set
+type APIConfig struct {
+	Listen    string `mapstructure:"listen"`
+	Token     string `mapstructure:"token"`
+	TokenFile string `mapstructure:"token_file"`
+}
+
+// HealthConfig represents the health endpoints for orchestration probes,
+// served without authentication on Listen (disabled when empty) and on the
+// API. A network is stale when its last successful check is older than
+// StaleMinutes, three check intervals when unset. HeartbeatURL is pinged
+// after every check cycle in which all networks were checked, for dead man's
+// switch services such as healthchecks.io
+type HealthConfig struct {
+	Listen       string `mapstructure:"listen"`
+	StaleMinutes int    `mapstructure:"stale_minutes"`
+	HeartbeatURL string `mapstructure:"heartbeat_url"`
+}
+
+// Config represents the main configuration structure. DryRun polls and
+// evaluates alerts as usual but only logs what would be delivered, and
+// leaves the state file untouched
 type Config struct {
+	DryRun        bool                     `mapstructure:"dry_run"`
 	Alerts        AlertConfig              `mapstructure:"alerts"`
 	Networks      map[string]NetworkConfig `mapstructure:"networks"`
 	Notifications NotificationConfig       `mapstructure:"notifications"`
 	Logging       LoggingConfig            `mapstructure:"logging"`
+	Performance   PerformanceConfig        `mapstructure:"performance"`
+	Metrics       MetricsConfig            `mapstructure:"metrics"`
+	Privacy       PrivacyConfig            `mapstructure:"privacy"`
+	State         StateConfig              `mapstructure:"state"`
+	Reports       ReportsConfig            `mapstructure:"reports"`
+	API           APIConfig                `mapstructure:"api"`
+	Formatting    FormattingConfig         `mapstructure:"formatting"`
+	Health        HealthConfig             `mapstructure:"health"`
+	Secrets       SecretsConfig            `mapstructure:"secrets"`
+	Analytics     AnalyticsConfig          `mapstructure:"analytics"`
+	// NetworksFromRegistry are chain-registry chain names added to the
+	// networks, see RegistryConfig
+	NetworksFromRegistry []string       `mapstructure:"networks_from_registry"`
+	Registry             RegistryConfig `mapstructure:"registry"`
+}
+
+// AnalyticsConfig represents the analytics database the proposal timeline
+// events and tally snapshots are streamed to, Backend clickhouse or
+// bigquery. Rows are inserted in batches of BatchSize, at least every
+// FlushIntervalSeconds, into tables created and migrated at startup
+type AnalyticsConfig struct {
+	Enabled              bool             `mapstructure:"enabled"`
+	Backend              string           `mapstructure:"backend"`
+	BatchSize            int              `mapstructure:"batch_size"`
+	FlushIntervalSeconds int              `mapstructure:"flush_interval_seconds"`
+	ClickHouse           ClickHouseConfig `mapstructure:"clickhouse"`
+	BigQuery             BigQueryConfig   `mapstructure:"bigquery"`
+}
+
+// Analytics backends
+const (
+	AnalyticsClickHouse = "clickhouse"
+	AnalyticsBigQuery   = "bigquery"
+)
+
+// ClickHouseConfig represents a ClickHouse server reached over its HTTP
+// interface at URL, writing to Database as Username
+type ClickHouseConfig struct {
+	URL          string `mapstructure:"url"`
+	Database     string `mapstructure:"database"`
+	Username     string `mapstructure:"username"`
+	Password     string `mapstructure:"password"`
+	PasswordFile string `mapstructure:"password_file"`
+}
+
+// BigQueryConfig represents a BigQuery dataset of Project, written with
+// the JSON key of a service account in CredentialsFile
+type BigQueryConfig struct {
+	Project         string `mapstructure:"project"`
+	Dataset         string `mapstructure:"dataset"`
+	CredentialsFile string `mapstructure:"credentials_file"`
+}
+
+// RegistryConfig represents the cosmos/chain-registry the networks of
+// networks_from_registry are read from. CachePath keeps the last fetched
+// chains so the service still starts when the registry is unreachable
+type RegistryConfig struct {
+	URL       string `mapstructure:"url"`
+	CachePath string `mapstructure:"cache_path"`
+}
+
+// SecretsConfig represents the secret managers that secret settings can
+// reference instead of holding the secret: vault:<path>#<key> reads a key
+// of a Vault KV secret, aws-sm:<secret id>#<key> a key of a JSON secret in
+// AWS Secrets Manager, or the whole secret string without #<key>
+type SecretsConfig struct {
+	Vault VaultConfig      `mapstructure:"vault"`
+	AWS   AWSSecretsConfig `mapstructure:"aws"`
+}
+
+// VaultConfig represents the Vault server secrets are read from. Address
+// and Token default to the VAULT_ADDR and VAULT_TOKEN variables
+type VaultConfig struct {
+	Address   string `mapstructure:"address"`
+	Token     string `mapstructure:"token"`
+	TokenFile string `mapstructure:"token_file"`
+	Namespace string `mapstructure:"namespace"`
+}
+
+// AWSSecretsConfig represents AWS Secrets Manager. Region defaults to the
+// AWS_REGION variable and credentials come from AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN. Endpoint overrides the
+// regional endpoint, e.g. for a VPC endpoint
+type AWSSecretsConfig struct {
+	Region   string `mapstructure:"region"`
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// Note is a remark attached to a proposal by a team member
+type Note struct {
+	Text   string    `json:"text"`
+	Author string    `json:"author"`
+	Time   time.Time `json:"time"`
+}
+
+// TrackingLink is an artifact tracking a proposal outside the chain, such
+// as a JIRA issue, a GitHub issue or a forum thread. System names the
+// tracker (jira, github, discourse...) and ID the artifact in it
+type TrackingLink struct {
+	System string    `json:"system"`
+	ID     string    `json:"id"`
+	URL    string    `json:"url,omitempty"`
+	Author string    `json:"author,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// MessageRef identifies a message sent to a channel so it can be edited or
+// replied to later. Slack webhooks return no message ID, so Slack refs only
+// record that the channel got the alert unless posted with a bot token
+type MessageRef struct {
+	Channel      string `json:"channel"`
+	ChatID       int64  `json:"chat_id,omitempty"`
+	MessageID    int    `json:"message_id,omitempty"`
+	SlackChannel string `json:"slack_channel,omitempty"`
+	Timestamp    string `json:"ts,omitempty"`
+	RoomID       string `json:"room_id,omitempty"`
+	EventID      string `json:"event_id,omitempty"`
+	Text         string `json:"text,omitempty"`
+}
+
+// Ack is the acknowledgement of a proposal's alerts by a team member. A
+// zero Until acknowledges them for good, otherwise reminders are snoozed
+// until then
+type Ack struct {
+	User  string    `json:"user"`
+	Time  time.Time `json:"time"`
+	Until time.Time `json:"until,omitempty"`
+}
+
+// RuntimeFlags are behaviors toggled at runtime through the API to manage
+// incidents without a configuration change. They are persisted with the
+// alert state. PausedChannels are channel names as in channel health and
+// DryRunNetworks chain IDs
+type RuntimeFlags struct {
+	TallyPollingDisabled bool     `json:"tally_polling_disabled"`
+	PausedChannels       []string `json:"paused_channels"`
+	DryRunNetworks       []string `json:"dry_run_networks"`
+}
+
+// TimelineEvent is something the service observed or did about a proposal
+type TimelineEvent struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Detail string    `json:"detail"`
+}
+
+// Timeline event kinds
+const (
+	TimelineFirstSeen = "first_seen"
+	TimelineStatus    = "status"
+	TimelineTally     = "tally"
+	TimelineAlert     = "alert"
+	TimelineAck       = "ack"
+	TimelineUpdate    = "update"
+)
+
+// ProposalSnapshot is what was known of a proposal at its last check, to
+// detect edits between checks. The description is kept as a hash and a
+// length so long descriptions do not bloat the state
+type ProposalSnapshot struct {
+	Title             string    `json:"title"`
+	DescriptionHash   string    `json:"description_hash"`
+	DescriptionLength int       `json:"description_length"`
+	Status            string    `json:"status"`
+	DepositEnd        time.Time `json:"deposit_end,omitempty"`
+	VotingStart       time.Time `json:"voting_start,omitempty"`
+	VotingEnd         time.Time `json:"voting_end,omitempty"`
+	Seen              time.Time `json:"seen"`
+}
+
+// ParticipationSnapshot is the turnout of a proposal when its voting
+// ended: the voting power that voted against the bonded stake, in base
+// units. Estimated is set for snapshots backfilled after the fact, which
+// are measured against the bonded stake at backfill time
+type ParticipationSnapshot struct {
+	ProposalID uint64    `json:"proposal_id"`
+	Title      string    `json:"title"`
+	VotingEnd  time.Time `json:"voting_end"`
+	Voted      string    `json:"voted"`
+	Bonded     string    `json:"bonded"`
+	Estimated  bool      `json:"estimated,omitempty"`
+}
+
+// EndpointIdentity is the chain ID an endpoint reported at its last
+// verification. Mismatch is set when it differed from the configured one
+type EndpointIdentity struct {
+	Endpoint   string    `json:"endpoint"`
+	ChainID    string    `json:"chain_id"`
+	VerifiedAt time.Time `json:"verified_at"`
+	Mismatch   bool      `json:"mismatch,omitempty"`
 }
 
-// NotificationMessage represents a notification message
+// NotificationMessage represents a notification message. Proposal is set
+// on proposal alerts for channels that forward structured data, Tally on
+// those that fetched the current tally and Links on those of proposals
+// tracked outside the chain. Service messages set Event to the event type
+// of their webhook envelope, not derived from an alert type
 type NotificationMessage struct {
-	Title       string
-	Content     string
-	Network     string
-	ChainID     string
-	ProposalID  uint64
-	ExplorerURL string
+	Title       string         `json:"title"`
+	Content     string         `json:"content"`
+	Network     string         `json:"network"`
+	ChainID     string         `json:"chain_id"`
+	ProposalID  uint64         `json:"proposal_id,omitempty"`
+	ExplorerURL string         `json:"explorer_url,omitempty"`
+	Role        string         `json:"role,omitempty"`
+	AlertType   string         `json:"alert_type,omitempty"`
+	Severity    string         `json:"severity,omitempty"`
+	Chart       []byte         `json:"-"`
+	Details     string         `json:"details,omitempty"`
+	Proposal    *Proposal      `json:"proposal,omitempty"`
+	Tally       []TallyOption  `json:"tally,omitempty"`
+	Links       []TrackingLink `json:"links,omitempty"`
+	Event       string         `json:"-"`
 }
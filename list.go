@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listJSON bool
+	listSort []string
+)
+
+var listCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List the active proposals of all configured networks",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runList,
+}
+
+func init() {
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print JSON instead of a table")
+	listCmd.Flags().StringSliceVar(&listSort, "sort", nil, "Sort keys in turn, of "+strings.Join(types.SortKeys, ", ")+" (default formatting.sort_by)")
+	rootCmd.AddCommand(listCmd)
+}
+
+// listedProposal is an active proposal printed by list
+type listedProposal struct {
+	Network     string    `json:"network"`
+	ChainID     string    `json:"chain_id"`
+	ID          uint64    `json:"id"`
+	Title       string    `json:"title"`
+	Category    string    `json:"category"`
+	Status      string    `json:"status"`
+	VotingStart time.Time `json:"voting_start"`
+	VotingEnd   time.Time `json:"voting_end"`
+	DepositEnd  time.Time `json:"deposit_end"`
+	Severity    string    `json:"severity,omitempty"`
+}
+
+// listFailure is a network whose proposals could not be fetched
+type listFailure struct {
+	Network string `json:"network"`
+	Error   string `json:"error"`
+}
+
+// runList queries every configured network once, without the daemon, and
+// prints the proposals in deposit or voting period, most urgent first
+func runList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	sortBy := cfg.Formatting.SortBy
+	if cmd.Flags().Changed("sort") {
+		sortBy = listSort
+	}
+	for _, key := range sortBy {
+		if !slices.Contains(types.SortKeys, key) {
+			return fmt.Errorf("unknown sort key %q (expected one of %s)", key, strings.Join(types.SortKeys, ", "))
+		}
+	}
+
+	privacy.Install(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		proposals = []listedProposal{}
+		failures  = []listFailure{}
+	)
+	for name, networkConfig := range cfg.Networks {
+		wg.Add(1)
+		go func(name string, networkConfig types.NetworkConfig) {
+			defer wg.Done()
+
+			active, err := listNetwork(ctx, networkConfig)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, listFailure{Network: name, Error: err.Error()})
+				return
+			}
+			for _, proposal := range active {
+				proposals = append(proposals, listedProposal{
+					Network:     name,
+					ChainID:     networkConfig.ChainID,
+					ID:          proposal.ID,
+					Title:       proposal.Title,
+					Category:    proposal.Category,
+					Status:      proposal.Status,
+					VotingStart: proposal.VotingStart,
+					VotingEnd:   proposal.VotingEnd,
+					DepositEnd:  proposal.DepositEnd,
+					Severity:    governance.Severity(cfg.Alerts.SeverityPolicies, networkConfig.ChainID, proposal, types.AlertVotingEnd),
+				})
+			}
+		}(name, networkConfig)
+	}
+	wg.Wait()
+
+	sort.Slice(proposals, func(i, j int) bool {
+		return governance.MoreUrgent(sortBy, proposals[i].urgency(), proposals[j].urgency())
+	})
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Network < failures[j].Network })
+
+	if listJSON {
+		out, err := json.MarshalIndent(map[string]interface{}{"proposals": proposals, "errors": failures}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode proposals: %w", err)
+		}
+		fmt.Println(string(out))
+	} else {
+		printProposalTable(proposals, time.Now())
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", failure.Network, failure.Error)
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d networks could not be listed", len(failures), len(cfg.Networks))
+	}
+	return nil
+}
+
+// urgency returns what a listed proposal is sorted by
+func (p listedProposal) urgency() governance.Urgency {
+	deadline := p.VotingEnd
+	if p.Status == string(cosmosgov.StatusDepositPeriod) {
+		deadline = p.DepositEnd
+	}
+	return governance.Urgency{Network: p.Network, ID: p.ID, Deadline: deadline, Severity: p.Severity}
+}
+
+// listNetwork fetches the proposals of a network in deposit or voting period
+func listNetwork(ctx context.Context, networkConfig types.NetworkConfig) ([]types.Proposal, error) {
+	client, err := governance.NewClient(networkConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	return client.GetActiveProposals(ctx)
+}
+
+// printProposalTable prints proposals as an aligned table. The window is
+// the voting period, or the deposit deadline of proposals in deposit period
+func printProposalTable(proposals []listedProposal, now time.Time) {
+	if len(proposals) == 0 {
+		fmt.Println("No active proposals")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NETWORK\tID\tSTATUS\tWINDOW\tREMAINING\tTITLE")
+	for _, proposal := range proposals {
+		status, window, deadline := "voting", fmt.Sprintf("%s → %s", proposal.VotingStart.Local().Format("01-02 15:04"), proposal.VotingEnd.Local().Format("01-02 15:04")), proposal.VotingEnd
+		if proposal.Status == string(cosmosgov.StatusDepositPeriod) {
+			status, window, deadline = "deposit", "deposit until "+proposal.DepositEnd.Local().Format("01-02 15:04"), proposal.DepositEnd
+		}
+
+		remaining := countdown(deadline.Sub(now))
+		if !deadline.After(now) {
+			remaining = "ended"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", proposal.Network, proposal.ID, status, window, remaining, truncateRunes(strings.TrimSpace(proposal.Title), 60))
+	}
+	w.Flush()
+}
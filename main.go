@@ -2,13 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"governance-alerts-cosmos/internal/api"
 	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/lifecycle"
+	"governance-alerts-cosmos/internal/privacy"
 	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/types"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -17,6 +26,8 @@ import (
 var (
 	configPath string
 	logLevel   string
+	pprofAddr  string
+	dryRun     bool
 )
 
 var rootCmd = &cobra.Command{
@@ -24,12 +35,35 @@ var rootCmd = &cobra.Command{
 	Short: "A service that monitors governance proposals on Cosmos networks",
 	Long: `A service that monitors governance proposals on Cosmos networks and sends 
 notifications when voting is about to start or end.`,
-	RunE: run,
+	RunE:          run,
+	SilenceErrors: true,
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&configPath, "config", "c", "config/config.yaml", "Path to configuration file")
-	rootCmd.Flags().StringVarP(&logLevel, "log-level", "l", "info", "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config/config.yaml", "Path to configuration file")
+	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "info", "Log level (debug, info, warn, error)")
+	rootCmd.Flags().StringVar(&pprofAddr, "pprof-addr", "", "Expose net/http/pprof profiling endpoints on this address (e.g. localhost:6060)")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Poll and evaluate alerts but only log what would be sent, leaving the state file untouched (overrides dry_run)")
+}
+
+// configureLogging applies the logging configuration. The --log-level flag
+// takes precedence over the configured level
+func configureLogging(cmd *cobra.Command, logging types.LoggingConfig) error {
+	if logging.Level != "" && !cmd.Flags().Changed("log-level") {
+		level, err := logrus.ParseLevel(logging.Level)
+		if err != nil {
+			return fmt.Errorf("invalid log level: %w", err)
+		}
+		logrus.SetLevel(level)
+	}
+
+	switch logging.Format {
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	}
+	return nil
 }
 
 func run(cmd *cobra.Command, args []string) error {
@@ -42,51 +76,203 @@ func run(cmd *cobra.Command, args []string) error {
 
 	// Load configuration
 	cfg, err := config.LoadConfig(configPath)
-	if err != nil {
+	if err != nil && !demoMode {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// Demo mode replaces the networks with a fake one, the configuration
+	// is only needed for notification channels
+	if demoMode {
+		if err != nil {
+			logrus.Warnf("Demo mode without configuration, alerts are only logged: %v", err)
+			if cfg, err = config.Default(); err != nil {
+				return err
+			}
+		}
+		stopDemo := startDemo(cfg)
+		defer stopDemo()
+		logrus.Info("Demo mode: monitoring a fake network with a new proposal every 2 minutes")
+	}
+
+	if err := configureLogging(cmd, cfg.Logging); err != nil {
+		return err
+	}
+	if dryRun {
+		cfg.DryRun = true
+	}
+
 	logrus.Info("Configuration loaded successfully")
+	if cfg.DryRun {
+		logrus.Warn("Dry run: notifications are only logged and the state file is not written")
+	}
 	logrus.Infof("Monitoring %d networks", len(cfg.Networks))
 	for name, network := range cfg.Networks {
 		logrus.Infof("  - %s (%s)", name, network.Name)
 	}
 
+	// Restrict outbound traffic before any client is created
+	privacy.Install(cfg)
+	if privacy.Active() {
+		logrus.Infof("Privacy mode enabled, outbound traffic limited to: %s", strings.Join(privacy.AllowedHosts(cfg), ", "))
+	}
+
 	// Create service
 	svc, err := service.NewService(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create service: %w", err)
 	}
 
-	// Create context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	logrus.Info("Service started. Press Ctrl+C to stop.")
-
-	// Start service in goroutine
-	go func() {
-		if err := svc.Run(ctx); err != nil {
-			logrus.Errorf("Service error: %v", err)
+	// Reload the configuration on SIGHUP or when the file changes. Demo
+	// mode replaces the networks and is not reloaded, nor is there a file
+	// to watch when the environment configures the service alone
+	reload := make(chan struct{}, 1)
+	hupChan := make(chan os.Signal, 1)
+	watchConfig := false
+	if !demoMode {
+		signal.Notify(hupChan, syscall.SIGHUP)
+		if config.EnvOnly(configPath) {
+			logrus.Infof("%s not found, configured from %s environment variables", configPath, config.EnvPrefix)
+		} else {
+			watchConfig = true
 		}
-	}()
+	}
 
-	<-sigChan
+	// Start the components in dependency order, they are stopped in reverse
+	components, err := newComponents(cfg, svc, watchConfig, reload)
+	if err != nil {
+		return err
+	}
+	svc.SetComponents(components.Statuses)
+	if err := components.Start(context.Background()); err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
 
-	// Stop service
-	svc.Stop()
+	logrus.Info("Service started. Press Ctrl+C to stop.")
+
+	for stopped := false; !stopped; {
+		select {
+		case <-sigChan:
+			stopped = true
+		case <-hupChan:
+			logrus.Info("SIGHUP received, reloading configuration")
+			cfg = reloadConfig(cmd, svc, cfg)
+		case <-reload:
+			logrus.Info("Configuration file changed, reloading")
+			cfg = reloadConfig(cmd, svc, cfg)
+		}
+	}
 
+	if err := components.Stop(); err != nil {
+		logrus.Warnf("Service stopped with components left running: %v", err)
+		return nil
+	}
 	logrus.Info("Service stopped gracefully")
 	return nil
 }
 
+// newComponents returns the components of the service: the health
+// endpoints first so probes answer until the end, then the monitoring loop
+// with its bots, and the operator API, configuration watcher and profiling
+// server that depend on it
+func newComponents(cfg *types.Config, svc *service.Service, watchConfig bool, reload chan<- struct{}) (*lifecycle.Manager, error) {
+	components := lifecycle.NewManager()
+
+	if cfg.Health.Listen != "" {
+		healthServer := api.NewHealthServer(cfg.Health.Listen, svc)
+		logrus.Infof("Health endpoints available at http://%s/healthz and /readyz", cfg.Health.Listen)
+		if err := components.Add(serverComponent("health", healthServer, nil)); err != nil {
+			return nil, err
+		}
+	}
+
+	// The monitoring loop finishes the request of a check in progress and
+	// stops the Telegram poller, whose long poll lasts up to 10s
+	err := components.Add(lifecycle.Component{
+		Name:        "service",
+		Run:         svc.Run,
+		StopTimeout: serviceStopTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.API.Listen != "" {
+		apiServer := api.NewServer(cfg.API, svc)
+		logrus.Infof("API available at http://%s/api/v1/", cfg.API.Listen)
+		if err := components.Add(serverComponent("api", apiServer, []string{"service"})); err != nil {
+			return nil, err
+		}
+	}
+
+	if watchConfig {
+		err := components.Add(lifecycle.Component{
+			Name:      "config_watcher",
+			DependsOn: []string{"service"},
+			Run: func(ctx context.Context) error {
+				watchConfigFile(ctx, configPath, reload)
+				<-ctx.Done()
+				return nil
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if pprofAddr != "" {
+		profiling := &http.Server{Addr: pprofAddr, Handler: http.DefaultServeMux, ReadHeaderTimeout: 10 * time.Second}
+		logrus.Infof("Profiling endpoints available at http://%s/debug/pprof/", pprofAddr)
+		err := components.Add(lifecycle.Component{
+			Name: "pprof",
+			Run: func(ctx context.Context) error {
+				if err := profiling.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+				return nil
+			},
+			Stop: profiling.Shutdown,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return components, nil
+}
+
+// serviceStopTimeout bounds how long the monitoring loop may take to stop
+const serviceStopTimeout = 15 * time.Second
+
+// serverComponent runs an HTTP server until its graceful shutdown
+func serverComponent(name string, server *api.Server, dependsOn []string) lifecycle.Component {
+	return lifecycle.Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run:       func(ctx context.Context) error { return server.ListenAndServe() },
+		Stop:      server.Shutdown,
+	}
+}
+
+// exitError is an error that exits with a specific code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,100 @@
+package cosmosgov
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bech32Charset is the bech32 data alphabet
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Polymod computes the bech32 checksum polynomial
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human readable part for checksum computation
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Decode splits an address into its human readable part and 5-bit
+// data, verifying the checksum
+func bech32Decode(address string) (string, []byte, error) {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return "", nil, fmt.Errorf("mixed case address %q", address)
+	}
+	address = strings.ToLower(address)
+
+	sep := strings.LastIndexByte(address, '1')
+	if sep < 1 || sep+7 > len(address) {
+		return "", nil, fmt.Errorf("invalid bech32 address %q", address)
+	}
+
+	hrp := address[:sep]
+	data := make([]byte, 0, len(address)-sep-1)
+	for _, c := range address[sep+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid character %q in address %q", c, address)
+		}
+		data = append(data, byte(v))
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != 1 {
+		return "", nil, fmt.Errorf("invalid checksum in address %q", address)
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// bech32Encode encodes 5-bit data with a human readable part
+func bech32Encode(hrp string, data []byte) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range data {
+		b.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		b.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return b.String()
+}
+
+// OperatorAccount returns the account address of a validator operator
+// address (cosmosvaloper1... to cosmos1...), which is the address the
+// validator votes with
+func OperatorAccount(operator string) (string, error) {
+	hrp, data, err := bech32Decode(operator)
+	if err != nil {
+		return "", err
+	}
+
+	prefix, ok := strings.CutSuffix(hrp, "valoper")
+	if !ok || prefix == "" {
+		return "", fmt.Errorf("%q is not a validator operator address", operator)
+	}
+	return bech32Encode(prefix, data), nil
+}
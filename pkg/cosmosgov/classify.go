@@ -0,0 +1,60 @@
+package cosmosgov
+
+// Proposal categories, grouping the message types that have the same
+// effect across SDK versions and modules
+const (
+	CategorySoftwareUpgrade    = "software_upgrade"
+	CategoryParameterChange    = "parameter_change"
+	CategoryCommunityPoolSpend = "community_pool_spend"
+	CategoryClientUpdate       = "client_update"
+	CategoryText               = "text"
+	CategoryOther              = "other"
+)
+
+// Categories lists every proposal category
+var Categories = []string{
+	CategorySoftwareUpgrade,
+	CategoryParameterChange,
+	CategoryCommunityPoolSpend,
+	CategoryClientUpdate,
+	CategoryText,
+	CategoryOther,
+}
+
+// categoryByType maps short message and legacy content type names to their
+// category
+var categoryByType = map[string]string{
+	"MsgSoftwareUpgrade":                    CategorySoftwareUpgrade,
+	"MsgCancelUpgrade":                      CategorySoftwareUpgrade,
+	"SoftwareUpgradeProposal":               CategorySoftwareUpgrade,
+	"CancelSoftwareUpgradeProposal":         CategorySoftwareUpgrade,
+	"MsgIBCSoftwareUpgrade":                 CategorySoftwareUpgrade,
+	"UpgradeProposal":                       CategorySoftwareUpgrade,
+	"MsgUpdateParams":                       CategoryParameterChange,
+	"ParameterChangeProposal":               CategoryParameterChange,
+	"MsgCommunityPoolSpend":                 CategoryCommunityPoolSpend,
+	"CommunityPoolSpendProposal":            CategoryCommunityPoolSpend,
+	"CommunityPoolSpendProposalWithDeposit": CategoryCommunityPoolSpend,
+	"MsgRecoverClient":                      CategoryClientUpdate,
+	"ClientUpdateProposal":                  CategoryClientUpdate,
+	"TextProposal":                          CategoryText,
+}
+
+// Category classifies a proposal by its messages, or by the content of
+// wrapped legacy proposals. The first message of a known category wins;
+// proposals without messages are signaling (text) proposals
+func (p *Proposal) Category() string {
+	if len(p.Messages) == 0 {
+		return CategoryText
+	}
+	for _, msg := range p.Messages {
+		typeURL := msg.TypeURL
+		if msg.Content != nil && msg.Content.TypeURL != "" {
+			typeURL = msg.Content.TypeURL
+		}
+		if category, ok := categoryByType[TypeName(typeURL)]; ok {
+			return category
+		}
+	}
+	return CategoryOther
+}
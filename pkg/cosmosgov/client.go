@@ -0,0 +1,366 @@
+package cosmosgov
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultUserAgent is sent when no user agent is configured
+const defaultUserAgent = "cosmosgov/" + Version
+
+// bufferPool recycles response buffers between requests
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// Client queries the gov module of one chain over its LCD REST API, or
+// its gRPC API when created with NewGRPC
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+	userAgent  string
+	apiVersion atomic.Int32
+	grpc       bool
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the LCD REST endpoint of a chain
+func New(endpoint string, opts ...Option) *Client {
+	c := &Client{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		userAgent:  defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Endpoint returns the LCD or gRPC endpoint of the client
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// ListProposals lists one page of proposals, optionally filtered by status.
+// Nodes that only serve the gov v1beta1 API are queried through it
+func (c *Client) ListProposals(ctx context.Context, req ListProposalsRequest) ([]Proposal, PageResponse, error) {
+	query := pageQuery(req.Page)
+	if req.Status != "" {
+		query.Set("proposal_status", string(req.Status))
+	}
+	if c.legacyAPI() {
+		return c.listProposalsV1beta1(ctx, query)
+	}
+
+	var response struct {
+		Proposals  []lcdProposal   `json:"proposals"`
+		Pagination lcdPageResponse `json:"pagination"`
+	}
+	if err := c.get(ctx, "/cosmos/gov/v1/proposals", query, &response); err != nil {
+		if c.fallBack(ctx, err) {
+			return c.listProposalsV1beta1(ctx, query)
+		}
+		return nil, PageResponse{}, fmt.Errorf("failed to list proposals: %w", err)
+	}
+
+	// Skip proposals that fail to normalize rather than failing the page
+	proposals := make([]Proposal, 0, len(response.Proposals))
+	var invalid []error
+	for _, raw := range response.Proposals {
+		proposal, err := raw.normalize()
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("proposal %s: %w", raw.ID, err))
+			continue
+		}
+		proposals = append(proposals, *proposal)
+	}
+
+	if len(invalid) > 0 {
+		return proposals, response.Pagination.normalize(), &InvalidProposalsError{Errors: invalid}
+	}
+	return proposals, response.Pagination.normalize(), nil
+}
+
+// InvalidProposalsError is returned by ListProposals together with the
+// valid proposals of a page when some proposals could not be normalized
+type InvalidProposalsError struct {
+	Errors []error
+}
+
+// Error implements error
+func (e *InvalidProposalsError) Error() string {
+	return fmt.Sprintf("%d invalid proposals: %v", len(e.Errors), errors.Join(e.Errors...))
+}
+
+// StatusError is returned when the LCD answers with a status other than 200
+type StatusError struct {
+	Code int
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.Code)
+}
+
+// IsNotFound reports whether err is a 404 answer of the LCD. Pruned nodes
+// and proposals deleted by cancellation return 404
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
+
+// GetProposal fetches a single proposal
+func (c *Client) GetProposal(ctx context.Context, proposalID uint64) (*Proposal, error) {
+	if c.legacyAPI() {
+		return c.getProposalV1beta1(ctx, proposalID)
+	}
+
+	var response struct {
+		Proposal lcdProposal `json:"proposal"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/cosmos/gov/v1/proposals/%d", proposalID), nil, &response); err != nil {
+		if c.fallBack(ctx, err) {
+			return c.getProposalV1beta1(ctx, proposalID)
+		}
+		return nil, fmt.Errorf("failed to fetch proposal %d: %w", proposalID, err)
+	}
+
+	proposal, err := response.Proposal.normalize()
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposal %d: %w", proposalID, err)
+	}
+	return proposal, nil
+}
+
+// GetTally fetches the current tally of a proposal
+func (c *Client) GetTally(ctx context.Context, proposalID uint64) (*TallyResult, error) {
+	if c.legacyAPI() {
+		return c.getTallyV1beta1(ctx, proposalID)
+	}
+
+	var response struct {
+		Tally lcdTally `json:"tally"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/cosmos/gov/v1/proposals/%d/tally", proposalID), nil, &response); err != nil {
+		if c.fallBack(ctx, err) {
+			return c.getTallyV1beta1(ctx, proposalID)
+		}
+		return nil, fmt.Errorf("failed to fetch tally for proposal %d: %w", proposalID, err)
+	}
+
+	tally := response.Tally.normalize()
+	return &tally, nil
+}
+
+// GetVotes fetches one page of votes cast on a proposal
+func (c *Client) GetVotes(ctx context.Context, proposalID uint64, page PageRequest) ([]Vote, PageResponse, error) {
+	if c.legacyAPI() {
+		return c.getVotesV1beta1(ctx, proposalID, page)
+	}
+
+	var response struct {
+		Votes      []lcdVote       `json:"votes"`
+		Pagination lcdPageResponse `json:"pagination"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/cosmos/gov/v1/proposals/%d/votes", proposalID), pageQuery(page), &response); err != nil {
+		if c.fallBack(ctx, err) {
+			return c.getVotesV1beta1(ctx, proposalID, page)
+		}
+		return nil, PageResponse{}, fmt.Errorf("failed to fetch votes for proposal %d: %w", proposalID, err)
+	}
+
+	votes := make([]Vote, 0, len(response.Votes))
+	for _, raw := range response.Votes {
+		votes = append(votes, raw.normalize())
+	}
+	return votes, response.Pagination.normalize(), nil
+}
+
+// GetVote fetches the vote of voter on a proposal. The LCD answers 404, or
+// 400 on SDK 0.47 and older, when the voter has not voted
+func (c *Client) GetVote(ctx context.Context, proposalID uint64, voter string) (*Vote, error) {
+	if c.legacyAPI() {
+		return c.getVoteV1beta1(ctx, proposalID, voter)
+	}
+
+	var response struct {
+		Vote lcdVote `json:"vote"`
+	}
+	path := fmt.Sprintf("/cosmos/gov/v1/proposals/%d/votes/%s", proposalID, url.PathEscape(voter))
+	if err := c.get(ctx, path, nil, &response); err != nil {
+		if c.fallBack(ctx, err) {
+			return c.getVoteV1beta1(ctx, proposalID, voter)
+		}
+		return nil, fmt.Errorf("failed to fetch vote of %s on proposal %d: %w", voter, proposalID, err)
+	}
+
+	vote := response.Vote.normalize()
+	return &vote, nil
+}
+
+// IsNoVote reports whether err is the answer of GetVote for a voter that
+// has not voted
+func IsNoVote(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && (statusErr.Code == http.StatusNotFound || statusErr.Code == http.StatusBadRequest)
+}
+
+// GetParams fetches the gov module parameters. SDK 0.47+ returns all of
+// them at once, older versions need one query per parameter type
+func (c *Client) GetParams(ctx context.Context) (*Params, error) {
+	params := &Params{}
+	for _, paramsType := range []string{"tallying", "voting", "deposit"} {
+		var response lcdParamsResponse
+		err := c.get(ctx, c.govPath()+"/params/"+paramsType, nil, &response)
+		if err != nil && c.fallBack(ctx, err) {
+			err = c.get(ctx, c.govPath()+"/params/"+paramsType, nil, &response)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s params: %w", paramsType, err)
+		}
+
+		if response.Params != nil {
+			return response.Params.normalize()
+		}
+		if err := response.mergeInto(params); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// GetStakingPool fetches the bonded and not bonded tokens of the chain,
+// bonded tokens are the base quorum is computed against
+func (c *Client) GetStakingPool(ctx context.Context) (*StakingPool, error) {
+	var response struct {
+		Pool StakingPool `json:"pool"`
+	}
+	if err := c.get(ctx, "/cosmos/staking/v1beta1/pool", nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch staking pool: %w", err)
+	}
+	return &response.Pool, nil
+}
+
+// GetCurrentPlan fetches the upgrade plan scheduled on the chain, nil if
+// none is
+func (c *Client) GetCurrentPlan(ctx context.Context) (*UpgradePlan, error) {
+	var response struct {
+		Plan *lcdPlan `json:"plan"`
+	}
+	if err := c.get(ctx, "/cosmos/upgrade/v1beta1/current_plan", nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch current upgrade plan: %w", err)
+	}
+	if response.Plan == nil {
+		return nil, nil
+	}
+	return response.Plan.normalize()
+}
+
+// GetLatestBlock fetches the height and time of the latest block
+func (c *Client) GetLatestBlock(ctx context.Context) (*Block, error) {
+	return c.getBlock(ctx, "latest")
+}
+
+// GetBlock fetches the height and time of the block at height
+func (c *Client) GetBlock(ctx context.Context, height int64) (*Block, error) {
+	return c.getBlock(ctx, strconv.FormatInt(height, 10))
+}
+
+// getBlock fetches a block by height or "latest"
+func (c *Client) getBlock(ctx context.Context, height string) (*Block, error) {
+	var response struct {
+		Block lcdBlock `json:"block"`
+	}
+	if err := c.get(ctx, "/cosmos/base/tendermint/v1beta1/blocks/"+height, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch block %s: %w", height, err)
+	}
+	return response.Block.normalize()
+}
+
+// get makes a GET request and decodes the JSON body into v
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	if c.grpc {
+		return c.getGRPC(ctx, path, query, v)
+	}
+
+	apiURL := c.endpoint + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Code: resp.StatusCode}
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// pageQuery encodes a page request as LCD query parameters
+func pageQuery(page PageRequest) url.Values {
+	query := url.Values{}
+	if page.Key != "" {
+		query.Set("pagination.key", page.Key)
+	}
+	if page.Limit > 0 {
+		query.Set("pagination.limit", strconv.FormatUint(page.Limit, 10))
+	}
+	if page.CountTotal {
+		query.Set("pagination.count_total", "true")
+	}
+	return query
+}
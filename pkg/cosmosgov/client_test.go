@@ -0,0 +1,296 @@
+package cosmosgov
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lcdNode is an LCD answering the requests in responses, keyed by path and
+// encoded query, with their JSON body. Other requests are answered with
+// missing, 501 when zero. It records the requests it received
+type lcdNode struct {
+	responses map[string]string
+	missing   int
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (n *lcdNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Path
+	if query := r.URL.Query().Encode(); query != "" {
+		key += "?" + query
+	}
+	n.mu.Lock()
+	n.requests = append(n.requests, key)
+	n.mu.Unlock()
+
+	body, ok := n.responses[key]
+	if !ok {
+		status := n.missing
+		if status == 0 {
+			status = http.StatusNotImplemented
+		}
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(body))
+}
+
+// received returns the requests received so far
+func (n *lcdNode) received() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.requests...)
+}
+
+// newLCD starts node and returns a client of it
+func newLCD(t *testing.T, node *lcdNode) *Client {
+	t.Helper()
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	return New(server.URL, WithHTTPClient(server.Client()))
+}
+
+func TestListAllProposalsPagination(t *testing.T) {
+	node := &lcdNode{responses: map[string]string{
+		"/cosmos/gov/v1/proposals?pagination.limit=100&proposal_status=PROPOSAL_STATUS_VOTING_PERIOD": `{
+			"proposals": [
+				{"id": "1", "title": "First", "status": "PROPOSAL_STATUS_VOTING_PERIOD", "voting_end_time": "2026-10-20T12:00:00Z"},
+				{"id": "2", "title": "Second", "status": "PROPOSAL_STATUS_VOTING_PERIOD"}
+			],
+			"pagination": {"next_key": "AAAAAAAAAAM=", "total": "0"}
+		}`,
+		"/cosmos/gov/v1/proposals?pagination.key=AAAAAAAAAAM%3D&pagination.limit=100&proposal_status=PROPOSAL_STATUS_VOTING_PERIOD": `{
+			"proposals": [
+				{"id": "bad", "title": "Unparsable ID"},
+				{"id": "3", "title": "Third", "summary": "Summary wins", "description": "Old description"}
+			],
+			"pagination": {"next_key": null}
+		}`,
+	}}
+	client := newLCD(t, node)
+
+	proposals, err := client.ListAllProposals(context.Background(), ListProposalsRequest{Status: StatusVotingPeriod})
+	var invalid *InvalidProposalsError
+	if !errors.As(err, &invalid) || len(invalid.Errors) != 1 {
+		t.Fatalf("ListAllProposals() error = %v, want one invalid proposal", err)
+	}
+
+	var ids []uint64
+	for _, proposal := range proposals {
+		ids = append(ids, proposal.ID)
+	}
+	if !reflect.DeepEqual(ids, []uint64{1, 2, 3}) {
+		t.Errorf("proposal IDs = %v, want [1 2 3]", ids)
+	}
+	if want := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC); !proposals[0].VotingEnd.Equal(want) {
+		t.Errorf("voting end = %s, want %s", proposals[0].VotingEnd, want)
+	}
+	if proposals[2].Description != "Summary wins" {
+		t.Errorf("description = %q, want the summary", proposals[2].Description)
+	}
+	if got := len(node.received()); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestListAllProposalsRepeatedKey(t *testing.T) {
+	node := &lcdNode{responses: map[string]string{
+		"/cosmos/gov/v1/proposals?pagination.limit=10":                     `{"proposals": [], "pagination": {"next_key": "a2V5"}}`,
+		"/cosmos/gov/v1/proposals?pagination.key=a2V5&pagination.limit=10": `{"proposals": [], "pagination": {"next_key": "a2V5"}}`,
+	}}
+	client := newLCD(t, node)
+
+	_, err := client.ListAllProposals(context.Background(), ListProposalsRequest{Page: PageRequest{Limit: 10}})
+	if err == nil {
+		t.Fatal("ListAllProposals() error = nil, want repeated key error")
+	}
+}
+
+func TestGetTally(t *testing.T) {
+	node := &lcdNode{responses: map[string]string{
+		"/cosmos/gov/v1/proposals/7/tally": `{"tally": {"yes_count": "600", "abstain_count": "50", "no_count": "300", "no_with_veto_count": "50"}}`,
+	}}
+	client := newLCD(t, node)
+
+	tally, err := client.GetTally(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetTally() error = %v", err)
+	}
+	want := &TallyResult{Yes: "600", Abstain: "50", No: "300", NoWithVeto: "50"}
+	if !reflect.DeepEqual(tally, want) {
+		t.Errorf("GetTally() = %+v, want %+v", tally, want)
+	}
+}
+
+func TestGetParams(t *testing.T) {
+	want := &Params{
+		MinDeposit:       []Coin{{Denom: "uatom", Amount: "250000000"}},
+		MaxDepositPeriod: 14 * 24 * time.Hour,
+		VotingPeriod:     14 * 24 * time.Hour,
+		Quorum:           "0.400000000000000000",
+		Threshold:        "0.500000000000000000",
+		VetoThreshold:    "0.334000000000000000",
+	}
+
+	tests := []struct {
+		name      string
+		responses map[string]string
+		requests  int
+	}{
+		{
+			name: "SDK 0.47 params",
+			responses: map[string]string{
+				"/cosmos/gov/v1/params/tallying": `{"params": {
+					"min_deposit": [{"denom": "uatom", "amount": "250000000"}],
+					"max_deposit_period": "1209600s", "voting_period": "1209600s",
+					"quorum": "0.400000000000000000", "threshold": "0.500000000000000000", "veto_threshold": "0.334000000000000000"
+				}}`,
+			},
+			requests: 1,
+		},
+		{
+			name: "typed params",
+			responses: map[string]string{
+				"/cosmos/gov/v1/params/tallying": `{
+					"voting_params": {"voting_period": "0s"},
+					"deposit_params": {"min_deposit": [], "max_deposit_period": "0s"},
+					"tally_params": {"quorum": "0.400000000000000000", "threshold": "0.500000000000000000", "veto_threshold": "0.334000000000000000"}
+				}`,
+				"/cosmos/gov/v1/params/voting": `{
+					"voting_params": {"voting_period": "1209600s"},
+					"tally_params": {"quorum": "0.000000000000000000", "threshold": "0.000000000000000000", "veto_threshold": "0.000000000000000000"}
+				}`,
+				"/cosmos/gov/v1/params/deposit": `{
+					"deposit_params": {"min_deposit": [{"denom": "uatom", "amount": "250000000"}], "max_deposit_period": "1209600s"}
+				}`,
+			},
+			requests: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &lcdNode{responses: tt.responses}
+			client := newLCD(t, node)
+
+			params, err := client.GetParams(context.Background())
+			if err != nil {
+				t.Fatalf("GetParams() error = %v", err)
+			}
+			if !reflect.DeepEqual(params, want) {
+				t.Errorf("GetParams() = %+v, want %+v", params, want)
+			}
+			if got := len(node.received()); got != tt.requests {
+				t.Errorf("requests = %d, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestGetVotes(t *testing.T) {
+	node := &lcdNode{responses: map[string]string{
+		"/cosmos/gov/v1/proposals/7/votes?pagination.count_total=true&pagination.key=AAE%3D&pagination.limit=2": `{
+			"votes": [
+				{"proposal_id": "7", "voter": "cosmos1a", "options": [{"option": "VOTE_OPTION_YES", "weight": "1.000000000000000000"}]},
+				{"proposal_id": "7", "voter": "cosmos1b", "options": [
+					{"option": "VOTE_OPTION_YES", "weight": "0.700000000000000000"},
+					{"option": "VOTE_OPTION_NO", "weight": "0.300000000000000000"}
+				], "metadata": "split"}
+			],
+			"pagination": {"next_key": "AAI=", "total": "5"}
+		}`,
+	}}
+	client := newLCD(t, node)
+
+	votes, page, err := client.GetVotes(context.Background(), 7, PageRequest{Key: "AAE=", Limit: 2, CountTotal: true})
+	if err != nil {
+		t.Fatalf("GetVotes() error = %v", err)
+	}
+	if page != (PageResponse{NextKey: "AAI=", Total: 5}) {
+		t.Errorf("page = %+v, want next key AAI= and total 5", page)
+	}
+	want := []Vote{
+		{ProposalID: 7, Voter: "cosmos1a", Options: []WeightedVoteOption{{Option: "VOTE_OPTION_YES", Weight: "1.000000000000000000"}}},
+		{ProposalID: 7, Voter: "cosmos1b", Options: []WeightedVoteOption{
+			{Option: "VOTE_OPTION_YES", Weight: "0.700000000000000000"},
+			{Option: "VOTE_OPTION_NO", Weight: "0.300000000000000000"},
+		}, Metadata: "split"},
+	}
+	if !reflect.DeepEqual(votes, want) {
+		t.Errorf("GetVotes() = %+v, want %+v", votes, want)
+	}
+}
+
+func TestV1beta1Fallback(t *testing.T) {
+	node := &lcdNode{
+		responses: map[string]string{
+			"/cosmos/gov/v1beta1/proposals?pagination.limit=100": `{
+				"proposals": [{
+					"proposal_id": "12",
+					"content": {"@type": "/cosmos.gov.v1beta1.TextProposal", "title": "Legacy", "description": "From content"},
+					"status": "PROPOSAL_STATUS_VOTING_PERIOD"
+				}],
+				"pagination": {"next_key": null, "total": "1"}
+			}`,
+			"/cosmos/gov/v1beta1/proposals/12/tally": `{"tally": {"yes": "10", "abstain": "0", "no": "5", "no_with_veto": "1"}}`,
+			"/cosmos/gov/v1beta1/proposals/12/votes": `{"votes": [{"proposal_id": "12", "voter": "cosmos1a", "option": "VOTE_OPTION_NO"}]}`,
+		},
+		missing: http.StatusNotImplemented,
+	}
+	client := newLCD(t, node)
+	ctx := context.Background()
+
+	proposals, err := client.ListAllProposals(ctx, ListProposalsRequest{})
+	if err != nil {
+		t.Fatalf("ListAllProposals() error = %v", err)
+	}
+	if len(proposals) != 1 || proposals[0].Title != "Legacy" || proposals[0].Description != "From content" {
+		t.Fatalf("ListAllProposals() = %+v, want the v1beta1 proposal", proposals)
+	}
+	if content := proposals[0].Messages[0].Content; content == nil || content.TypeURL != "/cosmos.gov.v1beta1.TextProposal" {
+		t.Errorf("legacy content = %+v, want the TextProposal", content)
+	}
+
+	// Once detected, the v1beta1 API is queried directly
+	before := len(node.received())
+	tally, err := client.GetTally(ctx, 12)
+	if err != nil || tally.Yes != "10" || tally.NoWithVeto != "1" {
+		t.Errorf("GetTally() = %+v, %v", tally, err)
+	}
+	votes, _, err := client.GetVotes(ctx, 12, PageRequest{})
+	if err != nil || len(votes) != 1 || votes[0].Options[0].Weight != "1.000000000000000000" {
+		t.Errorf("GetVotes() = %+v, %v, want a single option vote of full weight", votes, err)
+	}
+	if got := node.received()[before:]; !reflect.DeepEqual(got, []string{"/cosmos/gov/v1beta1/proposals/12/tally", "/cosmos/gov/v1beta1/proposals/12/votes"}) {
+		t.Errorf("requests after detection = %v, want v1beta1 only", got)
+	}
+}
+
+func TestMissingProposalOnV1Node(t *testing.T) {
+	node := &lcdNode{
+		responses: map[string]string{
+			"/cosmos/gov/v1/proposals?pagination.limit=1": `{"proposals": [], "pagination": {}}`,
+		},
+		missing: http.StatusNotFound,
+	}
+	client := newLCD(t, node)
+
+	_, err := client.GetProposal(context.Background(), 404)
+	if !IsNotFound(err) {
+		t.Fatalf("GetProposal() error = %v, want not found", err)
+	}
+	for _, request := range node.received() {
+		if request == "/cosmos/gov/v1beta1/proposals/404" {
+			t.Error("a node serving gov v1 fell back to v1beta1 on a missing proposal")
+		}
+	}
+}
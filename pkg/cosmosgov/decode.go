@@ -0,0 +1,145 @@
+package cosmosgov
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DecoderFunc renders the JSON of a message or legacy content of one type as
+// a short human readable description
+type DecoderFunc func(raw json.RawMessage) (string, error)
+
+// decoders holds the registered decoders by type URL
+var decoders = struct {
+	sync.RWMutex
+	byType map[string]DecoderFunc
+}{byType: make(map[string]DecoderFunc)}
+
+// RegisterDecoder registers a decoder for a message or content type URL,
+// replacing any existing one
+func RegisterDecoder(typeURL string, decoder DecoderFunc) {
+	decoders.Lock()
+	defer decoders.Unlock()
+	decoders.byType[typeURL] = decoder
+}
+
+// Describe returns a human readable description of a proposal message. The
+// content of legacy proposals is described instead of the wrapper, and
+// types without a decoder are described by their short type name
+func Describe(msg Message) string {
+	content := msg.Content
+	if content == nil {
+		content = unwrapLegacyContent(msg)
+	}
+
+	typeURL, raw := msg.TypeURL, msg.Raw
+	if content != nil {
+		typeURL, raw = content.TypeURL, content.Raw
+	}
+
+	decoders.RLock()
+	decoder, ok := decoders.byType[typeURL]
+	decoders.RUnlock()
+
+	name := TypeName(typeURL)
+	if !ok {
+		return name
+	}
+
+	description, err := decoder(raw)
+	if err != nil || description == "" {
+		return name
+	}
+	return fmt.Sprintf("%s: %s", name, description)
+}
+
+// decodeJSON returns a DecoderFunc that unmarshals into T and renders it
+func decodeJSON[T any](render func(T) string) DecoderFunc {
+	return func(raw json.RawMessage) (string, error) {
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return "", err
+		}
+		return render(v), nil
+	}
+}
+
+// coinFormat holds the function rendering coins in descriptions
+var coinFormat = struct {
+	sync.RWMutex
+	format func(amount, denom string) string
+}{}
+
+// SetCoinFormatter sets how coins are rendered in message descriptions,
+// such as in display units. A nil format restores the SDK form, 100uatom
+func SetCoinFormatter(format func(amount, denom string) string) {
+	coinFormat.Lock()
+	defer coinFormat.Unlock()
+	coinFormat.format = format
+}
+
+// formatCoins renders coins as "100uatom, 5uosmo", or with the formatter
+// set by SetCoinFormatter
+func formatCoins(coins []Coin) string {
+	coinFormat.RLock()
+	format := coinFormat.format
+	coinFormat.RUnlock()
+
+	parts := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		if format != nil {
+			parts = append(parts, format(coin.Amount, coin.Denom))
+			continue
+		}
+		parts = append(parts, coin.Amount+coin.Denom)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// paramChange is a single change of a ParameterChangeProposal
+type paramChange struct {
+	Subspace string `json:"subspace"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+func init() {
+	RegisterDecoder("/cosmos.params.v1beta1.ParameterChangeProposal", decodeJSON(func(p struct {
+		Changes []paramChange `json:"changes"`
+	}) string {
+		parts := make([]string, 0, len(p.Changes))
+		for _, c := range p.Changes {
+			parts = append(parts, fmt.Sprintf("%s.%s = %s", c.Subspace, c.Key, c.Value))
+		}
+		return strings.Join(parts, "; ")
+	}))
+
+	upgrade := decodeJSON(func(p struct {
+		Plan struct {
+			Name   string `json:"name"`
+			Height string `json:"height"`
+		} `json:"plan"`
+	}) string {
+		return fmt.Sprintf("upgrade %q at height %s", p.Plan.Name, p.Plan.Height)
+	})
+	RegisterDecoder("/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal", upgrade)
+	RegisterDecoder("/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade", upgrade)
+
+	spend := decodeJSON(func(p struct {
+		Recipient string `json:"recipient"`
+		Amount    []Coin `json:"amount"`
+	}) string {
+		return fmt.Sprintf("%s to %s", formatCoins(p.Amount), p.Recipient)
+	})
+	RegisterDecoder("/cosmos.distribution.v1beta1.CommunityPoolSpendProposal", spend)
+	RegisterDecoder("/cosmos.distribution.v1beta1.MsgCommunityPoolSpend", spend)
+}
+
+// sortedStrings returns s sorted
+func sortedStrings(s []string) []string {
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,68 @@
+package cosmosgov
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Deposit is a deposit made on a proposal
+type Deposit struct {
+	ProposalID uint64 `json:"proposal_id"`
+	Depositor  string `json:"depositor"`
+	Amount     []Coin `json:"amount"`
+}
+
+// lcdDeposit is a deposit as returned by the LCD, the same in gov v1 and
+// v1beta1
+type lcdDeposit struct {
+	ProposalID string `json:"proposal_id"`
+	Depositor  string `json:"depositor"`
+	Amount     []Coin `json:"amount"`
+}
+
+// normalize converts an LCD deposit into a Deposit
+func (d lcdDeposit) normalize() Deposit {
+	id, _ := strconv.ParseUint(d.ProposalID, 10, 64)
+	return Deposit{ProposalID: id, Depositor: d.Depositor, Amount: d.Amount}
+}
+
+// GetDeposits fetches one page of the deposits made on a proposal. Nodes
+// delete deposits once they are refunded or burned at the end of voting
+func (c *Client) GetDeposits(ctx context.Context, proposalID uint64, page PageRequest) ([]Deposit, PageResponse, error) {
+	var response struct {
+		Deposits   []lcdDeposit    `json:"deposits"`
+		Pagination lcdPageResponse `json:"pagination"`
+	}
+	path := func() string { return fmt.Sprintf("%s/proposals/%d/deposits", c.govPath(), proposalID) }
+	err := c.get(ctx, path(), pageQuery(page), &response)
+	if err != nil && c.fallBack(ctx, err) {
+		err = c.get(ctx, path(), pageQuery(page), &response)
+	}
+	if err != nil {
+		return nil, PageResponse{}, fmt.Errorf("failed to fetch deposits for proposal %d: %w", proposalID, err)
+	}
+
+	deposits := make([]Deposit, 0, len(response.Deposits))
+	for _, raw := range response.Deposits {
+		deposits = append(deposits, raw.normalize())
+	}
+	return deposits, response.Pagination.normalize(), nil
+}
+
+// GetAllDeposits fetches every deposit made on a proposal
+func (c *Client) GetAllDeposits(ctx context.Context, proposalID uint64) ([]Deposit, error) {
+	var all []Deposit
+	page := PageRequest{Limit: DefaultProposalPageSize}
+	for {
+		deposits, next, err := c.GetDeposits(ctx, proposalID, page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, deposits...)
+		if next.NextKey == "" || next.NextKey == page.Key {
+			return all, nil
+		}
+		page.Key = next.NextKey
+	}
+}
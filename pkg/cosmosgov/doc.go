@@ -0,0 +1,32 @@
+// Package cosmosgov queries and normalizes the governance module of Cosmos
+// SDK chains over their LCD REST API.
+//
+// It is a module of its own, without dependencies beyond the standard
+// library, versioned apart from governance-alerts-cosmos with tags of the
+// form pkg/cosmosgov/vX.Y.Z, so other Go tools can require it:
+//
+//	go get github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov@latest
+//
+// Transport concerns such as TLS, authentication or retries are left to the
+// *http.Client passed with WithHTTPClient.
+//
+// Nodes of older SDK versions that only serve the gov v1beta1 API are
+// detected on the first query and queried through it from then on, with
+// the same normalized results.
+//
+// NewGRPC queries the gRPC API of a node instead, for operators that only
+// expose gRPC. Responses are rendered as the LCD would, so both clients
+// return the same results.
+//
+//	client := cosmosgov.New("https://rest.cosmos.directory/cosmoshub")
+//	proposals, page, err := client.ListProposals(ctx, cosmosgov.ListProposalsRequest{
+//		Status: cosmosgov.StatusVotingPeriod,
+//	})
+//
+// ListAllProposals follows the pagination of ListProposals to the last page,
+// as GetAllDeposits does for GetDeposits.
+package cosmosgov
+
+// Version is the semantic version of the cosmosgov API, released as the
+// pkg/cosmosgov/v<Version> tag of the repository
+const Version = "0.1.0"
@@ -0,0 +1,3 @@
+module github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov
+
+go 1.24
@@ -0,0 +1,628 @@
+package cosmosgov
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NewGRPC creates a client for the gRPC endpoint of a chain, such as
+// https://grpc.example.com:443 or http://10.0.0.5:9090. Queries go to the
+// cosmos.gov.v1 (or v1beta1) Query service and the responses are rendered
+// as the LCD would, so results are the same as with New. The HTTP client
+// must speak HTTP/2: the default one does over TLS, plaintext endpoints
+// need a transport with unencrypted HTTP/2 enabled.
+//
+// Messages are decoded from protobuf for the upgrade, text, parameter
+// change and community pool spend types only; other messages keep their
+// type URL but no fields, so their descriptions are shorter than over LCD
+func NewGRPC(endpoint string, opts ...Option) *Client {
+	c := New(endpoint, opts...)
+	c.grpc = true
+	return c
+}
+
+// grpcRoute is the gRPC method serving an LCD path, with its encoded
+// request and the conversion of its response into the LCD JSON
+type grpcRoute struct {
+	method  string
+	request []byte
+	decode  func(protoMessage) (any, error)
+}
+
+// getGRPC is get over gRPC: the LCD path is mapped to its gRPC method and
+// the protobuf response rendered as LCD JSON before being decoded into v
+func (c *Client) getGRPC(ctx context.Context, path string, query url.Values, v interface{}) error {
+	route, err := grpcRouteFor(path, query)
+	if err != nil {
+		return err
+	}
+
+	// Length-prefixed message: compression flag and big endian length
+	frame := make([]byte, 5, 5+len(route.request))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(route.request)))
+	frame = append(frame, route.request...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/"+route.method, bytes.NewReader(frame))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Code: resp.StatusCode}
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Trailers arrive after the body; errors without a body send them as
+	// headers instead
+	status, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if status == "" {
+		return fmt.Errorf("gRPC response without status, is %s a gRPC endpoint?", c.endpoint)
+	}
+	if status != "0" {
+		message, _ = url.PathUnescape(message)
+		return fmt.Errorf("gRPC status %s: %s: %w", status, message, &StatusError{Code: grpcHTTPStatus(status)})
+	}
+
+	payload := buf.Bytes()
+	if len(payload) < 5 || int(binary.BigEndian.Uint32(payload[1:5])) != len(payload)-5 {
+		return fmt.Errorf("failed to parse response: malformed gRPC frame")
+	}
+	if payload[0] != 0 {
+		return fmt.Errorf("failed to parse response: compressed gRPC responses are not supported")
+	}
+	msg, err := parseProto(payload[5:])
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	rendered, err := route.decode(msg)
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	encoded, err := json.Marshal(rendered)
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if err := json.Unmarshal(encoded, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// grpcHTTPStatus maps gRPC status codes to the HTTP status the LCD answers
+// with, so IsNotFound, IsNoVote and the v1beta1 fallback work the same
+func grpcHTTPStatus(code string) int {
+	switch code {
+	case "3": // INVALID_ARGUMENT
+		return http.StatusBadRequest
+	case "4": // DEADLINE_EXCEEDED
+		return http.StatusGatewayTimeout
+	case "5": // NOT_FOUND
+		return http.StatusNotFound
+	case "7": // PERMISSION_DENIED
+		return http.StatusForbidden
+	case "8": // RESOURCE_EXHAUSTED
+		return http.StatusTooManyRequests
+	case "12": // UNIMPLEMENTED
+		return http.StatusNotImplemented
+	case "14": // UNAVAILABLE
+		return http.StatusServiceUnavailable
+	case "16": // UNAUTHENTICATED
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// grpcRouteFor maps an LCD path and query to its gRPC method
+func grpcRouteFor(path string, query url.Values) (*grpcRoute, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	unsupported := fmt.Errorf("%s is not supported over gRPC", path)
+
+	switch {
+	case len(parts) >= 4 && parts[0] == "cosmos" && parts[1] == "gov" && (parts[2] == "v1" || parts[2] == "v1beta1"):
+		return govRoute(parts[2], parts[3:], query)
+	case path == "/cosmos/staking/v1beta1/pool":
+		return &grpcRoute{method: "cosmos.staking.v1beta1.Query/Pool", decode: decodePool}, nil
+	case path == "/cosmos/upgrade/v1beta1/current_plan":
+		return &grpcRoute{method: "cosmos.upgrade.v1beta1.Query/CurrentPlan", decode: decodeCurrentPlan}, nil
+	case len(parts) == 6 && strings.HasPrefix(path, "/cosmos/base/tendermint/v1beta1/blocks/"):
+		if parts[5] == "latest" {
+			return &grpcRoute{method: "cosmos.base.tendermint.v1beta1.Service/GetLatestBlock", decode: decodeBlock}, nil
+		}
+		height, err := strconv.ParseUint(parts[5], 10, 64)
+		if err != nil {
+			return nil, unsupported
+		}
+		var w protoWriter
+		w.uint(1, height)
+		return &grpcRoute{method: "cosmos.base.tendermint.v1beta1.Service/GetBlockByHeight", request: w.buf, decode: decodeBlock}, nil
+	}
+	return nil, unsupported
+}
+
+// govRoute maps a gov module LCD path, without its /cosmos/gov/<version>
+// prefix, to its gRPC method
+func govRoute(version string, parts []string, query url.Values) (*grpcRoute, error) {
+	service := "cosmos.gov." + version + ".Query/"
+	legacy := version == "v1beta1"
+
+	if parts[0] == "params" && len(parts) == 2 {
+		var w protoWriter
+		w.string(1, parts[1])
+		return &grpcRoute{method: service + "Params", request: w.buf, decode: func(m protoMessage) (any, error) {
+			return decodeGovParams(m, legacy)
+		}}, nil
+	}
+	if parts[0] != "proposals" {
+		return nil, fmt.Errorf("/cosmos/gov/%s/%s is not supported over gRPC", version, strings.Join(parts, "/"))
+	}
+
+	page, err := pageRequest(query.Get("pagination.key"), query.Get("pagination.limit"), query.Get("pagination.count_total"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 1 {
+		var w protoWriter
+		if status := query.Get("proposal_status"); status != "" {
+			w.uint(1, uint64(enumIndex(proposalStatuses, status)))
+		}
+		w.bytes(4, page)
+		return &grpcRoute{method: service + "Proposals", request: w.buf, decode: func(m protoMessage) (any, error) {
+			proposals, err := m.repeated(1)
+			if err != nil {
+				return nil, err
+			}
+			rendered := make([]map[string]any, 0, len(proposals))
+			for _, p := range proposals {
+				proposal, err := decodeProposal(p, legacy)
+				if err != nil {
+					return nil, err
+				}
+				rendered = append(rendered, proposal)
+			}
+			pagination, err := m.pageResponse(2)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"proposals": rendered, "pagination": pagination}, nil
+		}}, nil
+	}
+
+	proposalID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposal ID %q", parts[1])
+	}
+	var w protoWriter
+	w.uint(1, proposalID)
+
+	switch {
+	case len(parts) == 2:
+		return &grpcRoute{method: service + "Proposal", request: w.buf, decode: func(m protoMessage) (any, error) {
+			p, err := m.message(1)
+			if err != nil {
+				return nil, err
+			}
+			proposal, err := decodeProposal(p, legacy)
+			return map[string]any{"proposal": proposal}, err
+		}}, nil
+	case len(parts) == 3 && parts[2] == "tally":
+		return &grpcRoute{method: service + "TallyResult", request: w.buf, decode: func(m protoMessage) (any, error) {
+			t, err := m.message(1)
+			return map[string]any{"tally": decodeTally(t, legacy)}, err
+		}}, nil
+	case len(parts) == 3 && parts[2] == "votes":
+		w.bytes(2, page)
+		return &grpcRoute{method: service + "Votes", request: w.buf, decode: func(m protoMessage) (any, error) {
+			votes, err := m.repeated(1)
+			if err != nil {
+				return nil, err
+			}
+			rendered := make([]map[string]any, 0, len(votes))
+			for _, vote := range votes {
+				decoded, err := decodeVote(vote)
+				if err != nil {
+					return nil, err
+				}
+				rendered = append(rendered, decoded)
+			}
+			pagination, err := m.pageResponse(2)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"votes": rendered, "pagination": pagination}, nil
+		}}, nil
+	case len(parts) == 4 && parts[2] == "votes":
+		voter, err := url.PathUnescape(parts[3])
+		if err != nil {
+			return nil, err
+		}
+		w.string(2, voter)
+		return &grpcRoute{method: service + "Vote", request: w.buf, decode: func(m protoMessage) (any, error) {
+			vote, err := m.message(1)
+			if err != nil {
+				return nil, err
+			}
+			decoded, err := decodeVote(vote)
+			return map[string]any{"vote": decoded}, err
+		}}, nil
+	case len(parts) == 3 && parts[2] == "deposits":
+		w.bytes(2, page)
+		return &grpcRoute{method: service + "Deposits", request: w.buf, decode: func(m protoMessage) (any, error) {
+			deposits, err := m.repeated(1)
+			if err != nil {
+				return nil, err
+			}
+			rendered := make([]map[string]any, 0, len(deposits))
+			for _, d := range deposits {
+				amount, err := d.coins(3)
+				if err != nil {
+					return nil, err
+				}
+				rendered = append(rendered, map[string]any{
+					"proposal_id": strconv.FormatUint(d.uint(1), 10),
+					"depositor":   d.string(2),
+					"amount":      amount,
+				})
+			}
+			pagination, err := m.pageResponse(2)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"deposits": rendered, "pagination": pagination}, nil
+		}}, nil
+	}
+	return nil, fmt.Errorf("/cosmos/gov/%s/%s is not supported over gRPC", version, strings.Join(parts, "/"))
+}
+
+// proposalStatuses are the proposal statuses by enum number
+var proposalStatuses = []string{
+	string(StatusUnspecified),
+	string(StatusDepositPeriod),
+	string(StatusVotingPeriod),
+	string(StatusPassed),
+	string(StatusRejected),
+	string(StatusFailed),
+}
+
+// voteOptions are the vote options by enum number
+var voteOptions = []string{
+	"VOTE_OPTION_UNSPECIFIED",
+	"VOTE_OPTION_YES",
+	"VOTE_OPTION_ABSTAIN",
+	"VOTE_OPTION_NO",
+	"VOTE_OPTION_NO_WITH_VETO",
+}
+
+// enumIndex returns the number of an enum name, 0 for unknown names
+func enumIndex(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// enumName returns the name of an enum number, the number itself for
+// values added after this client
+func enumName(names []string, v uint64) string {
+	if v < uint64(len(names)) {
+		return names[v]
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// decodeProposal renders a gov v1 or v1beta1 Proposal as the LCD does
+func decodeProposal(p protoMessage, legacy bool) (map[string]any, error) {
+	rendered := map[string]any{"status": enumName(proposalStatuses, p.uint(3))}
+
+	times := map[string]int{"submit_time": 5, "deposit_end_time": 6, "voting_start_time": 8, "voting_end_time": 9}
+	for name, num := range times {
+		t, err := p.timestamp(num)
+		if err != nil {
+			return nil, err
+		}
+		rendered[name] = t
+	}
+	deposit, err := p.coins(7)
+	if err != nil {
+		return nil, err
+	}
+	rendered["total_deposit"] = deposit
+
+	if p.has(4) {
+		tally, err := p.message(4)
+		if err != nil {
+			return nil, err
+		}
+		rendered["final_tally_result"] = decodeTally(tally, legacy)
+	}
+
+	if legacy {
+		rendered["proposal_id"] = strconv.FormatUint(p.uint(1), 10)
+		if p.has(2) {
+			content, err := decodeAny(p.bytes(2), true)
+			if err != nil {
+				return nil, err
+			}
+			rendered["content"] = content
+		}
+		return rendered, nil
+	}
+
+	rendered["id"] = strconv.FormatUint(p.uint(1), 10)
+	rendered["metadata"] = p.string(10)
+	rendered["title"] = p.string(11)
+	rendered["summary"] = p.string(12)
+	rendered["proposer"] = p.string(13)
+	messages := make([]map[string]any, 0)
+	for _, f := range p {
+		if f.Num != 2 {
+			continue
+		}
+		msg, err := decodeAny(f.Bytes, false)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	rendered["messages"] = messages
+	return rendered, nil
+}
+
+// decodeTally renders a TallyResult. v1 counts are named yes_count...,
+// v1beta1 ones yes...
+func decodeTally(t protoMessage, legacy bool) map[string]any {
+	if legacy {
+		return map[string]any{"yes": t.string(1), "abstain": t.string(2), "no": t.string(3), "no_with_veto": t.string(4)}
+	}
+	return map[string]any{"yes_count": t.string(1), "abstain_count": t.string(2), "no_count": t.string(3), "no_with_veto_count": t.string(4)}
+}
+
+// decodeVote renders a Vote. The single option only exists in v1beta1,
+// weights of v1beta1 are scaled decimals
+func decodeVote(v protoMessage) (map[string]any, error) {
+	weighted, err := v.repeated(4)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]map[string]any, 0, len(weighted))
+	for _, o := range weighted {
+		options = append(options, map[string]any{"option": enumName(voteOptions, o.uint(1)), "weight": protoDec(o.string(2))})
+	}
+	rendered := map[string]any{
+		"proposal_id": strconv.FormatUint(v.uint(1), 10),
+		"voter":       v.string(2),
+		"options":     options,
+		"metadata":    v.string(5),
+	}
+	if v.has(3) {
+		rendered["option"] = enumName(voteOptions, v.uint(3))
+	}
+	return rendered, nil
+}
+
+// decodeGovParams renders a QueryParamsResponse. The v1 params block only
+// exists on SDK 0.47+, typed blocks are only set when present
+func decodeGovParams(m protoMessage, legacy bool) (any, error) {
+	rendered := map[string]any{}
+	if m.has(1) {
+		voting, err := m.message(1)
+		if err != nil {
+			return nil, err
+		}
+		period, err := voting.duration(1)
+		if err != nil {
+			return nil, err
+		}
+		rendered["voting_params"] = map[string]any{"voting_period": period}
+	}
+	if m.has(2) {
+		deposit, err := m.message(2)
+		if err != nil {
+			return nil, err
+		}
+		minDeposit, err := deposit.coins(1)
+		if err != nil {
+			return nil, err
+		}
+		period, err := deposit.duration(2)
+		if err != nil {
+			return nil, err
+		}
+		rendered["deposit_params"] = map[string]any{"min_deposit": minDeposit, "max_deposit_period": period}
+	}
+	if m.has(3) {
+		tally, err := m.message(3)
+		if err != nil {
+			return nil, err
+		}
+		rendered["tally_params"] = map[string]any{
+			"quorum":         protoDec(tally.string(1)),
+			"threshold":      protoDec(tally.string(2)),
+			"veto_threshold": protoDec(tally.string(3)),
+		}
+	}
+	if m.has(4) && !legacy {
+		params, err := m.message(4)
+		if err != nil {
+			return nil, err
+		}
+		minDeposit, err := params.coins(1)
+		if err != nil {
+			return nil, err
+		}
+		depositPeriod, err := params.duration(2)
+		if err != nil {
+			return nil, err
+		}
+		votingPeriod, err := params.duration(3)
+		if err != nil {
+			return nil, err
+		}
+		rendered["params"] = map[string]any{
+			"min_deposit":        minDeposit,
+			"max_deposit_period": depositPeriod,
+			"voting_period":      votingPeriod,
+			"quorum":             params.string(4),
+			"threshold":          params.string(5),
+			"veto_threshold":     params.string(6),
+		}
+	}
+	return rendered, nil
+}
+
+// decodePool renders a QueryPoolResponse
+func decodePool(m protoMessage) (any, error) {
+	pool, err := m.message(1)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"pool": map[string]any{"not_bonded_tokens": pool.string(1), "bonded_tokens": pool.string(2)}}, nil
+}
+
+// decodeCurrentPlan renders a QueryCurrentPlanResponse, the plan is null
+// when none is scheduled
+func decodeCurrentPlan(m protoMessage) (any, error) {
+	if !m.has(1) {
+		return map[string]any{"plan": nil}, nil
+	}
+	plan, err := m.message(1)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"plan": decodePlan(plan)}, nil
+}
+
+// decodePlan renders an upgrade Plan
+func decodePlan(plan protoMessage) map[string]any {
+	return map[string]any{"name": plan.string(1), "height": strconv.FormatInt(int64(plan.uint(3)), 10), "info": plan.string(4)}
+}
+
+// decodeBlock renders the header of a GetLatestBlockResponse or
+// GetBlockByHeightResponse. SDK 0.47+ also sets sdk_block, which has the
+// same header
+func decodeBlock(m protoMessage) (any, error) {
+	num := 2
+	if !m.has(num) {
+		num = 3
+	}
+	block, err := m.message(num)
+	if err != nil {
+		return nil, err
+	}
+	header, err := block.message(1)
+	if err != nil {
+		return nil, err
+	}
+	t, err := header.timestamp(4)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"block": map[string]any{"header": map[string]any{
+		"chain_id": header.string(2),
+		"height":   strconv.FormatInt(int64(header.uint(3)), 10),
+		"time":     t,
+	}}}, nil
+}
+
+// decodeAny renders a google.protobuf.Any as LCD JSON, with the fields of
+// the message types decoded here. Legacy content types not decoded here
+// still get their title and description, fields 1 and 2 of every content
+// type of the SDK
+func decodeAny(b []byte, content bool) (map[string]any, error) {
+	anyMsg, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	typeURL := anyMsg.string(1)
+	rendered := map[string]any{"@type": typeURL}
+	value, err := anyMsg.message(2)
+	if err != nil {
+		// Keep the type of values this decoder cannot read
+		return rendered, nil
+	}
+
+	switch typeURL {
+	case "/cosmos.gov.v1.MsgExecLegacyContent":
+		inner, err := decodeAny(value.bytes(1), true)
+		if err != nil {
+			return nil, err
+		}
+		rendered["content"] = inner
+		rendered["authority"] = value.string(2)
+	case "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade":
+		plan, err := value.message(2)
+		if err != nil {
+			return nil, err
+		}
+		rendered["authority"] = value.string(1)
+		rendered["plan"] = decodePlan(plan)
+	case "/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal":
+		plan, err := value.message(3)
+		if err != nil {
+			return nil, err
+		}
+		rendered["title"], rendered["description"] = value.string(1), value.string(2)
+		rendered["plan"] = decodePlan(plan)
+	case "/cosmos.upgrade.v1beta1.MsgCancelUpgrade":
+		rendered["authority"] = value.string(1)
+	case "/cosmos.params.v1beta1.ParameterChangeProposal":
+		changes, err := value.repeated(3)
+		if err != nil {
+			return nil, err
+		}
+		rendered["title"], rendered["description"] = value.string(1), value.string(2)
+		list := make([]map[string]any, 0, len(changes))
+		for _, change := range changes {
+			list = append(list, map[string]any{"subspace": change.string(1), "key": change.string(2), "value": change.string(3)})
+		}
+		rendered["changes"] = list
+	case "/cosmos.distribution.v1beta1.CommunityPoolSpendProposal":
+		amount, err := value.coins(4)
+		if err != nil {
+			return nil, err
+		}
+		rendered["title"], rendered["description"] = value.string(1), value.string(2)
+		rendered["recipient"], rendered["amount"] = value.string(3), amount
+	case "/cosmos.distribution.v1beta1.MsgCommunityPoolSpend":
+		amount, err := value.coins(3)
+		if err != nil {
+			return nil, err
+		}
+		rendered["authority"], rendered["recipient"], rendered["amount"] = value.string(1), value.string(2), amount
+	default:
+		if content {
+			rendered["title"], rendered["description"] = value.string(1), value.string(2)
+		}
+	}
+	return rendered, nil
+}
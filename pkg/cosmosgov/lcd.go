@@ -0,0 +1,285 @@
+package cosmosgov
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lcdProposal is a proposal as returned by the gov v1 LCD API
+type lcdProposal struct {
+	ID               string            `json:"id"`
+	Title            string            `json:"title"`
+	Summary          string            `json:"summary"`
+	Description      string            `json:"description"`
+	Status           string            `json:"status"`
+	SubmitTime       string            `json:"submit_time"`
+	DepositEndTime   string            `json:"deposit_end_time"`
+	VotingStart      string            `json:"voting_start_time"`
+	VotingEnd        string            `json:"voting_end_time"`
+	Metadata         string            `json:"metadata"`
+	Proposer         string            `json:"proposer"`
+	Messages         []json.RawMessage `json:"messages"`
+	TotalDeposit     []Coin            `json:"total_deposit"`
+	FinalTallyResult *lcdTally         `json:"final_tally_result"`
+}
+
+// normalize converts an LCD proposal into a Proposal
+func (p lcdProposal) normalize() (*Proposal, error) {
+	id, err := strconv.ParseUint(p.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proposal ID: %w", err)
+	}
+
+	proposal := &Proposal{
+		ID:           id,
+		Title:        p.Title,
+		Description:  p.Summary,
+		Status:       ProposalStatus(p.Status),
+		Metadata:     p.Metadata,
+		Proposer:     p.Proposer,
+		TotalDeposit: p.TotalDeposit,
+	}
+	if proposal.Description == "" {
+		proposal.Description = p.Description
+	}
+
+	times := []struct {
+		name  string
+		value string
+		dest  *time.Time
+	}{
+		{"submit time", p.SubmitTime, &proposal.SubmitTime},
+		{"deposit end time", p.DepositEndTime, &proposal.DepositEndTime},
+		{"voting start time", p.VotingStart, &proposal.VotingStart},
+		{"voting end time", p.VotingEnd, &proposal.VotingEnd},
+	}
+	for _, t := range times {
+		if *t.dest, err = parseTime(t.value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", t.name, err)
+		}
+	}
+
+	for _, raw := range p.Messages {
+		var header struct {
+			TypeURL string `json:"@type"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return nil, fmt.Errorf("failed to parse message: %w", err)
+		}
+		proposal.Messages = append(proposal.Messages, Message{TypeURL: header.TypeURL, Raw: raw})
+	}
+
+	proposal.applyLegacyContent()
+
+	if p.FinalTallyResult != nil {
+		tally := p.FinalTallyResult.normalize()
+		proposal.FinalTally = &tally
+	}
+
+	return proposal, nil
+}
+
+// lcdTally is a tally as returned by the gov v1 LCD API
+type lcdTally struct {
+	YesCount        string `json:"yes_count"`
+	AbstainCount    string `json:"abstain_count"`
+	NoCount         string `json:"no_count"`
+	NoWithVetoCount string `json:"no_with_veto_count"`
+}
+
+// normalize converts an LCD tally into a TallyResult
+func (t lcdTally) normalize() TallyResult {
+	return TallyResult{
+		Yes:        t.YesCount,
+		Abstain:    t.AbstainCount,
+		No:         t.NoCount,
+		NoWithVeto: t.NoWithVetoCount,
+	}
+}
+
+// lcdVote is a vote as returned by the gov v1 LCD API
+type lcdVote struct {
+	ProposalID string               `json:"proposal_id"`
+	Voter      string               `json:"voter"`
+	Options    []WeightedVoteOption `json:"options"`
+	Metadata   string               `json:"metadata"`
+}
+
+// normalize converts an LCD vote into a Vote
+func (v lcdVote) normalize() Vote {
+	id, _ := strconv.ParseUint(v.ProposalID, 10, 64)
+	return Vote{
+		ProposalID: id,
+		Voter:      v.Voter,
+		Options:    v.Options,
+		Metadata:   v.Metadata,
+	}
+}
+
+// lcdPageResponse is the pagination block of LCD list responses
+type lcdPageResponse struct {
+	NextKey string `json:"next_key"`
+	Total   string `json:"total"`
+}
+
+// normalize converts an LCD page response into a PageResponse
+func (p lcdPageResponse) normalize() PageResponse {
+	total, _ := strconv.ParseUint(p.Total, 10, 64)
+	return PageResponse{NextKey: p.NextKey, Total: total}
+}
+
+// lcdParams are the gov params of SDK 0.47+
+type lcdParams struct {
+	MinDeposit       []Coin `json:"min_deposit"`
+	MaxDepositPeriod string `json:"max_deposit_period"`
+	VotingPeriod     string `json:"voting_period"`
+	Quorum           string `json:"quorum"`
+	Threshold        string `json:"threshold"`
+	VetoThreshold    string `json:"veto_threshold"`
+}
+
+// normalize converts LCD params into Params
+func (p lcdParams) normalize() (*Params, error) {
+	maxDepositPeriod, err := parseDuration(p.MaxDepositPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse max deposit period: %w", err)
+	}
+	votingPeriod, err := parseDuration(p.VotingPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse voting period: %w", err)
+	}
+
+	return &Params{
+		MinDeposit:       p.MinDeposit,
+		MaxDepositPeriod: maxDepositPeriod,
+		VotingPeriod:     votingPeriod,
+		Quorum:           p.Quorum,
+		Threshold:        p.Threshold,
+		VetoThreshold:    p.VetoThreshold,
+	}, nil
+}
+
+// lcdParamsResponse is the response of the gov v1 params query. Params is
+// only set by SDK 0.47+, older versions fill the typed parameter blocks
+type lcdParamsResponse struct {
+	Params       *lcdParams `json:"params"`
+	VotingParams *struct {
+		VotingPeriod string `json:"voting_period"`
+	} `json:"voting_params"`
+	DepositParams *struct {
+		MinDeposit       []Coin `json:"min_deposit"`
+		MaxDepositPeriod string `json:"max_deposit_period"`
+	} `json:"deposit_params"`
+	TallyParams *struct {
+		Quorum        string `json:"quorum"`
+		Threshold     string `json:"threshold"`
+		VetoThreshold string `json:"veto_threshold"`
+	} `json:"tally_params"`
+}
+
+// mergeInto copies the typed parameter blocks that are set into params.
+// Nodes before SDK 0.46 answer with every block, zero for the ones not
+// asked for, so zero values are skipped
+func (r lcdParamsResponse) mergeInto(params *Params) error {
+	var err error
+	if r.VotingParams != nil && r.VotingParams.VotingPeriod != "" {
+		period, err := parseDuration(r.VotingParams.VotingPeriod)
+		if err != nil {
+			return fmt.Errorf("failed to parse voting period: %w", err)
+		}
+		if period > 0 {
+			params.VotingPeriod = period
+		}
+	}
+	if r.DepositParams != nil && len(r.DepositParams.MinDeposit) > 0 {
+		params.MinDeposit = r.DepositParams.MinDeposit
+		if params.MaxDepositPeriod, err = parseDuration(r.DepositParams.MaxDepositPeriod); err != nil {
+			return fmt.Errorf("failed to parse max deposit period: %w", err)
+		}
+	}
+	if r.TallyParams != nil && strings.Trim(r.TallyParams.Quorum, "0.") != "" {
+		params.Quorum = r.TallyParams.Quorum
+		params.Threshold = r.TallyParams.Threshold
+		params.VetoThreshold = r.TallyParams.VetoThreshold
+	}
+	return nil
+}
+
+// lcdPlan is an upgrade plan as returned by the upgrade module LCD API
+type lcdPlan struct {
+	Name   string `json:"name"`
+	Height string `json:"height"`
+	Info   string `json:"info"`
+}
+
+// normalize converts an LCD plan into an UpgradePlan
+func (p lcdPlan) normalize() (*UpgradePlan, error) {
+	height, err := strconv.ParseInt(p.Height, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse upgrade height: %w", err)
+	}
+	return &UpgradePlan{Name: p.Name, Height: height, Info: p.Info}, nil
+}
+
+// lcdBlock is a block as returned by the tendermint LCD API, only the
+// header fields needed are decoded
+type lcdBlock struct {
+	Header struct {
+		ChainID string `json:"chain_id"`
+		Height  string `json:"height"`
+		Time    string `json:"time"`
+	} `json:"header"`
+}
+
+// normalize converts an LCD block into a Block
+func (b lcdBlock) normalize() (*Block, error) {
+	height, err := strconv.ParseInt(b.Header.Height, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block height: %w", err)
+	}
+	t, err := parseTime(b.Header.Time)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block time: %w", err)
+	}
+	return &Block{ChainID: b.Header.ChainID, Height: height, Time: t}, nil
+}
+
+// parseTime parses an RFC3339 time, returning the zero time if unset
+func parseTime(value string) (time.Time, error) {
+	if value == "" || strings.HasPrefix(value, "0001-01-01") {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// parseDuration parses a protobuf JSON duration such as "172800s"
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// ParseProposal decodes a proposal in gov v1 LCD JSON form obtained from
+// another source, such as an indexer or an archive dump
+func ParseProposal(data []byte) (*Proposal, error) {
+	var raw lcdProposal
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode proposal: %w", err)
+	}
+	return raw.normalize()
+}
+
+// ParseTally decodes a tally in gov v1 LCD JSON form obtained from another
+// source
+func ParseTally(data []byte) (*TallyResult, error) {
+	var raw lcdTally
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode tally: %w", err)
+	}
+	tally := raw.normalize()
+	return &tally, nil
+}
@@ -0,0 +1,94 @@
+package cosmosgov
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// MsgExecLegacyContentType is the type URL of the gov v1 message wrapping
+// gov v1beta1 proposal content
+const MsgExecLegacyContentType = "/cosmos.gov.v1.MsgExecLegacyContent"
+
+// LegacyContent is v1beta1 proposal content such as a TextProposal or a
+// SoftwareUpgradeProposal
+type LegacyContent struct {
+	TypeURL     string          `json:"type_url"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Raw         json.RawMessage `json:"raw,omitempty"`
+}
+
+// unwrapLegacyContent decodes the content of a MsgExecLegacyContent
+// message, returning nil for any other message
+func unwrapLegacyContent(msg Message) *LegacyContent {
+	if msg.TypeURL != MsgExecLegacyContentType {
+		return nil
+	}
+
+	var wrapper struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(msg.Raw, &wrapper); err != nil || len(wrapper.Content) == 0 {
+		return nil
+	}
+
+	var content struct {
+		TypeURL     string `json:"@type"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(wrapper.Content, &content); err != nil {
+		return nil
+	}
+
+	return &LegacyContent{
+		TypeURL:     content.TypeURL,
+		Title:       content.Title,
+		Description: content.Description,
+		Raw:         wrapper.Content,
+	}
+}
+
+// applyLegacyContent surfaces wrapped legacy content on a proposal: its
+// title and description fill in missing ones, and its type becomes the
+// proposal type
+func (p *Proposal) applyLegacyContent() {
+	for i := range p.Messages {
+		content := unwrapLegacyContent(p.Messages[i])
+		if content == nil {
+			continue
+		}
+		p.Messages[i].Content = content
+
+		if p.Title == "" {
+			p.Title = content.Title
+		}
+		if p.Description == "" {
+			p.Description = content.Description
+		}
+	}
+}
+
+// Type returns the type URL that best describes the proposal: the content
+// type of a wrapped legacy proposal, otherwise the first message type
+func (p *Proposal) Type() string {
+	for _, msg := range p.Messages {
+		if msg.Content != nil && msg.Content.TypeURL != "" {
+			return msg.Content.TypeURL
+		}
+	}
+	if len(p.Messages) > 0 {
+		return p.Messages[0].TypeURL
+	}
+	return ""
+}
+
+// TypeName returns the short name of a type URL, e.g.
+// "/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal" becomes
+// "SoftwareUpgradeProposal"
+func TypeName(typeURL string) string {
+	if i := strings.LastIndex(typeURL, "."); i >= 0 {
+		return typeURL[i+1:]
+	}
+	return strings.TrimPrefix(typeURL, "/")
+}
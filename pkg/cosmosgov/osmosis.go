@@ -0,0 +1,116 @@
+package cosmosgov
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxListedItems caps how many records long Osmosis proposals list
+const maxListedItems = 10
+
+// listItems joins items, summarizing the overflow beyond maxListedItems
+func listItems(items []string) string {
+	if len(items) <= maxListedItems {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:maxListedItems], ", "), len(items)-maxListedItems)
+}
+
+// poolIncentiveRecords are the distribution records of pool incentive
+// proposals, a weight of 0 removes the gauge
+type poolIncentiveRecords struct {
+	Records []struct {
+		GaugeID string `json:"gauge_id"`
+		Weight  string `json:"weight"`
+	} `json:"records"`
+}
+
+// render describes the gauge weight changes
+func (p poolIncentiveRecords) render() string {
+	items := make([]string, 0, len(p.Records))
+	for _, r := range p.Records {
+		if r.Weight == "0" {
+			items = append(items, fmt.Sprintf("gauge %s removed", r.GaugeID))
+			continue
+		}
+		items = append(items, fmt.Sprintf("gauge %s weight %s", r.GaugeID, r.Weight))
+	}
+	return listItems(items)
+}
+
+// superfluidAssetTypes maps superfluid asset type enums to readable names
+var superfluidAssetTypes = map[string]string{
+	"SuperfluidAssetTypeNative":                "native",
+	"SuperfluidAssetTypeLPShare":               "LP share",
+	"SuperfluidAssetTypeConcentratedShare":     "CL share",
+	"SUPERFLUID_ASSET_TYPE_NATIVE":             "native",
+	"SUPERFLUID_ASSET_TYPE_LP_SHARE":           "LP share",
+	"SUPERFLUID_ASSET_TYPE_CONCENTRATED_SHARE": "CL share",
+}
+
+func init() {
+	RegisterDecoder("/osmosis.poolincentives.v1beta1.UpdatePoolIncentivesProposal", decodeJSON(func(p poolIncentiveRecords) string {
+		return "update " + p.render()
+	}))
+	RegisterDecoder("/osmosis.poolincentives.v1beta1.ReplacePoolIncentivesProposal", decodeJSON(func(p poolIncentiveRecords) string {
+		return "replace all with " + p.render()
+	}))
+
+	RegisterDecoder("/osmosis.superfluid.v1beta1.SetSuperfluidAssetsProposal", decodeJSON(func(p struct {
+		Assets []struct {
+			Denom     string `json:"denom"`
+			AssetType string `json:"asset_type"`
+		} `json:"assets"`
+	}) string {
+		items := make([]string, 0, len(p.Assets))
+		for _, a := range p.Assets {
+			assetType := superfluidAssetTypes[a.AssetType]
+			if assetType == "" {
+				assetType = a.AssetType
+			}
+			items = append(items, fmt.Sprintf("%s (%s)", a.Denom, assetType))
+		}
+		return "add " + listItems(items)
+	}))
+	RegisterDecoder("/osmosis.superfluid.v1beta1.RemoveSuperfluidAssetsProposal", decodeJSON(func(p struct {
+		Denoms []string `json:"superfluid_asset_denoms"`
+	}) string {
+		return "remove " + listItems(p.Denoms)
+	}))
+
+	RegisterDecoder("/osmosis.concentratedliquidity.v1beta1.CreateConcentratedLiquidityPoolsProposal", decodeJSON(func(p struct {
+		Records []struct {
+			Denom0       string `json:"denom0"`
+			Denom1       string `json:"denom1"`
+			TickSpacing  string `json:"tick_spacing"`
+			SpreadFactor string `json:"spread_factor"`
+		} `json:"pool_records"`
+	}) string {
+		items := make([]string, 0, len(p.Records))
+		for _, r := range p.Records {
+			items = append(items, fmt.Sprintf("%s/%s tick spacing %s spread %s", r.Denom0, r.Denom1, r.TickSpacing, r.SpreadFactor))
+		}
+		return "create " + listItems(items)
+	}))
+	RegisterDecoder("/osmosis.concentratedliquidity.v1beta1.TickSpacingDecreaseProposal", decodeJSON(func(p struct {
+		Records []struct {
+			PoolID         string `json:"pool_id"`
+			NewTickSpacing string `json:"new_tick_spacing"`
+		} `json:"pool_id_to_tick_spacing_records"`
+	}) string {
+		items := make([]string, 0, len(p.Records))
+		for _, r := range p.Records {
+			items = append(items, fmt.Sprintf("pool %s tick spacing → %s", r.PoolID, r.NewTickSpacing))
+		}
+		return listItems(items)
+	}))
+	RegisterDecoder("/osmosis.concentratedliquidity.v1beta1.MsgUpdateParams", decodeJSON(func(p struct {
+		Params map[string]interface{} `json:"params"`
+	}) string {
+		keys := make([]string, 0, len(p.Params))
+		for k := range p.Params {
+			keys = append(keys, k)
+		}
+		return "set CL params " + listItems(sortedStrings(keys))
+	}))
+}
@@ -0,0 +1,58 @@
+package cosmosgov
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// upgradeTypes are the message and legacy content types carrying an
+// upgrade plan
+var upgradeTypes = map[string]bool{
+	"/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade":      true,
+	"/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal": true,
+}
+
+// UpgradePlan returns the plan of a software upgrade proposal, nil for
+// other proposals or a plan that cannot be decoded
+func (p *Proposal) UpgradePlan() *UpgradePlan {
+	for _, msg := range p.Messages {
+		typeURL, raw := msg.TypeURL, msg.Raw
+		if msg.Content != nil {
+			typeURL, raw = msg.Content.TypeURL, msg.Content.Raw
+		}
+		if !upgradeTypes[typeURL] {
+			continue
+		}
+
+		var upgrade struct {
+			Plan lcdPlan `json:"plan"`
+		}
+		if err := json.Unmarshal(raw, &upgrade); err != nil {
+			return nil
+		}
+		plan, err := upgrade.Plan.normalize()
+		if err != nil {
+			return nil
+		}
+		return plan
+	}
+	return nil
+}
+
+// PlanBinaries returns the binaries listed in the info of an upgrade plan
+// by platform (e.g. linux/amd64), in the format read by Cosmovisor:
+// {"binaries": {"linux/amd64": "https://...?checksum=sha256:..."}}. Info
+// that is not such JSON, such as a URL to it, gives no binaries
+func PlanBinaries(info string) map[string]string {
+	info = strings.TrimSpace(info)
+	if !strings.HasPrefix(info, "{") {
+		return nil
+	}
+	var parsed struct {
+		Binaries map[string]string `json:"binaries"`
+	}
+	if err := json.Unmarshal([]byte(info), &parsed); err != nil {
+		return nil
+	}
+	return parsed.Binaries
+}
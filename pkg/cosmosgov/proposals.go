@@ -0,0 +1,50 @@
+package cosmosgov
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultProposalPageSize is the page size of ListAllProposals when the
+// request has no page limit
+const DefaultProposalPageSize = 100
+
+// ListAllProposals lists every proposal matching req, following
+// pagination.next_key from req.Page.Key until the last page. Proposals that
+// fail to normalize are skipped and returned in a single
+// InvalidProposalsError together with the valid ones
+func (c *Client) ListAllProposals(ctx context.Context, req ListProposalsRequest) ([]Proposal, error) {
+	if req.Page.Limit == 0 {
+		req.Page.Limit = DefaultProposalPageSize
+	}
+
+	var all []Proposal
+	var invalid []error
+	seen := make(map[string]bool)
+	for {
+		proposals, page, err := c.ListProposals(ctx, req)
+		var invalidErr *InvalidProposalsError
+		if errors.As(err, &invalidErr) {
+			invalid = append(invalid, invalidErr.Errors...)
+		} else if err != nil {
+			return nil, err
+		}
+		all = append(all, proposals...)
+
+		if page.NextKey == "" {
+			break
+		}
+		// A node returning the same key again would loop forever
+		if seen[page.NextKey] {
+			return nil, fmt.Errorf("failed to list proposals: pagination key %q repeated", page.NextKey)
+		}
+		seen[page.NextKey] = true
+		req.Page.Key = page.NextKey
+	}
+
+	if len(invalid) > 0 {
+		return all, &InvalidProposalsError{Errors: invalid}
+	}
+	return all, nil
+}
@@ -0,0 +1,291 @@
+package cosmosgov
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// errTruncated is returned for protobuf messages that end mid-field
+var errTruncated = errors.New("truncated protobuf message")
+
+// protoWriter encodes a protobuf message field by field. Only the wire
+// types of the gRPC queries made by the client are supported
+type protoWriter struct {
+	buf []byte
+}
+
+// varint appends an unsigned varint
+func (w *protoWriter) varint(v uint64) {
+	for v >= 0x80 {
+		w.buf = append(w.buf, byte(v)|0x80)
+		v >>= 7
+	}
+	w.buf = append(w.buf, byte(v))
+}
+
+// uint appends a varint field, omitted when zero like proto3 defaults
+func (w *protoWriter) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.varint(uint64(field)<<3 | wireVarint)
+	w.varint(v)
+}
+
+// bytes appends a length-delimited field, omitted when empty
+func (w *protoWriter) bytes(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	w.varint(uint64(field)<<3 | wireBytes)
+	w.varint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// string appends a string field, omitted when empty
+func (w *protoWriter) string(field int, s string) {
+	w.bytes(field, []byte(s))
+}
+
+// protoField is one decoded field of a protobuf message. Varint holds the
+// value of varint and fixed fields, Bytes the content of length-delimited
+// ones
+type protoField struct {
+	Num    int
+	Varint uint64
+	Bytes  []byte
+}
+
+// protoMessage is a decoded protobuf message, fields in wire order
+type protoMessage []protoField
+
+// parseProto splits a protobuf message into its fields without knowing its
+// schema. Repeated fields appear once per value
+func parseProto(b []byte) (protoMessage, error) {
+	var msg protoMessage
+	for len(b) > 0 {
+		key, n := readVarint(b)
+		if n == 0 {
+			return nil, errTruncated
+		}
+		b = b[n:]
+		field := protoField{Num: int(key >> 3)}
+
+		switch key & 7 {
+		case wireVarint:
+			if field.Varint, n = readVarint(b); n == 0 {
+				return nil, errTruncated
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return nil, errTruncated
+			}
+			for i := 7; i >= 0; i-- {
+				field.Varint = field.Varint<<8 | uint64(b[i])
+			}
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return nil, errTruncated
+			}
+			for i := 3; i >= 0; i-- {
+				field.Varint = field.Varint<<8 | uint64(b[i])
+			}
+			b = b[4:]
+		case wireBytes:
+			length, n := readVarint(b)
+			if n == 0 || uint64(len(b)-n) < length {
+				return nil, errTruncated
+			}
+			field.Bytes = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			return nil, fmt.Errorf("unsupported protobuf wire type %d", key&7)
+		}
+		msg = append(msg, field)
+	}
+	return msg, nil
+}
+
+// readVarint reads an unsigned varint, returning 0 bytes read when b ends
+// before it does
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// uint returns the last value of a varint field
+func (m protoMessage) uint(num int) uint64 {
+	var v uint64
+	for _, f := range m {
+		if f.Num == num {
+			v = f.Varint
+		}
+	}
+	return v
+}
+
+// bytes returns the last value of a length-delimited field
+func (m protoMessage) bytes(num int) []byte {
+	var b []byte
+	for _, f := range m {
+		if f.Num == num {
+			b = f.Bytes
+		}
+	}
+	return b
+}
+
+// string returns the last value of a string field
+func (m protoMessage) string(num int) string {
+	return string(m.bytes(num))
+}
+
+// has reports whether a field is present
+func (m protoMessage) has(num int) bool {
+	for _, f := range m {
+		if f.Num == num {
+			return true
+		}
+	}
+	return false
+}
+
+// message decodes the last value of an embedded message field
+func (m protoMessage) message(num int) (protoMessage, error) {
+	return parseProto(m.bytes(num))
+}
+
+// repeated decodes every value of a repeated embedded message field
+func (m protoMessage) repeated(num int) ([]protoMessage, error) {
+	var msgs []protoMessage
+	for _, f := range m {
+		if f.Num != num {
+			continue
+		}
+		msg, err := parseProto(f.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// timestamp decodes a google.protobuf.Timestamp field as the RFC 3339 time
+// the LCD renders, empty when unset
+func (m protoMessage) timestamp(num int) (string, error) {
+	if !m.has(num) {
+		return "", nil
+	}
+	ts, err := m.message(num)
+	if err != nil {
+		return "", err
+	}
+	return time.Unix(int64(ts.uint(1)), int64(ts.uint(2))).UTC().Format(time.RFC3339Nano), nil
+}
+
+// duration decodes a google.protobuf.Duration field as the "172800s" form
+// the LCD renders
+func (m protoMessage) duration(num int) (string, error) {
+	d, err := m.message(num)
+	if err != nil {
+		return "", err
+	}
+	seconds := strconv.FormatInt(int64(d.uint(1)), 10)
+	if nanos := d.uint(2); nanos != 0 {
+		seconds += strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0")
+	}
+	return seconds + "s", nil
+}
+
+// coins decodes a repeated cosmos.base.v1beta1.Coin field
+func (m protoMessage) coins(num int) ([]map[string]any, error) {
+	msgs, err := m.repeated(num)
+	if err != nil {
+		return nil, err
+	}
+	coins := make([]map[string]any, 0, len(msgs))
+	for _, coin := range msgs {
+		coins = append(coins, map[string]any{"denom": coin.string(1), "amount": coin.string(2)})
+	}
+	return coins, nil
+}
+
+// decimalPrecision is the number of decimals of SDK decimals
+const decimalPrecision = 18
+
+// protoDec renders an SDK decimal as the LCD does. Gogoproto custom type
+// decimals travel as their integer representation scaled by 10^18, plain
+// strings already carry the point
+func protoDec(s string) string {
+	if s == "" || strings.Contains(s, ".") {
+		return s
+	}
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if len(s) <= decimalPrecision {
+		s = strings.Repeat("0", decimalPrecision-len(s)+1) + s
+	}
+	s = s[:len(s)-decimalPrecision] + "." + s[len(s)-decimalPrecision:]
+	if negative {
+		s = "-" + s
+	}
+	return s
+}
+
+// pageRequest encodes LCD pagination query parameters as a
+// cosmos.base.query.v1beta1.PageRequest
+func pageRequest(key, limit, countTotal string) ([]byte, error) {
+	var w protoWriter
+	if key != "" {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pagination key: %w", err)
+		}
+		w.bytes(1, decoded)
+	}
+	if limit != "" {
+		n, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pagination limit: %w", err)
+		}
+		w.uint(3, n)
+	}
+	if countTotal == "true" {
+		w.uint(4, 1)
+	}
+	return w.buf, nil
+}
+
+// pageResponse decodes a cosmos.base.query.v1beta1.PageResponse field
+func (m protoMessage) pageResponse(num int) (map[string]any, error) {
+	page, err := m.message(num)
+	if err != nil {
+		return nil, err
+	}
+	var nextKey any
+	if key := page.bytes(1); len(key) > 0 {
+		nextKey = base64.StdEncoding.EncodeToString(key)
+	}
+	return map[string]any{"next_key": nextKey, "total": strconv.FormatUint(page.uint(2), 10)}, nil
+}
@@ -0,0 +1,124 @@
+package cosmosgov
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProposalStatus is the status of a governance proposal
+type ProposalStatus string
+
+// Proposal statuses as reported by the gov module
+const (
+	StatusUnspecified   ProposalStatus = "PROPOSAL_STATUS_UNSPECIFIED"
+	StatusDepositPeriod ProposalStatus = "PROPOSAL_STATUS_DEPOSIT_PERIOD"
+	StatusVotingPeriod  ProposalStatus = "PROPOSAL_STATUS_VOTING_PERIOD"
+	StatusPassed        ProposalStatus = "PROPOSAL_STATUS_PASSED"
+	StatusRejected      ProposalStatus = "PROPOSAL_STATUS_REJECTED"
+	StatusFailed        ProposalStatus = "PROPOSAL_STATUS_FAILED"
+)
+
+// Proposal is a normalized governance proposal
+type Proposal struct {
+	ID             uint64         `json:"id"`
+	Title          string         `json:"title"`
+	Description    string         `json:"description"`
+	Status         ProposalStatus `json:"status"`
+	SubmitTime     time.Time      `json:"submit_time"`
+	DepositEndTime time.Time      `json:"deposit_end_time"`
+	VotingStart    time.Time      `json:"voting_start"`
+	VotingEnd      time.Time      `json:"voting_end"`
+	Metadata       string         `json:"metadata,omitempty"`
+	Proposer       string         `json:"proposer,omitempty"`
+	Messages       []Message      `json:"messages,omitempty"`
+	TotalDeposit   []Coin         `json:"total_deposit,omitempty"`
+	FinalTally     *TallyResult   `json:"final_tally,omitempty"`
+}
+
+// Message is a proposal message, Raw holds the full JSON for decoding.
+// Content is set for MsgExecLegacyContent messages
+type Message struct {
+	TypeURL string          `json:"type_url"`
+	Raw     json.RawMessage `json:"raw,omitempty"`
+	Content *LegacyContent  `json:"content,omitempty"`
+}
+
+// Coin is an amount of a denom, amounts are kept as strings since they
+// routinely exceed 64 bits
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// TallyResult is the vote tally of a proposal
+type TallyResult struct {
+	Yes        string `json:"yes"`
+	Abstain    string `json:"abstain"`
+	No         string `json:"no"`
+	NoWithVeto string `json:"no_with_veto"`
+}
+
+// Params are the gov module parameters
+type Params struct {
+	MinDeposit       []Coin        `json:"min_deposit"`
+	MaxDepositPeriod time.Duration `json:"max_deposit_period"`
+	VotingPeriod     time.Duration `json:"voting_period"`
+	Quorum           string        `json:"quorum"`
+	Threshold        string        `json:"threshold"`
+	VetoThreshold    string        `json:"veto_threshold"`
+}
+
+// Vote is a vote cast on a proposal
+type Vote struct {
+	ProposalID uint64               `json:"proposal_id"`
+	Voter      string               `json:"voter"`
+	Options    []WeightedVoteOption `json:"options"`
+	Metadata   string               `json:"metadata,omitempty"`
+}
+
+// WeightedVoteOption is a vote option with its weight
+type WeightedVoteOption struct {
+	Option string `json:"option"`
+	Weight string `json:"weight"`
+}
+
+// StakingPool is the staking pool of a chain
+type StakingPool struct {
+	BondedTokens    string `json:"bonded_tokens"`
+	NotBondedTokens string `json:"not_bonded_tokens"`
+}
+
+// UpgradePlan is a software upgrade scheduled at a block height
+type UpgradePlan struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+	Info   string `json:"info,omitempty"`
+}
+
+// Block is the height and time of a block, with the chain it belongs to
+type Block struct {
+	ChainID string    `json:"chain_id"`
+	Height  int64     `json:"height"`
+	Time    time.Time `json:"time"`
+}
+
+// PageRequest selects a page of results, CountTotal asks the node to
+// return the total number of results
+type PageRequest struct {
+	Key        string
+	Limit      uint64
+	CountTotal bool
+}
+
+// PageResponse describes the page returned, NextKey is empty on the last page
+type PageResponse struct {
+	NextKey string
+	Total   uint64
+}
+
+// ListProposalsRequest filters the proposals listed, an empty Status lists
+// proposals of any status
+type ListProposalsRequest struct {
+	Status ProposalStatus
+	Page   PageRequest
+}
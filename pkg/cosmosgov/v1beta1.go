@@ -0,0 +1,261 @@
+package cosmosgov
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Gov API versions served by a node, detected on first use
+const (
+	apiUnknown int32 = iota
+	apiV1
+	apiV1beta1
+)
+
+// isUnsupported reports whether err is the answer of a node that does not
+// serve the queried API
+func isUnsupported(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && (statusErr.Code == http.StatusNotFound || statusErr.Code == http.StatusNotImplemented)
+}
+
+// legacyAPI reports whether the node was detected to only serve the gov
+// v1beta1 API
+func (c *Client) legacyAPI() bool {
+	return c.apiVersion.Load() == apiV1beta1
+}
+
+// govPath returns the path prefix of the gov API the node serves
+func (c *Client) govPath() string {
+	if c.legacyAPI() {
+		return "/cosmos/gov/v1beta1"
+	}
+	return "/cosmos/gov/v1"
+}
+
+// fallBack reports whether a failed gov v1 query should be retried against
+// the v1beta1 API. A 404 also means a missing proposal or vote, so the v1
+// proposal list is probed to tell both apart. The result is remembered
+func (c *Client) fallBack(ctx context.Context, err error) bool {
+	if !isUnsupported(err) || c.apiVersion.Load() == apiV1 {
+		return false
+	}
+
+	probe := url.Values{"pagination.limit": {"1"}}
+	probeErr := c.get(ctx, "/cosmos/gov/v1/proposals", probe, &struct{}{})
+	switch {
+	case probeErr == nil:
+		c.apiVersion.Store(apiV1)
+		return false
+	case isUnsupported(probeErr):
+		c.apiVersion.Store(apiV1beta1)
+		return true
+	default:
+		return false
+	}
+}
+
+// lcdV1beta1Proposal is a proposal as returned by the gov v1beta1 LCD API,
+// its title and description live in the content
+type lcdV1beta1Proposal struct {
+	ProposalID       string           `json:"proposal_id"`
+	Content          json.RawMessage  `json:"content"`
+	Status           string           `json:"status"`
+	FinalTallyResult *lcdV1beta1Tally `json:"final_tally_result"`
+	SubmitTime       string           `json:"submit_time"`
+	DepositEndTime   string           `json:"deposit_end_time"`
+	TotalDeposit     []Coin           `json:"total_deposit"`
+	VotingStart      string           `json:"voting_start_time"`
+	VotingEnd        string           `json:"voting_end_time"`
+}
+
+// normalize converts a v1beta1 proposal into a Proposal. The content
+// becomes the proposal's only message, as if wrapped in MsgExecLegacyContent
+func (p lcdV1beta1Proposal) normalize() (*Proposal, error) {
+	id, err := strconv.ParseUint(p.ProposalID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proposal ID: %w", err)
+	}
+
+	proposal := &Proposal{
+		ID:           id,
+		Status:       ProposalStatus(p.Status),
+		TotalDeposit: p.TotalDeposit,
+	}
+
+	times := []struct {
+		name  string
+		value string
+		dest  *time.Time
+	}{
+		{"submit time", p.SubmitTime, &proposal.SubmitTime},
+		{"deposit end time", p.DepositEndTime, &proposal.DepositEndTime},
+		{"voting start time", p.VotingStart, &proposal.VotingStart},
+		{"voting end time", p.VotingEnd, &proposal.VotingEnd},
+	}
+	for _, t := range times {
+		if *t.dest, err = parseTime(t.value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", t.name, err)
+		}
+	}
+
+	if len(p.Content) > 0 {
+		var content struct {
+			TypeURL     string `json:"@type"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		}
+		if err := json.Unmarshal(p.Content, &content); err != nil {
+			return nil, fmt.Errorf("failed to parse content: %w", err)
+		}
+		proposal.Title = content.Title
+		proposal.Description = content.Description
+		proposal.Messages = []Message{{
+			TypeURL: content.TypeURL,
+			Raw:     p.Content,
+			Content: &LegacyContent{
+				TypeURL:     content.TypeURL,
+				Title:       content.Title,
+				Description: content.Description,
+				Raw:         p.Content,
+			},
+		}}
+	}
+
+	if p.FinalTallyResult != nil {
+		tally := p.FinalTallyResult.normalize()
+		proposal.FinalTally = &tally
+	}
+
+	return proposal, nil
+}
+
+// lcdV1beta1Tally is a tally as returned by the gov v1beta1 LCD API
+type lcdV1beta1Tally struct {
+	Yes        string `json:"yes"`
+	Abstain    string `json:"abstain"`
+	No         string `json:"no"`
+	NoWithVeto string `json:"no_with_veto"`
+}
+
+// normalize converts a v1beta1 tally into a TallyResult
+func (t lcdV1beta1Tally) normalize() TallyResult {
+	return TallyResult{
+		Yes:        t.Yes,
+		Abstain:    t.Abstain,
+		No:         t.No,
+		NoWithVeto: t.NoWithVeto,
+	}
+}
+
+// lcdV1beta1Vote is a vote as returned by the gov v1beta1 LCD API. Nodes
+// older than SDK 0.43 only set the single option
+type lcdV1beta1Vote struct {
+	ProposalID string               `json:"proposal_id"`
+	Voter      string               `json:"voter"`
+	Option     string               `json:"option"`
+	Options    []WeightedVoteOption `json:"options"`
+}
+
+// normalize converts a v1beta1 vote into a Vote
+func (v lcdV1beta1Vote) normalize() Vote {
+	id, _ := strconv.ParseUint(v.ProposalID, 10, 64)
+	options := v.Options
+	if len(options) == 0 && v.Option != "" {
+		options = []WeightedVoteOption{{Option: v.Option, Weight: "1.000000000000000000"}}
+	}
+	return Vote{ProposalID: id, Voter: v.Voter, Options: options}
+}
+
+// listProposalsV1beta1 is ListProposals against the v1beta1 API
+func (c *Client) listProposalsV1beta1(ctx context.Context, query url.Values) ([]Proposal, PageResponse, error) {
+	var response struct {
+		Proposals  []lcdV1beta1Proposal `json:"proposals"`
+		Pagination lcdPageResponse      `json:"pagination"`
+	}
+	if err := c.get(ctx, "/cosmos/gov/v1beta1/proposals", query, &response); err != nil {
+		return nil, PageResponse{}, fmt.Errorf("failed to list proposals: %w", err)
+	}
+
+	proposals := make([]Proposal, 0, len(response.Proposals))
+	var invalid []error
+	for _, raw := range response.Proposals {
+		proposal, err := raw.normalize()
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("proposal %s: %w", raw.ProposalID, err))
+			continue
+		}
+		proposals = append(proposals, *proposal)
+	}
+
+	if len(invalid) > 0 {
+		return proposals, response.Pagination.normalize(), &InvalidProposalsError{Errors: invalid}
+	}
+	return proposals, response.Pagination.normalize(), nil
+}
+
+// getProposalV1beta1 is GetProposal against the v1beta1 API
+func (c *Client) getProposalV1beta1(ctx context.Context, proposalID uint64) (*Proposal, error) {
+	var response struct {
+		Proposal lcdV1beta1Proposal `json:"proposal"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/cosmos/gov/v1beta1/proposals/%d", proposalID), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch proposal %d: %w", proposalID, err)
+	}
+
+	proposal, err := response.Proposal.normalize()
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposal %d: %w", proposalID, err)
+	}
+	return proposal, nil
+}
+
+// getTallyV1beta1 is GetTally against the v1beta1 API
+func (c *Client) getTallyV1beta1(ctx context.Context, proposalID uint64) (*TallyResult, error) {
+	var response struct {
+		Tally lcdV1beta1Tally `json:"tally"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/cosmos/gov/v1beta1/proposals/%d/tally", proposalID), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch tally for proposal %d: %w", proposalID, err)
+	}
+
+	tally := response.Tally.normalize()
+	return &tally, nil
+}
+
+// getVotesV1beta1 is GetVotes against the v1beta1 API
+func (c *Client) getVotesV1beta1(ctx context.Context, proposalID uint64, page PageRequest) ([]Vote, PageResponse, error) {
+	var response struct {
+		Votes      []lcdV1beta1Vote `json:"votes"`
+		Pagination lcdPageResponse  `json:"pagination"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/cosmos/gov/v1beta1/proposals/%d/votes", proposalID), pageQuery(page), &response); err != nil {
+		return nil, PageResponse{}, fmt.Errorf("failed to fetch votes for proposal %d: %w", proposalID, err)
+	}
+
+	votes := make([]Vote, 0, len(response.Votes))
+	for _, raw := range response.Votes {
+		votes = append(votes, raw.normalize())
+	}
+	return votes, response.Pagination.normalize(), nil
+}
+
+// getVoteV1beta1 is GetVote against the v1beta1 API
+func (c *Client) getVoteV1beta1(ctx context.Context, proposalID uint64, voter string) (*Vote, error) {
+	var response struct {
+		Vote lcdV1beta1Vote `json:"vote"`
+	}
+	path := fmt.Sprintf("/cosmos/gov/v1beta1/proposals/%d/votes/%s", proposalID, url.PathEscape(voter))
+	if err := c.get(ctx, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch vote of %s on proposal %d: %w", voter, proposalID, err)
+	}
+
+	vote := response.Vote.normalize()
+	return &vote, nil
+}
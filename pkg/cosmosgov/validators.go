@@ -0,0 +1,50 @@
+package cosmosgov
+
+import (
+	"context"
+	"fmt"
+)
+
+// Validator is a validator of the active set
+type Validator struct {
+	OperatorAddress string `json:"operator_address"`
+	Moniker         string `json:"moniker"`
+	// Tokens bonded to the validator, in base units
+	Tokens string `json:"tokens"`
+}
+
+// lcdValidator is a validator as returned by the LCD
+type lcdValidator struct {
+	OperatorAddress string `json:"operator_address"`
+	Tokens          string `json:"tokens"`
+	Description     struct {
+		Moniker string `json:"moniker"`
+	} `json:"description"`
+}
+
+// GetBondedValidators fetches every validator of the active set. Not
+// available over gRPC
+func (c *Client) GetBondedValidators(ctx context.Context) ([]Validator, error) {
+	var all []Validator
+	page := PageRequest{Limit: DefaultProposalPageSize}
+	for {
+		var response struct {
+			Validators []lcdValidator  `json:"validators"`
+			Pagination lcdPageResponse `json:"pagination"`
+		}
+		query := pageQuery(page)
+		query.Set("status", "BOND_STATUS_BONDED")
+		if err := c.get(ctx, "/cosmos/staking/v1beta1/validators", query, &response); err != nil {
+			return nil, fmt.Errorf("failed to fetch validators: %w", err)
+		}
+
+		for _, raw := range response.Validators {
+			all = append(all, Validator{OperatorAddress: raw.OperatorAddress, Moniker: raw.Description.Moniker, Tokens: raw.Tokens})
+		}
+		next := response.Pagination.normalize()
+		if next.NextKey == "" || next.NextKey == page.Key {
+			return all, nil
+		}
+		page.Key = next.NextKey
+	}
+}
@@ -0,0 +1,59 @@
+package cosmosgov
+
+import "context"
+
+// DefaultVotePageSize is the page size of a VoteIterator created with a
+// page size of 0
+const DefaultVotePageSize = 100
+
+// VoteIterator walks the votes of a proposal page by page. Its position is
+// the page key returned by Key, so a walk interrupted by an error or a page
+// budget can be resumed later by passing the key to Client.Votes
+type VoteIterator struct {
+	client     *Client
+	proposalID uint64
+	pageSize   uint64
+	key        string
+	done       bool
+}
+
+// Votes returns an iterator over the votes of a proposal starting at key,
+// or at the first vote when key is empty
+func (c *Client) Votes(proposalID uint64, pageSize uint64, key string) *VoteIterator {
+	if pageSize == 0 {
+		pageSize = DefaultVotePageSize
+	}
+	return &VoteIterator{
+		client:     c,
+		proposalID: proposalID,
+		pageSize:   pageSize,
+		key:        key,
+	}
+}
+
+// Next fetches the next page of votes. The position only advances when the
+// page was fetched, so Next can be retried after an error
+func (it *VoteIterator) Next(ctx context.Context) ([]Vote, error) {
+	if it.done {
+		return nil, nil
+	}
+
+	votes, page, err := it.client.GetVotes(ctx, it.proposalID, PageRequest{Key: it.key, Limit: it.pageSize})
+	if err != nil {
+		return nil, err
+	}
+
+	it.key = page.NextKey
+	it.done = page.NextKey == ""
+	return votes, nil
+}
+
+// Done reports whether every page has been fetched
+func (it *VoteIterator) Done() bool {
+	return it.done
+}
+
+// Key returns the page key of the next page to fetch
+func (it *VoteIterator) Key() string {
+	return it.key
+}
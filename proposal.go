@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"governance-alerts-cosmos/internal/amounts"
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/report"
+	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/store"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	proposalFormat string
+	recheckReset   bool
+	recheckAPI     string
+	checklistText  bool
+)
+
+var proposalCmd = &cobra.Command{
+	Use:   "proposal",
+	Short: "Inspect individual proposals",
+}
+
+var proposalShowCmd = &cobra.Command{
+	Use:          "show <network> <id>",
+	Short:        "Show everything known about a proposal",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runProposalShow,
+}
+
+var proposalRecheckCmd = &cobra.Command{
+	Use:          "recheck <network> <id>",
+	Short:        "Make the running service re-evaluate a proposal",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runProposalRecheck,
+}
+
+var proposalChecklistCmd = &cobra.Command{
+	Use:          "checklist <network> <id>",
+	Short:        "Print the pre-vote checklist of a software upgrade proposal",
+	Long:         "Print the pre-vote checklist of a software upgrade proposal as a GitHub issue body, covering binaries, release notes, the halt height estimate and dependencies.",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runProposalChecklist,
+}
+
+func init() {
+	proposalShowCmd.Flags().StringVarP(&proposalFormat, "format", "f", "md", "Output format (md, json, yaml)")
+	proposalRecheckCmd.Flags().BoolVar(&recheckReset, "reset", false, "Forget the alerts already sent for the proposal first")
+	proposalRecheckCmd.Flags().StringVar(&recheckAPI, "api", "", "Base URL of the service API (default from api.listen)")
+	proposalCmd.AddCommand(proposalShowCmd)
+	proposalChecklistCmd.Flags().BoolVar(&checklistText, "text", false, "Print the checklist as plain text instead of Markdown")
+	proposalCmd.AddCommand(proposalRecheckCmd)
+	proposalCmd.AddCommand(proposalChecklistCmd)
+	rootCmd.AddCommand(proposalCmd)
+}
+
+func runProposalShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	_, networkConfig, ok := config.LookupNetwork(cfg, args[0])
+	if !ok {
+		return fmt.Errorf("unknown network %q", args[0])
+	}
+
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid proposal ID %q", args[1])
+	}
+
+	privacy.Install(cfg)
+	amounts.Install(cfg.Formatting)
+
+	client, err := governance.NewClient(networkConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	proposalReport, err := report.BuildProposalReport(ctx, client, networkConfig, proposalID)
+	if err != nil {
+		return err
+	}
+
+	// Include team notes, tags and tracking links recorded by the service
+	state, err := store.Open(cfg.State.Path)
+	if err != nil {
+		return err
+	}
+	key := store.ProposalKey(networkConfig.ChainID, proposalID)
+	proposalReport.Tags = state.Tags(key)
+	proposalReport.Notes = state.Notes(key)
+	proposalReport.Links = state.Links(key)
+	for _, late := range state.LateAlerts(key) {
+		proposalReport.AddEvent(late.SentAt, fmt.Sprintf("%s alert sent %s late", late.AlertType, late.Lateness.Round(time.Minute)))
+	}
+	for _, event := range state.Timeline(key) {
+		proposalReport.AddEvent(event.Time, timelineEventText(event))
+	}
+
+	out, err := proposalReport.Render(proposalFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(out)
+	return nil
+}
+
+// runProposalChecklist prints the upgrade checklist the service sends to
+// ops channels when an upgrade proposal enters voting period
+func runProposalChecklist(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	_, networkConfig, ok := config.LookupNetwork(cfg, args[0])
+	if !ok {
+		return fmt.Errorf("unknown network %q", args[0])
+	}
+
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid proposal ID %q", args[1])
+	}
+
+	privacy.Install(cfg)
+
+	client, err := governance.NewClient(networkConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	if err != nil {
+		return err
+	}
+	checklist := report.BuildUpgradeChecklist(ctx, client, networkConfig, *proposal)
+	if checklist == nil {
+		return fmt.Errorf("proposal #%d is not a software upgrade", proposalID)
+	}
+
+	if checklistText {
+		fmt.Println(checklist.Text())
+		return nil
+	}
+	fmt.Print(checklist.Markdown())
+	return nil
+}
+
+// timelineEventText describes an event the service recorded about a
+// proposal
+func timelineEventText(event types.TimelineEvent) string {
+	switch event.Kind {
+	case types.TimelineStatus:
+		return "Status: " + event.Detail
+	case types.TimelineTally:
+		return "Tally: " + event.Detail
+	default:
+		return event.Detail
+	}
+}
+
+// runProposalRecheck asks the running service through its API to re-fetch a
+// proposal and re-run its alert decisions, so the service stays the only
+// writer of the alert state
+func runProposalRecheck(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if _, err := strconv.ParseUint(args[1], 10, 64); err != nil {
+		return fmt.Errorf("invalid proposal ID %q", args[1])
+	}
+
+	client, err := newAPIClient(cfg, recheckAPI)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var result service.RecheckResult
+	path := fmt.Sprintf("/api/v1/networks/%s/proposals/%s/recheck?reset=%t", url.PathEscape(args[0]), args[1], recheckReset)
+	if err := client.call(ctx, http.MethodPost, path, &result); err != nil {
+		return fmt.Errorf("recheck failed: %w", err)
+	}
+
+	fmt.Printf("Proposal #%d on %s: %s\n", result.ProposalID, result.Network, result.Status)
+	if result.Reset {
+		fmt.Println("Alert state reset")
+	}
+	if len(result.Sent) == 0 {
+		fmt.Println("No alerts sent")
+	}
+	for _, alertType := range result.Sent {
+		fmt.Printf("Sent: %s\n", alertType)
+	}
+	return nil
+}
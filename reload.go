@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// configPollInterval is how often the configuration file is checked for
+// changes
+const configPollInterval = 5 * time.Second
+
+// watchConfigFile signals on reload whenever the file at path changes,
+// until ctx is done. Editors that replace the file are handled by
+// comparing modification time and size instead of watching the inode
+func watchConfigFile(ctx context.Context, path string, reload chan<- struct{}) {
+	last, err := os.Stat(path)
+	if err != nil {
+		logrus.WithError(err).Warn("Not watching the configuration file")
+		return
+	}
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+			last = info
+			select {
+			case reload <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// reloadConfig loads the configuration file again and applies it to svc,
+// returning the configuration now in effect. A configuration that fails to
+// load or apply is rejected and current stays in effect
+func reloadConfig(cmd *cobra.Command, svc *service.Service, current *types.Config) *types.Config {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logrus.WithError(err).Error("Configuration reload rejected, keeping the running configuration")
+		return current
+	}
+	if dryRun {
+		cfg.DryRun = true
+	}
+
+	// New endpoints and channels must be reachable as soon as they are
+	// created
+	privacy.Update(cfg)
+	if err := svc.Reload(cfg); err != nil {
+		privacy.Update(current)
+		logrus.WithError(err).Error("Configuration reload failed, keeping the running configuration")
+		return current
+	}
+
+	if err := configureLogging(cmd, cfg.Logging); err != nil {
+		logrus.WithError(err).Warn("Logging configuration not applied")
+	}
+	return cfg
+}
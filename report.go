@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/report"
+	"governance-alerts-cosmos/internal/store"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormat    string
+	reportProposals int
+	reportBackfill  bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Print governance reports",
+}
+
+var reportParticipationCmd = &cobra.Command{
+	Use:   "participation [network...]",
+	Short: "Print the voting power weighted participation of the latest proposals",
+	Long: "Print the share of the bonded stake that voted on the latest proposals of each network and its trend, " +
+		"from the turnout the service recorded as voting ended. With --backfill, proposals missing from the state " +
+		"are fetched from the chain and estimated against the current bonded stake.",
+	SilenceUsage: true,
+	RunE:         runReportParticipation,
+}
+
+func init() {
+	reportParticipationCmd.Flags().StringVarP(&reportFormat, "format", "f", "md", "Output format (md, json, yaml)")
+	reportParticipationCmd.Flags().IntVarP(&reportProposals, "proposals", "n", 0, "Latest proposals per network (default reports.participation_proposals)")
+	reportParticipationCmd.Flags().BoolVar(&reportBackfill, "backfill", false, "Fetch finished proposals missing from the state from the chain")
+	reportCmd.AddCommand(reportParticipationCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+// runReportParticipation prints the participation report of the given
+// networks, all configured networks by default
+func runReportParticipation(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	networks := make([]types.NetworkConfig, 0, len(cfg.Networks))
+	if len(args) == 0 {
+		for _, networkConfig := range cfg.Networks {
+			networks = append(networks, networkConfig)
+		}
+	}
+	for _, name := range args {
+		_, networkConfig, ok := config.LookupNetwork(cfg, name)
+		if !ok {
+			return fmt.Errorf("unknown network %q", name)
+		}
+		networks = append(networks, networkConfig)
+	}
+
+	limit := reportProposals
+	if limit <= 0 {
+		limit = cfg.Reports.ParticipationProposals
+	}
+
+	state, err := store.Open(cfg.State.Path)
+	if err != nil {
+		return err
+	}
+	privacy.Install(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	r := &report.ParticipationReport{Time: time.Now(), Proposals: limit}
+	for _, networkConfig := range networks {
+		snapshots := state.Participation(networkConfig.ChainID)
+		if reportBackfill {
+			client, err := governance.NewClient(networkConfig)
+			if err != nil {
+				return fmt.Errorf("failed to create client for %s: %w", networkConfig.Name, err)
+			}
+			if snapshots, err = report.BackfillParticipation(ctx, client, snapshots, limit); err != nil {
+				return fmt.Errorf("failed to backfill %s: %w", networkConfig.Name, err)
+			}
+		}
+		r.Networks = append(r.Networks, report.NewNetworkParticipation(networkConfig, snapshots, limit))
+	}
+	sort.Slice(r.Networks, func(i, j int) bool { return r.Networks[i].Network < r.Networks[j].Network })
+
+	out, err := r.Render(reportFormat)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package main
+
+import "fmt"
+
+// terminalState is the terminal mode to restore after raw mode
+type terminalState struct{}
+
+// makeRaw is not supported on this platform
+func makeRaw(fd int) (*terminalState, error) {
+	return nil, fmt.Errorf("terminal UI is not supported on this platform")
+}
+
+// restoreTerminal is not supported on this platform
+func restoreTerminal(fd int, state *terminalState) error {
+	return nil
+}
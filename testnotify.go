@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/notifications"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/spf13/cobra"
+)
+
+var testNotifyPagerDuty bool
+
+var testNotifyCmd = &cobra.Command{
+	Use:          "test-notify",
+	Short:        "Send a test notification through every enabled channel",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runTestNotify,
+}
+
+func init() {
+	testNotifyCmd.Flags().BoolVar(&testNotifyPagerDuty, "pagerduty", false, "Also open and immediately resolve a PagerDuty test incident")
+	rootCmd.AddCommand(testNotifyCmd)
+}
+
+// runTestNotify sends a synthetic message to every enabled channel and
+// reports which ones delivered it, so tokens and webhook URLs can be
+// checked before deploying
+func runTestNotify(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	privacy.Install(cfg)
+
+	notifier, err := notifications.NewNotifier(&cfg.Notifications, cfg.Networks)
+	if err != nil {
+		return fmt.Errorf("failed to create notifier: %w", err)
+	}
+
+	host, _ := os.Hostname()
+	msg := types.NotificationMessage{
+		Title:       "🧪 Test Notification",
+		Content:     fmt.Sprintf("This is a test notification sent by test-notify from %s at %s. No action is needed.", host, time.Now().Format("2006-01-02 15:04 MST")),
+		Network:     "Governance Alerts",
+		ChainID:     "Service",
+		ProposalID:  0,
+		ExplorerURL: "",
+		Role:        types.RoleOps,
+	}
+
+	statuses := notifier.SendTest(msg, testNotifyPagerDuty)
+	if len(statuses) == 0 {
+		return fmt.Errorf("no notification channel is enabled")
+	}
+
+	failed := 0
+	for _, status := range statuses {
+		if status.OK {
+			fmt.Printf("✓ %s\n", status.Channel)
+			continue
+		}
+		failed++
+		fmt.Printf("✗ %s: %v\n", status.Channel, status.Error)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d channels failed", failed, len(statuses))
+	}
+	return nil
+}
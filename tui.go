@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tuiAPI     string
+	tuiRefresh time.Duration
+)
+
+var tuiCmd = &cobra.Command{
+	Use:          "tui",
+	Short:        "Show a live terminal dashboard of the running service",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runTUI,
+}
+
+func init() {
+	tuiCmd.Flags().StringVar(&tuiAPI, "api", "", "Base URL of the service API (default from api.listen)")
+	tuiCmd.Flags().DurationVar(&tuiRefresh, "refresh", 5*time.Second, "Refresh interval")
+	rootCmd.AddCommand(tuiCmd)
+}
+
+// ANSI escape sequences used by the dashboard
+const (
+	ansiClear   = "\x1b[H\x1b[2J"
+	ansiHide    = "\x1b[?25l"
+	ansiShow    = "\x1b[?25h"
+	ansiAltOn   = "\x1b[?1049h"
+	ansiAltOff  = "\x1b[?1049l"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiReverse = "\x1b[7m"
+	ansiReset   = "\x1b[0m"
+)
+
+// dashboard is the state of the terminal dashboard
+type dashboard struct {
+	client     *apiClient
+	snoozeHrs  int
+	status     *service.Status
+	selected   int
+	message    string
+	lastUpdate time.Time
+}
+
+// dashboardRow is a selectable proposal of the dashboard
+type dashboardRow struct {
+	network  string
+	proposal service.TrackedProposal
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client, err := newAPIClient(cfg, tuiAPI)
+	if err != nil {
+		return err
+	}
+
+	d := &dashboard{client: client, snoozeHrs: cfg.Notifications.Reactions.SnoozeHours}
+	if d.snoozeHrs <= 0 {
+		d.snoozeHrs = 6
+	}
+	if err := d.refresh(); err != nil {
+		return err
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := makeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	fmt.Print(ansiAltOn + ansiHide)
+	defer func() {
+		fmt.Print(ansiShow + ansiAltOff)
+		restoreTerminal(fd, state)
+	}()
+
+	keys := make(chan string)
+	go readKeys(keys)
+
+	ticker := time.NewTicker(tuiRefresh)
+	defer ticker.Stop()
+
+	for {
+		d.render()
+		select {
+		case <-ticker.C:
+			d.report(d.refresh())
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch key {
+			case "q", "\x03":
+				return nil
+			case "j", "down":
+				d.move(1)
+			case "k", "up":
+				d.move(-1)
+			case "r":
+				d.report(d.refresh())
+			case "a":
+				d.report(d.ack(0))
+			case "s":
+				d.report(d.ack(d.snoozeHrs))
+			}
+		}
+	}
+}
+
+// readKeys sends key presses read from stdin, arrow keys as "up"/"down"
+func readKeys(keys chan<- string) {
+	buf := make([]byte, 8)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		switch input := string(buf[:n]); input {
+		case "\x1b[A":
+			keys <- "up"
+		case "\x1b[B":
+			keys <- "down"
+		default:
+			keys <- input
+		}
+	}
+}
+
+// refresh fetches the status of the service
+func (d *dashboard) refresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var status service.Status
+	if err := d.client.call(ctx, http.MethodGet, "/api/v1/status", &status); err != nil {
+		return fmt.Errorf("failed to fetch status: %w", err)
+	}
+	d.status = &status
+	d.lastUpdate = time.Now()
+	d.move(0)
+	return nil
+}
+
+// ack acknowledges the selected proposal, or snoozes it for snoozeHours
+func (d *dashboard) ack(snoozeHours int) error {
+	rows := d.rows()
+	if len(rows) == 0 {
+		return nil
+	}
+	row := rows[d.selected]
+
+	path := fmt.Sprintf("/api/v1/networks/%s/proposals/%d/ack?user=%s", url.PathEscape(row.network), row.proposal.ID, url.QueryEscape(tuiUser()))
+	if snoozeHours > 0 {
+		path += fmt.Sprintf("&snooze_hours=%d", snoozeHours)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var response struct {
+		Message string `json:"message"`
+	}
+	if err := d.client.call(ctx, http.MethodPost, path, &response); err != nil {
+		return fmt.Errorf("failed to acknowledge: %w", err)
+	}
+	d.message = response.Message
+	return d.refresh()
+}
+
+// report shows err in the status line
+func (d *dashboard) report(err error) {
+	if err != nil {
+		d.message = err.Error()
+	}
+}
+
+// move moves the selection by delta, keeping it within the rows
+func (d *dashboard) move(delta int) {
+	count := len(d.rows())
+	d.selected += delta
+	if d.selected >= count {
+		d.selected = count - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+}
+
+// rows lists the proposals of every network in display order
+func (d *dashboard) rows() []dashboardRow {
+	var rows []dashboardRow
+	if d.status == nil {
+		return rows
+	}
+	for _, network := range d.status.Networks {
+		for _, proposal := range network.Proposals {
+			rows = append(rows, dashboardRow{network: network.Network, proposal: proposal})
+		}
+	}
+	return rows
+}
+
+// render draws the dashboard
+func (d *dashboard) render() {
+	var b strings.Builder
+	now := time.Now()
+
+	b.WriteString(ansiClear)
+	fmt.Fprintf(&b, "%sGovernance Alerts%s  %s\n", ansiBold, ansiReset, now.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "%sj/k select · a ack · s snooze %dh · r refresh · q quit%s\n\n", ansiDim, d.snoozeHrs, ansiReset)
+
+	row := 0
+	for _, network := range d.status.Networks {
+		health := ansiGreen + "●" + ansiReset
+		if network.Failing {
+			health = ansiRed + "● failing" + ansiReset
+		}
+		checked := "not checked yet"
+		if !network.CheckedAt.IsZero() {
+			checked = "checked " + countdown(now.Sub(network.CheckedAt)) + " ago"
+		}
+		if next := network.NextCheck.Sub(now); next > 0 {
+			checked += ", next in " + countdown(next)
+		}
+		if network.LastErrorAt.After(network.CheckedAt) {
+			checked += ", failing: " + network.LastError
+		}
+		fmt.Fprintf(&b, "%s%s%s (%s) %s %s%s%s\n", ansiBold, network.Name, ansiReset, network.ChainID, health, ansiDim, checked, ansiReset)
+
+		if len(network.Proposals) == 0 {
+			fmt.Fprintf(&b, "  %sno proposals in voting period%s\n", ansiDim, ansiReset)
+		}
+		for _, proposal := range network.Proposals {
+			left := proposal.VotingEnd.Sub(now)
+			remaining := countdown(left) + " left"
+			color := ""
+			switch {
+			case left <= 0:
+				remaining, color = "ended", ansiDim
+			case left < 6*time.Hour:
+				color = ansiRed
+			case left < 24*time.Hour:
+				color = ansiYellow
+			}
+			line := fmt.Sprintf("  #%-5d %s%-12s%s %s", proposal.ID, color, remaining, ansiReset, truncateRunes(proposal.Title, 60))
+			if proposal.Held != "" {
+				line += fmt.Sprintf("  %s[%s]%s", ansiDim, proposal.Held, ansiReset)
+			}
+			if row == d.selected {
+				line = ansiReverse + ">" + ansiReset + line[1:]
+			}
+			b.WriteString(line + "\n")
+			row++
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "%sChannels%s\n", ansiBold, ansiReset)
+	if len(d.status.Channels) == 0 {
+		fmt.Fprintf(&b, "  %snone enabled%s\n", ansiDim, ansiReset)
+	}
+	for _, channel := range d.status.Channels {
+		switch {
+		case channel.LastSuccess.IsZero() && channel.LastError == "":
+			fmt.Fprintf(&b, "  %-10s %snothing sent yet%s\n", channel.Channel, ansiDim, ansiReset)
+		case channel.OK():
+			fmt.Fprintf(&b, "  %-10s %s✓%s last delivery %s ago\n", channel.Channel, ansiGreen, ansiReset, countdown(now.Sub(channel.LastSuccess)))
+		default:
+			fmt.Fprintf(&b, "  %-10s %s✗ %s%s (%s ago)\n", channel.Channel, ansiRed, truncateRunes(channel.LastError, 60), ansiReset, countdown(now.Sub(channel.LastErrorAt)))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%sRecent alerts%s\n", ansiBold, ansiReset)
+	if len(d.status.Alerts) == 0 {
+		fmt.Fprintf(&b, "  %snone since the service started%s\n", ansiDim, ansiReset)
+	}
+	for i, alert := range d.status.Alerts {
+		if i == 10 {
+			break
+		}
+		fmt.Fprintf(&b, "  %s  %s\n", alert.Time.Local().Format("01-02 15:04"), truncateRunes(alert.Title, 70))
+	}
+
+	if d.message != "" {
+		fmt.Fprintf(&b, "\n%s\n", d.message)
+	}
+	fmt.Fprintf(&b, "%supdated %s ago%s\n", ansiDim, countdown(now.Sub(d.lastUpdate)), ansiReset)
+
+	// Raw mode leaves output processing on, "\n" still returns the carriage
+	fmt.Print(b.String())
+}
+
+// countdown formats a duration for the dashboard, such as "2d 5h" or "12s"
+func countdown(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+// truncateRunes shortens s to at most max characters
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// tuiUser names the dashboard user in acknowledgements
+func tuiUser() string {
+	if user := os.Getenv("USER"); user != "" {
+		return user + " (tui)"
+	}
+	return "tui"
+}
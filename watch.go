@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"governance-alerts-cosmos/internal/config"
+	"governance-alerts-cosmos/internal/governance"
+	"governance-alerts-cosmos/internal/privacy"
+	"governance-alerts-cosmos/internal/service"
+	"governance-alerts-cosmos/internal/types"
+
+	"github.com/q163i/governance-alerts-cosmos/pkg/cosmosgov"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchTelegramToken string
+	watchChatID        int64
+	watchChainID       string
+	watchName          string
+	watchInterval      time.Duration
+	watchHoursBefore   int
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <rest-endpoint> <proposal-id>",
+	Short: "Watch a single proposal until it resolves, without a config file",
+	Long: `Watch a single proposal until it resolves, without a config file.
+
+The proposal gets the same alerts as with a configured network: new
+proposal, voting start and end reminders, tally updates and the outcome,
+after which the command exits. Alerts go to Telegram when a token and a
+chat ID are given and are only logged otherwise.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runWatch,
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&watchTelegramToken, "telegram-token", "", "Telegram bot token to send alerts with")
+	watchCmd.Flags().Int64Var(&watchChatID, "chat-id", 0, "Telegram chat to send alerts to")
+	watchCmd.Flags().StringVar(&watchChainID, "chain-id", "", "Chain ID of the network (default from the latest block)")
+	watchCmd.Flags().StringVar(&watchName, "name", "", "Network name shown in alerts (default the chain ID)")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 5*time.Minute, "How often the proposal is checked")
+	watchCmd.Flags().IntVar(&watchHoursBefore, "hours-before-end", 6, "Hours before the end of voting to send the reminder")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchTallyPercents are the shares of the voting period left at which the
+// watched proposal gets a tally update
+var watchTallyPercents = []int{50, 25, 10}
+
+// runWatch monitors one proposal with an in-memory service built from the
+// flags, checking until the proposal leaves deposit and voting period
+func runWatch(cmd *cobra.Command, args []string) error {
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %w", err)
+	}
+	logrus.SetLevel(level)
+
+	proposalID, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid proposal ID %q", args[1])
+	}
+	if (watchTelegramToken == "") != (watchChatID == 0) {
+		return fmt.Errorf("--telegram-token and --chat-id must be given together")
+	}
+	if watchInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+
+	cfg, err := watchConfig(args[0], proposalID)
+	if err != nil {
+		return err
+	}
+	networkConfig := cfg.Networks["watch"]
+
+	privacy.Install(cfg)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	client, err := governance.NewClient(networkConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	// The chain ID keys the alert state, take it from the node when unset
+	if networkConfig.ChainID == "" {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to detect the chain ID, set --chain-id: %w", err)
+		}
+		networkConfig.ChainID = chainID
+		if watchName == "" {
+			networkConfig.Name = chainID
+		}
+		cfg.Networks["watch"] = networkConfig
+	}
+	if err := config.Validate(cfg); err != nil {
+		return err
+	}
+
+	proposal, err := client.GetProposalDetails(ctx, proposalID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch proposal %d: %w", proposalID, err)
+	}
+	if !watchOpen(proposal.Status) {
+		return fmt.Errorf("proposal %d is already %s", proposalID, governance.StatusLabel(proposal.Status))
+	}
+
+	svc, err := service.NewService(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	defer svc.Stop()
+
+	if watchTelegramToken == "" {
+		logrus.Warn("No Telegram token given, alerts are only logged")
+	}
+	logrus.Infof("Watching %s proposal %d (%s): %s", networkConfig.Name, proposalID, governance.StatusLabel(proposal.Status), proposal.Title)
+
+	for {
+		if err := svc.CheckOnce(ctx); err != nil && ctx.Err() == nil {
+			logrus.WithError(err).Warn("Check failed, retrying at the next interval")
+		}
+
+		status, err := client.CheckProposalStatus(ctx, proposalID)
+		switch {
+		case ctx.Err() != nil:
+			logrus.Info("Watch stopped")
+			return nil
+		case errors.Is(err, governance.ErrNotFound):
+			// Proposals that miss their minimum deposit are deleted
+			logrus.Infof("Proposal %d was removed, it did not reach its deposit", proposalID)
+			return nil
+		case err != nil:
+			logrus.WithError(err).Warn("Failed to fetch proposal status")
+		case !watchOpen(status):
+			// One more check sends the outcome if the proposal resolved
+			// after the check above
+			if err := svc.CheckOnce(ctx); err != nil {
+				logrus.WithError(err).Warn("Final check failed")
+			}
+			logrus.Infof("Proposal %d is %s, watch done", proposalID, governance.StatusLabel(status))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Watch stopped")
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// watchConfig builds the configuration of the watch command: one network
+// limited to the proposal, an in-memory state and Telegram as the only
+// channel
+func watchConfig(endpoint string, proposalID uint64) (*types.Config, error) {
+	cfg, err := config.Default()
+	if err != nil {
+		return nil, err
+	}
+
+	name := watchName
+	if name == "" {
+		name = watchChainID
+	}
+	cfg.Networks = map[string]types.NetworkConfig{
+		"watch": {
+			Name:         name,
+			RestEndpoint: endpoint,
+			ChainID:      watchChainID,
+			ProposalIDs:  []uint64{proposalID},
+		},
+	}
+	cfg.Alerts.HoursBeforeStart = 1
+	cfg.Alerts.HoursBeforeEnd = watchHoursBefore
+	cfg.Alerts.CheckIntervalMinutes = max(int(watchInterval/time.Minute), 1)
+	cfg.Alerts.NotifyOnNewProposal = true
+	cfg.Alerts.TallyUpdates.RemainingPercent = watchTallyPercents
+	cfg.Performance.MaxConcurrentNetworks = 1
+	cfg.State.Path = ""
+
+	if watchTelegramToken != "" {
+		cfg.Notifications.Telegram = types.TelegramConfig{
+			Enabled:  true,
+			BotToken: watchTelegramToken,
+			ChatID:   watchChatID,
+		}
+	}
+	return cfg, nil
+}
+
+// watchOpen reports whether a proposal is still in deposit or voting period
+func watchOpen(status string) bool {
+	return status == string(cosmosgov.StatusDepositPeriod) || status == string(cosmosgov.StatusVotingPeriod)
+}